@@ -0,0 +1,100 @@
+package ex
+
+import "errors"
+
+// annotation is the wrapper returned by Annotate. It adds context to an
+// error without changing its message, code, ID, or identity.
+type annotation struct {
+	err    error
+	op     string
+	fields []Field
+}
+
+// Annotate wraps err with fields, leaving everything else about it
+// unchanged: Error() returns the same text, and errors.Is and errors.As see
+// the same chain, including the original Exception's code and ID. It is
+// meant for middle layers that know useful context but have no business
+// classifying the error:
+//
+//	if err := repo.Save(ctx, order); err != nil {
+//	    return ex.Annotate(err, ex.F("order_id", order.ID))
+//	}
+//
+// err may be any error. Annotate returns nil for a nil err, and err itself
+// when there are no fields to add. Use FieldsOf to read the fields back.
+func Annotate(err error, fields ...Field) error {
+	return AnnotateOp(err, "", fields...)
+}
+
+// AnnotateOp is Annotate with the name of the operation that was being
+// performed, such as "orders.Save". Use Ops to read operations back.
+func AnnotateOp(err error, op string, fields ...Field) error {
+	if err == nil || (op == "" && len(fields) == 0) {
+		return err
+	}
+	a := &annotation{err: err, op: op}
+	if len(fields) > 0 {
+		a.fields = make([]Field, len(fields))
+		for i, f := range fields {
+			f.Value = attachValue(f.Value)
+			a.fields[i] = f
+		}
+	}
+	return a
+}
+
+// Error returns the wrapped error's message unchanged.
+func (a *annotation) Error() string {
+	return a.err.Error()
+}
+
+// Unwrap returns the annotated error.
+func (a *annotation) Unwrap() error {
+	return a.err
+}
+
+// FieldsOf returns the fields attached anywhere in err's chain, by
+// Annotate, by an Exception's With* methods, or by any other Errorer,
+// outermost first. When a key appears more than once, the outermost
+// value wins.
+func FieldsOf(err error) []Field {
+	var out []Field
+	seen := map[string]bool{}
+	add := func(fields []Field) {
+		for _, f := range fields {
+			if !seen[f.Key] {
+				seen[f.Key] = true
+				out = append(out, f)
+			}
+		}
+	}
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch v := cur.(type) {
+		case *annotation:
+			add(v.fields)
+		case Exception:
+			add(v.fields)
+		case Errorer:
+			add(v.Fields())
+		}
+	}
+	return out
+}
+
+// Ops returns the operations recorded in err's chain by AnnotateOp, and
+// the intermediate messages preserved by Compact, outermost first.
+// Operations survive JSON encoding; see MarshalJSON.
+func Ops(err error) []string {
+	var out []string
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch v := cur.(type) {
+		case *annotation:
+			if v.op != "" {
+				out = append(out, v.op)
+			}
+		case Exception:
+			out = append(out, v.ops...)
+		}
+	}
+	return out
+}
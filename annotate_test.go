@@ -0,0 +1,79 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotate_PreservesIdentity(t *testing.T) {
+	orig := ex.New(ex.ExTypeApplicationFailure, 500, "Database down")
+	annotated := ex.AnnotateOp(orig, "orders.Save", ex.F("order_id", 7))
+
+	assert.Equal(t, orig.Error(), annotated.Error())
+	assert.ErrorIs(t, annotated, ex.New(ex.ExTypeApplicationFailure, 500, ""))
+
+	var e ex.Exception
+	require.True(t, errors.As(annotated, &e))
+	assert.Equal(t, 500, e.ID())
+
+	plain := errors.New("EOF")
+	assert.ErrorIs(t, ex.Annotate(plain, ex.F("k", "v")), plain)
+
+	assert.NoError(t, ex.Annotate(nil, ex.F("k", "v")))
+	assert.Equal(t, plain, ex.Annotate(plain))
+}
+
+func TestFieldsOfAndOps(t *testing.T) {
+	base := ex.New(ex.ExTypeApplicationFailure, 500, "Database down").
+		WithFields(ex.F("table", "orders"), ex.F("attempt", 1))
+	err := ex.AnnotateOp(
+		fmt.Errorf("checkout: %w", ex.AnnotateOp(base, "orders.Save", ex.F("attempt", 3))),
+		"checkout.Submit", ex.F("user", "42"))
+
+	assert.Equal(t, []ex.Field{
+		ex.F("user", "42"), ex.F("attempt", 3), ex.F("table", "orders"),
+	}, ex.FieldsOf(err))
+	assert.Equal(t, []string{"checkout.Submit", "orders.Save"}, ex.Ops(err))
+	assert.Nil(t, ex.Ops(base))
+}
+
+func TestAnnotate_JSON(t *testing.T) {
+	base := ex.New(ex.ExTypeApplicationFailure, 500, "Database down").WithField("table", "orders")
+	wrapped := ex.New(ex.ExTypeApplicationFailure, 503, "Checkout failed").
+		WithInnerError(ex.AnnotateOp(base, "orders.Save", ex.F("table", "orders_2026"), ex.F("order_id", 7)))
+
+	data, err := json.Marshal(wrapped)
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	var inner ex.Exception
+	require.True(t, errors.As(decoded.InnerError(), &inner))
+	table, _ := inner.FieldValue("table")
+	assert.Equal(t, "orders_2026", table, "the outermost value wins, as in FieldsOf")
+	assert.Contains(t, ex.FieldsOf(wrapped), ex.F("table", "orders_2026"))
+	orderID, _ := inner.FieldValue("order_id")
+	assert.Equal(t, float64(7), orderID)
+	assert.Equal(t, []string{"orders.Save"}, ex.Ops(decoded))
+}
+
+func TestAnnotate_RedactedAndHTML(t *testing.T) {
+	saved := ex.SensitiveKeys()
+	ex.SetSensitiveKeys("password")
+	t.Cleanup(func() { ex.SetSensitiveKeys(saved...) })
+
+	err := ex.New(ex.ExTypeIncorrectData, 400, "Login failed").
+		WithInnerError(ex.AnnotateOp(errors.New("bad hash"), "auth.Check", ex.F("password", "hunter2")))
+
+	assert.Equal(t, []ex.Field{ex.F("password", ex.RedactedValue)}, ex.FieldsOf(err.Redacted()))
+
+	html := string(ex.ToHTML(err))
+	assert.Contains(t, html, "annotation: auth.Check")
+	assert.NotContains(t, html, "hunter2")
+}
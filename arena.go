@@ -0,0 +1,91 @@
+package ex
+
+import "slices"
+
+// arenaChunk is the number of elements in each chunk of an Arena.
+const arenaChunk = 4096
+
+// Arena is an allocator for batch pipelines that create exceptions by the
+// million and drop them together: it carves their field slices and
+// captured stacks out of fixed-size chunks, and Release makes every chunk
+// available again at once. After the first batch, an arena reused across
+// batches stops allocating for field and stack storage altogether:
+//
+//	arena := ex.NewArena()
+//	for batch := range batches {
+//	    for _, rec := range batch {
+//	        if rec.Amount < 0 {
+//	            report(arena.New(ex.ExTypeIncorrectData, 4001, "Negative amount",
+//	                ex.F("record", rec.ID)))
+//	        }
+//	    }
+//	    flush()
+//	    arena.Release()
+//	}
+//
+// Exceptions are values, so building one costs nothing on the heap by
+// itself; the arena takes over the storage they point to. Boxing an
+// exception into an error still allocates, as usual.
+//
+// Like Pool, an Arena is unsafe by design: after Release, every exception
+// built through it may see its fields and stack overwritten, so none may
+// outlive the batch. Encode an exception, for example with MarshalJSON, to
+// keep a copy. Unlike a Pool, an Arena keeps its chunks when released and
+// is meant to be reused rather than returned; chunks are fixed-size, so a
+// growing batch adds chunks instead of copying them. Hooks are the
+// exception: New hands them fields copied off the arena, since a hook
+// such as a Reporter's may keep them. A nil *Arena is valid and allocates
+// normally. An Arena is safe for concurrent use.
+type Arena struct {
+	store chunkStore
+}
+
+// NewArena returns an empty Arena.
+func NewArena() *Arena {
+	return &Arena{store: chunkStore{size: arenaChunk}}
+}
+
+// New is New with fields attached, as by WithFields, in storage taken
+// from a. Like New, it follows the stack policy and runs the hooks. Hooks
+// may keep what they are given, so they see a copy of the fields on the
+// heap, which Release does not overwrite; that copy is the one allocation
+// an arena makes while hooks are registered.
+func (a *Arena) New(code ExType, id int, message string, fields ...Field) Exception {
+	checkNew(code, id, message)
+	e := Exception{code: code, id: id, message: message, stack: autoStack(code, id, 1), sizeHint: len(message) + 1}
+	e = a.WithFields(e, fields...)
+	hooked := e
+	if a != nil && hooks.active() {
+		hooked.fields = slices.Clone(e.fields)
+	}
+	runHooks(hooked)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// WithFields is e.WithFields with the merged field slice taken from a.
+func (a *Arena) WithFields(e Exception, fields ...Field) Exception {
+	if a == nil {
+		return e.WithFields(fields...)
+	}
+	return a.store.withFields(e, fields)
+}
+
+// WithStack is e.WithStack with the program counters stored in a.
+func (a *Arena) WithStack(e Exception) Exception {
+	if a == nil || stacksDisabled() {
+		return e.WithStack()
+	}
+	return a.store.withStack(e)
+}
+
+// Release invalidates every exception built through a and makes all of
+// its storage available to the next batch. Released field values are
+// cleared, so the arena does not keep what they refer to alive. Releasing
+// a nil Arena does nothing.
+func (a *Arena) Release() {
+	if a == nil {
+		return
+	}
+	a.store.reset()
+}
@@ -0,0 +1,101 @@
+package ex_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArena_New(t *testing.T) {
+	a := ex.NewArena()
+	defer a.Release()
+
+	var hooked []ex.Field
+	remove := ex.AddHook(func(e ex.Exception) { hooked = e.Fields() })
+	t.Cleanup(remove)
+
+	e := a.New(ex.ExTypeIncorrectData, 4001, "Negative amount", ex.F("record", 7), ex.F("record", 8), ex.F("batch", "b1"))
+	assert.Equal(t, "Negative amount", e.Error())
+	assert.Equal(t, []ex.Field{{Key: "record", Value: 8}, {Key: "batch", Value: "b1"}}, e.Fields())
+	assert.Equal(t, e.Fields(), hooked, "hooks see the fields")
+
+	more := a.WithFields(e, ex.F("line", 12))
+	assert.Equal(t, []ex.Field{{Key: "record", Value: 8}, {Key: "batch", Value: "b1"}, {Key: "line", Value: 12}}, more.Fields())
+	assert.Len(t, e.Fields(), 2, "deriving does not clobber the original")
+}
+
+func TestArena_HooksKeepFields(t *testing.T) {
+	a := ex.NewArena()
+	defer a.Release()
+
+	var kept ex.Exception
+	remove := ex.AddHook(func(e ex.Exception) { kept = e })
+	t.Cleanup(remove)
+
+	_ = a.New(ex.ExTypeIncorrectData, 4001, "Negative amount", ex.F("record", 7))
+	held := kept
+	a.Release()
+	_ = a.New(ex.ExTypeIncorrectData, 4001, "Negative amount", ex.F("record", 8))
+
+	v, ok := held.FieldValue("record")
+	require.True(t, ok)
+	assert.Equal(t, 7, v, "what a hook keeps outlives Release")
+}
+
+func TestArena_ManyChunks(t *testing.T) {
+	a := ex.NewArena()
+	defer a.Release()
+
+	var all []ex.Exception
+	for i := range 10000 {
+		all = append(all, a.New(ex.ExTypeIncorrectData, i, "x", ex.F("i", i)))
+	}
+	big := make([]ex.Field, 5000)
+	for i := range big {
+		big[i] = ex.F(strconv.Itoa(i), i)
+	}
+	huge := a.New(ex.ExTypeIncorrectData, 1, "huge", big...)
+	for i, e := range all {
+		v, ok := e.FieldValue("i")
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.Len(t, huge.Fields(), 5000)
+}
+
+func TestArena_WithStack(t *testing.T) {
+	a := ex.NewArena()
+	defer a.Release()
+
+	e := a.WithStack(ex.New(ex.ExTypeApplicationFailure, 500, "Failed"))
+	require.True(t, e.HasStack())
+	assert.Contains(t, e.StackTrace()[0].Function, "TestArena_WithStack")
+}
+
+func TestArena_ReuseAfterRelease(t *testing.T) {
+	if ex.Tracking() {
+		t.Skip("creation tracking allocates for every exception")
+	}
+	a := ex.NewArena()
+	batch := func() {
+		for i := range 1000 {
+			_ = a.WithStack(a.New(ex.ExTypeIncorrectData, i, "x", ex.F("batch", "b1")))
+		}
+		a.Release()
+	}
+	batch()
+
+	allocs := testing.AllocsPerRun(5, batch)
+	assert.Zero(t, allocs, "a warmed-up arena allocates nothing")
+}
+
+func TestArena_Nil(t *testing.T) {
+	var a *ex.Arena
+	e := a.New(ex.ExTypeIncorrectData, 400, "x", ex.F("a", 1))
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 1}}, e.Fields())
+	assert.True(t, a.WithStack(e).HasStack())
+	a.Release()
+}
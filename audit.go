@@ -0,0 +1,75 @@
+package ex
+
+import "errors"
+
+// Field keys used by WithAudit. They are ordinary fields, so they travel
+// through every encoder and adapter that understands fields.
+const (
+	AuditActorKey    = "audit.actor"
+	AuditActionKey   = "audit.action"
+	AuditResourceKey = "audit.resource"
+)
+
+// AuditRecord is the who/what/where of a security-relevant failure,
+// extracted from an exception tagged with WithAudit.
+type AuditRecord struct {
+	Actor    string `json:"actor"`
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+
+	// Code, ID, and Message identify the exception that carried the tags.
+	Code    ExType `json:"code"`
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+// WithAudit returns a new Exception tagged with the actor that attempted
+// an action, the action itself, and the resource it targeted. It is meant
+// for ExTypeLoginRequired and ExTypePermissionDenied exceptions that must
+// flow into an audit log, but works with any code.
+func (e Exception) WithAudit(actor, action, resource string) Exception {
+	return e.WithFields(
+		Field{Key: AuditActorKey, Value: actor},
+		Field{Key: AuditActionKey, Value: action},
+		Field{Key: AuditResourceKey, Value: resource},
+	)
+}
+
+// AuditRecord returns the audit tags of the first exception in the chain,
+// starting with e itself, that was tagged with WithAudit.
+func (e Exception) AuditRecord() (AuditRecord, bool) {
+	var err error = e
+	for err != nil {
+		if exc, ok := err.(Errorer); ok {
+			if rec, found := auditRecordOf(exc); found {
+				return rec, true
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return AuditRecord{}, false
+}
+
+// auditRecordOf extracts the audit tags of e's level only.
+func auditRecordOf(e Errorer) (AuditRecord, bool) {
+	var actor, action, resource any
+	found := false
+	for _, f := range e.Fields() {
+		switch f.Key {
+		case AuditActorKey:
+			actor, found = f.Value, true
+		case AuditActionKey:
+			action, found = f.Value, true
+		case AuditResourceKey:
+			resource, found = f.Value, true
+		}
+	}
+	if !found {
+		return AuditRecord{}, false
+	}
+	rec := AuditRecord{Code: e.Code(), ID: e.ID(), Message: e.Message()}
+	rec.Actor, _ = actor.(string)
+	rec.Action, _ = action.(string)
+	rec.Resource, _ = resource.(string)
+	return rec, true
+}
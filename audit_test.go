@@ -0,0 +1,54 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestException_WithAudit(t *testing.T) {
+	exc := ex.New(ex.ExTypePermissionDenied, 403, "Access denied").
+		WithAudit("user:42", "delete", "invoice:7")
+
+	rec, ok := exc.AuditRecord()
+	require.True(t, ok)
+	assert.Equal(t, ex.AuditRecord{
+		Actor:    "user:42",
+		Action:   "delete",
+		Resource: "invoice:7",
+		Code:     ex.ExTypePermissionDenied,
+		ID:       403,
+		Message:  "Access denied",
+	}, rec)
+}
+
+func TestException_AuditRecord_SearchesChain(t *testing.T) {
+	denied := ex.New(ex.ExTypeLoginRequired, 401, "Login required").
+		WithAudit("anonymous", "read", "/admin")
+	outer := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").WithInnerError(denied)
+
+	rec, ok := outer.AuditRecord()
+	require.True(t, ok)
+	assert.Equal(t, "anonymous", rec.Actor)
+	assert.Equal(t, ex.ExTypeLoginRequired, rec.Code)
+
+	_, ok = ex.New(ex.ExTypeIncorrectData, 400, "untagged").AuditRecord()
+	assert.False(t, ok)
+}
+
+func TestException_AuditRecord_SurvivesJSON(t *testing.T) {
+	exc := ex.New(ex.ExTypePermissionDenied, 403, "Access denied").
+		WithAudit("user:42", "delete", "invoice:7")
+
+	data, err := json.Marshal(exc)
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	rec, ok := decoded.AuditRecord()
+	require.True(t, ok)
+	assert.Equal(t, "invoice:7", rec.Resource)
+}
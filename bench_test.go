@@ -16,6 +16,18 @@ func BenchmarkNew(b *testing.B) {
 	}
 }
 
+// BenchmarkFast measures the hook-free constructor, which must never
+// allocate.
+func BenchmarkFast(b *testing.B) {
+	if allocs := testing.AllocsPerRun(100, func() { _ = ex.Fast(ex.ExTypeIncorrectData, 4001, "Negative quantity") }); allocs != 0 {
+		b.Fatalf("Fast allocates %v times per call, want 0", allocs)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ex.Fast(ex.ExTypeIncorrectData, 4001, "Negative quantity")
+	}
+}
+
 // Benchmark exception creation with inner error
 func BenchmarkNewWithInnerError(b *testing.B) {
 	innerErr := errors.New("database connection failed")
@@ -155,3 +167,222 @@ func BenchmarkErrorChainTraversal(b *testing.B) {
 		}
 	}
 }
+
+// Benchmark symbolizing a captured stack, with and without the frame cache
+func BenchmarkStackTrace(b *testing.B) {
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Internal server error").WithStack()
+	b.Cleanup(func() { ex.SetFrameCacheSize(ex.DefaultFrameCacheSize) })
+
+	for _, bc := range []struct {
+		name string
+		size int
+	}{
+		{"Uncached", 0},
+		{"Cached", ex.DefaultFrameCacheSize},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ex.SetFrameCacheSize(bc.size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = exc.StackTrace()
+			}
+		})
+	}
+}
+
+func BenchmarkPoolWithFields(b *testing.B) {
+	base := ex.New(ex.ExTypeIncorrectData, 400, "Bad input")
+	b.Run("Heap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = base.WithFields(ex.F("a", "x"), ex.F("b", "y"))
+		}
+	})
+	b.Run("Pooled", func(b *testing.B) {
+		p := ex.AcquirePool()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			// Release periodically, as a request boundary would.
+			if i%100 == 99 {
+				p.Release()
+				p = ex.AcquirePool()
+			}
+			_ = p.WithFields(base, ex.F("a", "x"), ex.F("b", "y"))
+		}
+		p.Release()
+	})
+}
+
+func BenchmarkArenaNew(b *testing.B) {
+	b.Run("Heap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = ex.New(ex.ExTypeIncorrectData, 4001, "Negative amount").WithFields(ex.F("batch", "b1"), ex.F("stage", "load"))
+		}
+	})
+	b.Run("Arena", func(b *testing.B) {
+		a := ex.NewArena()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			// Release periodically, as a batch boundary would.
+			if i%10000 == 9999 {
+				a.Release()
+			}
+			_ = a.New(ex.ExTypeIncorrectData, 4001, "Negative amount", ex.F("batch", "b1"), ex.F("stage", "load"))
+		}
+		a.Release()
+	})
+}
+
+func BenchmarkFrozen(b *testing.B) {
+	exc := ex.New(ex.ExTypeRateLimited, 4290, "Quota exceeded").
+		WithFields(ex.F("plan", "free"), ex.F("limit", 100)).
+		WithInnerError(ex.New(ex.ExTypeRateLimited, 4291, "Daily limit reached"))
+
+	for _, bc := range []struct {
+		name string
+		exc  ex.Exception
+	}{
+		{"Plain", exc},
+		{"Frozen", exc.Freeze()},
+	} {
+		b.Run(bc.name+"/Fingerprint", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = bc.exc.Fingerprint()
+			}
+		})
+		b.Run(bc.name+"/MarshalJSON", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = bc.exc.MarshalJSON()
+			}
+		})
+	}
+}
+
+func BenchmarkErrorDeepChain(b *testing.B) {
+	for _, depth := range []int{10, 50} {
+		var exc ex.Exception
+		var inner error = errors.New("root cause") //nolint:staticcheck // ST1023: required for later interface reassignment
+		for range depth {
+			exc = ex.New(ex.ExTypeApplicationFailure, 500, "Service unavailable").WithInnerError(inner)
+			inner = exc
+		}
+		b.Run(fmt.Sprintf("Depth%d", depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = exc.Error()
+			}
+		})
+	}
+}
+
+// scenario is one point of the benchmark matrix: an exception chain of
+// the given depth whose outermost level carries the given number of
+// fields, optionally with a stack on every level.
+type scenario struct {
+	fields int
+	depth  int
+	stack  bool
+}
+
+func (s scenario) String() string {
+	name := fmt.Sprintf("Fields%d/Depth%d", s.fields, s.depth)
+	if s.stack {
+		name += "/Stack"
+	}
+	return name
+}
+
+// scenarios is the benchmark matrix: fields 0/5/20 × depth 1/5/20 ×
+// stacks off/on.
+func scenarios() []scenario {
+	var out []scenario
+	for _, stack := range []bool{false, true} {
+		for _, depth := range []int{1, 5, 20} {
+			for _, fields := range []int{0, 5, 20} {
+				out = append(out, scenario{fields: fields, depth: depth, stack: stack})
+			}
+		}
+	}
+	return out
+}
+
+// scenarioFields holds the fields attached by build, created once so that
+// building them is not measured.
+var scenarioFields = func() []ex.Field {
+	fields := make([]ex.Field, 20)
+	for i := range fields {
+		fields[i] = ex.F(fmt.Sprintf("key%02d", i), "value")
+	}
+	return fields
+}()
+
+// scenarioRoot is the plain error at the bottom of every chain.
+var scenarioRoot = errors.New("connection refused")
+
+// build creates the scenario's chain, as a service would on its error
+// path.
+func (s scenario) build() ex.Exception {
+	var err error = scenarioRoot //nolint:staticcheck // ST1023: required for later interface reassignment
+	var e ex.Exception
+	for i := range s.depth {
+		e = ex.New(ex.ExTypeApplicationFailure, 500+i, "Operation failed").WithInnerError(err)
+		if s.stack {
+			e = e.WithStack()
+		}
+		err = e
+	}
+	if s.fields > 0 {
+		e = e.WithFields(scenarioFields[:s.fields]...)
+	}
+	return e
+}
+
+func BenchmarkScenarios(b *testing.B) {
+	// Box both sides once so the Is benchmark measures matching, not the
+	// interface conversions at the call site.
+	var target error = ex.New(ex.ExTypeApplicationFailure, 500, "")
+	for _, s := range scenarios() {
+		b.Run(s.String()+"/Build", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = s.build()
+			}
+		})
+		e := s.build()
+		var err error = e
+		b.Run(s.String()+"/Error", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = e.Error()
+			}
+		})
+		b.Run(s.String()+"/AppendError", func(b *testing.B) {
+			buf := make([]byte, 0, 1024)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = e.AppendError(buf[:0])
+			}
+		})
+		b.Run(s.String()+"/Is", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = errors.Is(err, target)
+			}
+		})
+		b.Run(s.String()+"/FieldValue", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = e.FieldValue("key04")
+			}
+		})
+		b.Run(s.String()+"/MarshalJSON", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = e.MarshalJSON()
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllocationBudgets enforces the allocation counts of the benchmark
+// scenarios (see BenchmarkScenarios), so a new feature cannot silently
+// regress the hot paths. Raise a budget only together with the README's
+// performance notes.
+func TestAllocationBudgets(t *testing.T) {
+	if ex.Tracking() {
+		t.Skip("creation tracking allocates for every exception")
+	}
+	var target error = ex.New(ex.ExTypeApplicationFailure, 500, "")
+	buf := make([]byte, 0, 1024)
+
+	for _, s := range scenarios() {
+		t.Run(s.String(), func(t *testing.T) {
+			// Each level is boxed into an error once when it is wrapped,
+			// the fields are one slice, and each stack is one slice.
+			buildBudget := s.depth
+			if s.fields > 0 {
+				buildBudget++
+			}
+			if s.stack {
+				buildBudget += s.depth
+			}
+
+			e := s.build()
+			var err error = e
+			budgets := []struct {
+				op     string
+				budget int
+				fn     func()
+			}{
+				{"Build", buildBudget, func() { _ = s.build() }},
+				{"Error", 1, func() { _ = e.Error() }},
+				{"AppendError", 0, func() { buf = e.AppendError(buf[:0]) }},
+				{"Size", 0, func() { _ = e.Size() }},
+				{"Is", 0, func() { _ = errors.Is(err, target) }},
+				{"FieldValue", 0, func() { _, _ = e.FieldValue("key04") }},
+				{"Accessors", 0, func() { _, _, _, _ = e.Code(), e.ID(), e.Message(), e.InnerError() }},
+			}
+			for _, b := range budgets {
+				allocs := testing.AllocsPerRun(20, b.fn)
+				assert.LessOrEqual(t, allocs, float64(b.budget), "%s allocates more than its budget", b.op)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package ex
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// bundleVersion is the format version written by Bundle.
+const bundleVersion = 1
+
+// maxBundleSize bounds the decompressed size ReadBundle and
+// UnmarshalCompressed accept, so a hostile or corrupt archive cannot
+// exhaust memory. A variable so tests can lower it.
+var maxBundleSize int64 = 64 << 20
+
+// ErrTooLarge is returned by ReadBundle and UnmarshalCompressed for input
+// that decompresses to more than 64 MiB.
+var ErrTooLarge = errors.New("ex: decompressed input too large")
+
+// ErrBundleVersion is returned by ReadBundle for archives written by a
+// newer, incompatible version of this package.
+var ErrBundleVersion = errors.New("ex: unsupported bundle version")
+
+// Environment describes the process that produced a bundle.
+type Environment struct {
+	Hostname      string `json:"hostname,omitempty"`
+	PID           int    `json:"pid"`
+	Executable    string `json:"executable,omitempty"`
+	GOOS          string `json:"goos"`
+	GOARCH        string `json:"goarch"`
+	GoVersion     string `json:"go_version"`
+	ModulePath    string `json:"module_path,omitempty"`
+	ModuleVersion string `json:"module_version,omitempty"`
+	NumGoroutine  int    `json:"num_goroutine"`
+}
+
+// BundleContents is a decoded post-mortem bundle.
+type BundleContents struct {
+	Created     time.Time
+	Environment Environment
+	// Errors holds the bundled errors in their original order. Exceptions
+	// come back as Exception values; other errors as plain errors carrying
+	// the original message.
+	Errors []error
+}
+
+// wireBundle is the JSON document inside the archive.
+type wireBundle struct {
+	Version     int          `json:"version"`
+	Created     time.Time    `json:"created"`
+	Environment Environment  `json:"environment"`
+	Errors      []*wireError `json:"errors"`
+}
+
+// Bundle serializes errs, together with a description of the current
+// process, into a gzip-compressed archive suitable for attaching to a bug
+// report. Nil errors are skipped. Use ReadBundle to load it again.
+//
+// Sensitive fields are redacted throughout each error's chain before
+// encoding (see SetSensitiveKeys), since bundles are meant to leave the
+// process.
+func Bundle(errs ...error) ([]byte, error) {
+	return BundleCompressed(-1, errs...)
+}
+
+// BundleCompressed is Bundle leaving the archive uncompressed JSON unless
+// it is longer than threshold bytes, with the threshold read as by
+// Exception.MarshalCompressed, so small bundles stay readable as they are.
+// ReadBundle decodes both forms.
+func BundleCompressed(threshold int, errs ...error) ([]byte, error) {
+	doc := wireBundle{
+		Version:     bundleVersion,
+		Created:     time.Now().UTC(),
+		Environment: CurrentEnvironment(),
+		Errors:      make([]*wireError, 0, len(errs)),
+	}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		doc.Errors = append(doc.Errors, toWire(redactChain(err)))
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if threshold == 0 {
+		threshold = DefaultCompressThreshold
+	}
+	if threshold >= 0 && len(data) <= threshold {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadBundle decodes an archive produced by Bundle or BundleCompressed,
+// compressed or not. Archives decompressing to more than 64 MiB fail with
+// ErrTooLarge.
+func ReadBundle(data []byte) (BundleContents, error) {
+	var r io.Reader = bytes.NewReader(data)
+	if IsCompressed(data) {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return BundleContents{}, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	raw, err := readLimited(r)
+	if err != nil {
+		return BundleContents{}, err
+	}
+	var doc wireBundle
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return BundleContents{}, err
+	}
+	if doc.Version > bundleVersion {
+		return BundleContents{}, fmt.Errorf("%w %d", ErrBundleVersion, doc.Version)
+	}
+
+	out := BundleContents{
+		Created:     doc.Created,
+		Environment: doc.Environment,
+		Errors:      make([]error, 0, len(doc.Errors)),
+	}
+	for _, w := range doc.Errors {
+		if w != nil {
+			out.Errors = append(out.Errors, fromWire(w))
+		}
+	}
+	return out, nil
+}
+
+// CurrentEnvironment describes the running process, as recorded by Bundle.
+func CurrentEnvironment() Environment {
+	env := Environment{
+		PID:          os.Getpid(),
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+		GoVersion:    runtime.Version(),
+		NumGoroutine: runtime.NumGoroutine(),
+	}
+	env.Hostname, _ = os.Hostname()
+	env.Executable, _ = os.Executable()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		env.ModulePath = info.Main.Path
+		env.ModuleVersion = info.Main.Version
+	}
+	return env
+}
@@ -0,0 +1,104 @@
+package ex_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_RoundTrip(t *testing.T) {
+	first := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").
+		WithFields(ex.F("request_id", "r-1"), ex.F("password", "hunter2")).
+		WithInnerError(errors.New("connection reset")).
+		WithStack()
+	second := errors.New("plain error")
+
+	data, err := ex.Bundle(first, nil, second)
+	require.NoError(t, err)
+
+	contents, err := ex.ReadBundle(data)
+	require.NoError(t, err)
+
+	assert.False(t, contents.Created.IsZero())
+	assert.Equal(t, runtime.GOOS, contents.Environment.GOOS)
+	assert.Equal(t, runtime.Version(), contents.Environment.GoVersion)
+	assert.Positive(t, contents.Environment.PID)
+
+	require.Len(t, contents.Errors, 2)
+	decoded, ok := contents.Errors[0].(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, first.Error(), decoded.Error())
+	assert.True(t, decoded.HasStack())
+	v, _ := decoded.FieldValue("request_id")
+	assert.Equal(t, "r-1", v)
+	v, _ = decoded.FieldValue("password")
+	assert.Equal(t, ex.RedactedValue, v, "bundles leave the process, so secrets are redacted")
+
+	assert.Equal(t, "plain error", contents.Errors[1].Error())
+}
+
+func TestBundle_RedactsChain(t *testing.T) {
+	inner := ex.New(ex.ExTypeApplicationFailure, 500, "Login failed").
+		WithField("password", "hunter2")
+	annotated := ex.AnnotateOp(inner, "login", ex.F("token", "abc"))
+
+	data, err := ex.BundleCompressed(-1, annotated)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+	assert.NotContains(t, string(data), `"abc"`)
+
+	contents, err := ex.ReadBundle(data)
+	require.NoError(t, err)
+	require.Len(t, contents.Errors, 1)
+	var decoded ex.Exception
+	require.True(t, errors.As(contents.Errors[0], &decoded))
+	v, _ := decoded.FieldValue("password")
+	assert.Equal(t, ex.RedactedValue, v)
+}
+
+func TestBundleCompressed(t *testing.T) {
+	small := ex.New(ex.ExTypeNotFound, 4041, "Missing")
+	data, err := ex.BundleCompressed(0, small)
+	require.NoError(t, err)
+	assert.False(t, ex.IsCompressed(data), "below the threshold")
+	contents, err := ex.ReadBundle(data)
+	require.NoError(t, err)
+	require.Len(t, contents.Errors, 1)
+	assert.Equal(t, "Missing", contents.Errors[0].Error())
+
+	large := small.WithField("rows", strings.Repeat("r", 8000))
+	data, err = ex.BundleCompressed(0, large)
+	require.NoError(t, err)
+	assert.True(t, ex.IsCompressed(data))
+	assert.Less(t, len(data), 2000)
+	contents, err = ex.ReadBundle(data)
+	require.NoError(t, err)
+	require.Len(t, contents.Errors, 1)
+	decoded, ok := contents.Errors[0].(ex.Exception)
+	require.True(t, ok)
+	v, _ := decoded.FieldValue("rows")
+	assert.Equal(t, strings.Repeat("r", 8000), v)
+
+	data, err = ex.Bundle(small)
+	require.NoError(t, err)
+	assert.True(t, ex.IsCompressed(data), "Bundle always compresses")
+}
+
+func TestReadBundle_Invalid(t *testing.T) {
+	_, err := ex.ReadBundle([]byte("not gzip"))
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(`{"version":99,"errors":[]}`))
+	require.NoError(t, zw.Close())
+	_, err = ex.ReadBundle(buf.Bytes())
+	assert.ErrorIs(t, err, ex.ErrBundleVersion)
+}
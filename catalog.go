@@ -0,0 +1,248 @@
+package ex
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateEntry is returned when a catalog already holds an entry
+// with the same (Code, ID).
+var ErrDuplicateEntry = errors.New("ex: duplicate catalog entry")
+
+// Entry documents one error a service can return. Entries are identified
+// by (Code, ID), the same identity Exception.Is uses.
+type Entry struct {
+	Code    ExType `json:"code"`
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+
+	// CodeString is the symbolic code given to exceptions built from the
+	// entry; see Exception.WithCodeString.
+	CodeString string `json:"code_string,omitempty"`
+
+	// HTTPStatus overrides the HTTP status registered for Code; zero
+	// means use the type default.
+	HTTPStatus int `json:"http_status,omitempty"`
+
+	// Remediation tells the reader what to do about the error. It
+	// becomes the hint of exceptions built from the entry.
+	Remediation string `json:"remediation,omitempty"`
+
+	// DocsURL links to documentation about this error. It overrides the
+	// DocsURL registered for Code.
+	DocsURL string `json:"docs_url,omitempty"`
+
+	// Fields lists the fields exceptions built from the entry carry; see
+	// FieldSpec.
+	Fields []FieldSpec `json:"fields,omitempty"`
+}
+
+// New creates an Exception from the entry's code, ID, message, and
+// symbolic code, with its remediation as the hint, its DocsURL as the
+// docs link, and fields attached as by WithFields. In strict mode it
+// panics if the fields do not match the entry's Fields.
+//
+// Like New, it follows the stack policy and runs the hooks, once the
+// exception is complete, so hooks see its fields, hint, and symbolic
+// code. Entries are defined in one place, so exstrict builds do not check
+// their call sites for duplicate IDs.
+func (e Entry) New(fields ...Field) Exception {
+	checkKnown(e.Code)
+	if buildStrict {
+		checkMisuse(e.Code, e.ID, e.Message, 1)
+	}
+	exc := Exception{
+		code:       e.Code,
+		id:         e.ID,
+		message:    e.Message,
+		codeString: e.CodeString,
+		hint:       e.Remediation,
+		docsURL:    e.DocsURL,
+		stack:      autoStack(e.Code, e.ID, 1),
+		sizeHint:   len(e.Message) + 1,
+	}
+	exc = exc.WithFields(fields...)
+	checkFields(strict.Load(), e, exc.fields)
+	runHooks(exc)
+	exc.creation = trackCreation(exc.code, exc.id, exc.message)
+	return exc
+}
+
+// Is reports whether err's chain contains an Exception with the entry's
+// (Code, ID).
+func (e Entry) Is(err error) bool {
+	return errors.Is(err, Exception{code: e.Code, id: e.ID})
+}
+
+// entryKey is the identity of a catalog entry.
+type entryKey struct {
+	code ExType
+	id   int
+}
+
+// Catalog is a registry of documented errors. It is safe for concurrent
+// use. Most programs use the package-level DefaultCatalog through Define,
+// Register, and Lookup.
+type Catalog struct {
+	mu      sync.RWMutex
+	entries map[entryKey]Entry
+}
+
+// NewCatalog returns an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{entries: make(map[entryKey]Entry)}
+}
+
+// DefaultCatalog is the catalog used by the package-level functions.
+var DefaultCatalog = NewCatalog()
+
+// Register adds e to the catalog. It returns ErrDuplicateEntry if an entry
+// with the same (Code, ID) is already registered, and ErrInvalidType for
+// the reserved ExType(0).
+func (c *Catalog) Register(e Entry) error {
+	if e.Code == 0 {
+		return ErrInvalidType
+	}
+	key := entryKey{e.Code, e.ID}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; exists {
+		return fmt.Errorf("%w %s/%d", ErrDuplicateEntry, e.Code, e.ID)
+	}
+	c.entries[key] = e
+	return nil
+}
+
+// Lookup returns the entry registered for (code, id).
+func (c *Catalog) Lookup(code ExType, id int) (Entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[entryKey{code, id}]
+	return e, ok
+}
+
+// Entries returns every entry, ordered by code and then ID.
+func (c *Catalog) Entries() []Entry {
+	c.mu.RLock()
+	out := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		out = append(out, e)
+	}
+	c.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Code != out[j].Code {
+			return out[i].Code < out[j].Code
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Define registers e in DefaultCatalog and returns it, panicking if the
+// entry is invalid or already registered. It is meant for package-level
+// declarations, where a duplicate is a programming error:
+//
+//	var ErrCardDeclined = ex.Define(ex.Entry{
+//	    Code: ex.ExTypeIncorrectData, ID: 4021,
+//	    Message:     "The card was declined",
+//	    Remediation: "Use a different payment method.",
+//	})
+//
+//	return ErrCardDeclined.New()
+func Define(e Entry) Entry {
+	if err := DefaultCatalog.Register(e); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Register adds e to DefaultCatalog.
+func Register(e Entry) error {
+	return DefaultCatalog.Register(e)
+}
+
+// Lookup returns the entry registered in DefaultCatalog for (code, id).
+func Lookup(code ExType, id int) (Entry, bool) {
+	return DefaultCatalog.Lookup(code, id)
+}
+
+// Snapshot is the serializable view of a catalog consumed by tooling such
+// as cmd/exdoc. Type metadata is resolved into each entry so the snapshot
+// is self-contained.
+//
+// Taxonomy is the taxonomy version the entries belong to (see
+// SetTaxonomyVersion), or zero when the scheme is unversioned.
+type Snapshot struct {
+	Taxonomy int             `json:"taxonomy,omitempty"`
+	Entries  []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotEntry is an Entry with its type metadata resolved.
+type SnapshotEntry struct {
+	Code        ExType `json:"code"`
+	Type        string `json:"type"`
+	ID          int    `json:"id"`
+	CodeString  string `json:"code_string,omitempty"`
+	Message     string `json:"message"`
+	HTTPStatus  int    `json:"http_status,omitempty"`
+	GRPCCode    uint32 `json:"grpc_code,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	DocsURL     string `json:"docs_url,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+
+	Fields []SnapshotField `json:"fields,omitempty"`
+}
+
+// SnapshotField is a FieldSpec with its type named. Type is "" for fields
+// that accept any value.
+type SnapshotField struct {
+	Key      string `json:"key"`
+	Type     string `json:"type,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Snapshot returns the catalog's entries with their type metadata
+// resolved, ordered by code and then ID. Encode it with encoding/json to
+// feed cmd/exdoc.
+func (c *Catalog) Snapshot() Snapshot {
+	entries := c.Entries()
+	snap := Snapshot{
+		Taxonomy: TaxonomyVersion(),
+		Entries:  make([]SnapshotEntry, len(entries)),
+	}
+	for i, e := range entries {
+		info, _ := LookupType(e.Code)
+		se := SnapshotEntry{
+			Code:        e.Code,
+			Type:        e.Code.String(),
+			ID:          e.ID,
+			CodeString:  e.CodeString,
+			Message:     e.Message,
+			HTTPStatus:  info.HTTPStatus,
+			GRPCCode:    info.GRPCCode,
+			DocsURL:     info.DocsURL,
+			Remediation: e.Remediation,
+		}
+		if e.HTTPStatus != 0 {
+			se.HTTPStatus = e.HTTPStatus
+		}
+		if e.DocsURL != "" {
+			se.DocsURL = e.DocsURL
+		}
+		if info.Severity != 0 {
+			se.Severity = info.Severity.String()
+		}
+		for _, f := range e.Fields {
+			se.Fields = append(se.Fields, SnapshotField{Key: f.Key, Type: f.typeName(), Optional: f.Optional})
+		}
+		snap.Entries[i] = se
+	}
+	return snap
+}
+
+// CatalogSnapshot returns the Snapshot of DefaultCatalog.
+func CatalogSnapshot() Snapshot {
+	return DefaultCatalog.Snapshot()
+}
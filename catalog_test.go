@@ -0,0 +1,79 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_RegisterLookup(t *testing.T) {
+	c := ex.NewCatalog()
+	entry := ex.Entry{Code: ex.ExTypeIncorrectData, ID: 4001, Message: "Invalid email", Remediation: "Check the address."}
+	require.NoError(t, c.Register(entry))
+
+	got, ok := c.Lookup(ex.ExTypeIncorrectData, 4001)
+	require.True(t, ok)
+	assert.Equal(t, entry, got)
+
+	_, ok = c.Lookup(ex.ExTypePermissionDenied, 4001)
+	assert.False(t, ok, "entries are keyed by (Code, ID)")
+
+	err := c.Register(ex.Entry{Code: ex.ExTypeIncorrectData, ID: 4001, Message: "again"})
+	assert.ErrorIs(t, err, ex.ErrDuplicateEntry)
+	assert.ErrorContains(t, err, "IncorrectData/4001")
+
+	assert.ErrorIs(t, c.Register(ex.Entry{ID: 1}), ex.ErrInvalidType)
+}
+
+func TestCatalog_EntriesOrdered(t *testing.T) {
+	c := ex.NewCatalog()
+	require.NoError(t, c.Register(ex.Entry{Code: ex.ExTypeApplicationFailure, ID: 1}))
+	require.NoError(t, c.Register(ex.Entry{Code: ex.ExTypeIncorrectData, ID: 9}))
+	require.NoError(t, c.Register(ex.Entry{Code: ex.ExTypeIncorrectData, ID: 2}))
+
+	entries := c.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, 2, entries[0].ID)
+	assert.Equal(t, 9, entries[1].ID)
+	assert.Equal(t, ex.ExTypeApplicationFailure, entries[2].Code)
+}
+
+var errCardDeclined = ex.Define(ex.Entry{
+	Code:        ex.ExTypeIncorrectData,
+	ID:          1133,
+	Message:     "The card was declined",
+	Remediation: "Use a different payment method.",
+})
+
+func TestDefine(t *testing.T) {
+	got, ok := ex.Lookup(ex.ExTypeIncorrectData, 1133)
+	require.True(t, ok)
+	assert.Equal(t, errCardDeclined, got)
+
+	exc := errCardDeclined.New()
+	assert.Equal(t, "The card was declined", exc.Error())
+	assert.True(t, errCardDeclined.Is(fmt.Errorf("charge: %w", exc)))
+	assert.False(t, errCardDeclined.Is(ex.New(ex.ExTypeIncorrectData, 1, "other")))
+
+	assert.Panics(t, func() { ex.Define(errCardDeclined) })
+	assert.ErrorIs(t, ex.Register(errCardDeclined), ex.ErrDuplicateEntry)
+}
+
+func TestCatalog_Snapshot(t *testing.T) {
+	c := ex.NewCatalog()
+	require.NoError(t, c.Register(ex.Entry{Code: ex.ExTypePermissionDenied, ID: 7, Message: "No access"}))
+	require.NoError(t, c.Register(ex.Entry{Code: ex.ExTypeIncorrectData, ID: 8, Message: "Gone", HTTPStatus: 410}))
+
+	data, err := json.Marshal(c.Snapshot())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"entries":[
+		{"code":1,"type":"IncorrectData","id":8,"message":"Gone","http_status":410,"grpc_code":3,"severity":"Warning"},
+		{"code":3,"type":"PermissionDenied","id":7,"message":"No access","http_status":403,"grpc_code":7,"severity":"Warning"}
+	]}`, string(data))
+
+	assert.NotEmpty(t, ex.CatalogSnapshot().Entries)
+}
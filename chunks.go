@@ -0,0 +1,105 @@
+package ex
+
+import (
+	"runtime"
+	"sync"
+)
+
+// chunkStore is the storage behind Pool and Arena: field slices and
+// captured stacks carved out of fixed-size chunks, all of which reset
+// makes available again at once. A chunkStore with a zero size allocates
+// every slice on its own.
+type chunkStore struct {
+	// size is the number of elements in each chunk.
+	size   int
+	mu     sync.Mutex
+	fields chunks[Field]
+	pcs    chunks[uintptr]
+}
+
+// chunks is a list of fixed-size slabs handed out front to back.
+type chunks[T any] struct {
+	list [][]T
+	// cur is the index in list of the chunk being carved.
+	cur int
+}
+
+// withFields is e.WithFields with the merged field slice taken from s.
+func (s *chunkStore) withFields(e Exception, fields []Field) Exception {
+	if len(fields) == 0 {
+		return e
+	}
+	s.mu.Lock()
+	merged := s.fields.alloc(len(e.fields)+len(fields), s.size)
+	s.mu.Unlock()
+	copy(merged, e.fields)
+	merged = merged[:len(e.fields)]
+	for _, f := range fields {
+		if i := indexField(merged, f.Key); i >= 0 {
+			merged[i] = f
+			continue
+		}
+		merged = append(merged, f)
+	}
+	e.fields = merged
+	e.frozen = nil
+	return e
+}
+
+// withStack is e.WithStack with the program counters stored in s. It must
+// be called directly by an exported WithStack method, whose caller is the
+// first frame recorded.
+func (s *chunkStore) withStack(e Exception) Exception {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	if n == 0 {
+		return e
+	}
+	s.mu.Lock()
+	out := s.pcs.alloc(n, s.size)
+	s.mu.Unlock()
+	copy(out, pcs[:n])
+	e.stack = out
+	e.frames = nil
+	e.frozen = nil
+	return e
+}
+
+// reset invalidates every slice handed out by s and keeps the chunks for
+// reuse.
+func (s *chunkStore) reset() {
+	s.mu.Lock()
+	s.fields.reset()
+	s.pcs.reset()
+	s.mu.Unlock()
+}
+
+// alloc returns a slice of length and capacity n, from the current chunk
+// if it has room, otherwise from the next one, which is allocated the
+// first time round. Requests larger than a chunk of size elements get a
+// slice of their own.
+func (c *chunks[T]) alloc(n, size int) []T {
+	if n > size {
+		return make([]T, n)
+	}
+	for {
+		if c.cur == len(c.list) {
+			c.list = append(c.list, make([]T, 0, size))
+		}
+		chunk := c.list[c.cur]
+		if start := len(chunk); cap(chunk)-start >= n {
+			c.list[c.cur] = chunk[:start+n]
+			return chunk[start : start+n : start+n]
+		}
+		c.cur++
+	}
+}
+
+// reset clears the used chunks and rewinds to the first.
+func (c *chunks[T]) reset() {
+	for i := range c.list[:min(c.cur+1, len(c.list))] {
+		clear(c.list[i])
+		c.list[i] = c.list[i][:0]
+	}
+	c.cur = 0
+}
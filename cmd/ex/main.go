@@ -0,0 +1,210 @@
+// Command ex inspects serialized exceptions: the JSON encoding produced by
+// ex.Exception.MarshalJSON, alone or inside the NDJSON records written by
+// package logfile.
+//
+//	ex pretty < err.json                   # every level with its fields and stack
+//	ex tree errors.ndjson                  # the shape of each chain, one line per level
+//	ex grep --code=PermissionDenied app.log
+//
+// pretty and tree read a stream of JSON values, pretty-printed or one per
+// line, from the named files or standard input. grep reads NDJSON line by
+// line and prints the lines holding an exception with a matching level,
+// exiting with status 1 when there are none, as grep does. Lines that do
+// not hold an exception are skipped, so grep works on mixed logs.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bold-minds/ex"
+)
+
+// errNoMatch is returned by run when grep matched nothing.
+var errNoMatch = errors.New("no matching exceptions")
+
+// maxLine is the longest NDJSON line grep accepts.
+const maxLine = 1 << 20
+
+const usage = "usage: ex pretty|tree|grep [flags] [file ...]"
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case errors.Is(err, errNoMatch):
+		os.Exit(1)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "ex:", err)
+		os.Exit(2)
+	}
+}
+
+// run implements the command; it is separate from main for testing.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "pretty":
+		return runEach("pretty", args[1:], stdin, stdout, writePretty)
+	case "tree":
+		return runEach("tree", args[1:], stdin, stdout, writeTree)
+	case "grep":
+		return runGrep(args[1:], stdin, stdout)
+	default:
+		return fmt.Errorf("unknown command %q; %s", args[0], usage)
+	}
+}
+
+// runEach decodes every exception in the input and renders it with
+// render, separating consecutive exceptions with a blank line.
+func runEach(name string, args []string, stdin io.Reader, stdout io.Writer, render func(io.Writer, ex.Exception)) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	first := true
+	return eachInput(flags.Args(), stdin, func(path string, r io.Reader) error {
+		dec := json.NewDecoder(r)
+		for {
+			var raw json.RawMessage
+			err := dec.Decode(&raw)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			e, ok := decode(raw)
+			if !ok {
+				continue
+			}
+			if !first {
+				fmt.Fprintln(stdout)
+			}
+			first = false
+			render(stdout, e)
+		}
+	})
+}
+
+// runGrep implements the grep command.
+func runGrep(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("grep", flag.ContinueOnError)
+	code := flags.String("code", "", "required code, by name or number")
+	ids := flags.String("id", "", "accepted IDs, comma-separated")
+	message := flags.String("message", "", "text the error text must contain")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	spec, err := grepSpec(*code, *ids, *message)
+	if err != nil {
+		return err
+	}
+
+	matched := false
+	err = eachInput(flags.Args(), stdin, func(path string, r io.Reader) error {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(nil, maxLine)
+		for sc.Scan() {
+			line := sc.Bytes()
+			if e, ok := decode(bytes.TrimSpace(line)); ok && matchAnyLevel(e, spec) {
+				matched = true
+				if _, writeErr := fmt.Fprintf(stdout, "%s\n", line); writeErr != nil {
+					return writeErr
+				}
+			}
+		}
+		if scanErr := sc.Err(); scanErr != nil {
+			return fmt.Errorf("%s: %w", path, scanErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return errNoMatch
+	}
+	return nil
+}
+
+// grepSpec builds the spec a level must match from grep's flags, reusing
+// the parsing of ex.ParseMatchSpec for codes.
+func grepSpec(code, ids, message string) (ex.MatchSpec, error) {
+	var spec ex.MatchSpec
+	if code != "" {
+		if err := spec.UnmarshalText([]byte("code=" + code)); err != nil {
+			return ex.MatchSpec{}, err
+		}
+	}
+	if ids != "" {
+		for _, s := range strings.Split(ids, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return ex.MatchSpec{}, fmt.Errorf("invalid -id %q", ids)
+			}
+			spec.IDIn = append(spec.IDIn, id)
+		}
+	}
+	spec.MessageContains = message
+	return spec, nil
+}
+
+// matchAnyLevel reports whether some level of e's chain matches spec on
+// its own, so a code buried under a generic wrapper is still found.
+func matchAnyLevel(e ex.Exception, spec ex.MatchSpec) bool {
+	for _, l := range levels(e) {
+		if l.exc != nil && ex.Match(*l.exc, spec) {
+			return true
+		}
+	}
+	return false
+}
+
+// decode extracts the exception from a JSON value: either an encoded
+// exception itself or a logfile record holding one under "exception".
+func decode(raw []byte) (ex.Exception, bool) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil {
+		return ex.Exception{}, false
+	}
+	if inner, ok := obj["exception"]; ok {
+		raw = inner
+	} else if _, hasCode := obj["code"]; !hasCode {
+		return ex.Exception{}, false
+	}
+	var e ex.Exception
+	if json.Unmarshal(raw, &e) != nil {
+		return ex.Exception{}, false
+	}
+	return e, true
+}
+
+// eachInput calls fn for every named file in turn, or for stdin when no
+// files are named.
+func eachInput(paths []string, stdin io.Reader, fn func(path string, r io.Reader) error) error {
+	if len(paths) == 0 {
+		return fn("stdin", stdin)
+	}
+	for _, path := range paths {
+		f, err := os.Open(path) // #nosec G304 -- path is a command-line argument
+		if err != nil {
+			return err
+		}
+		err = fn(path, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Pretty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"pretty", "testdata/chain.json"}, nil, &out))
+
+	want := `ApplicationFailure/500 [SAVE_FAILED]: Save failed
+    origin: billing
+    hint:   Retry in a minute.
+    ops:    repo.Save
+    fields: attempt=2
+            user="u-1"
+    stack:  billing.(*Repo).Save
+                billing/repo.go:42
+caused by: Unavailable/503: Query failed
+caused by: *pq.Error: deadlock detected
+`
+	assert.Equal(t, want, out.String())
+}
+
+func TestRun_Tree(t *testing.T) {
+	in := strings.NewReader(`{"time":"2026-10-17T09:00:01Z","exception":{"code":3,"id":403,"message":"Access denied"}}
+{"level":"INFO","msg":"not an exception"}
+`)
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"tree", "testdata/chain.json"}, nil, &out))
+	require.NoError(t, run([]string{"tree"}, in, &out))
+
+	want := `ApplicationFailure/500 [SAVE_FAILED]: Save failed
+└─ Unavailable/503: Query failed
+   └─ *pq.Error: deadlock detected
+PermissionDenied/403: Access denied
+`
+	assert.Equal(t, want, out.String())
+}
+
+func TestRun_Grep(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"grep", "--code=PermissionDenied", "testdata/app.ndjson"}, nil, &out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2, "matches at any level, in logfile records too")
+	assert.Contains(t, lines[0], `"Access denied"`)
+	assert.Contains(t, lines[1], `"Export failed"`)
+
+	out.Reset()
+	require.NoError(t, run([]string{"grep", "-code=3", "-id=4031,9", "testdata/app.ndjson"}, nil, &out))
+	assert.Contains(t, out.String(), "Bucket is read-only")
+	assert.NotContains(t, out.String(), "Access denied")
+
+	out.Reset()
+	require.NoError(t, run([]string{"grep", "-message=such user", "testdata/app.ndjson"}, nil, &out))
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+
+	out.Reset()
+	assert.ErrorIs(t, run([]string{"grep", "-code=Conflict", "testdata/app.ndjson"}, nil, &out), errNoMatch)
+	assert.Empty(t, out.String())
+}
+
+func TestRun_Errors(t *testing.T) {
+	var out bytes.Buffer
+	assert.Error(t, run(nil, nil, &out))
+	assert.Error(t, run([]string{"frobnicate"}, nil, &out))
+	assert.Error(t, run([]string{"grep", "-code=Bogus"}, nil, &out))
+	assert.Error(t, run([]string{"grep", "-id=x"}, nil, &out))
+	assert.Error(t, run([]string{"pretty", "testdata/missing.json"}, nil, &out))
+
+	err := run([]string{"pretty"}, strings.NewReader("{not json"), &out)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errNoMatch)
+}
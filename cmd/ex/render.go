@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bold-minds/ex"
+)
+
+// level is one level of a decoded chain: an exception, or the plain error
+// that ends the chain.
+type level struct {
+	exc *ex.Exception
+	err error
+}
+
+// levels flattens e's chain, outermost first.
+func levels(e ex.Exception) []level {
+	var out []level
+	var err error = e
+	for err != nil {
+		x, ok := err.(ex.Exception)
+		if !ok {
+			return append(out, level{err: err})
+		}
+		out = append(out, level{exc: &x})
+		err = x.InnerError()
+	}
+	return out
+}
+
+// title renders the headline of a level, e.g.
+// "PermissionDenied/403 [ACCOUNT_LOCKED]: Account is locked" or
+// "*pq.Error: deadlock detected".
+func (l level) title() string {
+	if l.exc == nil {
+		if r, ok := l.err.(*ex.RemoteError); ok {
+			return r.TypeName() + ": " + r.Message()
+		}
+		return l.err.Error()
+	}
+	e := *l.exc
+	s := e.Code().String() + "/" + strconv.Itoa(e.ID())
+	if cs := e.CodeString(); cs != "" {
+		s += " [" + cs + "]"
+	}
+	if msg := e.Message(); msg != "" {
+		s += ": " + msg
+	}
+	return s
+}
+
+// writeTree renders e's chain as a tree, one line per level.
+func writeTree(w io.Writer, e ex.Exception) {
+	for i, l := range levels(e) {
+		if i == 0 {
+			fmt.Fprintln(w, l.title())
+			continue
+		}
+		fmt.Fprintf(w, "%s└─ %s\n", strings.Repeat("   ", i-1), l.title())
+	}
+}
+
+// writePretty renders every level of e's chain with what it carries.
+func writePretty(w io.Writer, e ex.Exception) {
+	for i, l := range levels(e) {
+		if i > 0 {
+			fmt.Fprint(w, "caused by: ")
+		}
+		fmt.Fprintln(w, l.title())
+		if l.exc == nil {
+			continue
+		}
+		x := *l.exc
+		p := prettyWriter{w: w}
+		p.line("origin", x.Origin())
+		p.line("hint", x.Hint())
+		p.line("docs", x.DocsURL())
+		if tc, ok := x.TraceContext(); ok {
+			p.line("trace", tc.TraceParent)
+		}
+		p.lines("ops", ownOps(x))
+		var fields []string
+		for _, f := range x.Fields() {
+			fields = append(fields, f.Key+"="+formatValue(f.Value))
+		}
+		p.lines("fields", fields)
+		var stack []string
+		for _, f := range x.StackTrace() {
+			stack = append(stack, f.Function, "    "+f.File+":"+strconv.Itoa(f.Line))
+		}
+		p.lines("stack", stack)
+	}
+}
+
+// ownOps returns the operations recorded on e's own level; ex.Ops lists
+// those of the whole chain.
+func ownOps(e ex.Exception) []string {
+	ops := ex.Ops(e)
+	return ops[:len(ops)-len(ex.Ops(e.InnerError()))]
+}
+
+// formatValue renders a decoded field value as JSON, so strings stand out
+// from numbers.
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// prettyWriter writes the labelled, indented detail lines of a level.
+type prettyWriter struct {
+	w io.Writer
+}
+
+// line writes a single-valued detail, if it is set.
+func (p prettyWriter) line(label, value string) {
+	if value != "" {
+		p.lines(label, []string{value})
+	}
+}
+
+// lines writes a detail with one value per line, aligned under the first.
+func (p prettyWriter) lines(label string, values []string) {
+	for i, v := range values {
+		if i == 0 {
+			fmt.Fprintf(p.w, "    %-8s%s\n", label+":", v)
+			continue
+		}
+		fmt.Fprintf(p.w, "    %-8s%s\n", "", v)
+	}
+}
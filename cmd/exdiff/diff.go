@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/bold-minds/ex"
+)
+
+// Kind classifies a Change.
+type Kind string
+
+// Change kinds.
+const (
+	KindRemoved        Kind = "removed"
+	KindRenumbered     Kind = "renumbered"
+	KindHTTPChanged    Kind = "http_status_changed"
+	KindGRPCChanged    Kind = "grpc_code_changed"
+	KindCodeChanged    Kind = "code_string_changed"
+	KindMessageChanged Kind = "message_changed"
+	KindAdded          Kind = "added"
+)
+
+// Change is one difference between two snapshots.
+type Change struct {
+	Kind     Kind   `json:"kind"`
+	Breaking bool   `json:"breaking"`
+	Entry    string `json:"entry"`
+	Detail   string `json:"detail"`
+}
+
+// key identifies an entry across snapshots, as errors.Is does.
+type key struct {
+	code ex.ExType
+	id   int
+}
+
+func keyOf(e ex.SnapshotEntry) key { return key{e.Code, e.ID} }
+
+// label renders an entry for reports, e.g. "IncorrectData/1001".
+func label(e ex.SnapshotEntry) string {
+	name := e.Type
+	if name == "" {
+		name = e.Code.String()
+	}
+	return name + "/" + strconv.Itoa(e.ID)
+}
+
+// diff compares two snapshots. Changes are ordered breaking first, then by
+// entry.
+//
+// An entry that disappeared while a new entry with the same code and
+// message appeared is reported as renumbered rather than as a removal and
+// an addition.
+func diff(oldSnap, newSnap ex.Snapshot) []Change {
+	oldByKey := index(oldSnap)
+	newByKey := index(newSnap)
+
+	var removed, added []ex.SnapshotEntry
+	var changes []Change
+	for k, o := range oldByKey {
+		n, ok := newByKey[k]
+		if !ok {
+			removed = append(removed, o)
+			continue
+		}
+		changes = append(changes, compare(o, n)...)
+	}
+	for k, n := range newByKey {
+		if _, ok := oldByKey[k]; !ok {
+			added = append(added, n)
+		}
+	}
+
+	// Pair removals with additions of the same code and message.
+	sortEntries(removed)
+	sortEntries(added)
+	claimed := make([]bool, len(added))
+	for _, o := range removed {
+		match := -1
+		for i, n := range added {
+			if !claimed[i] && n.Code == o.Code && n.Message == o.Message {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			changes = append(changes, Change{Kind: KindRemoved, Breaking: true, Entry: label(o),
+				Detail: fmt.Sprintf("%q is no longer defined", o.Message)})
+			continue
+		}
+		claimed[match] = true
+		changes = append(changes, Change{Kind: KindRenumbered, Breaking: true, Entry: label(o),
+			Detail: "now " + label(added[match])})
+	}
+	for i, n := range added {
+		if !claimed[i] {
+			changes = append(changes, Change{Kind: KindAdded, Entry: label(n), Detail: strconv.Quote(n.Message)})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Breaking != changes[j].Breaking {
+			return changes[i].Breaking
+		}
+		if changes[i].Entry != changes[j].Entry {
+			return changes[i].Entry < changes[j].Entry
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// compare reports the differences between two versions of one entry.
+func compare(o, n ex.SnapshotEntry) []Change {
+	var out []Change
+	if o.HTTPStatus != n.HTTPStatus {
+		out = append(out, Change{Kind: KindHTTPChanged, Breaking: true, Entry: label(o),
+			Detail: fmt.Sprintf("HTTP status %d → %d", o.HTTPStatus, n.HTTPStatus)})
+	}
+	if o.GRPCCode != n.GRPCCode {
+		out = append(out, Change{Kind: KindGRPCChanged, Breaking: true, Entry: label(o),
+			Detail: fmt.Sprintf("gRPC code %d → %d", o.GRPCCode, n.GRPCCode)})
+	}
+	if o.CodeString != n.CodeString {
+		out = append(out, Change{Kind: KindCodeChanged, Breaking: true, Entry: label(o),
+			Detail: fmt.Sprintf("code string %q → %q", o.CodeString, n.CodeString)})
+	}
+	if o.Message != n.Message {
+		out = append(out, Change{Kind: KindMessageChanged, Entry: label(o),
+			Detail: fmt.Sprintf("%q → %q", o.Message, n.Message)})
+	}
+	return out
+}
+
+func index(snap ex.Snapshot) map[key]ex.SnapshotEntry {
+	m := make(map[key]ex.SnapshotEntry, len(snap.Entries))
+	for _, e := range snap.Entries {
+		m[keyOf(e)] = e
+	}
+	return m
+}
+
+func sortEntries(entries []ex.SnapshotEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Code != entries[j].Code {
+			return entries[i].Code < entries[j].Code
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+// writeText prints changes one per line, breaking ones marked with "!".
+func writeText(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+	for _, c := range changes {
+		mark := " "
+		if c.Breaking {
+			mark = "!"
+		}
+		if _, err := fmt.Fprintf(w, "%s %-20s %-24s %s\n", mark, c.Kind, c.Entry, c.Detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
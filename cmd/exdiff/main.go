@@ -0,0 +1,89 @@
+// Command exdiff compares two ex catalog snapshots and reports changes
+// that break clients: removed or renumbered error IDs, changed HTTP or
+// gRPC mappings, and changed code strings. It exits with status 1 when it
+// finds any, so it can gate a release pipeline:
+//
+//	exdiff previous-release/catalog.json catalog.json
+//
+// Snapshots are written by the program itself with
+// json.Marshal(ex.CatalogSnapshot()), or recovered from source by exdoc.
+// Added entries and reworded messages are reported but are not breaking.
+// With -json the report is printed as JSON instead of text.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bold-minds/ex"
+)
+
+// errBreaking is returned by run when breaking changes were found.
+var errBreaking = errors.New("breaking changes found")
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	switch {
+	case errors.Is(err, errBreaking):
+		os.Exit(1)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "exdiff:", err)
+		os.Exit(2)
+	}
+}
+
+// run implements the command; it is separate from main for testing.
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("exdiff", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("usage: exdiff [-json] old.json new.json")
+	}
+
+	oldSnap, err := readSnapshot(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	newSnap, err := readSnapshot(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := diff(oldSnap, newSnap)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(changes); err != nil {
+			return err
+		}
+	} else if err := writeText(stdout, changes); err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if c.Breaking {
+			return errBreaking
+		}
+	}
+	return nil
+}
+
+// readSnapshot loads a JSON-encoded ex.Snapshot.
+func readSnapshot(path string) (ex.Snapshot, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a command-line argument
+	if err != nil {
+		return ex.Snapshot{}, err
+	}
+	var snap ex.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return ex.Snapshot{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return snap, nil
+}
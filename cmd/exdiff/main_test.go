@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Text(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{"testdata/old.json", "testdata/new.json"}, &out)
+	assert.ErrorIs(t, err, errBreaking)
+
+	want := `! grpc_code_changed    ApplicationFailure/5001  gRPC code 13 → 14
+! http_status_changed  ApplicationFailure/5001  HTTP status 500 → 503
+! renumbered           IncorrectData/1002       now IncorrectData/1102
+! removed              PermissionDenied/3001    "Account is locked" is no longer defined
+  added                ApplicationFailure/5003  "Fraud check timed out"
+  message_changed      IncorrectData/1001       "The email address is invalid" → "Enter a valid email address"
+`
+	assert.Equal(t, want, out.String())
+}
+
+func TestRun_JSON(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{"-json", "testdata/old.json", "testdata/new.json"}, &out)
+	assert.ErrorIs(t, err, errBreaking)
+
+	var changes []Change
+	require.NoError(t, json.Unmarshal(out.Bytes(), &changes))
+	require.Len(t, changes, 6)
+	assert.Equal(t, Change{Kind: KindRenumbered, Breaking: true, Entry: "IncorrectData/1002", Detail: "now IncorrectData/1102"}, changes[2])
+}
+
+func TestDiff_CodeString(t *testing.T) {
+	entry := ex.SnapshotEntry{Code: ex.ExTypeIncorrectData, Type: "IncorrectData", ID: 1001, CodeString: "EMAIL_INVALID", Message: "Bad email"}
+	renamed := entry
+	renamed.CodeString = "INVALID_EMAIL"
+
+	changes := diff(ex.Snapshot{Entries: []ex.SnapshotEntry{entry}}, ex.Snapshot{Entries: []ex.SnapshotEntry{renamed}})
+	assert.Equal(t, []Change{{Kind: KindCodeChanged, Breaking: true, Entry: "IncorrectData/1001",
+		Detail: `code string "EMAIL_INVALID" → "INVALID_EMAIL"`}}, changes)
+}
+
+func TestRun_NoBreakingChanges(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"testdata/new.json", "testdata/new.json"}, &out))
+	assert.Equal(t, "no changes\n", out.String())
+}
+
+func TestRun_Errors(t *testing.T) {
+	var out bytes.Buffer
+	assert.Error(t, run([]string{"testdata/old.json"}, &out))
+	assert.Error(t, run([]string{"testdata/old.json", "testdata/missing.json"}, &out))
+	assert.NotErrorIs(t, run([]string{"testdata/old.json", "main.go"}, &out), errBreaking)
+}
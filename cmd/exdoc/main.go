@@ -0,0 +1,90 @@
+// Command exdoc generates an error reference page from an ex catalog.
+//
+// The catalog can come from a JSON snapshot written by the running
+// program (json.Marshal(ex.CatalogSnapshot())), or be recovered from
+// source by scanning for ex.Entry composite literals:
+//
+//	exdoc -snapshot catalog.json -format html -o errors.html
+//	exdoc ./...
+//
+// Scanning understands literal values and the predefined ExType
+// constants, and picks up names and HTTP statuses of custom codes
+// registered with ex.RegisterType in the scanned files. A snapshot is
+// authoritative; scanning is a best effort for code that is not running.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bold-minds/ex"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "exdoc:", err)
+		os.Exit(1)
+	}
+}
+
+// run implements the command; it is separate from main for testing.
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("exdoc", flag.ContinueOnError)
+	format := flags.String("format", "markdown", "output format: markdown or html")
+	snapshotPath := flags.String("snapshot", "", "read the catalog from a JSON snapshot instead of scanning source")
+	title := flags.String("title", "Error Reference", "document title")
+	outPath := flags.String("o", "", "write output to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var snap ex.Snapshot
+	var err error
+	switch {
+	case *snapshotPath != "":
+		snap, err = readSnapshot(*snapshotPath)
+	case flags.NArg() > 0:
+		snap, err = scan(flags.Args())
+	default:
+		return errors.New("nothing to document: pass -snapshot or source directories")
+	}
+	if err != nil {
+		return err
+	}
+
+	out := stdout
+	if *outPath != "" {
+		f, createErr := os.Create(*outPath)
+		if createErr != nil {
+			return createErr
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch *format {
+	case "markdown", "md":
+		return renderMarkdown(out, *title, snap)
+	case "html":
+		return renderHTML(out, *title, snap)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// readSnapshot loads a JSON-encoded ex.Snapshot.
+func readSnapshot(path string) (ex.Snapshot, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a command-line argument
+	if err != nil {
+		return ex.Snapshot{}, err
+	}
+	var snap ex.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return ex.Snapshot{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return snap, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	snap, err := scan([]string{"testdata/src/..."})
+	require.NoError(t, err)
+	require.Len(t, snap.Entries, 3)
+
+	byID := map[int]ex.SnapshotEntry{}
+	for _, e := range snap.Entries {
+		byID[e.ID] = e
+	}
+
+	email := byID[1001]
+	assert.Equal(t, "IncorrectData", email.Type)
+	assert.Equal(t, 400, email.HTTPStatus)
+	assert.Equal(t, "The email address is invalid", email.Message)
+	assert.Equal(t, "Use an address of the form name@example.com.", email.Remediation)
+	assert.Equal(t, "EMAIL_INVALID", email.CodeString)
+	assert.Equal(t, "https://docs.example.com/errors/email", email.DocsURL)
+
+	quota := byID[2001]
+	assert.Equal(t, "Quota", quota.Type, "names come from RegisterType calls in scanned files")
+	assert.Equal(t, 429, quota.HTTPStatus)
+
+	gone := byID[3001]
+	assert.Equal(t, ex.ExTypeApplicationFailure, gone.Code)
+	assert.Equal(t, 410, gone.HTTPStatus, "entry overrides win over type defaults")
+}
+
+func TestScan_NonRecursive(t *testing.T) {
+	snap, err := scan([]string{"testdata/src"})
+	require.NoError(t, err)
+	assert.Len(t, snap.Entries, 2)
+}
+
+func TestRun_Markdown(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"-title", "Billing Errors", "testdata/src/..."}, &out))
+
+	md := out.String()
+	assert.Contains(t, md, "# Billing Errors")
+	assert.Contains(t, md, "| [IncorrectData](#incorrectdata-1001) | 1001 | 400 | The email address is invalid |")
+	assert.Contains(t, md, `Quota \| exceeded`)
+	assert.Contains(t, md, "## Quota / 2001")
+	assert.Contains(t, md, "**Remediation:** Use an address of the form name@example.com.")
+	assert.Contains(t, md, "- **Symbolic code:** `EMAIL_INVALID`")
+	assert.Contains(t, md, "- **Docs:** https://docs.example.com/errors/email")
+}
+
+func TestRun_HTMLFromSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	snap := ex.Snapshot{Entries: []ex.SnapshotEntry{{
+		Code: ex.ExTypePermissionDenied, Type: "PermissionDenied", ID: 7,
+		Message: "No <access>", HTTPStatus: 403, DocsURL: "https://example.com/403",
+	}}}
+	data, err := json.Marshal(snap)
+	require.NoError(t, err)
+	snapPath := filepath.Join(dir, "catalog.json")
+	require.NoError(t, os.WriteFile(snapPath, data, 0o600))
+
+	outPath := filepath.Join(dir, "errors.html")
+	require.NoError(t, run([]string{"-snapshot", snapPath, "-format", "html", "-o", outPath}, nil))
+
+	html, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), `<section id="permissiondenied-7">`)
+	assert.Contains(t, string(html), "No &lt;access&gt;")
+	assert.Contains(t, string(html), `<a href="https://example.com/403">`)
+}
+
+func TestRun_Errors(t *testing.T) {
+	var out bytes.Buffer
+	assert.Error(t, run(nil, &out))
+	assert.Error(t, run([]string{"-format", "pdf", "testdata/src"}, &out))
+	assert.Error(t, run([]string{"-snapshot", "does-not-exist.json"}, &out))
+}
+
+func TestRun_Empty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{t.TempDir()}, &out))
+	assert.Contains(t, out.String(), "No errors are documented.")
+}
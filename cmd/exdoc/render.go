@@ -0,0 +1,101 @@
+package main
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bold-minds/ex"
+)
+
+// view is the data passed to both templates.
+type view struct {
+	Title   string
+	Entries []ex.SnapshotEntry
+}
+
+var funcs = map[string]any{
+	"anchor": anchor,
+	"cell":   markdownCell,
+}
+
+var markdownTemplate = template.Must(template.New("md").Funcs(funcs).Parse(
+	`# {{.Title}}
+{{if .Entries}}
+| Code | ID | HTTP | Message |
+|------|----|------|---------|
+{{range .Entries}}| [{{.Type}}](#{{anchor .}}) | {{.ID}} | {{if .HTTPStatus}}{{.HTTPStatus}}{{end}} | {{cell .Message}} |
+{{end}}{{range .Entries}}
+## {{.Type}} / {{.ID}}
+
+{{if .Message}}> {{.Message}}
+
+{{end}}- **Code:** ` + "`{{.Type}}`" + ` ({{printf "%d" .Code}})
+- **ID:** {{.ID}}
+{{if .CodeString}}- **Symbolic code:** ` + "`{{.CodeString}}`" + `
+{{end}}{{if .HTTPStatus}}- **HTTP status:** {{.HTTPStatus}}
+{{end}}{{if .GRPCCode}}- **gRPC code:** {{.GRPCCode}}
+{{end}}{{if .Severity}}- **Severity:** {{.Severity}}
+{{end}}{{if .DocsURL}}- **Docs:** {{.DocsURL}}
+{{end}}{{if .Remediation}}
+**Remediation:** {{.Remediation}}
+{{end}}{{end}}{{else}}
+No errors are documented.
+{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Entries}}<table>
+<thead><tr><th>Code</th><th>ID</th><th>HTTP</th><th>Message</th></tr></thead>
+<tbody>
+{{range .Entries}}<tr><td><a href="#{{anchor .}}">{{.Type}}</a></td><td>{{.ID}}</td><td>{{if .HTTPStatus}}{{.HTTPStatus}}{{end}}</td><td>{{.Message}}</td></tr>
+{{end}}</tbody>
+</table>
+{{range .Entries}}<section id="{{anchor .}}">
+<h2>{{.Type}} / {{.ID}}</h2>
+{{if .Message}}<blockquote>{{.Message}}</blockquote>{{end}}
+<dl>
+<dt>Code</dt><dd><code>{{.Type}}</code> ({{printf "%d" .Code}})</dd>
+{{if .CodeString}}<dt>Symbolic code</dt><dd><code>{{.CodeString}}</code></dd>{{end}}
+{{if .HTTPStatus}}<dt>HTTP status</dt><dd>{{.HTTPStatus}}</dd>{{end}}
+{{if .GRPCCode}}<dt>gRPC code</dt><dd>{{.GRPCCode}}</dd>{{end}}
+{{if .Severity}}<dt>Severity</dt><dd>{{.Severity}}</dd>{{end}}
+{{if .DocsURL}}<dt>Docs</dt><dd><a href="{{.DocsURL}}">{{.DocsURL}}</a></dd>{{end}}
+</dl>
+{{if .Remediation}}<p><strong>Remediation:</strong> {{.Remediation}}</p>{{end}}
+</section>
+{{end}}{{else}}<p>No errors are documented.</p>
+{{end}}</body>
+</html>
+`))
+
+func renderMarkdown(w io.Writer, title string, snap ex.Snapshot) error {
+	return markdownTemplate.Execute(w, view{Title: title, Entries: snap.Entries})
+}
+
+func renderHTML(w io.Writer, title string, snap ex.Snapshot) error {
+	return htmlTemplate.Execute(w, view{Title: title, Entries: snap.Entries})
+}
+
+// anchor returns the fragment identifier for an entry.
+func anchor(e ex.SnapshotEntry) string {
+	slug := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(e.Type))
+	return strings.Trim(slug, "-") + "-" + strconv.Itoa(e.ID)
+}
+
+// markdownCell escapes text for use inside a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bold-minds/ex"
+)
+
+// exImportPath is the import path whose Entry literals are collected.
+const exImportPath = "github.com/bold-minds/ex"
+
+// builtinCodes maps the predefined constant names to their codes.
+var builtinCodes = map[string]ex.ExType{
+	"ExTypeIncorrectData":      ex.ExTypeIncorrectData,
+	"ExTypeLoginRequired":      ex.ExTypeLoginRequired,
+	"ExTypePermissionDenied":   ex.ExTypePermissionDenied,
+	"ExTypeApplicationFailure": ex.ExTypeApplicationFailure,
+	"ExTypeTimeout":            ex.ExTypeTimeout,
+	"ExTypeUnavailable":        ex.ExTypeUnavailable,
+	"ExTypeNotFound":           ex.ExTypeNotFound,
+	"ExTypeConflict":           ex.ExTypeConflict,
+	"ExTypeRateLimited":        ex.ExTypeRateLimited,
+}
+
+// scannedEntry is an ex.Entry literal found in source. codeExpr is the
+// source text of its Code expression, used to match RegisterType calls.
+type scannedEntry struct {
+	entry    ex.Entry
+	codeExpr string
+}
+
+// scanner accumulates entries and custom type registrations.
+type scanner struct {
+	fset    *token.FileSet
+	entries []scannedEntry
+	types   map[string]ex.TypeInfo
+}
+
+// scan parses the Go files under the given patterns. A pattern ending in
+// "/..." is walked recursively; any other pattern names one directory.
+func scan(patterns []string) (ex.Snapshot, error) {
+	s := &scanner{fset: token.NewFileSet(), types: map[string]ex.TypeInfo{}}
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(pattern, "/...")
+		if pattern == "..." {
+			root, recursive = ".", true
+		}
+		if err := s.scanDir(root, recursive); err != nil {
+			return ex.Snapshot{}, err
+		}
+	}
+	return s.snapshot(), nil
+}
+
+// scanDir parses the non-test Go files in dir, descending into
+// subdirectories when recursive is set.
+func (s *scanner) scanDir(dir string, recursive bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == dir {
+				return nil
+			}
+			name := d.Name()
+			if !recursive || name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		return s.scanFile(path)
+	})
+}
+
+// scanFile collects the Entry literals and RegisterType calls in one file.
+func (s *scanner) scanFile(path string) error {
+	src, err := os.ReadFile(path) // #nosec G304 -- paths come from walking user-supplied directories
+	if err != nil {
+		return err
+	}
+	file, err := parser.ParseFile(s.fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	pkg := exPackageName(file)
+	if pkg == "" {
+		return nil
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			if isExType(n.Type, pkg, "Entry") {
+				s.entries = append(s.entries, s.entryLiteral(n, pkg, src))
+			}
+		case *ast.CallExpr:
+			if isExType(n.Fun, pkg, "RegisterType") && len(n.Args) == 2 {
+				if lit, ok := n.Args[1].(*ast.CompositeLit); ok {
+					s.types[exprText(src, s.fset, n.Args[0])] = typeInfoLiteral(lit)
+				}
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+// exPackageName returns the name under which file refers to the ex
+// package: "" if it does not import it, or "." when the file is part of
+// package ex itself.
+func exPackageName(file *ast.File) string {
+	if file.Name.Name == "ex" {
+		return "."
+	}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path != exImportPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "ex"
+	}
+	return ""
+}
+
+// isExType reports whether expr names the identifier name from package ex.
+func isExType(expr ast.Expr, pkg, name string) bool {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		return ok && x.Name == pkg && e.Sel.Name == name
+	case *ast.Ident:
+		return pkg == "." && e.Name == name
+	}
+	return false
+}
+
+// entryLiteral extracts the fields of an ex.Entry literal. Values that
+// are not literals are left at their zero value, except Code, whose
+// source text is kept for matching against RegisterType calls.
+func (s *scanner) entryLiteral(lit *ast.CompositeLit, pkg string, src []byte) scannedEntry {
+	var out scannedEntry
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Code":
+			out.codeExpr = exprText(src, s.fset, kv.Value)
+			out.entry.Code = codeValue(kv.Value, pkg)
+		case "ID":
+			out.entry.ID = intValue(kv.Value)
+		case "HTTPStatus":
+			out.entry.HTTPStatus = intValue(kv.Value)
+		case "Message":
+			out.entry.Message = stringValue(kv.Value)
+		case "CodeString":
+			out.entry.CodeString = stringValue(kv.Value)
+		case "Remediation":
+			out.entry.Remediation = stringValue(kv.Value)
+		case "DocsURL":
+			out.entry.DocsURL = stringValue(kv.Value)
+		}
+	}
+	return out
+}
+
+// typeInfoLiteral extracts the literal fields of an ex.TypeInfo literal.
+func typeInfoLiteral(lit *ast.CompositeLit) ex.TypeInfo {
+	var info ex.TypeInfo
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Name":
+			info.Name = stringValue(kv.Value)
+		case "HTTPStatus":
+			info.HTTPStatus = intValue(kv.Value)
+		case "GRPCCode":
+			info.GRPCCode = uint32(intValue(kv.Value)) // #nosec G115 -- gRPC codes are small
+		case "DocsURL":
+			info.DocsURL = stringValue(kv.Value)
+		}
+	}
+	return info
+}
+
+// codeValue resolves a Code expression: a predefined constant, or a
+// conversion such as ex.ExType(42). Anything else resolves to 0.
+func codeValue(expr ast.Expr, pkg string) ex.ExType {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == pkg {
+			return builtinCodes[e.Sel.Name]
+		}
+	case *ast.Ident:
+		if pkg == "." {
+			return builtinCodes[e.Name]
+		}
+	case *ast.CallExpr:
+		if isExType(e.Fun, pkg, "ExType") && len(e.Args) == 1 {
+			return ex.ExType(intValue(e.Args[0]))
+		}
+	}
+	return 0
+}
+
+// intValue returns the value of an integer literal, or 0.
+func intValue(expr ast.Expr) int {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0
+	}
+	v, err := strconv.ParseInt(lit.Value, 0, 64)
+	if err != nil {
+		return 0
+	}
+	return int(v)
+}
+
+// stringValue returns the value of a string literal, or "".
+func stringValue(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	v, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return v
+}
+
+// exprText returns the source text of expr.
+func exprText(src []byte, fset *token.FileSet, expr ast.Expr) string {
+	start := fset.Position(expr.Pos()).Offset
+	end := fset.Position(expr.End()).Offset
+	if start < 0 || end > len(src) || start > end {
+		return ""
+	}
+	return string(src[start:end])
+}
+
+// snapshot resolves the scanned entries into an ex.Snapshot.
+func (s *scanner) snapshot() ex.Snapshot {
+	snap := ex.Snapshot{Entries: make([]ex.SnapshotEntry, 0, len(s.entries))}
+	for _, se := range s.entries {
+		e := se.entry
+		info, registered := s.types[se.codeExpr]
+		if !registered {
+			info, _ = ex.LookupType(e.Code)
+		}
+		out := ex.SnapshotEntry{
+			Code:        e.Code,
+			Type:        e.Code.String(),
+			ID:          e.ID,
+			CodeString:  e.CodeString,
+			Message:     e.Message,
+			HTTPStatus:  info.HTTPStatus,
+			GRPCCode:    info.GRPCCode,
+			DocsURL:     info.DocsURL,
+			Remediation: e.Remediation,
+		}
+		switch {
+		case info.Name != "":
+			out.Type = info.Name
+		case e.Code == 0:
+			out.Type = se.codeExpr
+		}
+		if info.Severity != 0 {
+			out.Severity = info.Severity.String()
+		}
+		if e.HTTPStatus != 0 {
+			out.HTTPStatus = e.HTTPStatus
+		}
+		if e.DocsURL != "" {
+			out.DocsURL = e.DocsURL
+		}
+		snap.Entries = append(snap.Entries, out)
+	}
+	sort.SliceStable(snap.Entries, func(i, j int) bool {
+		a, b := snap.Entries[i], snap.Entries[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.ID < b.ID
+	})
+	return snap
+}
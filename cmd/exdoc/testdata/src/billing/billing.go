@@ -0,0 +1,5 @@
+package billing
+
+import "github.com/bold-minds/ex"
+
+var ErrGone = ex.Define(ex.Entry{Code: ex.ExType(4), ID: 3001, Message: "Invoice deleted", HTTPStatus: 410})
@@ -0,0 +1,21 @@
+package src
+
+import errs "github.com/bold-minds/ex"
+
+const ExTypeQuota = errs.ExType(177)
+
+func init() {
+	_ = errs.RegisterType(ExTypeQuota, errs.TypeInfo{Name: "Quota", HTTPStatus: 429})
+}
+
+var (
+	ErrBadEmail = errs.Define(errs.Entry{
+		Code:        errs.ExTypeIncorrectData,
+		ID:          1001,
+		Message:     "The email address is invalid",
+		CodeString:  "EMAIL_INVALID",
+		Remediation: "Use an address of the form name@example.com.",
+		DocsURL:     "https://docs.example.com/errors/email",
+	})
+	ErrQuota = errs.Define(errs.Entry{Code: ExTypeQuota, ID: 2001, Message: "Quota | exceeded"})
+)
@@ -0,0 +1,48 @@
+package ex
+
+// ChainEnd selects which Errorer of a chain CodeOf and IDOf read.
+type ChainEnd int
+
+const (
+	// Outermost reads the first Errorer in the chain, as errors.As finds
+	// it. It is the default.
+	Outermost ChainEnd = iota
+
+	// Innermost reads the last Errorer in the chain, the one closest to
+	// the root cause. Errors that wrap several at once (errors.Join) end
+	// the search.
+	Innermost
+)
+
+// CodeOf returns the code of the outermost Errorer in err's chain, or of
+// the innermost one if end is Innermost. It reports false if the chain
+// holds none:
+//
+//	if code, ok := ex.CodeOf(err); ok && code == ex.ExTypeNotFound { ... }
+func CodeOf(err error, end ...ChainEnd) (ExType, bool) {
+	e, ok := errorerAt(err, end)
+	if !ok {
+		return 0, false
+	}
+	return e.Code(), true
+}
+
+// IDOf returns the ID of the outermost Errorer in err's chain, or of the
+// innermost one if end is Innermost. It reports false if the chain holds
+// none.
+func IDOf(err error, end ...ChainEnd) (int, bool) {
+	e, ok := errorerAt(err, end)
+	if !ok {
+		return 0, false
+	}
+	return e.ID(), true
+}
+
+// errorerAt returns the Errorer at the chosen end of err's chain. Only the
+// first element of end is considered.
+func errorerAt(err error, end []ChainEnd) (Errorer, bool) {
+	if len(end) > 0 && end[0] == Innermost {
+		return innermost(err)
+	}
+	return outermost(err)
+}
@@ -0,0 +1,41 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOfIDOf(t *testing.T) {
+	chain := fmt.Errorf("handler: %w",
+		ex.New(ex.ExTypeApplicationFailure, 500, "Lookup failed").
+			WithInnerError(ex.New(ex.ExTypeNotFound, 404, "No such user")))
+
+	code, ok := ex.CodeOf(chain)
+	assert.True(t, ok)
+	assert.Equal(t, ex.ExTypeApplicationFailure, code)
+	id, ok := ex.IDOf(chain, ex.Outermost)
+	assert.True(t, ok)
+	assert.Equal(t, 500, id)
+
+	code, ok = ex.CodeOf(chain, ex.Innermost)
+	assert.True(t, ok)
+	assert.Equal(t, ex.ExTypeNotFound, code)
+	id, ok = ex.IDOf(chain, ex.Innermost)
+	assert.True(t, ok)
+	assert.Equal(t, 404, id)
+}
+
+func TestCodeOfIDOf_NoException(t *testing.T) {
+	for _, err := range []error{nil, errors.New("plain")} {
+		_, ok := ex.CodeOf(err)
+		assert.False(t, ok)
+		_, ok = ex.CodeOf(err, ex.Innermost)
+		assert.False(t, ok)
+		_, ok = ex.IDOf(err)
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,44 @@
+package ex
+
+// WithCodeString returns a copy of e carrying s as its symbolic code, such
+// as "BILLING_CARD_DECLINED". Numeric IDs are convenient inside a system,
+// but external API consumers usually want a stable, self-describing
+// string to switch on; the symbolic code travels with the exception
+// through the JSON encoding, the httpx envelope, problem details, and the
+// log adapters. It does not take part in errors.Is matching, which stays
+// on (Code, ID).
+//
+// Catalog entries can carry one too (see Entry.CodeString), so that every
+// exception built from the entry gets it.
+func (e Exception) WithCodeString(s string) Exception {
+	e.codeString = s
+	e.frozen = nil
+	return e
+}
+
+// CodeString returns the symbolic code set with WithCodeString, or "".
+func (e Exception) CodeString() string {
+	return e.codeString
+}
+
+// CodeStringOf returns the symbolic code of the outermost Errorer in err's
+// chain, for Errorers that have one (Exception and any other type with a
+// CodeString() string method). It reports false if that Errorer has none.
+func CodeStringOf(err error) (string, bool) {
+	e, ok := outermost(err)
+	if !ok {
+		return "", false
+	}
+	return codeStringOf(e)
+}
+
+// codeStringOf returns e's symbolic code, if its type supports one and it
+// is set.
+func codeStringOf(e Errorer) (string, bool) {
+	cs, ok := e.(interface{ CodeString() string })
+	if !ok {
+		return "", false
+	}
+	s := cs.CodeString()
+	return s, s != ""
+}
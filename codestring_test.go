@@ -0,0 +1,63 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCodeString(t *testing.T) {
+	base := ex.New(ex.ExTypeIncorrectData, 4021, "Card declined")
+	e := base.WithCodeString("BILLING_CARD_DECLINED")
+
+	assert.Equal(t, "BILLING_CARD_DECLINED", e.CodeString())
+	assert.Empty(t, base.CodeString())
+	assert.True(t, errors.Is(e, base), "the symbolic code does not affect matching")
+
+	cs, ok := ex.CodeStringOf(fmt.Errorf("charge: %w", e))
+	assert.True(t, ok)
+	assert.Equal(t, "BILLING_CARD_DECLINED", cs)
+
+	_, ok = ex.CodeStringOf(base)
+	assert.False(t, ok)
+	_, ok = ex.CodeStringOf(errors.New("plain"))
+	assert.False(t, ok)
+}
+
+func TestCodeString_JSONRoundTrip(t *testing.T) {
+	e := ex.New(ex.ExTypeIncorrectData, 4021, "Card declined").WithCodeString("BILLING_CARD_DECLINED")
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"code_string":"BILLING_CARD_DECLINED"`)
+
+	canonical, err := e.MarshalCanonical()
+	require.NoError(t, err)
+	assert.Contains(t, string(canonical), `"code_string":"BILLING_CARD_DECLINED"`)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "BILLING_CARD_DECLINED", decoded.CodeString())
+
+	plain, err := json.Marshal(ex.New(ex.ExTypeIncorrectData, 4021, "Card declined"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(plain), "code_string")
+}
+
+func TestCodeString_CatalogAndCompact(t *testing.T) {
+	entry := ex.Entry{Code: ex.ExTypeIncorrectData, ID: 4921, Message: "Card declined", CodeString: "BILLING_CARD_DECLINED"}
+	assert.Equal(t, "BILLING_CARD_DECLINED", entry.New().CodeString())
+
+	chain := ex.New(ex.ExTypeIncorrectData, 4921, "Checkout failed").WithInnerError(entry.New())
+	assert.Equal(t, "BILLING_CARD_DECLINED", ex.Compact(chain).CodeString())
+
+	own := chain.WithCodeString("CHECKOUT_FAILED")
+	assert.Equal(t, "CHECKOUT_FAILED", ex.Compact(own).CodeString())
+
+	assert.Contains(t, ex.ToHTML(entry.New()), "BILLING_CARD_DECLINED")
+}
@@ -0,0 +1,87 @@
+package ex
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Collector accumulates non-fatal errors encountered while handling one
+// unit of work, such as a request, so they can be reported together at the
+// end instead of being dropped. It is safe for concurrent use.
+type Collector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// collectorKey is the context key under which a Collector is stored.
+type collectorKey struct{}
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records err. Nil errors are ignored.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+// Errors returns a copy of the collected errors in the order they were
+// added.
+func (c *Collector) Errors() []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.errs) == 0 {
+		return nil
+	}
+	out := make([]error, len(c.errs))
+	copy(out, c.errs)
+	return out
+}
+
+// Len returns the number of collected errors.
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.errs)
+}
+
+// Err joins the collected errors with errors.Join, returning nil when
+// nothing was collected.
+func (c *Collector) Err() error {
+	return errors.Join(c.Errors()...)
+}
+
+// ContextWithCollector returns a copy of ctx carrying c.
+func ContextWithCollector(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, collectorKey{}, c)
+}
+
+// CollectorFrom returns the Collector carried by ctx, or nil.
+func CollectorFrom(ctx context.Context) *Collector {
+	c, _ := ctx.Value(collectorKey{}).(*Collector)
+	return c
+}
+
+// Collect records err in the Collector carried by ctx, tagged with ctx's
+// tenant (see TagTenant), and reports whether there was one to record it
+// in. Code deep in a call stack can use it to surface a non-fatal problem
+// without changing its return values:
+//
+//	if err := cache.Set(ctx, key, v); err != nil {
+//	    ex.Collect(ctx, err) // degrade gracefully, but don't lose it
+//	}
+func Collect(ctx context.Context, err error) bool {
+	c := CollectorFrom(ctx)
+	if c == nil || err == nil {
+		return false
+	}
+	c.Add(TagTenant(ctx, err))
+	return true
+}
@@ -0,0 +1,56 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	c := ex.NewCollector()
+	ctx := ex.ContextWithCollector(context.Background(), c)
+	assert.Same(t, c, ex.CollectorFrom(ctx))
+
+	first := ex.New(ex.ExTypeApplicationFailure, 500, "cache unavailable")
+	second := errors.New("metrics push failed")
+	assert.True(t, ex.Collect(ctx, first))
+	assert.True(t, ex.Collect(ctx, second))
+	assert.False(t, ex.Collect(ctx, nil))
+
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, []error{first, second}, c.Errors())
+
+	joined := c.Err()
+	assert.True(t, errors.Is(joined, second))
+	assert.Equal(t, "cache unavailable\nmetrics push failed", joined.Error())
+}
+
+func TestCollect_NoCollector(t *testing.T) {
+	assert.Nil(t, ex.CollectorFrom(context.Background()))
+	assert.False(t, ex.Collect(context.Background(), errors.New("dropped")))
+
+	c := ex.NewCollector()
+	assert.Nil(t, c.Errors())
+	assert.NoError(t, c.Err())
+}
+
+func TestCollector_Concurrent(t *testing.T) {
+	c := ex.NewCollector()
+	ctx := ex.ContextWithCollector(context.Background(), c)
+
+	var wg sync.WaitGroup
+	const workers = 32
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			ex.Collect(ctx, errors.New("partial failure"))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, workers, c.Len())
+}
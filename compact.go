@@ -0,0 +1,119 @@
+package ex
+
+// Compact returns err's chain with adjacent Exception levels that share a
+// code and ID merged into one, which is what middleware layers that
+// re-wrap blindly tend to produce:
+//
+//	ApplicationFailure/500 "request failed"
+//	  ApplicationFailure/500 "handler failed"
+//	    ApplicationFailure/500 "query failed"
+//	      sql: connection refused
+//
+// compacts to a single ApplicationFailure/500 "request failed" level
+// wrapping the driver error, with "handler failed" and "query failed"
+// preserved, outermost first, in its operations (see Ops). When merging,
+// the outer level's message, fields, details, trace context, symbolic
+// code, hint, and docs link take precedence, and the innermost captured
+// stack is kept since it is closest to the failure. Messages repeating the
+// one before them are dropped.
+//
+// Compact never runs hooks for the levels it rebuilds. An err that is not
+// an Exception is first converted with Promote(err, nil), and a nil err
+// yields the zero Exception.
+func Compact(err error) Exception {
+	if err == nil {
+		return Exception{}
+	}
+	e, ok := err.(Exception)
+	if !ok {
+		e = Promote(err, nil)
+	}
+	return compact(e)
+}
+
+// compact merges e with its matching inner levels, then compacts the rest
+// of the chain.
+func compact(e Exception) Exception {
+	for {
+		inner, ok := e.innerError.(Exception)
+		if !ok || inner.code != e.code || inner.id != e.id {
+			break
+		}
+		e = mergeLevels(e, inner)
+	}
+	if inner, ok := e.innerError.(Exception); ok {
+		e.innerError = compact(inner)
+		e.sizeHint = 0
+		e.frozen = nil
+	}
+	return e
+}
+
+// mergeLevels folds inner into outer, taking over inner's inner error.
+func mergeLevels(outer, inner Exception) Exception {
+	m := outer
+	m.innerError = inner.innerError
+	m.sizeHint = 0
+	m.frozen = nil
+
+	ops := make([]string, 0, len(outer.ops)+1+len(inner.ops))
+	ops = append(ops, outer.ops...)
+	last := m.message
+	if len(ops) > 0 {
+		last = ops[len(ops)-1]
+	}
+	switch {
+	case m.message == "":
+		m.message = inner.message
+	case inner.message != "" && inner.message != last:
+		ops = append(ops, inner.message)
+	}
+	ops = append(ops, inner.ops...)
+	m.ops = nil
+	if len(ops) > 0 {
+		m.ops = ops
+	}
+
+	m.fields = mergeFields(outer.fields, inner.fields)
+	m.details = mergeFields(outer.details, inner.details)
+	if inner.HasStack() {
+		m.stack, m.frames = inner.stack, inner.frames
+	}
+	if m.trace == nil {
+		m.trace = inner.trace
+	}
+	if m.codeString == "" {
+		m.codeString = inner.codeString
+	}
+	// The merged level inherits a status only if both did: otherwise
+	// the status came from their shared code.
+	m.keepStatus = outer.keepStatus && inner.keepStatus
+	if m.hint == "" {
+		m.hint = inner.hint
+	}
+	if m.docsURL == "" {
+		m.docsURL = inner.docsURL
+	}
+	if m.panicked == nil {
+		m.panicked = inner.panicked
+	}
+	if m.stackDump == "" {
+		m.stackDump = inner.stackDump
+	}
+	return m
+}
+
+// mergeFields returns outer extended with the keys of inner it lacks.
+func mergeFields(outer, inner []Field) []Field {
+	if len(inner) == 0 {
+		return outer
+	}
+	merged := make([]Field, len(outer), len(outer)+len(inner))
+	copy(merged, outer)
+	for _, f := range inner {
+		if indexField(merged, f.Key) < 0 {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
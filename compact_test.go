@@ -0,0 +1,57 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact(t *testing.T) {
+	root := errors.New("connection refused")
+	query := ex.New(ex.ExTypeApplicationFailure, 500, "query failed").
+		WithField("table", "orders").
+		WithInnerError(root).
+		WithStack()
+	handler := ex.New(ex.ExTypeApplicationFailure, 500, "handler failed").
+		WithFields(ex.F("table", "orders_v2"), ex.F("route", "/orders")).
+		WithInnerError(query)
+	request := ex.New(ex.ExTypeApplicationFailure, 500, "request failed").
+		WithInnerError(ex.New(ex.ExTypeApplicationFailure, 500, "request failed").WithInnerError(handler))
+	outer := ex.New(ex.ExTypeIncorrectData, 400, "bad request").WithInnerError(request)
+
+	got := ex.Compact(outer)
+	assert.Equal(t, "bad request: request failed: connection refused", got.Error())
+	assert.ErrorIs(t, got, root)
+
+	merged, ok := got.InnerError().(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, root, merged.InnerError(), "three matching levels collapse into one")
+	assert.Equal(t, []string{"handler failed", "query failed"}, ex.Ops(merged))
+	assert.Equal(t, []ex.Field{ex.F("table", "orders_v2"), ex.F("route", "/orders")}, merged.Fields(),
+		"outer fields win")
+	assert.True(t, merged.HasStack(), "the innermost stack is kept")
+
+	// The original chain is untouched.
+	assert.Equal(t, "bad request: request failed: request failed: handler failed: query failed: connection refused", outer.Error())
+}
+
+func TestCompact_NothingToMerge(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "outer").
+		WithInnerError(ex.New(ex.ExTypeApplicationFailure, 501, "inner"))
+	got := ex.Compact(e)
+	assert.Equal(t, e.Error(), got.Error())
+	assert.Nil(t, ex.Ops(got))
+}
+
+func TestCompact_NonException(t *testing.T) {
+	assert.Equal(t, 0, ex.Compact(nil).ID())
+
+	err := fmt.Errorf("loading: %w", errors.New("EOF"))
+	got := ex.Compact(err)
+	assert.Equal(t, ex.ExTypeApplicationFailure, got.Code())
+	assert.Equal(t, "loading: EOF", got.Error())
+}
@@ -0,0 +1,273 @@
+// Package crdb mirrors the marker and safe-detail parts of
+// github.com/cockroachdb/errors on top of ex Exceptions, for teams moving
+// between the two libraries.
+//
+// Marks make an error match a reference error without wrapping it:
+//
+//	var ErrRetry = errors.New("retry")
+//
+//	err = crdb.Mark(err, ErrRetry)
+//	crdb.Is(err, ErrRetry) // true
+//
+// As in cockroachdb/errors, a reference is identified by its Go type name
+// and message rather than by identity, and an exception by its code, ID,
+// and message, so marks and plain errors keep matching after a chain has
+// been serialized and decoded in another process, and sentinels made
+// with New stay distinct although they share a code and ID. Marks are
+// recorded in a field of the outermost exception, so they are seen by
+// this package's Is and IsAny, not by errors.Is.
+//
+// Safe details support redacted reporting. Newf and Wrapf treat their
+// arguments as sensitive unless wrapped with Safe, and Redact renders a
+// chain with every sensitive argument, and the text of every error that
+// is not an Exception, replaced by RedactedMarker:
+//
+//	err := crdb.Newf("user %s exceeded quota %d", name, crdb.Safe(limit))
+//	crdb.Redact(err) // "user ‹×› exceeded quota 100"
+//
+// Exception messages written without Newf or Wrapf are considered safe:
+// they are the developer's own text.
+package crdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bold-minds/ex"
+)
+
+// RedactedMarker replaces sensitive text in the output of Redact.
+const RedactedMarker = "‹×›"
+
+// Fields in which the package records its state on exceptions. They
+// travel with the JSON encoding like any other field.
+const (
+	// MarksField lists the marks added by Mark.
+	MarksField = "crdb.marks"
+	// SafeMessageField holds the redacted message of a level built by
+	// Newf or Wrapf.
+	SafeMessageField = "crdb.safe_message"
+	// SafeDetailsField lists the details added by WithSafeDetails.
+	SafeDetailsField = "crdb.safe_details"
+)
+
+// SafeValue is an argument that Newf, Wrapf, and WithSafeDetails may
+// report unredacted. Create one with Safe.
+type SafeValue struct {
+	v any
+}
+
+// Safe marks v as safe to report.
+func Safe(v any) SafeValue {
+	return SafeValue{v: v}
+}
+
+// Format formats the wrapped value as if it had been passed directly.
+func (s SafeValue) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), s.v)
+}
+
+// redacted stands in for a sensitive argument in redacted output,
+// whatever the verb.
+type redacted struct{}
+
+// Format writes RedactedMarker.
+func (redacted) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, RedactedMarker)
+}
+
+// New returns an ApplicationFailure exception with ID 0 and a stack, as
+// errors.New of cockroachdb/errors does. The message is considered safe.
+func New(message string) error {
+	return ex.New(ex.ExTypeApplicationFailure, 0, message).WithStack()
+}
+
+// Newf is New with a formatted message. Arguments other than SafeValues
+// are redacted by Redact.
+func Newf(format string, args ...any) error {
+	return withSafeMessage(ex.New(ex.ExTypeApplicationFailure, 0, fmt.Sprintf(format, args...)).WithStack(), format, args)
+}
+
+// Wrap wraps err with message and a stack, inferring the code as
+// ex.WrapAuto does. It returns nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return ex.WrapAuto(err, 0, message).WithStack()
+}
+
+// Wrapf is Wrap with a formatted message. Arguments other than SafeValues
+// are redacted by Redact.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return withSafeMessage(ex.WrapAuto(err, 0, fmt.Sprintf(format, args...)).WithStack(), format, args)
+}
+
+// withSafeMessage records the redacted rendering of format on e, if it
+// differs from the message.
+func withSafeMessage(e ex.Exception, format string, args []any) ex.Exception {
+	if safe := fmt.Sprintf(format, redactArgs(args)...); safe != e.Message() {
+		return e.WithField(SafeMessageField, safe)
+	}
+	return e
+}
+
+// redactArgs replaces the arguments that are not SafeValues by redacted.
+func redactArgs(args []any) []any {
+	out := make([]any, len(args))
+	for i, a := range args {
+		if s, ok := a.(SafeValue); ok {
+			out[i] = s
+		} else {
+			out[i] = redacted{}
+		}
+	}
+	return out
+}
+
+// WithSafeDetails annotates err with a detail that is safe to report,
+// formatted like Newf's message. Details are listed by GetSafeDetails and
+// do not change err's text. It returns nil if err is nil.
+func WithSafeDetails(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return addToList(err, SafeDetailsField, fmt.Sprintf(format, redactArgs(args)...))
+}
+
+// GetSafeDetails returns the details added with WithSafeDetails anywhere
+// in err's chain, outermost first.
+func GetSafeDetails(err error) []string {
+	var details []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(ex.Exception); ok {
+			details = append(details, listField(e, SafeDetailsField)...)
+		}
+	}
+	return details
+}
+
+// Redact renders err like Error() with sensitive text replaced by
+// RedactedMarker: the arguments of Newf and Wrapf not wrapped with Safe,
+// and the whole text of errors that are not Exceptions.
+func Redact(err error) string {
+	var parts []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		e, ok := err.(ex.Exception)
+		if !ok {
+			parts = append(parts, RedactedMarker)
+			break
+		}
+		msg := e.Message()
+		if v, found := e.FieldValue(SafeMessageField); found {
+			if s, isString := v.(string); isString {
+				msg = s
+			}
+		}
+		if msg != "" {
+			parts = append(parts, msg)
+		}
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Mark returns err marked with reference, so that Is(err, reference)
+// holds while err's text and chain are unchanged. Exceptions record the
+// mark in place; other errors are first wrapped as ex.WrapAuto does. It
+// returns nil if err is nil.
+func Mark(err error, reference error) error {
+	if err == nil {
+		return nil
+	}
+	return addToList(err, MarksField, markKey(reference))
+}
+
+// Is reports whether reference matches err or any error in its chain:
+// by a mark added with Mark, or by having the same Go type name and
+// message, or for an exception the same code, ID, and message, which lets
+// errors match after a round trip through JSON. References that are not
+// exceptions also match by errors.Is; exceptions do not, since
+// errors.Is compares only their code and ID.
+func Is(err, reference error) bool {
+	if err == nil || reference == nil {
+		return err == reference
+	}
+	if _, isException := reference.(ex.Exception); !isException && errors.Is(err, reference) {
+		return true
+	}
+	key := markKey(reference)
+	for ; err != nil; err = errors.Unwrap(err) {
+		if markKey(err) == key {
+			return true
+		}
+		if e, ok := err.(ex.Exception); ok && slices.Contains(listField(e, MarksField), key) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAny reports whether err matches any of references, as Is does.
+func IsAny(err error, references ...error) bool {
+	for _, ref := range references {
+		if Is(err, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// markKey identifies an error across processes: exceptions by code, ID,
+// and message, others by Go type name and message.
+func markKey(err error) string {
+	if e, ok := err.(ex.Exception); ok {
+		return "ex:" + e.Code().String() + "/" + strconv.Itoa(e.ID()) + ": " + e.Message()
+	}
+	if r, ok := err.(*ex.RemoteError); ok {
+		return r.TypeName() + ": " + r.Message()
+	}
+	return fmt.Sprintf("%T", err) + ": " + err.Error()
+}
+
+// addToList appends value to the list field key of err's outermost level,
+// wrapping err in an exception first if it is not one.
+func addToList(err error, key, value string) ex.Exception {
+	e, ok := err.(ex.Exception)
+	if !ok {
+		e = ex.WrapAuto(err, 0, "")
+	}
+	list := listField(e, key)
+	if slices.Contains(list, value) {
+		return e
+	}
+	return e.WithField(key, append(slices.Clip(list), value))
+}
+
+// listField returns the list stored in e's field key, which holds a
+// []string, or a []any of strings once decoded from JSON.
+func listField(e ex.Exception, key string) []string {
+	v, ok := e.FieldValue(key)
+	if !ok {
+		return nil
+	}
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, isString := item.(string); isString {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package crdb_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/compat/crdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRetry = errors.New("retry")
+
+func roundTrip(t *testing.T, err error) ex.Exception {
+	t.Helper()
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	data, marshalErr := json.Marshal(e)
+	require.NoError(t, marshalErr)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func TestMark(t *testing.T) {
+	assert.NoError(t, crdb.Mark(nil, errRetry))
+
+	base := ex.New(ex.ExTypeUnavailable, 503, "Upstream busy")
+	marked := crdb.Mark(base, errRetry)
+	assert.Equal(t, "Upstream busy", marked.Error())
+	assert.True(t, crdb.Is(marked, errRetry))
+	assert.False(t, crdb.Is(base, errRetry))
+	assert.False(t, crdb.Is(marked, io.EOF))
+	assert.True(t, crdb.IsAny(marked, io.EOF, errRetry))
+	assert.False(t, crdb.IsAny(marked))
+
+	// Marks survive serialization, and marking twice is harmless.
+	decoded := roundTrip(t, crdb.Mark(crdb.Mark(marked, errRetry), io.EOF))
+	assert.True(t, crdb.Is(decoded, errRetry))
+	assert.True(t, crdb.Is(decoded, io.EOF))
+
+	// Plain errors are wrapped to carry the mark.
+	plain := crdb.Mark(io.ErrUnexpectedEOF, errRetry)
+	assert.Equal(t, io.ErrUnexpectedEOF.Error(), plain.Error())
+	assert.True(t, crdb.Is(plain, errRetry))
+	assert.True(t, crdb.Is(plain, io.ErrUnexpectedEOF))
+}
+
+func TestIs_ByTypeAndMessage(t *testing.T) {
+	err := crdb.Wrap(errRetry, "sync")
+	decoded := roundTrip(t, err)
+	assert.False(t, errors.Is(decoded, errRetry), "identity is lost on the wire")
+	assert.True(t, crdb.Is(decoded, errRetry))
+	assert.False(t, crdb.Is(decoded, errors.New("other")))
+
+	assert.True(t, crdb.Is(nil, nil))
+	assert.False(t, crdb.Is(err, nil))
+	assert.True(t, crdb.Is(decoded, crdb.New("sync")))
+	assert.False(t, crdb.Is(decoded, ex.New(ex.ExTypeApplicationFailure, 0, "")))
+}
+
+func TestIs_DistinctSentinels(t *testing.T) {
+	errA, errB := crdb.New("conflict"), crdb.New("not found")
+	assert.False(t, crdb.Is(errB, errA), "sentinels sharing a code and ID stay distinct")
+	assert.True(t, crdb.Is(errA, errA))
+	assert.True(t, crdb.Is(crdb.Wrap(errA, "sync"), errA))
+	assert.False(t, crdb.Is(crdb.Wrap(io.EOF, "sync"), errA))
+
+	marked := crdb.Mark(io.EOF, errA)
+	assert.True(t, crdb.Is(marked, errA))
+	assert.False(t, crdb.Is(marked, errB), "a mark names one sentinel")
+	assert.True(t, crdb.Is(roundTrip(t, marked), errA))
+	assert.False(t, crdb.Is(roundTrip(t, marked), errB))
+}
+
+func TestRedact(t *testing.T) {
+	err := crdb.Newf("user %s exceeded quota %d", "alice", crdb.Safe(100))
+	assert.Equal(t, "user alice exceeded quota 100", err.Error())
+	assert.Equal(t, "user ‹×› exceeded quota 100", crdb.Redact(err))
+
+	wrapped := crdb.Wrapf(crdb.Wrap(errors.New("dial tcp 10.0.0.7:5432"), "connect"), "loading %v", crdb.Safe("orders"))
+	assert.Equal(t, "loading orders: connect: dial tcp 10.0.0.7:5432", wrapped.Error())
+	assert.Equal(t, "loading orders: connect: ‹×›", crdb.Redact(wrapped))
+	assert.Equal(t, "loading orders: connect: ‹×›", crdb.Redact(roundTrip(t, wrapped)))
+
+	assert.Equal(t, "Plain message", crdb.Redact(crdb.New("Plain message")))
+	assert.NoError(t, crdb.Wrap(nil, "x"))
+	assert.NoError(t, crdb.Wrapf(nil, "x %d", 1))
+}
+
+func TestSafeDetails(t *testing.T) {
+	assert.NoError(t, crdb.WithSafeDetails(nil, "x"))
+
+	err := crdb.WithSafeDetails(crdb.New("Job failed"), "shard %d of %s", crdb.Safe(3), "tenant-secret")
+	err = crdb.WithSafeDetails(crdb.Wrap(err, "retry exhausted"), "attempts=%d", crdb.Safe(5))
+	assert.Equal(t, "retry exhausted: Job failed", err.Error())
+	assert.Equal(t, []string{"attempts=5", "shard 3 of ‹×›"}, crdb.GetSafeDetails(err))
+	assert.Equal(t, []string{"attempts=5", "shard 3 of ‹×›"}, crdb.GetSafeDetails(roundTrip(t, err)))
+}
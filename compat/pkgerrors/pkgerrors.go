@@ -0,0 +1,149 @@
+// Package pkgerrors is a drop-in replacement for the archived
+// github.com/pkg/errors whose errors are ex Exceptions, for migrating
+// large codebases one import at a time:
+//
+//	import "github.com/pkg/errors"
+//
+// becomes
+//
+//	import errors "github.com/bold-minds/ex/compat/pkgerrors"
+//
+// and every call keeps compiling. New, Errorf, Wrap, Wrapf, and WithStack
+// capture a stack as their pkg/errors counterparts do; the stack begins
+// at the function of this package that captured it. Wrapping levels infer
+// their code from the chain, as ex.WrapAuto does, so an exception with a
+// real code deep in the chain keeps its classification through the
+// wrappers, and a chain of plain errors becomes ExTypeApplicationFailure
+// with ID 0. Assign proper codes and IDs as code is migrated further.
+//
+// As in pkg/errors, the errors of New and Errorf are identified by
+// pointer, so sentinels declared with them match only themselves in
+// errors.Is; the exception they hold (see ex.As) has code
+// ExTypeApplicationFailure and ID 0, which ex's own matching would treat
+// as equal.
+//
+// One behavior differs: formatting with %+v prints the message chain, not
+// the stack. Read stacks with ex.Exception.StackTrace and render them
+// with ex.FormatStack.
+package pkgerrors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bold-minds/ex"
+)
+
+// fundamental is the error of New and Errorf: an exception behind a
+// pointer, so that errors.Is matches it by identity rather than by the
+// code and ID every such exception shares.
+type fundamental struct {
+	e ex.Exception
+}
+
+// Error returns the message of the exception.
+func (f *fundamental) Error() string {
+	return f.e.Error()
+}
+
+// Unwrap returns the exception, so that ex.As and errors.As find it.
+func (f *fundamental) Unwrap() error {
+	return f.e
+}
+
+// New returns an error with the supplied message and a stack.
+func New(message string) error {
+	return &fundamental{e: ex.New(ex.ExTypeApplicationFailure, 0, message).WithStack()}
+}
+
+// Errorf formats according to a format specifier and returns the result
+// as an error with a stack. As in pkg/errors, %w is not supported; use
+// Wrap.
+func Errorf(format string, args ...any) error {
+	return &fundamental{e: ex.New(ex.ExTypeApplicationFailure, 0, fmt.Sprintf(format, args...)).WithStack()}
+}
+
+// WithStack annotates err with a stack. It returns nil if err is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return ex.WrapAuto(err, 0, "").WithStack()
+}
+
+// Wrap returns an error annotating err with message and a stack. It
+// returns nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return ex.WrapAuto(err, 0, message).WithStack()
+}
+
+// Wrapf returns an error annotating err with a formatted message and a
+// stack. It returns nil if err is nil.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return ex.WrapAuto(err, 0, fmt.Sprintf(format, args...)).WithStack()
+}
+
+// WithMessage annotates err with message, without a stack. It returns nil
+// if err is nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return ex.WrapAuto(err, 0, message)
+}
+
+// WithMessagef annotates err with a formatted message, without a stack.
+// It returns nil if err is nil.
+func WithMessagef(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return ex.WrapAuto(err, 0, fmt.Sprintf(format, args...))
+}
+
+// Cause returns the underlying cause of err: the last error of its chain,
+// following Cause methods of errors still built by pkg/errors as well as
+// Unwrap, or the error made by New or Errorf it wraps. It returns nil if
+// err is nil.
+func Cause(err error) error {
+	for err != nil {
+		if _, ok := err.(*fundamental); ok {
+			break
+		}
+		var next error
+		if c, ok := err.(interface{ Cause() error }); ok {
+			next = c.Cause()
+		} else {
+			next = errors.Unwrap(err)
+		}
+		if next == nil {
+			break
+		}
+		err = next
+	}
+	return err
+}
+
+// Is reports whether any error in err's chain matches target; it is
+// errors.Is.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target; it is
+// errors.As.
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err; it is
+// errors.Unwrap.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
@@ -0,0 +1,86 @@
+package pkgerrors_test
+
+import (
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	errors "github.com/bold-minds/ex/compat/pkgerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAndErrorf(t *testing.T) {
+	err := errors.New("boom")
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, "boom", e.Error())
+	assert.Equal(t, ex.ExTypeApplicationFailure, e.Code())
+	assert.True(t, e.HasStack())
+
+	assert.Equal(t, "user 42 not found", errors.Errorf("user %d not found", 42).Error())
+}
+
+func TestNew_Identity(t *testing.T) {
+	errConflict := errors.New("conflict")
+	errNotFound := errors.New("not found")
+
+	assert.False(t, errors.Is(errConflict, errNotFound), "sentinels match only themselves")
+	assert.False(t, errors.Is(errors.Errorf("not found"), errNotFound))
+	assert.False(t, errors.Is(errors.Wrap(io.EOF, "x"), errNotFound))
+	assert.True(t, errors.Is(errNotFound, errNotFound))
+	assert.True(t, errors.Is(errors.Wrapf(errNotFound, "user %d", 7), errNotFound))
+	assert.True(t, errors.Is(errors.WithStack(errors.WithMessage(errNotFound, "x")), errNotFound))
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, errors.Wrap(nil, "x"))
+	assert.NoError(t, errors.Wrapf(nil, "x %d", 1))
+	assert.NoError(t, errors.WithMessage(nil, "x"))
+	assert.NoError(t, errors.WithMessagef(nil, "x %d", 1))
+	assert.NoError(t, errors.WithStack(nil))
+
+	err := errors.Wrapf(errors.WithMessage(sql.ErrNoRows, "query"), "loading user %d", 7)
+	assert.Equal(t, "loading user 7: query: sql: no rows in result set", err.Error())
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.Equal(t, sql.ErrNoRows, errors.Cause(err))
+
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.True(t, e.HasStack())
+	inner, ok := ex.As(e.InnerError())
+	require.True(t, ok)
+	assert.False(t, inner.HasStack(), "WithMessage captures no stack")
+
+	stacked := errors.WithStack(io.EOF)
+	assert.Equal(t, "EOF", stacked.Error())
+	assert.Equal(t, io.EOF, errors.Cause(stacked))
+}
+
+func TestWrap_KeepsCode(t *testing.T) {
+	err := errors.Wrap(ex.New(ex.ExTypeNotFound, 404, "No such user"), "handler")
+	code, ok := ex.CodeOf(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeNotFound, code)
+
+	var target ex.Exception
+	assert.True(t, errors.As(err, &target))
+	assert.NotNil(t, errors.Unwrap(err))
+}
+
+// causer mimics an error still built by github.com/pkg/errors.
+type causer struct{ cause error }
+
+func (c causer) Error() string { return "wrapped: " + c.cause.Error() }
+func (c causer) Cause() error  { return c.cause }
+
+func TestCause(t *testing.T) {
+	assert.NoError(t, errors.Cause(nil))
+	assert.Equal(t, io.EOF, errors.Cause(errors.Wrap(causer{io.EOF}, "x")))
+
+	plain := errors.New("root")
+	assert.Equal(t, plain, errors.Cause(plain))
+	assert.True(t, strings.HasPrefix(errors.Cause(causer{plain}).Error(), "root"))
+}
@@ -0,0 +1,88 @@
+package ex
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// DefaultCompressThreshold is the encoded size, in bytes, above which
+// MarshalCompressed compresses when given a threshold of zero.
+const DefaultCompressThreshold = 4 << 10
+
+// gzipMagic starts every gzip stream; no JSON document starts with it.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// MarshalCompressed returns the JSON encoding of e, as MarshalJSON does,
+// gzip-compressed if it is longer than threshold bytes. A threshold of
+// zero means DefaultCompressThreshold; a negative one always compresses.
+// Chains with many fields and stacks compress well, which keeps them
+// under the message-size limits of queues and brokers:
+//
+//	payload, err := e.MarshalCompressed(0)
+//
+// UnmarshalCompressed decodes both forms, so readers need not know which
+// was written.
+func (e Exception) MarshalCompressed(threshold int) ([]byte, error) {
+	data, err := e.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if threshold == 0 {
+		threshold = DefaultCompressThreshold
+	}
+	if len(data) <= threshold {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalCompressed decodes an exception written by MarshalCompressed,
+// compressed or not, or by MarshalJSON. Compressed input is limited to the
+// same decompressed size as ReadBundle accepts, and fails with ErrTooLarge
+// past it.
+func UnmarshalCompressed(data []byte) (Exception, error) {
+	if IsCompressed(data) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return Exception{}, err
+		}
+		defer zr.Close()
+		if data, err = readLimited(zr); err != nil {
+			return Exception{}, err
+		}
+	}
+	var e Exception
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Exception{}, err
+	}
+	return e, nil
+}
+
+// readLimited reads r to the end, failing with ErrTooLarge rather than
+// truncating if it holds more than maxBundleSize bytes.
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBundleSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// IsCompressed reports whether data is a compressed encoding written by
+// MarshalCompressed.
+func IsCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, gzipMagic)
+}
@@ -0,0 +1,83 @@
+package ex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeChain returns an exception whose encoding is several KiB.
+func largeChain() ex.Exception {
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Import failed").
+		WithInnerError(errors.New("row 7: " + strings.Repeat("bad value ", 200))).
+		WithStack()
+	for _, k := range []string{"batch", "file", "table", "owner"} {
+		e = e.WithField(k, strings.Repeat(k, 100))
+	}
+	return ex.New(ex.ExTypeIncorrectData, 4001, "Upload rejected").WithInnerError(e)
+}
+
+func TestMarshalCompressed(t *testing.T) {
+	e := largeChain()
+	plain, err := e.MarshalJSON()
+	require.NoError(t, err)
+	require.Greater(t, len(plain), ex.DefaultCompressThreshold)
+
+	data, err := e.MarshalCompressed(0)
+	require.NoError(t, err)
+	assert.True(t, ex.IsCompressed(data))
+	assert.Less(t, len(data), len(plain)/2)
+
+	got, err := ex.UnmarshalCompressed(data)
+	require.NoError(t, err)
+	assert.Equal(t, e.Error(), got.Error())
+	assert.True(t, errors.Is(got, e))
+	assert.Equal(t, len(ex.FieldsOf(e)), len(ex.FieldsOf(got)))
+	inner, ok := got.InnerError().(ex.Exception)
+	require.True(t, ok)
+	assert.NotEmpty(t, inner.StackTrace())
+}
+
+func TestMarshalCompressed_BelowThreshold(t *testing.T) {
+	e := ex.New(ex.ExTypeNotFound, 4041, "Account not found")
+	data, err := e.MarshalCompressed(0)
+	require.NoError(t, err)
+	plain, err := e.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, plain, data, "small encodings are left alone")
+	assert.False(t, ex.IsCompressed(data))
+
+	got, err := ex.UnmarshalCompressed(data)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(got, e))
+
+	forced, err := e.MarshalCompressed(-1)
+	require.NoError(t, err)
+	assert.True(t, ex.IsCompressed(forced))
+}
+
+func TestUnmarshalCompressed_TooLarge(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "Batch failed").WithField("rows", strings.Repeat("r", 512))
+	data, err := e.MarshalCompressed(-1)
+	require.NoError(t, err)
+	bundle, err := ex.Bundle(e)
+	require.NoError(t, err)
+
+	restore := ex.SetMaxBundleSize(256)
+	defer restore()
+	_, err = ex.UnmarshalCompressed(data)
+	assert.ErrorIs(t, err, ex.ErrTooLarge, "input is rejected, not truncated")
+	_, err = ex.ReadBundle(bundle)
+	assert.ErrorIs(t, err, ex.ErrTooLarge)
+}
+
+func TestUnmarshalCompressed_Invalid(t *testing.T) {
+	_, err := ex.UnmarshalCompressed([]byte{0x1f, 0x8b, 0x00})
+	assert.Error(t, err)
+	_, err = ex.UnmarshalCompressed([]byte("{"))
+	assert.Error(t, err)
+}
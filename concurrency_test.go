@@ -0,0 +1,196 @@
+package ex_test
+
+// The tests in this file exercise the immutability contract of Exception
+// under concurrent use. They assert results, but their main value is
+// under -race, which CI always enables.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrentWorkers is the number of goroutines each test starts.
+const concurrentWorkers = 16
+
+// runConcurrently calls fn from concurrentWorkers goroutines at once.
+func runConcurrently(fn func()) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	wg.Add(concurrentWorkers)
+	for range concurrentWorkers {
+		go func() {
+			defer wg.Done()
+			<-start
+			fn()
+		}()
+	}
+	close(start)
+	wg.Wait()
+}
+
+// sharedException builds an exception using every kind of state an
+// Exception can carry.
+func sharedException() ex.Exception {
+	inner := ex.New(ex.ExTypeUnavailable, 503, "Query failed").
+		WithFields(ex.F("table", "users"), ex.F("password", "hunter2")).
+		WithStack().
+		WithInnerError(errors.New("connection refused"))
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").
+		WithInnerError(ex.AnnotateOp(inner, "repo.Load", ex.F("attempt", 2))).
+		WithField("request", "r-1").
+		WithTraceContext(ex.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}).
+		WithStack()
+	return ex.WithDetail(e, ex.Help{Links: []ex.HelpLink{{Description: "Runbook", URL: "https://example.com"}}})
+}
+
+func TestConcurrency_SharedReads(t *testing.T) {
+	t.Parallel()
+	e := sharedException()
+
+	wantError := e.Error()
+	wantJSON, err := json.Marshal(e)
+	require.NoError(t, err)
+	wantFingerprint := e.Fingerprint()
+	wantHTML := ex.ToHTML(e)
+	wantFields := ex.FieldsOf(e)
+
+	runConcurrently(func() {
+		assert.Equal(t, wantError, e.Error())
+		assert.Equal(t, len(wantError), e.Size())
+		assert.Equal(t, wantError, string(e.AppendError(nil)))
+
+		data, marshalErr := json.Marshal(e)
+		assert.NoError(t, marshalErr)
+		assert.Equal(t, wantJSON, data)
+		assert.Equal(t, wantFingerprint, e.Fingerprint())
+		assert.Equal(t, wantHTML, ex.ToHTML(e))
+
+		assert.Equal(t, wantFields, ex.FieldsOf(e))
+		v, ok := e.FieldValue("request")
+		assert.True(t, ok)
+		assert.Equal(t, "r-1", v)
+		assert.NotEmpty(t, e.StackTrace())
+		assert.Equal(t, []string{"repo.Load"}, ex.Ops(e))
+
+		_, ok = ex.Detail[ex.Help](e)
+		assert.True(t, ok)
+		assert.True(t, errors.Is(e, ex.New(ex.ExTypeUnavailable, 503, "")))
+		_, ok = ex.As(e)
+		assert.True(t, ok)
+		assert.Equal(t, ex.ExTypeApplicationFailure, ex.Compact(e).Code())
+	})
+
+	assert.Equal(t, wantError, e.Error())
+}
+
+func TestConcurrency_Derivation(t *testing.T) {
+	t.Parallel()
+	e := sharedException()
+	want, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	var n atomic.Int64
+	runConcurrently(func() {
+		i := n.Add(1)
+		key := fmt.Sprintf("worker%d", i)
+		derived := e.WithField(key, i).
+			WithFields(ex.F("request", key)).
+			WithInnerError(errors.New(key)).
+			Redacted()
+		derived = ex.WithDetail(derived, ex.Help{})
+
+		v, ok := derived.FieldValue("request")
+		assert.True(t, ok)
+		assert.Equal(t, key, v)
+		assert.Equal(t, "Request failed: "+key, derived.Error())
+	})
+
+	// None of the derivations leaked into the shared value.
+	got, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestConcurrency_HookDispatch(t *testing.T) {
+	var created atomic.Int64
+	remove := ex.AddHook(func(ex.Exception) { created.Add(1) })
+	defer remove()
+
+	// Hooks are added and removed while other goroutines create
+	// exceptions; the registered hook must see every creation.
+	runConcurrently(func() {
+		for range 100 {
+			stop := ex.AddHook(func(e ex.Exception) { _ = e.Code() })
+			_ = ex.New(ex.ExTypeIncorrectData, 400, "x")
+			stop()
+		}
+	})
+	assert.Equal(t, int64(concurrentWorkers*100), created.Load())
+}
+
+func TestConcurrency_RegistriesWhileRendering(t *testing.T) {
+	saved := ex.SensitiveKeys()
+	t.Cleanup(func() { ex.SetSensitiveKeys(saved...) })
+	e := sharedException()
+	code := ex.ExType(7201)
+
+	var n atomic.Int64
+	runConcurrently(func() {
+		i := n.Add(1)
+		if i%2 == 0 {
+			ex.SetSensitiveKeys(saved...)
+			assert.NoError(t, ex.RegisterType(code, ex.TypeInfo{Name: "Concurrent", HTTPStatus: 418}))
+			return
+		}
+		_ = ex.ToHTML(e.Redacted())
+		_, _ = ex.LookupType(code)
+		_ = ex.LogLevel(e)
+		_ = code.String()
+	})
+}
+
+func TestImmutability_DefensiveCopies(t *testing.T) {
+	input := []ex.Field{ex.F("a", 1), ex.F("b", 2)}
+	e := ex.New(ex.ExTypeIncorrectData, 400, "x").WithFields(input...).WithStack()
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	// Mutating the caller's input does not reach the exception.
+	input[0].Value = "changed"
+	v, _ := e.FieldValue("a")
+	assert.Equal(t, 1, v)
+
+	// Neither does mutating anything an accessor returns.
+	fields := e.Fields()
+	fields[1].Value = "changed"
+	v, _ = e.FieldValue("b")
+	assert.Equal(t, 2, v)
+
+	ofChain := ex.FieldsOf(e)
+	ofChain[0].Value = "changed"
+	v, _ = e.FieldValue("a")
+	assert.Equal(t, 1, v)
+
+	for _, exc := range []ex.Exception{e, decoded} {
+		frames := exc.StackTrace()
+		require.NotEmpty(t, frames)
+		want := frames[0].Function
+		frames[0].Function = "changed"
+		assert.Equal(t, want, exc.StackTrace()[0].Function)
+	}
+
+	annotated := ex.AnnotateOp(e, "op")
+	ops := ex.Ops(annotated)
+	ops[0] = "changed"
+	assert.Equal(t, []string{"op"}, ex.Ops(annotated))
+}
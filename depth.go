@@ -0,0 +1,112 @@
+package ex
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// FieldCollapsed is the field, set to true, that marks where SetMaxChainDepth
+// collapsed the middle of a chain.
+const FieldCollapsed = "ex.collapsed"
+
+// minChainDepth is the smallest limit SetMaxChainDepth accepts: the new
+// exception, the collapse marker, and the root cause.
+const minChainDepth = 3
+
+// maxChainDepth is the chain depth limit; zero means none. See
+// SetMaxChainDepth.
+var maxChainDepth atomic.Int32
+
+// SetMaxChainDepth bounds the length of the chains built by WithInnerError,
+// WrapAuto, WrapKeepStatus, Newf, and the Factory wrappers, counted in
+// errors.Unwrap steps from the new exception to the root cause. Wrapping
+// an error whose chain would exceed n levels keeps the outermost levels
+// and the root cause, and drops the levels in between, so a retry loop
+// that keeps re-wrapping the same error cannot hold on to an unbounded
+// chain. The root cause is kept as it was, message, fingerprint, and
+// identity for errors.Is included, under an annotation (see Annotate)
+// with FieldCollapsed set to true.
+//
+// Levels other than exceptions and annotations cannot be re-linked, so the
+// collapse starts at the first of them if it is nearer the top. Errors
+// that wrap several (errors.Join) count as one level, as the root cause.
+// n is raised to 3 if lower; zero, the default, turns the limit off.
+func SetMaxChainDepth(n int) {
+	if n > 0 {
+		n = max(n, minChainDepth)
+	}
+	maxChainDepth.Store(int32(max(n, 0)))
+}
+
+// MaxChainDepth returns the chain depth limit set with SetMaxChainDepth,
+// or zero.
+func MaxChainDepth() int {
+	return int(maxChainDepth.Load())
+}
+
+// limitDepth returns err, collapsed if needed so that an exception
+// wrapping it stays within the depth limit.
+func limitDepth(err error) error {
+	n := int(maxChainDepth.Load())
+	if n == 0 || err == nil {
+		return err
+	}
+	// levels holds err's chain, outermost first; the new exception takes
+	// one more level.
+	var levels []error
+	for cur := err; cur != nil; {
+		levels = append(levels, cur)
+		if len(levels) > n {
+			break
+		}
+		u, ok := cur.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		cur = u.Unwrap()
+	}
+	if len(levels) < n {
+		return err
+	}
+	root := levels[len(levels)-1]
+	for next := errors.Unwrap(root); next != nil; next = errors.Unwrap(root) {
+		root = next
+	}
+	// Keep the outermost levels that can be re-linked, leaving room for
+	// the marker and the root.
+	keep := 0
+	for keep < n-minChainDepth && isRelinkable(levels[keep]) {
+		keep++
+	}
+	cur := error(&annotation{err: root, fields: []Field{F(FieldCollapsed, true)}})
+	for i := keep - 1; i >= 0; i-- {
+		cur = relink(levels[i], cur)
+	}
+	return cur
+}
+
+// isRelinkable reports whether relink can change what err wraps.
+func isRelinkable(err error) bool {
+	switch err.(type) {
+	case Exception, *annotation:
+		return true
+	}
+	return false
+}
+
+// relink returns a copy of level, an Exception or annotation, wrapping
+// inner instead.
+func relink(level, inner error) error {
+	switch v := level.(type) {
+	case Exception:
+		v.innerError = inner
+		v.sizeHint = sizeHintOf(v.message, inner)
+		v.frozen = nil
+		return v
+	case *annotation:
+		a := *v
+		a.err = inner
+		return &a
+	}
+	return level
+}
@@ -0,0 +1,117 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+// setMaxChainDepth sets the chain depth limit for the duration of t.
+func setMaxChainDepth(t *testing.T, n int) {
+	t.Helper()
+	prev := ex.MaxChainDepth()
+	ex.SetMaxChainDepth(n)
+	t.Cleanup(func() { ex.SetMaxChainDepth(prev) })
+}
+
+// chainDepth counts the errors.Unwrap steps from err to its root cause.
+func chainDepth(err error) int {
+	n := 1
+	for err = errors.Unwrap(err); err != nil; err = errors.Unwrap(err) {
+		n++
+	}
+	return n
+}
+
+func TestSetMaxChainDepth_OffByDefault(t *testing.T) {
+	assert.Equal(t, 0, ex.MaxChainDepth())
+
+	var err error = ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable")
+	for range 50 {
+		err = ex.WrapAuto(err, 5001, "retrying")
+	}
+	assert.Equal(t, 51, chainDepth(err))
+}
+
+func TestSetMaxChainDepth_Clamps(t *testing.T) {
+	setMaxChainDepth(t, 1)
+	assert.Equal(t, 3, ex.MaxChainDepth())
+
+	ex.SetMaxChainDepth(-1)
+	assert.Equal(t, 0, ex.MaxChainDepth())
+}
+
+func TestSetMaxChainDepth_CollapsesRetryLoop(t *testing.T) {
+	setMaxChainDepth(t, 6)
+	root := ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable").WithField("host", "db1")
+
+	var err error = root
+	var last ex.Exception
+	var prints []string
+	for range 20 {
+		last = ex.WrapAuto(err, 5001, "retrying")
+		prints = append(prints, last.Fingerprint())
+		err = last
+	}
+
+	assert.Equal(t, 6, chainDepth(err))
+	assert.ErrorIs(t, err, root)
+	assert.True(t, ex.IsUnavailable(err))
+	fields := map[string]any{}
+	for _, f := range ex.FieldsOf(err) {
+		fields[f.Key] = f.Value
+	}
+	assert.Equal(t, map[string]any{ex.FieldCollapsed: true, "host": "db1"}, fields)
+
+	var inner ex.Exception
+	for cur := errors.Unwrap(err); cur != nil; cur = errors.Unwrap(cur) {
+		if e, ok := cur.(ex.Exception); ok {
+			inner = e
+		}
+	}
+	assert.Equal(t, root.Message(), inner.Message())
+	assert.Equal(t, root.Fingerprint(), inner.Fingerprint())
+
+	// Once collapsing, each further wrap has the same shape.
+	assert.Equal(t, prints[len(prints)-2], prints[len(prints)-1])
+	assert.Equal(t, len(last.Error()), last.Size())
+}
+
+func TestSetMaxChainDepth_KeepsOutermostLevels(t *testing.T) {
+	setMaxChainDepth(t, 5)
+	var err error = fs.ErrNotExist
+	for i := range 6 {
+		err = ex.New(ex.ExTypeApplicationFailure, 5000+i, fmt.Sprintf("level %d", i)).WithInnerError(err)
+	}
+	e := ex.New(ex.ExTypeApplicationFailure, 5010, "top").WithInnerError(err)
+
+	assert.Equal(t, "top: level 5: level 4: file does not exist", e.Error())
+	assert.Equal(t, 5, chainDepth(e))
+	assert.ErrorIs(t, e, fs.ErrNotExist)
+}
+
+func TestSetMaxChainDepth_StopsAtForeignLevel(t *testing.T) {
+	setMaxChainDepth(t, 5)
+	var err error = fs.ErrNotExist
+	for i := range 6 {
+		err = ex.New(ex.ExTypeApplicationFailure, 5000+i, fmt.Sprintf("level %d", i)).WithInnerError(err)
+	}
+	err = fmt.Errorf("foreign: %w", err)
+	e := ex.New(ex.ExTypeApplicationFailure, 5010, "top").WithInnerError(err)
+
+	assert.Equal(t, "top: file does not exist", e.Error())
+	assert.Equal(t, 3, chainDepth(e))
+	assert.ErrorIs(t, e, fs.ErrNotExist)
+}
+
+func TestSetMaxChainDepth_ShallowChainsUntouched(t *testing.T) {
+	setMaxChainDepth(t, 5)
+	inner := ex.AnnotateOp(fmt.Errorf("dial: %w", fs.ErrPermission), "connect")
+	e := ex.WrapKeepStatus(inner, ex.ExTypeUnavailable, 5031, "Database unreachable")
+
+	assert.Equal(t, inner, e.InnerError())
+}
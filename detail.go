@@ -0,0 +1,156 @@
+package ex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrDuplicateDetail is returned by RegisterDetail when the name or the
+// type is already registered to something else.
+var ErrDuplicateDetail = errors.New("ex: duplicate detail registration")
+
+// detailRegistry binds detail type names to Go types in both directions.
+type detailRegistry struct {
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+var (
+	detailsMu sync.Mutex
+	// detailTypes is published copy-on-write; lookups do not lock.
+	detailTypes atomic.Pointer[detailRegistry]
+)
+
+// RegisterDetail binds the detail type T to name, the discriminator it is
+// encoded under, and lets decoding rebuild values of T from it. Register
+// every detail type a service sends or expects to receive during
+// initialization:
+//
+//	type PreconditionFailure struct {
+//	    Violations []string `json:"violations"`
+//	}
+//
+//	func init() { ex.RegisterDetail[PreconditionFailure]("acme.PreconditionFailure") }
+//
+// Re-registering the same binding is harmless; binding either the name or
+// T to something else returns ErrDuplicateDetail.
+func RegisterDetail[T any](name string) error {
+	typ := reflect.TypeFor[T]()
+	detailsMu.Lock()
+	defer detailsMu.Unlock()
+	cur := detailTypes.Load()
+	if cur == nil {
+		cur = &detailRegistry{}
+	}
+	if bound, ok := cur.byName[name]; ok {
+		if bound == typ {
+			return nil
+		}
+		return fmt.Errorf("%w: name %q is bound to %s", ErrDuplicateDetail, name, bound)
+	}
+	if bound, ok := cur.byType[typ]; ok {
+		return fmt.Errorf("%w: %s is bound to %q", ErrDuplicateDetail, typ, bound)
+	}
+	next := &detailRegistry{
+		byName: make(map[string]reflect.Type, len(cur.byName)+1),
+		byType: make(map[reflect.Type]string, len(cur.byType)+1),
+	}
+	for k, v := range cur.byName {
+		next.byName[k] = v
+	}
+	for k, v := range cur.byType {
+		next.byType[k] = v
+	}
+	next.byName[name] = typ
+	next.byType[typ] = name
+	detailTypes.Store(next)
+	return nil
+}
+
+// detailName returns the discriminator for typ: its registered name, or
+// its Go type name if it has none.
+func detailName(typ reflect.Type) string {
+	if reg := detailTypes.Load(); reg != nil {
+		if name, ok := reg.byType[typ]; ok {
+			return name
+		}
+	}
+	return typ.String()
+}
+
+// WithDetail returns a copy of e carrying d as a typed detail payload: a
+// machine-actionable description of the failure, in the manner of gRPC
+// error details. An exception holds at most one detail per type; adding
+// another replaces it.
+//
+//	err := ex.WithDetail(ex.New(ex.ExTypeIncorrectData, 4001, "Precondition failed"),
+//	    PreconditionFailure{Violations: []string{"terms not accepted"}})
+//
+// Details are encoded in a "details" object keyed by the name given to
+// RegisterDetail, or by the Go type name for unregistered types. On
+// decode, details with a registered name are rebuilt as their type; the
+// rest keep the generic encoding/json form and are re-encoded unchanged.
+//
+// Like field values, details are stored as given and returned by Detail
+// without a deep copy; treat them as read-only.
+func WithDetail[T any](e Exception, d T) Exception {
+	name := detailName(reflect.TypeFor[T]())
+	details := make([]Field, len(e.details), len(e.details)+1)
+	copy(details, e.details)
+	if i := indexField(details, name); i >= 0 {
+		details[i].Value = d
+	} else {
+		details = append(details, Field{Key: name, Value: d})
+	}
+	e.details = details
+	e.frozen = nil
+	return e
+}
+
+// Detail returns the detail of type T from the first exception in err's
+// chain that carries one.
+func Detail[T any](err error) (T, bool) {
+	name := detailName(reflect.TypeFor[T]())
+	for err != nil {
+		if e, ok := err.(Exception); ok {
+			if i := indexField(e.details, name); i >= 0 {
+				if d, isT := e.details[i].Value.(T); isT {
+					return d, true
+				}
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	var zero T
+	return zero, false
+}
+
+// decodeDetails rebuilds decoded details, converting those with a
+// registered name to their type. A detail that does not fit its type is
+// kept in its generic form rather than failing the whole decode.
+func decodeDetails(m map[string]any) []Field {
+	details := mapFields(m)
+	reg := detailTypes.Load()
+	if reg == nil {
+		return details
+	}
+	for i, d := range details {
+		typ, ok := reg.byName[d.Key]
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(d.Value)
+		if err != nil {
+			continue
+		}
+		v := reflect.New(typ)
+		if json.Unmarshal(data, v.Interface()) == nil {
+			details[i].Value = v.Elem().Interface()
+		}
+	}
+	return details
+}
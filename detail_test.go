@@ -0,0 +1,89 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type preconditionFailure struct {
+	Violations []string `json:"violations"`
+}
+
+type retryInfo struct {
+	DelaySeconds int `json:"delay_seconds"`
+}
+
+func init() {
+	if err := ex.RegisterDetail[preconditionFailure]("test.PreconditionFailure"); err != nil {
+		panic(err)
+	}
+}
+
+func TestDetail_AttachAndRead(t *testing.T) {
+	base := ex.New(ex.ExTypeIncorrectData, 4001, "Precondition failed")
+	e := ex.WithDetail(base, preconditionFailure{Violations: []string{"terms"}})
+	e = ex.WithDetail(e, preconditionFailure{Violations: []string{"terms", "age"}})
+
+	got, ok := ex.Detail[preconditionFailure](fmt.Errorf("handler: %w", e))
+	require.True(t, ok)
+	assert.Equal(t, []string{"terms", "age"}, got.Violations)
+
+	// The receiver is unchanged and other types are absent.
+	_, ok = ex.Detail[preconditionFailure](base)
+	assert.False(t, ok)
+	_, ok = ex.Detail[retryInfo](e)
+	assert.False(t, ok)
+	_, ok = ex.Detail[retryInfo](nil)
+	assert.False(t, ok)
+}
+
+func TestDetail_JSONRoundTrip(t *testing.T) {
+	e := ex.WithDetail(ex.New(ex.ExTypeIncorrectData, 4001, "Precondition failed"),
+		preconditionFailure{Violations: []string{"terms"}})
+	e = ex.WithDetail(e, retryInfo{DelaySeconds: 3})
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code":1,"type":"IncorrectData","id":4001,"message":"Precondition failed",
+		"details":{"ex_test.retryInfo":{"delay_seconds":3},"test.PreconditionFailure":{"violations":["terms"]}}}`,
+		string(data))
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	got, ok := ex.Detail[preconditionFailure](decoded)
+	require.True(t, ok)
+	assert.Equal(t, []string{"terms"}, got.Violations)
+
+	// Unregistered details survive re-encoding in their generic form.
+	_, ok = ex.Detail[retryInfo](decoded)
+	assert.False(t, ok)
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Contains(t, string(again), `"ex_test.retryInfo":{"delay_seconds":3}`)
+}
+
+func TestRegisterDetail_Conflicts(t *testing.T) {
+	require.NoError(t, ex.RegisterDetail[preconditionFailure]("test.PreconditionFailure"))
+	assert.ErrorIs(t, ex.RegisterDetail[retryInfo]("test.PreconditionFailure"), ex.ErrDuplicateDetail)
+	assert.ErrorIs(t, ex.RegisterDetail[preconditionFailure]("test.Other"), ex.ErrDuplicateDetail)
+}
+
+func TestStandardDetails_RoundTrip(t *testing.T) {
+	e := ex.WithDetail(ex.New(ex.ExTypeIncorrectData, 4001, "Validation failed"),
+		ex.BadRequest{FieldViolations: []ex.BadField{{Field: "email", Description: "invalid"}}})
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"ex.BadRequest":{"field_violations":[{"field":"email","description":"invalid"}]}`)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	br, ok := ex.Detail[ex.BadRequest](decoded)
+	require.True(t, ok)
+	assert.Equal(t, "email", br.FieldViolations[0].Field)
+}
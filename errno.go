@@ -0,0 +1,93 @@
+//go:build !plan9
+
+package ex
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+)
+
+// ErrnoField is the field under which FromErrno records the OS error
+// number, so that Errno still finds it after the exception has been
+// serialized and the syscall.Errno itself has been reduced to a message.
+const ErrnoField = "errno"
+
+// FromErrno converts an OS error number into an Exception, for system
+// tooling that receives errno values from the kernel or from a C library
+// but reports failures with ex:
+//
+//	if errno != 0 {
+//	    return ex.FromErrno(errno)
+//	}
+//
+// The ID is the error number and the code follows its meaning: the
+// fs.ErrNotExist family becomes ExTypeNotFound, fs.ErrPermission
+// ExTypePermissionDenied, fs.ErrExist ExTypeConflict, EINVAL
+// ExTypeIncorrectData, ETIMEDOUT ExTypeTimeout, and temporary conditions
+// and refused connections ExTypeUnavailable; anything else is
+// ExTypeApplicationFailure.
+//
+// errno is kept as the inner error, and the exception has no message of
+// its own, so Error() is the system's description and errors.Is checks
+// against fs.ErrNotExist and the like keep working. Like New, FromErrno
+// follows the stack policy and runs the hooks.
+func FromErrno(errno syscall.Errno) Exception {
+	code := errnoCode(errno)
+	e := Exception{
+		code:       code,
+		id:         int(errno),
+		innerError: errno,
+		fields:     []Field{{Key: ErrnoField, Value: int(errno)}},
+		stack:      autoStack(code, int(errno), 1),
+		sizeHint:   sizeHintOf("", errno),
+	}
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// errnoCode picks the code FromErrno gives errno.
+func errnoCode(errno syscall.Errno) ExType {
+	switch {
+	case errno.Is(fs.ErrNotExist):
+		return ExTypeNotFound
+	case errno.Is(fs.ErrPermission):
+		return ExTypePermissionDenied
+	case errno.Is(fs.ErrExist):
+		return ExTypeConflict
+	case errno == syscall.EINVAL:
+		return ExTypeIncorrectData
+	case errno == syscall.ETIMEDOUT:
+		return ExTypeTimeout
+	case errno.Temporary(), errno == syscall.ECONNREFUSED:
+		return ExTypeUnavailable
+	default:
+		return ExTypeApplicationFailure
+	}
+}
+
+// Errno returns the OS error number in err's chain: a syscall.Errno
+// wrapped anywhere in it, as in an *fs.PathError, or failing that the
+// number FromErrno recorded on an exception that has since been decoded
+// from JSON.
+func Errno(err error) (syscall.Errno, bool) {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return errno, true
+	}
+	for err != nil {
+		if e, ok := err.(Exception); ok {
+			if v, found := e.FieldValue(ErrnoField); found {
+				switch n := v.(type) {
+				case int:
+					return syscall.Errno(n), true
+				case float64:
+					return syscall.Errno(n), true
+				}
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return 0, false
+}
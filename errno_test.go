@@ -0,0 +1,79 @@
+//go:build !plan9
+
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromErrno(t *testing.T) {
+	tests := []struct {
+		errno syscall.Errno
+		want  ex.ExType
+	}{
+		{syscall.ENOENT, ex.ExTypeNotFound},
+		{syscall.EACCES, ex.ExTypePermissionDenied},
+		{syscall.EEXIST, ex.ExTypeConflict},
+		{syscall.EINVAL, ex.ExTypeIncorrectData},
+		{syscall.ETIMEDOUT, ex.ExTypeTimeout},
+		{syscall.EAGAIN, ex.ExTypeUnavailable},
+		{syscall.ECONNREFUSED, ex.ExTypeUnavailable},
+		{syscall.ENOSPC, ex.ExTypeApplicationFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.errno.Error(), func(t *testing.T) {
+			e := ex.FromErrno(tt.errno)
+			assert.Equal(t, tt.want, e.Code())
+			assert.Equal(t, int(tt.errno), e.ID())
+			assert.Equal(t, tt.errno.Error(), e.Error())
+			assert.Len(t, e.Error(), e.Size())
+		})
+	}
+
+	e := ex.FromErrno(syscall.ENOENT)
+	assert.True(t, errors.Is(e, fs.ErrNotExist))
+	assert.True(t, errors.Is(e, syscall.ENOENT))
+}
+
+func TestErrno(t *testing.T) {
+	errno, ok := ex.Errno(ex.New(ex.ExTypeApplicationFailure, 500, "Sync failed").
+		WithInnerError(ex.FromErrno(syscall.ENOSPC)))
+	require.True(t, ok)
+	assert.Equal(t, syscall.ENOSPC, errno)
+
+	// An errno wrapped by the standard library is found too.
+	_, err := os.Open("/definitely/not/here")
+	errno, ok = ex.Errno(fmt.Errorf("load: %w", err))
+	require.True(t, ok)
+	assert.Equal(t, syscall.ENOENT, errno)
+
+	_, ok = ex.Errno(ex.New(ex.ExTypeApplicationFailure, 500, "x"))
+	assert.False(t, ok)
+	_, ok = ex.Errno(nil)
+	assert.False(t, ok)
+}
+
+func TestErrno_AfterRoundTrip(t *testing.T) {
+	data, err := json.Marshal(ex.New(ex.ExTypeApplicationFailure, 500, "Sync failed").
+		WithInnerError(ex.FromErrno(syscall.EACCES)))
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	errno, ok := ex.Errno(decoded)
+	require.True(t, ok)
+	assert.Equal(t, syscall.EACCES, errno)
+	inner, ok := ex.As(decoded.InnerError())
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypePermissionDenied, inner.Code())
+}
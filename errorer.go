@@ -0,0 +1,34 @@
+package ex
+
+import "errors"
+
+// Errorer is the read surface of an Exception. Exception implements it,
+// and every helper in this module that inspects a chain (predicates,
+// TypeInfoOf, FieldsOf, the JSON encoding, ToHTML, and the adapters in the
+// subpackages) accepts any Errorer in its place, so test doubles and
+// alternative implementations, such as lazily-built exceptions,
+// interoperate with them. Those that need an Exception promote the chain
+// (see Promote), which keeps each Errorer's code, ID, and message.
+//
+// StackTrace returns nil when no stack is available, and Unwrap returns
+// the next error in the chain, or nil.
+type Errorer interface {
+	error
+	Code() ExType
+	ID() int
+	Message() string
+	Fields() []Field
+	StackTrace() []Frame
+	Unwrap() error
+}
+
+// AsErrorer returns the first Errorer in err's chain, as found by
+// errors.As.
+func AsErrorer(err error) (Errorer, bool) {
+	var e Errorer
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// Compile-time check that Exception satisfies Errorer.
+var _ Errorer = Exception{}
@@ -0,0 +1,121 @@
+package ex_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeErrorer is a minimal alternative Errorer implementation, as a test
+// double would be.
+type fakeErrorer struct {
+	code   ex.ExType
+	id     int
+	msg    string
+	fields []ex.Field
+	inner  error
+}
+
+func (f fakeErrorer) Error() string          { return f.msg }
+func (f fakeErrorer) Code() ex.ExType        { return f.code }
+func (f fakeErrorer) ID() int                { return f.id }
+func (f fakeErrorer) Message() string        { return f.msg }
+func (f fakeErrorer) Fields() []ex.Field     { return f.fields }
+func (f fakeErrorer) StackTrace() []ex.Frame { return nil }
+func (f fakeErrorer) Unwrap() error          { return f.inner }
+
+func TestErrorer_Helpers(t *testing.T) {
+	fake := fakeErrorer{
+		code:   ex.ExTypePermissionDenied,
+		id:     403,
+		msg:    "Access denied",
+		fields: []ex.Field{ex.F("user", "42")},
+		inner:  errors.New("acl miss"),
+	}
+	err := fmt.Errorf("handler: %w", fake)
+
+	got, ok := ex.AsErrorer(err)
+	require.True(t, ok)
+	assert.Equal(t, 403, got.ID())
+
+	assert.True(t, ex.CodeIs(ex.ExTypePermissionDenied)(err))
+	assert.True(t, ex.IDIn(403)(err))
+	assert.Equal(t, ex.SeverityWarning, ex.SeverityOf(err))
+	id, ok := ex.IDAs[int](err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, id)
+
+	info, ok := ex.TypeInfoOf(err)
+	require.True(t, ok)
+	assert.Equal(t, 403, info.HTTPStatus)
+	assert.Equal(t, []ex.Field{ex.F("user", "42")}, ex.FieldsOf(err))
+
+	_, isErrorer := ex.AsErrorer(errors.New("plain"))
+	assert.False(t, isErrorer)
+}
+
+func TestErrorer_EncodesAsException(t *testing.T) {
+	fake := fakeErrorer{code: ex.ExTypeIncorrectData, id: 400, msg: "Bad input", fields: []ex.Field{ex.F("field", "email")}}
+	data, err := json.Marshal(ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").WithInnerError(fake))
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	var inner ex.Exception
+	require.True(t, errors.As(decoded.InnerError(), &inner))
+	assert.Equal(t, ex.ExTypeIncorrectData, inner.Code())
+	assert.Equal(t, "Bad input", inner.Message())
+	assert.Equal(t, []ex.Field{ex.F("field", "email")}, inner.Fields())
+
+	assert.Contains(t, string(ex.ToHTML(fake)), "IncorrectData / 400")
+}
+
+func TestErrorer_AuditRecord(t *testing.T) {
+	fake := fakeErrorer{code: ex.ExTypePermissionDenied, id: 403, msg: "Access denied", fields: []ex.Field{
+		ex.F(ex.AuditActorKey, "alice"), ex.F(ex.AuditActionKey, "delete"),
+	}}
+	rec, ok := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").WithInnerError(fake).AuditRecord()
+	require.True(t, ok)
+	assert.Equal(t, "alice", rec.Actor)
+	assert.Equal(t, "delete", rec.Action)
+	assert.Empty(t, rec.Resource)
+	assert.Equal(t, 403, rec.ID)
+}
+
+func TestErrorer_Promote(t *testing.T) {
+	fake := fakeErrorer{
+		code:   ex.ExTypePermissionDenied,
+		id:     403,
+		msg:    "Access denied",
+		fields: []ex.Field{ex.F("user", "42")},
+		inner:  errors.New("acl miss"),
+	}
+	promoted := ex.Promote(fmt.Errorf("handler: %w", fake), nil)
+	assert.Equal(t, ex.ExTypePermissionDenied, promoted.Code(), "wrapping levels take the Errorer's code")
+	assert.Equal(t, 403, promoted.ID())
+	assert.Equal(t, "handler", promoted.Message())
+
+	inner, ok := promoted.InnerError().(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, "Access denied", inner.Message())
+	assert.Equal(t, []ex.Field{ex.F("user", "42")}, inner.Fields())
+	assert.Equal(t, "handler: Access denied: acl miss", promoted.Error())
+
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	defer r.Shutdown(context.Background())
+	r.Report(fake)
+	require.NoError(t, r.Flush(context.Background()))
+	batches := s.all()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, ex.ExTypePermissionDenied, batches[0][0].Sample.Code())
+	assert.Equal(t, "Access denied", batches[0][0].Sample.Message())
+}
@@ -0,0 +1,104 @@
+package ex
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrorFormat selects how Exception.Error renders exceptions; set it
+// process-wide with SetErrorFormat.
+type ErrorFormat int
+
+const (
+	// FormatPlain renders messages only, joined with ": ", as in
+	// "Access denied: token expired". It is the default.
+	FormatPlain ErrorFormat = iota
+
+	// FormatTagged prefixes the plain text with the code and ID of the
+	// outermost exception in brackets, as in
+	// "[PermissionDenied/403] Access denied: token expired", so triage of
+	// plain-text logs can grep for stable identifiers instead of prose.
+	FormatTagged
+)
+
+// String returns the name of the format.
+func (f ErrorFormat) String() string {
+	if f == FormatTagged {
+		return "Tagged"
+	}
+	return "Plain"
+}
+
+// errorFormat holds the active format; see SetErrorFormat.
+var errorFormat atomic.Int32
+
+// SetErrorFormat sets the process-wide format of Exception.Error, Size,
+// and AppendError:
+//
+//	ex.SetErrorFormat(ex.FormatTagged)
+//
+// Set it once at startup. It applies to frozen exceptions (see Freeze)
+// too, even sentinels frozen before it was called, but exceptions wrapped
+// in other error types record their text as it was rendered when they
+// were wrapped.
+func SetErrorFormat(f ErrorFormat) {
+	errorFormat.Store(int32(f))
+}
+
+// CurrentErrorFormat returns the format set by SetErrorFormat.
+func CurrentErrorFormat() ErrorFormat {
+	return ErrorFormat(errorFormat.Load())
+}
+
+// tagged reports whether Error prefixes the code and ID.
+func tagged() bool {
+	return errorFormat.Load() == int32(FormatTagged)
+}
+
+// taggedError renders e under FormatTagged, allocating once.
+func (e Exception) taggedError() string {
+	size, tail, measured := e.sizeHint-1, "", false
+	if e.sizeHint == 0 {
+		size, tail = e.measure()
+		measured = true
+	}
+	var num [20]byte
+	var b strings.Builder
+	b.Grow(joinedTag(e.tagLen(), size))
+	b.Write(e.appendTag(num[:0]))
+	tagEnd := b.Len()
+	e.segments(tail, measured, func(s string) {
+		if b.Len() > tagEnd {
+			b.WriteString(": ")
+		} else {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s)
+	})
+	return b.String()
+}
+
+// appendTag appends the "[Code/ID]" prefix of FormatTagged to dst.
+func (e Exception) appendTag(dst []byte) []byte {
+	dst = append(dst, '[')
+	dst = append(dst, e.code.String()...)
+	dst = append(dst, '/')
+	dst = strconv.AppendInt(dst, int64(e.id), 10)
+	return append(dst, ']')
+}
+
+// tagLen returns the length appendTag appends.
+func (e Exception) tagLen() int {
+	n, id := len(e.code.String())+3, e.id
+	if id < 0 {
+		n++
+	}
+	for {
+		n++
+		id /= 10
+		if id == 0 {
+			return n
+		}
+	}
+}
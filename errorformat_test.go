@@ -0,0 +1,107 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+// tagErrors switches to FormatTagged for the rest of the test.
+func tagErrors(t *testing.T) {
+	t.Helper()
+	saved := ex.CurrentErrorFormat()
+	ex.SetErrorFormat(ex.FormatTagged)
+	t.Cleanup(func() { ex.SetErrorFormat(saved) })
+}
+
+func TestErrorFormat_Tagged(t *testing.T) {
+	lenient(t)
+
+	tagErrors(t)
+	assert.Equal(t, ex.FormatTagged, ex.CurrentErrorFormat())
+	assert.Equal(t, "Tagged", ex.CurrentErrorFormat().String())
+
+	inner := ex.New(ex.ExTypeUnavailable, 5031, "Token service down").WithInnerError(errors.New("dial tcp: refused"))
+	tests := []struct {
+		name string
+		e    ex.Exception
+		want string
+	}{
+		{"message only", ex.New(ex.ExTypePermissionDenied, 403, "Access denied"), "[PermissionDenied/403] Access denied"},
+		{"chain", ex.New(ex.ExTypePermissionDenied, 403, "Access denied").WithInnerError(inner),
+			"[PermissionDenied/403] Access denied: Token service down: dial tcp: refused"},
+		{"plain inner", ex.New(ex.ExTypeNotFound, 4041, "").WithInnerError(errors.New("no rows")), "[NotFound/4041] no rows"},
+		{"empty", ex.New(ex.ExTypeNotFound, 4041, ""), "[NotFound/4041]"},
+		{"negative ID", ex.New(ex.ExTypeNotFound, -7, "x"), "[NotFound/-7] x"},
+		{"large ID", ex.New(ex.ExTypeNotFound, math.MaxInt32, "x"), "[NotFound/2147483647] x"},
+		{"custom code", ex.New(ex.ExType(4651), 1, "x"), "[Unknown(4651)/1] x"},
+		{"decoded", decode(t, ex.New(ex.ExTypeConflict, 4091, "Duplicate").WithInnerError(inner)),
+			"[Conflict/4091] Duplicate: Token service down: dial tcp: refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.e.Error())
+			assert.Equal(t, len(tt.want), tt.e.Size())
+			assert.Equal(t, "prefix:"+tt.want, string(tt.e.AppendError([]byte("prefix:"))))
+		})
+	}
+}
+
+// decode round-trips e through JSON, which drops its recorded size.
+func decode(t *testing.T, e ex.Exception) ex.Exception {
+	t.Helper()
+	data, err := e.MarshalJSON()
+	assert.NoError(t, err)
+	var out ex.Exception
+	assert.NoError(t, out.UnmarshalJSON(data))
+	return out
+}
+
+func TestErrorFormat_Wrapped(t *testing.T) {
+	tagErrors(t)
+	e := ex.New(ex.ExTypePermissionDenied, 403, "Access denied")
+	assert.Equal(t, "handler: [PermissionDenied/403] Access denied", fmt.Errorf("handler: %w", e).Error())
+	assert.Equal(t, "[PermissionDenied/403] Access denied", ex.Annotate(e, ex.F("user", 7)).Error())
+
+	outer := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").WithInnerError(ex.Annotate(e, ex.F("user", 7)))
+	assert.Equal(t, "[ApplicationFailure/500] Request failed: Access denied", outer.Error(), "only the outermost level is tagged")
+	assert.Equal(t, len(outer.Error()), outer.Size())
+}
+
+// errFormatSentinel is frozen at package initialization, before any test
+// sets the error format.
+var errFormatSentinel = ex.New(ex.ExTypePermissionDenied, 403, "Access denied").Freeze()
+
+func TestErrorFormat_Frozen(t *testing.T) {
+	assert.Equal(t, "Access denied", errFormatSentinel.Error())
+
+	tagErrors(t)
+	assert.Equal(t, "[PermissionDenied/403] Access denied", errFormatSentinel.Error())
+	assert.Equal(t, len("[PermissionDenied/403] Access denied"), errFormatSentinel.Size())
+	assert.Equal(t, "[PermissionDenied/403] Access denied", string(errFormatSentinel.AppendError(nil)))
+
+	frozen := ex.New(ex.ExTypeNotFound, 4041, "Missing").Freeze()
+	ex.SetErrorFormat(ex.FormatPlain)
+	assert.Equal(t, "Missing", frozen.Error(), "frozen under FormatTagged")
+	assert.Equal(t, "Missing", frozen.WithField("k", 1).Error())
+}
+
+func TestErrorFormat_RenderBudget(t *testing.T) {
+	tagErrors(t)
+	e := ex.New(ex.ExTypePermissionDenied, 403, "Access denied")
+	var b strings.Builder
+	_, err := e.RenderBudget(&b, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "[PermissionDenied/403] Access denied", b.String())
+}
+
+func TestErrorFormat_Default(t *testing.T) {
+	assert.Equal(t, ex.FormatPlain, ex.CurrentErrorFormat())
+	assert.Equal(t, "Plain", ex.FormatPlain.String())
+	assert.Equal(t, "Access denied", ex.New(ex.ExTypePermissionDenied, 403, "Access denied").Error())
+}
@@ -1,17 +1,26 @@
 package ex
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 )
 
 // ExType is the type of exception being returned.
 //
 // The zero value (ExType(0)) is reserved and considered invalid: the
-// predefined constants begin at iota + 1. Custom codes created via
-// ExType(n) are supported for n > 0, and will be rendered by String()
-// as "Unknown(n)".
+// predefined constants begin at iota + 1, and codes up to MaxBuiltinType
+// are reserved for them, current and future. Custom codes created via
+// ExType(n) are supported for n > MaxBuiltinType, below the facet bits
+// (see FacetMask), and will be rendered by String() as "Unknown(n)"
+// unless a name is registered for them with RegisterType.
 type ExType int
 
+// MaxBuiltinType is the last code reserved for predefined types. New
+// predefined types are allocated at or below it, so custom codes above
+// it never collide with them.
+const MaxBuiltinType ExType = 99
+
 const (
 	// ExTypeIncorrectData indicates that the data is invalid, missing or conflicting
 	ExTypeIncorrectData ExType = iota + 1
@@ -24,11 +33,35 @@ const (
 
 	// ExTypeApplicationFailure indicates that the application tried to perform an action that is invalid
 	ExTypeApplicationFailure
+
+	// ExTypeTimeout indicates that an operation did not complete before its deadline
+	ExTypeTimeout
+
+	// ExTypeUnavailable indicates that a dependency or the service itself is temporarily unable to handle the request
+	ExTypeUnavailable
+
+	// ExTypeNotFound indicates that the requested resource does not exist
+	ExTypeNotFound
+
+	// ExTypeConflict indicates that the request conflicts with the current state of the resource, such as a duplicate
+	ExTypeConflict
+
+	// ExTypeRateLimited indicates that the caller exceeded a rate limit or quota; see WithRateLimit
+	ExTypeRateLimited
 )
 
 // String returns a string representation of the ExType for debugging and logging.
-// The zero value renders as "Invalid(0)". Custom codes render as "Unknown(N)".
+// The zero value renders as "Invalid(0)". Custom codes render as the Name
+// they were registered with (see RegisterType), or "Unknown(N)". Facets
+// (see FacetTransient) follow the category, as in "Timeout|Transient",
+// unless the faceted code is registered with a Name of its own.
 func (et ExType) String() string {
+	if facets := et.Facets(); facets != 0 {
+		if info, ok := (*types.Load())[et]; ok && info.Name != "" {
+			return info.Name
+		}
+		return et.Category().String() + facetString(facets)
+	}
 	switch et {
 	case 0:
 		return "Invalid(0)"
@@ -40,7 +73,20 @@ func (et ExType) String() string {
 		return "PermissionDenied"
 	case ExTypeApplicationFailure:
 		return "ApplicationFailure"
+	case ExTypeTimeout:
+		return "Timeout"
+	case ExTypeUnavailable:
+		return "Unavailable"
+	case ExTypeNotFound:
+		return "NotFound"
+	case ExTypeConflict:
+		return "Conflict"
+	case ExTypeRateLimited:
+		return "RateLimited"
 	default:
+		if info, ok := LookupType(et); ok && info.Name != "" {
+			return info.Name
+		}
 		return "Unknown(" + strconv.Itoa(int(et)) + ")"
 	}
 }
@@ -66,6 +112,63 @@ type Exception struct {
 	id         int
 	message    string
 	innerError error
+	// fields is copy-on-write: With* methods always allocate a new slice
+	// so that exceptions derived from a shared value never alias it.
+	fields []Field
+	// details holds typed detail payloads keyed by detail name; see
+	// WithDetail. Copy-on-write, like fields.
+	details []Field
+	// stack holds program counters captured by WithStack; nil when no
+	// stack was captured. Never modified after capture.
+	stack []uintptr
+	// frames holds an already-symbolized stack, set when an exception is
+	// decoded from its serialized form. Never modified after decoding.
+	frames []Frame
+	// remote is set on exceptions decoded from a serialized form, and
+	// origin holds the name of the service that created them.
+	remote bool
+	origin string
+	// trace is the attached W3C trace context, or nil. Never modified
+	// after it is set.
+	trace *TraceContext
+	// ops holds operations recorded by AnnotateOp layers that were folded
+	// into this exception when it was encoded. Never modified.
+	ops []string
+	// panicked holds the recovered value for exceptions built by
+	// FromPanic, or nil.
+	panicked *panicValue
+	// stackDump holds the goroutine dump given to FromStackDump, or "".
+	stackDump string
+	// sizeHint is the length of Error() plus one, recorded by the
+	// constructors that can compute it cheaply; zero means unknown. It
+	// must be cleared whenever message or innerError change. See Size.
+	sizeHint int
+	// taxonomy is the taxonomy version of a decoded exception; see
+	// Taxonomy.
+	taxonomy int
+	// codeString is the symbolic code set by WithCodeString, or "".
+	codeString string
+	// keepStatus makes the exception report the HTTP status and gRPC code
+	// of its inner error; see WrapKeepStatus.
+	keepStatus bool
+	// quiet keeps the exception from hooks and reporting sinks; see
+	// Quiet.
+	quiet bool
+	// expected marks the exception as part of normal control flow; see
+	// WithExpected.
+	expected bool
+	// hint and docsURL are set by WithHint and WithDocsURL.
+	hint    string
+	docsURL string
+	// frozen holds the renderings precomputed by Freeze, or nil. Every
+	// method that derives a changed exception must clear it.
+	frozen *frozenState
+	// factory is the Factory that created the exception, or nil; its
+	// type registry resolves the exception's metadata. See TypeInfoOf.
+	factory *Factory
+	// creation is the tracking record of this exception's creation, or
+	// nil when tracking was off; see SetTracking.
+	creation *creation
 	// noCmp is a zero-sized, non-comparable marker that makes the
 	// surrounding struct non-comparable. Do not remove — see the type
 	// doc above for why this matters for errors.Is panic safety.
@@ -74,11 +177,13 @@ type Exception struct {
 
 // Code is a read-only property for the exception type code
 func (e Exception) Code() ExType {
+	e.creation.observe()
 	return e.code
 }
 
 // ID is a read-only property for the exception id
 func (e Exception) ID() int {
+	e.creation.observe()
 	return e.id
 }
 
@@ -94,9 +199,17 @@ func (e Exception) InnerError() error {
 
 // WithInnerError returns a new Exception with the specified inner error.
 // This method creates a copy of the current Exception, preserving immutability.
-// The inner error can be nil to clear any existing inner error.
+// The inner error can be nil to clear any existing inner error. Builds
+// with the exstrict tag panic if err is e itself, or nil with nothing to
+// clear.
 func (e Exception) WithInnerError(err error) Exception {
-	e.innerError = err
+	if buildStrict {
+		checkInner(e, err)
+	}
+	observeErr(err)
+	e.innerError = limitDepth(err)
+	e.sizeHint = sizeHintOf(e.message, e.innerError)
+	e.frozen = nil
 	return e
 }
 
@@ -108,23 +221,102 @@ func (e Exception) WithInnerError(err error) Exception {
 //   - If the message is empty but an inner error is present, the inner
 //     error's Error() string is returned.
 //   - Otherwise the message alone is returned.
+//
+// Under FormatTagged (see SetErrorFormat), the text is prefixed with the
+// code and ID in brackets and a space, or is just the brackets if empty.
 func (e Exception) Error() string {
-	innerMsg := ""
-	if e.innerError != nil {
-		innerMsg = e.innerError.Error()
+	e.creation.observe()
+	if e.frozen != nil {
+		return e.frozen.text()
 	}
+	if tagged() {
+		return e.taggedError()
+	}
+	return e.plainError()
+}
 
-	switch {
-	case e.message == "":
-		// innerMsg is "" when innerError is nil OR innerError.Error() == "";
-		// either way, returning it avoids a leading-colon bug and an empty
-		// inner is indistinguishable from no inner at the Error() layer.
-		return innerMsg
-	case innerMsg != "":
-		return e.message + ": " + innerMsg
-	default:
+// plainError renders e under FormatPlain.
+func (e Exception) plainError() string {
+	if e.innerError == nil {
 		return e.message
 	}
+	if _, ok := nextLevel(e.innerError); !ok {
+		// A single level, the common case: at most one concatenation.
+		innerMsg := e.innerError.Error()
+		switch {
+		case e.message == "":
+			return innerMsg
+		case innerMsg != "":
+			return e.message + ": " + innerMsg
+		default:
+			return e.message
+		}
+	}
+	return e.chainError()
+}
+
+// chainError renders a chain of nested Exceptions in one pass. Applying
+// the formatting rules level by level amounts to joining the non-empty
+// messages, followed by the non-empty text of the first non-Exception
+// error, with ": ". The buffer is sized up front from the recorded length
+// (see Size), or by measuring the chain when there is none, so rendering
+// allocates once however deep the chain is, instead of once per level
+// with quadratic copying.
+func (e Exception) chainError() string {
+	size, tail, measured := e.sizeHint-1, "", false
+	if e.sizeHint == 0 {
+		size, tail = e.measure()
+		measured = true
+	}
+
+	var b strings.Builder
+	b.Grow(size)
+	e.segments(tail, measured, func(s string) {
+		if b.Len() > 0 {
+			b.WriteString(": ")
+		}
+		b.WriteString(s)
+	})
+	return b.String()
+}
+
+// segments calls write with each non-empty message of e's chain in turn,
+// then with the text of the first non-Exception error if it is not empty.
+// When tailKnown is set that text is tail, as returned by measure;
+// otherwise it is obtained from the error.
+func (e Exception) segments(tail string, tailKnown bool, write func(string)) {
+	cur := e
+	for {
+		if cur.message != "" {
+			write(cur.message)
+		}
+		next, ok := nextLevel(cur.innerError)
+		if !ok {
+			break
+		}
+		cur = next
+	}
+	if !tailKnown && cur.innerError != nil {
+		tail = cur.innerError.Error()
+	}
+	if tail != "" {
+		write(tail)
+	}
+}
+
+// nextLevel returns the Exception err renders as, looking through
+// annotations, which render as the error they wrap.
+func nextLevel(err error) (Exception, bool) {
+	for {
+		switch v := err.(type) {
+		case Exception:
+			return v, true
+		case *annotation:
+			err = v.err
+		default:
+			return Exception{}, false
+		}
+	}
 }
 
 // Unwrap returns the inner error for errors.Is and errors.As compatibility.
@@ -140,16 +332,48 @@ func (e Exception) Unwrap() error {
 //
 //   - If target is an Exception, Is returns true only when both the Code
 //     and ID match. This treats (Code, ID) as the exception's identity.
-//   - If target is any other error, Is returns false here and lets
-//     errors.Is continue walking the wrapped chain via Unwrap.
+//   - If target is any other error, Is returns true only when target is
+//     aliased to the exception's Code and ID (see RegisterIsAlias), and
+//     otherwise lets errors.Is continue walking the wrapped chain via
+//     Unwrap.
 func (e Exception) Is(target error) bool {
+	e.creation.observe()
 	t, ok := target.(Exception)
 	if !ok {
-		return false
+		return isAlias(e.code, e.id, target)
 	}
 	return e.code == t.code && e.id == t.id
 }
 
+// As supports errors.As with a *Exception target, which would otherwise
+// never match because the chain holds Exception values, not pointers:
+//
+//	var p *ex.Exception
+//	if errors.As(err, &p) { ... }
+//
+// Targets of type *Exception match through the standard assignability
+// rule and never reach this method.
+func (e Exception) As(target any) bool {
+	p, ok := target.(**Exception)
+	if !ok {
+		return false
+	}
+	e.creation.observe()
+	*p = &e
+	return true
+}
+
+// As returns the first Exception in err's chain. It saves declaring a
+// target for errors.As and sidesteps the value-versus-pointer question:
+//
+//	if e, ok := ex.As(err); ok { ... }
+func As(err error) (Exception, bool) {
+	var e Exception
+	ok := errors.As(err, &e)
+	e.creation.observe()
+	return e, ok
+}
+
 // New creates an exception with the specified code, ID, and message.
 //
 // The code should be a predefined ExType constant or a custom ExType(n)
@@ -158,8 +382,39 @@ func (e Exception) Is(target error) bool {
 //
 // The ID is typically an HTTP status code or application-specific error
 // code. The message should be a human-readable description of the error.
+//
+// Every registered Hook is called with the new Exception before it is
+// returned; see AddHook. New captures a stack only if the stack policy
+// asks for one; see SetStackPolicy. In strict mode New panics on unknown
+// codes; see SetStrict. Creation tracking, when on, records New's caller;
+// see SetTracking.
 func New(code ExType, id int, message string) Exception {
-	return Exception{code: code, id: id, message: message, innerError: nil}
+	checkNew(code, id, message)
+	e := Exception{code: code, id: id, message: message, stack: autoStack(code, id, 1), sizeHint: len(message) + 1}
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// Fast creates an exception like New but does nothing else: no hooks run,
+// no stack is captured whatever the stack policy, and creation tracking
+// does not see it. It never allocates, which suits per-request hot loops
+// such as validators and parsers that reject many inputs and handle the
+// failures locally:
+//
+//	for _, row := range rows {
+//	    if row.Qty < 0 {
+//	        errs = append(errs, ex.Fast(ex.ExTypeIncorrectData, 4001, "Negative quantity"))
+//	    }
+//	}
+//
+// Because hooks do not run, exceptions made with Fast are invisible to
+// metrics, reporters, and anything else fed by AddHook; use New for
+// errors that leave the hot loop. Strict mode still applies; see
+// SetStrict.
+func Fast(code ExType, id int, message string) Exception {
+	checkNew(code, id, message)
+	return Exception{code: code, id: id, message: message, sizeHint: len(message) + 1}
 }
 
 // Compile-time checks that Exception satisfies the standard error interfaces.
@@ -167,4 +422,5 @@ var (
 	_ error                       = Exception{}
 	_ interface{ Unwrap() error } = Exception{}
 	_ interface{ Is(error) bool } = Exception{}
+	_ interface{ As(any) bool }   = Exception{}
 )
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/bold-minds/ex"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var exceptionTestsCases = []struct {
@@ -51,7 +53,10 @@ func Test_NewExWithInnerEx(t *testing.T) {
 	for _, testCase := range exceptionTestsCases {
 
 		e := ex.New(testCase.errCode, testCase.errID, testCase.errMsg)
-		e = e.WithInnerError(testCase.innerErr)
+		if testCase.innerErr != nil {
+			// exstrict builds reject WithInnerError(nil) with nothing to clear.
+			e = e.WithInnerError(testCase.innerErr)
+		}
 
 		if e.Code() != testCase.errCode {
 			t.Errorf("Error code not correct: expected %d; got %d", testCase.errCode, e.Code())
@@ -231,6 +236,31 @@ func TestExType_String(t *testing.T) {
 			exType:   ex.ExTypeApplicationFailure,
 			expected: "ApplicationFailure",
 		},
+		{
+			name:     "Timeout",
+			exType:   ex.ExTypeTimeout,
+			expected: "Timeout",
+		},
+		{
+			name:     "Unavailable",
+			exType:   ex.ExTypeUnavailable,
+			expected: "Unavailable",
+		},
+		{
+			name:     "NotFound",
+			exType:   ex.ExTypeNotFound,
+			expected: "NotFound",
+		},
+		{
+			name:     "Conflict",
+			exType:   ex.ExTypeConflict,
+			expected: "Conflict",
+		},
+		{
+			name:     "RateLimited",
+			exType:   ex.ExTypeRateLimited,
+			expected: "RateLimited",
+		},
 		{
 			name:     "Unknown type",
 			exType:   ex.ExType(999),
@@ -246,6 +276,8 @@ func TestExType_String(t *testing.T) {
 }
 
 func TestExTypeCasting(t *testing.T) {
+	lenient(t)
+
 	// Test that users can cast any int to ExType
 	customCode42 := ex.ExType(42)
 	customCode999 := ex.ExType(999)
@@ -344,6 +376,65 @@ func TestException_LongChainTraversal(t *testing.T) {
 	assert.Equal(t, 11, depth)
 }
 
+func TestException_ErrorDeepChain(t *testing.T) {
+	// Empty messages at any level, an empty root, and annotations must
+	// render exactly as the level-by-level rules describe.
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "all levels",
+			err: ex.New(ex.ExTypeApplicationFailure, 1, "a").
+				WithInnerError(ex.New(ex.ExTypeApplicationFailure, 2, "b").
+					WithInnerError(ex.New(ex.ExTypeApplicationFailure, 3, "c").
+						WithInnerError(errors.New("root")))),
+			want: "a: b: c: root",
+		},
+		{
+			name: "empty middle and root",
+			err: ex.New(ex.ExTypeApplicationFailure, 1, "a").
+				WithInnerError(ex.New(ex.ExTypeApplicationFailure, 2, "").
+					WithInnerError(ex.New(ex.ExTypeApplicationFailure, 3, "c").
+						WithInnerError(errors.New("")))),
+			want: "a: c",
+		},
+		{
+			name: "empty outer",
+			err: ex.New(ex.ExTypeApplicationFailure, 1, "").
+				WithInnerError(ex.New(ex.ExTypeApplicationFailure, 2, "b")),
+			want: "b",
+		},
+		{
+			name: "annotated level",
+			err: ex.New(ex.ExTypeApplicationFailure, 1, "a").
+				WithInnerError(ex.Annotate(ex.New(ex.ExTypeApplicationFailure, 2, "b").
+					WithInnerError(errors.New("root")), ex.F("k", "v"))),
+			want: "a: b: root",
+		},
+		{
+			name: "plain wrapper in the middle",
+			err: ex.New(ex.ExTypeApplicationFailure, 1, "a").
+				WithInnerError(fmt.Errorf("mid: %w", ex.New(ex.ExTypeApplicationFailure, 2, "b"))),
+			want: "a: mid: b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Error())
+		})
+	}
+
+	var deep error = errors.New("root") //nolint:staticcheck // ST1023: required for later interface reassignment
+	want := "root"
+	for range 50 {
+		deep = ex.New(ex.ExTypeApplicationFailure, 500, "level").WithInnerError(deep)
+		want = "level: " + want
+	}
+	assert.Equal(t, want, deep.Error())
+}
+
 func TestException_ConcurrentUse(t *testing.T) {
 	t.Parallel()
 	// Exception is an immutable value type; concurrent reads and
@@ -388,6 +479,32 @@ func TestErrorsAs_WrongTargetType(t *testing.T) {
 	assert.Nil(t, stdTarget, "target should remain nil when As returns false")
 }
 
+func TestErrorsAs_PointerTarget(t *testing.T) {
+	exc := ex.New(ex.ExTypeIncorrectData, 400, "bad")
+	wrapped := fmt.Errorf("handler: %w", exc)
+
+	var p *ex.Exception
+	require.True(t, errors.As(wrapped, &p))
+	require.NotNil(t, p)
+	assert.Equal(t, 400, p.ID())
+
+	p = nil
+	assert.False(t, errors.As(errors.New("plain"), &p))
+	assert.Nil(t, p)
+}
+
+func TestAs(t *testing.T) {
+	inner := ex.New(ex.ExTypeIncorrectData, 400, "bad")
+	got, ok := ex.As(fmt.Errorf("handler: %w", inner))
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeIncorrectData, got.Code())
+
+	_, ok = ex.As(errors.New("plain"))
+	assert.False(t, ok)
+	_, ok = ex.As(nil)
+	assert.False(t, ok)
+}
+
 // customNonChainError is a type never inserted into any Exception chain,
 // used to verify the errors.As failure path.
 type customNonChainError struct{}
@@ -412,6 +529,7 @@ func TestException_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("WithInnerError with nil", func(t *testing.T) {
+		skipExstrict(t)
 		exc := ex.New(ex.ExTypeIncorrectData, 400, "Test message")
 		excWithNil := exc.WithInnerError(nil)
 		assert.Nil(t, excWithNil.InnerError())
@@ -473,3 +591,23 @@ func TestException_IsDoesNotPanicOnNonComparableInner(t *testing.T) {
 	sameIdentity := ex.New(ex.ExTypeApplicationFailure, 500, "different message")
 	assert.True(t, errors.Is(exc, sameIdentity))
 }
+
+func TestFast(t *testing.T) {
+	withStackPolicy(t, ex.StackAlways)
+	trackCreations(t)
+	var hooked atomic.Int32
+	remove := ex.AddHook(func(ex.Exception) { hooked.Add(1) })
+	t.Cleanup(remove)
+
+	e := ex.Fast(ex.ExTypeIncorrectData, 4001, "Negative quantity")
+	assert.Equal(t, ex.New(ex.ExTypeIncorrectData, 4001, "Negative quantity").Error(), e.Error())
+	assert.Equal(t, ex.ExTypeIncorrectData, e.Code())
+	assert.Equal(t, 4001, e.ID())
+	assert.Equal(t, len("Negative quantity"), e.Size())
+	assert.False(t, e.HasStack())
+	assert.Equal(t, int32(1), hooked.Load(), "only New runs the hooks")
+	assert.Empty(t, ex.Unobserved(0))
+
+	allocs := testing.AllocsPerRun(100, func() { _ = ex.Fast(ex.ExTypeIncorrectData, 4001, "Negative quantity") })
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,97 @@
+// Package exassert provides testify assertions for exceptions, reporting
+// failures with the codes and IDs involved rather than only the error
+// text:
+//
+//	exassert.ErrorCode(t, err, ex.ExTypeNotFound)
+//	exassert.ErrorChain(t, err, []ex.CodeID{
+//	    {Code: ex.ExTypeApplicationFailure, ID: 500},
+//	    {Code: ex.ExTypeUnavailable, ID: 503},
+//	})
+//
+// Like the functions of github.com/stretchr/testify/assert, they mark
+// themselves as helpers, accept optional message arguments, report
+// whether the assertion held, and let the test continue on failure; see
+// package exrequire for variants that stop it.
+package exassert
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+// tHelper is implemented by *testing.T and *testing.B.
+type tHelper interface {
+	Helper()
+}
+
+// ErrorCode asserts that the outermost Errorer in err's chain has code,
+// the one ex.CodeOf reads.
+func ErrorCode(t assert.TestingT, err error, code ex.ExType, msgAndArgs ...any) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if err == nil {
+		return assert.Fail(t, "Expected an error with code "+code.String()+", got nil", msgAndArgs...)
+	}
+	got, ok := ex.CodeOf(err)
+	if !ok {
+		return assert.Fail(t, fmt.Sprintf("Expected an error with code %s, got one without a code: %q", code, err.Error()), msgAndArgs...)
+	}
+	if got != code {
+		return assert.Fail(t, fmt.Sprintf("Error code mismatch:\n"+
+			"expected: %s\n"+
+			"actual  : %s\n"+
+			"error   : %q", code, got, err.Error()), msgAndArgs...)
+	}
+	return true
+}
+
+// ErrorChain asserts that the Errorers in err's chain have exactly the
+// codes and IDs in want, outermost first. Plain errors in the chain, such
+// as fmt.Errorf wrappers and the root cause, are not listed.
+func ErrorChain(t assert.TestingT, err error, want []ex.CodeID, msgAndArgs ...any) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if err == nil {
+		return assert.Fail(t, "Expected an error chain "+formatChain(want)+", got nil", msgAndArgs...)
+	}
+	got := chainOf(err)
+	if !slices.Equal(got, want) {
+		return assert.Fail(t, fmt.Sprintf("Error chain mismatch:\n"+
+			"expected: %s\n"+
+			"actual  : %s\n"+
+			"error   : %q", formatChain(want), formatChain(got), err.Error()), msgAndArgs...)
+	}
+	return true
+}
+
+// chainOf lists the codes and IDs of the Errorers in err's chain.
+func chainOf(err error) []ex.CodeID {
+	var chain []ex.CodeID
+	for err != nil {
+		if e, ok := err.(ex.Errorer); ok {
+			chain = append(chain, ex.CodeID{Code: e.Code(), ID: e.ID()})
+		}
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
+
+// formatChain renders a chain as "ApplicationFailure/500 → Unavailable/503".
+func formatChain(chain []ex.CodeID) string {
+	if len(chain) == 0 {
+		return "(no exceptions)"
+	}
+	parts := make([]string, len(chain))
+	for i, c := range chain {
+		parts[i] = c.Code.String() + "/" + strconv.Itoa(c.ID)
+	}
+	return strings.Join(parts, " → ")
+}
@@ -0,0 +1,58 @@
+package exassert_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/exassert"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is an assert.TestingT that records failure messages.
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestErrorCode(t *testing.T) {
+	err := fmt.Errorf("handler: %w", ex.New(ex.ExTypeNotFound, 404, "No such user"))
+	assert.True(t, exassert.ErrorCode(t, err, ex.ExTypeNotFound))
+
+	var r recorder
+	assert.False(t, exassert.ErrorCode(&r, err, ex.ExTypeConflict, "creating %s", "user"))
+	assert.False(t, exassert.ErrorCode(&r, errors.New("plain"), ex.ExTypeConflict))
+	assert.False(t, exassert.ErrorCode(&r, nil, ex.ExTypeConflict))
+	if assert.Len(t, r.failures, 3) {
+		assert.Contains(t, r.failures[0], "expected: Conflict")
+		assert.Contains(t, r.failures[0], "actual  : NotFound")
+		assert.Contains(t, r.failures[0], "creating user")
+		assert.Contains(t, r.failures[1], `without a code: "plain"`)
+		assert.Contains(t, r.failures[2], "got nil")
+	}
+}
+
+func TestErrorChain(t *testing.T) {
+	err := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").
+		WithInnerError(fmt.Errorf("repo: %w", ex.New(ex.ExTypeUnavailable, 503, "Query failed").
+			WithInnerError(errors.New("connection refused"))))
+	assert.True(t, exassert.ErrorChain(t, err, []ex.CodeID{
+		{Code: ex.ExTypeApplicationFailure, ID: 500},
+		{Code: ex.ExTypeUnavailable, ID: 503},
+	}))
+
+	var r recorder
+	assert.False(t, exassert.ErrorChain(&r, err, []ex.CodeID{{Code: ex.ExTypeApplicationFailure, ID: 500}}))
+	assert.False(t, exassert.ErrorChain(&r, errors.New("plain"), []ex.CodeID{{Code: ex.ExTypeTimeout, ID: 1}}))
+	assert.False(t, exassert.ErrorChain(&r, nil, nil))
+	if assert.Len(t, r.failures, 3) {
+		assert.Contains(t, r.failures[0], "expected: ApplicationFailure/500\n")
+		assert.Contains(t, r.failures[0], "actual  : ApplicationFailure/500 → Unavailable/503")
+		assert.Contains(t, r.failures[1], "actual  : (no exceptions)")
+	}
+	assert.True(t, exassert.ErrorChain(t, errors.New("plain"), nil))
+}
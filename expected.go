@@ -0,0 +1,50 @@
+package ex
+
+// WithExpected returns a copy of e marked as expected, or not: an error
+// that is part of normal control flow, such as a cache miss or an
+// optimistic-lock conflict that will be retried. Expected exceptions are
+// still created, hooked, and logged, but never escalated: LogLevel and
+// SeverityAtLeast treat their severity as SeverityInfo at most,
+// Reporter.Report and the slo tracker's Report skip them, and the otlpx
+// exporter sends them at INFO.
+//
+// Hooks run inside New, before WithExpected can mark the exception, so
+// they see it unmarked: a Reporter or slo tracker fed by hooks counts it.
+// Feed those where errors are handled, or create the error with QuietNew
+// or QuietWrap if no hook should see it.
+//
+//	if errors.Is(err, redis.Nil) {
+//	    return ex.WrapAuto(err, 4044, "Cache miss").WithExpected(true)
+//	}
+//
+// The mark belongs to e alone; exceptions wrapping it are not expected
+// unless marked themselves. Use Quiet for errors nothing should see.
+func (e Exception) WithExpected(expected bool) Exception {
+	e.expected = expected
+	e.frozen = nil
+	return e
+}
+
+// Expected reports whether e is marked expected; see WithExpected.
+func (e Exception) Expected() bool {
+	return e.expected
+}
+
+// IsExpected reports whether the first Exception in err's chain is marked
+// expected; see WithExpected.
+func IsExpected(err error) bool {
+	e, ok := As(err)
+	return ok && e.expected
+}
+
+// AlertSeverity returns the severity err is logged, routed, and alerted
+// at: its registered severity (see SeverityOf), capped at SeverityInfo if
+// err is expected. Expected errors without a registered severity are
+// SeverityInfo; other errors without one yield zero.
+func AlertSeverity(err error) Severity {
+	s := SeverityOf(err)
+	if IsExpected(err) && (s == 0 || s > SeverityInfo) {
+		return SeverityInfo
+	}
+	return s
+}
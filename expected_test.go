@@ -0,0 +1,99 @@
+package ex_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExpected(t *testing.T) {
+	e := ex.New(ex.ExTypeNotFound, 4044, "Cache miss")
+	assert.False(t, e.Expected())
+	assert.False(t, ex.IsExpected(e))
+
+	expected := e.WithExpected(true)
+	assert.True(t, expected.Expected())
+	assert.True(t, ex.IsExpected(fmt.Errorf("get: %w", expected)))
+	assert.False(t, e.Expected(), "the original is not modified")
+	assert.False(t, expected.WithExpected(false).Expected())
+
+	wrapped := ex.WrapAuto(expected, 5001, "Loading profile failed")
+	assert.False(t, ex.IsExpected(wrapped), "the mark is not inherited")
+	assert.False(t, ex.IsExpected(nil))
+}
+
+func TestWithExpected_Severity(t *testing.T) {
+	failure := ex.New(ex.ExTypeApplicationFailure, 5001, "Version conflict")
+	expected := failure.WithExpected(true)
+
+	assert.Equal(t, slog.LevelError, ex.LogLevel(failure))
+	assert.Equal(t, slog.LevelInfo, ex.LogLevel(expected))
+	assert.True(t, ex.SeverityAtLeast(ex.SeverityError)(failure))
+	assert.False(t, ex.SeverityAtLeast(ex.SeverityError)(expected))
+	assert.True(t, ex.SeverityAtLeast(ex.SeverityInfo)(expected))
+	assert.Equal(t, ex.SeverityError, ex.SeverityOf(expected), "the registered severity is unchanged")
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4941), ex.TypeInfo{Severity: ex.SeverityDebug}))
+	debug := ex.New(ex.ExType(4941), 1, "Probe").WithExpected(true)
+	assert.Equal(t, slog.LevelDebug, ex.LogLevel(debug), "lower severities are kept")
+	assert.Equal(t, ex.SeverityDebug, ex.AlertSeverity(debug))
+	assert.Equal(t, ex.SeverityInfo, ex.AlertSeverity(expected))
+	assert.Equal(t, ex.SeverityError, ex.AlertSeverity(failure))
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4942), ex.TypeInfo{}))
+	unrated := ex.New(ex.ExType(4942), 1, "Miss")
+	assert.Zero(t, ex.AlertSeverity(unrated))
+	assert.Equal(t, ex.SeverityInfo, ex.AlertSeverity(unrated.WithExpected(true)), "expected errors are Info without a registered severity")
+}
+
+func TestWithExpected_JSON(t *testing.T) {
+	data, err := json.Marshal(ex.New(ex.ExTypeNotFound, 4044, "Cache miss").WithExpected(true))
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.Expected())
+
+	plain := ex.New(ex.ExTypeNotFound, 4044, "Cache miss")
+	frozen := plain.Freeze()
+	assert.NotEqual(t, frozen.Fingerprint(), frozen.WithExpected(true).Fingerprint())
+}
+
+func TestWithExpected_Reporter(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	defer r.Shutdown(context.Background())
+
+	r.Report(ex.New(ex.ExTypeNotFound, 4044, "Cache miss").WithExpected(true))
+	r.Report(ex.New(ex.ExTypeApplicationFailure, 500, "Failed"))
+
+	require.NoError(t, r.Flush(context.Background()))
+	batches := s.all()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, 500, batches[0][0].Sample.ID())
+}
+
+func TestWithExpected_ReporterHook(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	defer r.Shutdown(context.Background())
+
+	remove := ex.AddHook(r.Hook())
+	miss := ex.New(ex.ExTypeNotFound, 4044, "Cache miss").WithExpected(true)
+	remove()
+	require.NoError(t, r.Flush(context.Background()))
+	require.Len(t, s.all(), 1, "hooks run before the mark is set")
+
+	r.Report(miss)
+	r.Report(fmt.Errorf("get: %w", miss))
+	require.NoError(t, r.Flush(context.Background()))
+	assert.Len(t, s.all(), 1, "reports at the boundary skip expected errors")
+}
@@ -0,0 +1,165 @@
+package ex
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Explain renders err's chain as a numbered narrative for people rather
+// than logs, suitable for CLI output and support tickets:
+//
+//  1. What happened: Checkout failed (ApplicationFailure/5001)
+//  2. Caused by: The card was declined (IncorrectData/4021)
+//     What to do: Use a different payment method.
+//  3. Caused by: gateway: issuer rejected the transaction
+//
+// Each Errorer in the chain contributes one step, described by its entry in
+// DefaultCatalog if there is one and by its own message otherwise, and
+// followed by its hint (see Exception.WithHint) or the entry's remediation
+// text, and its docs link, if it has one. Plain errors that wrap an Errorer
+// are skipped, since wrappers such as fmt.Errorf repeat the text of the
+// error they wrap; below the last Errorer, the outermost plain error is
+// reported as the final cause. Explain returns "" for a nil err.
+//
+// Errors that aggregate several, such as those of errors.Join, contribute
+// a step counting them, followed by a narrative for each, numbered below
+// it:
+//
+//  2. Caused by 2 errors:
+//     2.1. The card was declined (IncorrectData/4021)
+//     2.2. Inventory unavailable (Unavailable/5031)
+//     2.2.1. Caused by: dial tcp: connection refused
+//
+// The failed branches of a *GroupError are labeled, in the order they
+// failed, with the time from the start of the group:
+//
+//  1. What happened: 2 of 3 branches:
+//     1.1. inventory, failed first at 35ms: Out of stock (NotFound/4041)
+//     1.2. shipping, failed at 5s: quote timed out
+func Explain(err error) string {
+	var b strings.Builder
+	explainChain(&b, err, "", "", "")
+	return b.String()
+}
+
+// explainChain writes the steps of err's chain to b, each line preceded
+// by indent. Top-level steps are numbered from 1; the steps of a branch
+// numbered prefix are numbered prefix, then prefix.1, prefix.2, and so
+// on, and the first step of a branch is preceded by its label, if any.
+func explainChain(b *strings.Builder, err error, indent, prefix, label string) {
+	step := 0
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		var what, hint, docs string
+		var branches []error
+		var labels []string
+		last := false
+		switch v := cur.(type) {
+		case *annotation:
+			continue
+		case Errorer:
+			what, hint, docs = explainLevel(v)
+		case *GroupError:
+			for i, br := range v.Failed() {
+				state := "failed at "
+				if i == 0 {
+					state = "failed first at "
+				}
+				branches = append(branches, br.Err)
+				labels = append(labels, br.Label+", "+state+offset(br.End, v.start)+": ")
+			}
+			what, last = strconv.Itoa(len(branches))+" of "+strconv.Itoa(len(v.branches))+" branches:", true
+		default:
+			if errs, ok := members(cur); ok {
+				branches = slices.DeleteFunc(slices.Clone(errs), func(e error) bool { return e == nil })
+				what, last = strconv.Itoa(len(branches))+" errors:", true
+				break
+			}
+			if _, below := AsErrorer(cur); below || aggregatesBelow(cur) {
+				continue
+			}
+			what, last = cur.Error(), true
+		}
+
+		step++
+		var number string
+		switch {
+		case prefix == "":
+			number = strconv.Itoa(step)
+		case step == 1:
+			number = prefix
+		default:
+			number = prefix + "." + strconv.Itoa(step-1)
+		}
+		b.WriteString(indent + number + ". ")
+		switch {
+		case step == 1 && prefix == "":
+			b.WriteString("What happened: ")
+		case step == 1:
+			// A branch opens with its own error.
+			b.WriteString(label)
+		case branches != nil:
+			b.WriteString("Caused by ")
+		default:
+			b.WriteString("Caused by: ")
+		}
+		b.WriteString(what)
+		b.WriteByte('\n')
+		pad := indent + strings.Repeat(" ", len(number)+2)
+		if hint != "" {
+			b.WriteString(pad + "What to do: " + hint + "\n")
+		}
+		if docs != "" {
+			b.WriteString(pad + "See: " + docs + "\n")
+		}
+		for i, branch := range branches {
+			var label string
+			if labels != nil {
+				label = labels[i]
+			}
+			explainChain(b, branch, pad, number+"."+strconv.Itoa(i+1), label)
+		}
+		if last {
+			break
+		}
+	}
+}
+
+// aggregatesBelow reports whether an error that aggregates several is
+// wrapped somewhere below err.
+func aggregatesBelow(err error) bool {
+	for cur := errors.Unwrap(err); cur != nil; cur = errors.Unwrap(cur) {
+		if _, ok := members(cur); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// explainLevel returns the description, remediation, and docs link of
+// one step of Explain's narrative.
+func explainLevel(e Errorer) (what, hint, docs string) {
+	msg := e.Message()
+	if h, ok := e.(interface{ Hint() string }); ok {
+		hint = h.Hint()
+	}
+	if d, ok := e.(interface{ DocsURL() string }); ok {
+		docs = d.DocsURL()
+	}
+	if entry, ok := Lookup(e.Code(), e.ID()); ok {
+		if entry.Message != "" {
+			msg = entry.Message
+		}
+		if hint == "" {
+			hint = entry.Remediation
+		}
+		if docs == "" {
+			docs = entry.DocsURL
+		}
+	}
+	if msg == "" {
+		msg = e.Code().String()
+	}
+	return msg + " (" + e.Code().String() + "/" + strconv.Itoa(e.ID()) + ")", hint, docs
+}
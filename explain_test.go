@@ -0,0 +1,63 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+var errExplainDeclined = ex.Define(ex.Entry{
+	Code:        ex.ExTypeIncorrectData,
+	ID:          4721,
+	Message:     "The card was declined",
+	Remediation: "Use a different payment method.",
+})
+
+func TestExplain(t *testing.T) {
+	err := fmt.Errorf("checkout: %w",
+		ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").
+			WithInnerError(ex.Annotate(
+				errExplainDeclined.New().WithInnerError(
+					fmt.Errorf("gateway: %w", errors.New("issuer rejected the transaction"))),
+				ex.F("card", "visa"))))
+
+	assert.Equal(t, "1. What happened: Checkout failed (ApplicationFailure/5001)\n"+
+		"2. Caused by: The card was declined (IncorrectData/4721)\n"+
+		"   What to do: Use a different payment method.\n"+
+		"3. Caused by: gateway: issuer rejected the transaction\n", ex.Explain(err))
+}
+
+func TestExplain_Edges(t *testing.T) {
+	assert.Empty(t, ex.Explain(nil))
+	assert.Equal(t, "1. What happened: EOF\n", ex.Explain(errors.New("EOF")))
+	assert.Equal(t, "1. What happened: NotFound (NotFound/404)\n", ex.Explain(ex.New(ex.ExTypeNotFound, 404, "")))
+}
+
+func TestExplain_Joined(t *testing.T) {
+	err := ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").
+		WithInnerError(errors.Join(
+			errExplainDeclined.New(),
+			ex.New(ex.ExTypeUnavailable, 5031, "Inventory unavailable").
+				WithInnerError(errors.New("dial tcp: connection refused")),
+		))
+
+	assert.Equal(t, "1. What happened: Checkout failed (ApplicationFailure/5001)\n"+
+		"2. Caused by 2 errors:\n"+
+		"   2.1. The card was declined (IncorrectData/4721)\n"+
+		"        What to do: Use a different payment method.\n"+
+		"   2.2. Inventory unavailable (Unavailable/5031)\n"+
+		"   2.2.1. Caused by: dial tcp: connection refused\n", ex.Explain(err))
+
+	assert.Equal(t, "1. What happened: 2 errors:\n"+
+		"   1.1. EOF\n"+
+		"   1.2. NotFound (NotFound/404)\n",
+		ex.Explain(fmt.Errorf("batch: %w", errors.Join(errors.New("EOF"), ex.New(ex.ExTypeNotFound, 404, "")))))
+
+	assert.Equal(t, "1. What happened: 2 errors:\n"+
+		"   1.1. EOF\n"+
+		"   1.2. unexpected EOF\n",
+		ex.Explain(fmt.Errorf("batch: %w", errors.Join(errors.New("EOF"), errors.New("unexpected EOF")))))
+}
@@ -0,0 +1,43 @@
+package ex
+
+// BuildStrict exposes, to the external tests, whether the exstrict build
+// tag is set.
+const BuildStrict = buildStrict
+
+// SaveProfiles snapshots the profile registry and returns the function
+// restoring it, for tests registering profiles.
+func SaveProfiles() (restore func()) {
+	saved := profiles.Load()
+	return func() { profiles.Store(saved) }
+}
+
+// SaveTypes snapshots the type registry and returns the function
+// restoring it, for tests registering types.
+func SaveTypes() (restore func()) {
+	saved := types.Load()
+	return func() { types.Store(saved) }
+}
+
+// ResetMigrations removes every registered migration, for tests
+// registering migrations.
+func ResetMigrations() {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	migrations.Store(nil)
+}
+
+// ResetIsAliases removes every alias registered with RegisterIsAlias, for
+// tests registering aliases.
+func ResetIsAliases() {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	aliases.Store(nil)
+}
+
+// SetMaxBundleSize lowers the decompressed size ReadBundle and
+// UnmarshalCompressed accept to n and returns the function restoring it.
+func SetMaxBundleSize(n int64) (restore func()) {
+	saved := maxBundleSize
+	maxBundleSize = n
+	return func() { maxBundleSize = saved }
+}
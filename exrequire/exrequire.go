@@ -0,0 +1,37 @@
+// Package exrequire provides the assertions of package exassert in the
+// style of github.com/stretchr/testify/require: a failed assertion stops
+// the test with t.FailNow.
+//
+//	exrequire.ErrorCode(t, err, ex.ExTypeNotFound)
+package exrequire
+
+import (
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/exassert"
+	"github.com/stretchr/testify/require"
+)
+
+// tHelper is implemented by *testing.T and *testing.B.
+type tHelper interface {
+	Helper()
+}
+
+// ErrorCode is exassert.ErrorCode, stopping the test on failure.
+func ErrorCode(t require.TestingT, err error, code ex.ExType, msgAndArgs ...any) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if !exassert.ErrorCode(t, err, code, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// ErrorChain is exassert.ErrorChain, stopping the test on failure.
+func ErrorChain(t require.TestingT, err error, want []ex.CodeID, msgAndArgs ...any) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if !exassert.ErrorChain(t, err, want, msgAndArgs...) {
+		t.FailNow()
+	}
+}
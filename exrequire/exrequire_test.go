@@ -0,0 +1,39 @@
+package exrequire_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/exrequire"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a require.TestingT that records failures.
+type recorder struct {
+	failures int
+	stopped  bool
+}
+
+func (r *recorder) Errorf(string, ...any) { r.failures++ }
+func (r *recorder) FailNow()              { r.stopped = true }
+
+func TestErrorCode(t *testing.T) {
+	err := ex.New(ex.ExTypeNotFound, 404, "No such user")
+	exrequire.ErrorCode(t, err, ex.ExTypeNotFound)
+
+	var r recorder
+	exrequire.ErrorCode(&r, err, ex.ExTypeConflict)
+	assert.Equal(t, 1, r.failures)
+	assert.True(t, r.stopped)
+}
+
+func TestErrorChain(t *testing.T) {
+	err := fmt.Errorf("handler: %w", ex.New(ex.ExTypeNotFound, 404, "No such user"))
+	exrequire.ErrorChain(t, err, []ex.CodeID{{Code: ex.ExTypeNotFound, ID: 404}})
+
+	var r recorder
+	exrequire.ErrorChain(&r, err, nil)
+	assert.Equal(t, 1, r.failures)
+	assert.True(t, r.stopped)
+}
@@ -0,0 +1,71 @@
+//go:build exstrict
+
+package ex_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with: go test -tags exstrict -run Exstrict
+
+func TestExstrict_Unregistered(t *testing.T) {
+	assert.True(t, ex.Strict())
+	assert.Panics(t, func() { ex.New(ex.ExType(4641), 1, "typo") })
+}
+
+func newDuplicate() ex.Exception { return ex.New(ex.ExTypeConflict, 46420, "first") }
+
+func TestExstrict_DuplicateID(t *testing.T) {
+	assert.NotPanics(t, func() {
+		ex.New(ex.ExTypeConflict, 46410, "first")
+		ex.New(ex.ExTypeConflict, 46410, "second")
+	}, "the duplicate ID check is off by default")
+
+	ex.SetStrictIDs(true)
+	t.Cleanup(func() { ex.SetStrictIDs(false) })
+	for range 3 {
+		assert.NotPanics(t, func() { newDuplicate() }, "one call site may create an ID any number of times")
+	}
+	defer func() {
+		msg, ok := recover().(string)
+		assert.True(t, ok)
+		assert.Contains(t, msg, "ex: exstrict: duplicate ID: Conflict/46420 is created at ")
+		assert.Contains(t, msg, "exstrict_test.go")
+	}()
+	ex.New(ex.ExTypeConflict, 46420, "second")
+	t.Fatal("no panic")
+}
+
+func TestExstrict_DuplicateIDExemptions(t *testing.T) {
+	ex.SetStrictIDs(true)
+	t.Cleanup(func() { ex.SetStrictIDs(false) })
+	assert.NotPanics(t, func() {
+		ex.New(ex.ExTypeConflict, 0, "no ID")
+		ex.New(ex.ExTypeConflict, 0, "no ID either")
+		entry := ex.Entry{Code: ex.ExTypeConflict, ID: 46430, Message: "Catalog entry"}
+		entry.New()
+		entry.New()
+	})
+}
+
+func TestExstrict_Message(t *testing.T) {
+	assert.NotPanics(t, func() { ex.New(ex.ExTypeConflict, 46440, strings.Repeat("x", ex.StrictMessageLimit)) })
+	assert.PanicsWithValue(t,
+		"ex: exstrict: message of Conflict/46450 is 1025 bytes, over StrictMessageLimit (1024); move the details to fields",
+		func() { ex.New(ex.ExTypeConflict, 46450, strings.Repeat("x", ex.StrictMessageLimit+1)) })
+}
+
+func TestExstrict_WithInnerError(t *testing.T) {
+	e := ex.New(ex.ExTypeConflict, 46460, "Conflict")
+	assert.PanicsWithValue(t, "ex: exstrict: Conflict/46460: WithInnerError(nil); wrap only non-nil errors",
+		func() { e.WithInnerError(nil) })
+	assert.PanicsWithValue(t, "ex: exstrict: Conflict/46460: WithInnerError with the exception itself",
+		func() { e.WithInnerError(e) })
+
+	wrapped := e.WithInnerError(ex.New(ex.ExTypeConflict, 46461, "Inner"))
+	assert.NotPanics(t, func() { wrapped.WithInnerError(nil) }, "clearing an inner error is fine")
+}
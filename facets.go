@@ -0,0 +1,120 @@
+package ex
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Facets are orthogonal classification bits combined into a code with |,
+// for classification beyond the category without parallel fields:
+//
+//	const ExTypeReplicaLag = ex.ExTypeUnavailable | ex.FacetTransient
+//
+//	e := ex.New(ExTypeReplicaLag, 5032, "Replica behind primary")
+//	e.Code().Category()  // ExTypeUnavailable
+//	e.Code().Transient() // true
+//	e.Code().String()    // "Unavailable|Transient"
+//
+// A faceted code is a code in its own right: Exception.Is and HasCode
+// compare codes whole, so compare Category() to ignore facets. IsTimeout
+// and IsUnavailable compare categories, and metadata lookups (see
+// LookupType) fall back to the category when the faceted code has no
+// registration of its own.
+const (
+	// FacetTransient marks failures that may succeed if retried; see
+	// Retryable.
+	FacetTransient ExType = 1 << (24 + iota)
+
+	// FacetInternal marks failures whose details are not for clients,
+	// even if their status makes them client faults. Client-facing
+	// renderings (Prune without InternalMessages, and the httpx,
+	// problem, and grpcx adapters) report their code name or status text
+	// instead of their message; see IsInternal.
+	FacetInternal
+
+	// FacetMask covers every bit reserved for facets. Categories,
+	// predefined or custom, are the codes below it.
+	FacetMask ExType = 0x7f << 24
+)
+
+// facetNames are the String renderings of the facets, in order.
+var facetNames = [...]struct {
+	facet ExType
+	name  string
+}{
+	{FacetTransient, "Transient"},
+	{FacetInternal, "Internal"},
+}
+
+// Category returns et without its facets.
+func (et ExType) Category() ExType {
+	return et &^ FacetMask
+}
+
+// Facets returns the facet bits of et.
+func (et ExType) Facets() ExType {
+	return et & FacetMask
+}
+
+// Has reports whether et carries every facet in facets. It reports false
+// for zero facets.
+func (et ExType) Has(facets ExType) bool {
+	facets &= FacetMask
+	return facets != 0 && et&facets == facets
+}
+
+// Transient reports whether et carries FacetTransient.
+func (et ExType) Transient() bool {
+	return et.Has(FacetTransient)
+}
+
+// Internal reports whether et carries FacetInternal.
+func (et ExType) Internal() bool {
+	return et.Has(FacetInternal)
+}
+
+// facetString renders the facets of et as String does, each preceded by
+// "|". Reserved bits without a name render as "Facet(N)", N being the bit
+// number.
+func facetString(facets ExType) string {
+	var b strings.Builder
+	for _, f := range facetNames {
+		if facets&f.facet != 0 {
+			b.WriteString("|" + f.name)
+			facets &^= f.facet
+		}
+	}
+	for bit := 24; facets != 0; bit++ {
+		if facets&(1<<bit) != 0 {
+			b.WriteString("|Facet(" + strconv.Itoa(bit) + ")")
+			facets &^= 1 << bit
+		}
+	}
+	return b.String()
+}
+
+// parseFacets resolves the "|"-separated facet names following a category
+// in the String rendering of a faceted code.
+func parseFacets(names string) (ExType, bool) {
+	var facets ExType
+	for name := range strings.SplitSeq(names, "|") {
+		found := false
+		for _, f := range facetNames {
+			if f.name == name {
+				facets, found = facets|f.facet, true
+				break
+			}
+		}
+		if rest, ok := strings.CutPrefix(name, "Facet("); ok && !found {
+			bit, err := strconv.Atoi(strings.TrimSuffix(rest, ")"))
+			if err != nil || bit < 24 || bit > 30 || !strings.HasSuffix(rest, ")") {
+				return 0, false
+			}
+			facets, found = facets|1<<bit, true
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return facets, true
+}
@@ -0,0 +1,80 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExType_Facets(t *testing.T) {
+	code := ex.ExTypeUnavailable | ex.FacetTransient
+
+	assert.Equal(t, ex.ExTypeUnavailable, code.Category())
+	assert.Equal(t, ex.FacetTransient, code.Facets())
+	assert.True(t, code.Transient())
+	assert.False(t, code.Internal())
+	assert.True(t, code.Has(ex.FacetTransient))
+	assert.False(t, code.Has(ex.FacetTransient|ex.FacetInternal))
+	assert.False(t, code.Has(0))
+	assert.Zero(t, ex.ExTypeUnavailable.Facets())
+	assert.Equal(t, ex.ExTypeUnavailable, ex.ExTypeUnavailable.Category())
+}
+
+func TestExType_FacetsString(t *testing.T) {
+	tests := []struct {
+		code ex.ExType
+		want string
+	}{
+		{ex.ExTypeTimeout | ex.FacetTransient, "Timeout|Transient"},
+		{ex.ExTypeApplicationFailure | ex.FacetInternal | ex.FacetTransient, "ApplicationFailure|Transient|Internal"},
+		{ex.ExType(4631) | ex.FacetInternal, "Unknown(4631)|Internal"},
+		{ex.ExTypeNotFound | 1<<30, "NotFound|Facet(30)"},
+		{ex.FacetTransient, "Invalid(0)|Transient"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.code.String())
+	}
+
+	named := ex.ExTypeConflict | ex.FacetTransient
+	require.NoError(t, ex.RegisterType(named, ex.TypeInfo{Name: "WriteRace", HTTPStatus: 409}))
+	assert.Equal(t, "WriteRace", named.String(), "a registered name wins")
+}
+
+func TestExType_FacetsMetadata(t *testing.T) {
+	e := ex.New(ex.ExTypeUnavailable|ex.FacetTransient, 5032, "Replica behind primary")
+
+	info, ok := ex.TypeInfoOf(e)
+	require.True(t, ok, "faceted codes inherit their category's metadata")
+	assert.Equal(t, 503, info.HTTPStatus)
+	assert.True(t, ex.IsServerFault(e))
+	assert.True(t, ex.IsKnownType(e.Code()))
+
+	f := ex.NewFactory(ex.Config{Strict: true})
+	assert.NotPanics(t, func() { f.New(ex.ExTypeNotFound|ex.FacetInternal, 1, "x") })
+	info, ok = f.LookupType(ex.ExTypeNotFound | ex.FacetInternal)
+	require.True(t, ok)
+	assert.Equal(t, 404, info.HTTPStatus)
+}
+
+func TestExType_FacetsPredicates(t *testing.T) {
+	lag := ex.New(ex.ExTypeUnavailable|ex.FacetTransient, 5032, "Replica behind primary")
+	assert.True(t, ex.IsUnavailable(fmt.Errorf("read: %w", lag)), "predicates compare categories")
+	assert.True(t, ex.IsTimeout(ex.New(ex.ExTypeTimeout|ex.FacetInternal, 5041, "Slow")))
+	assert.True(t, ex.Retryable(ex.New(ex.ExTypeConflict|ex.FacetTransient, 4091, "Write race")))
+
+	assert.True(t, ex.IsInternal(ex.New(ex.ExTypeNotFound|ex.FacetInternal, 4041, "No row in shard 7")))
+	assert.False(t, ex.IsInternal(lag))
+	assert.False(t, ex.IsInternal(errors.New("plain")))
+}
+
+func TestExType_FacetsIdentity(t *testing.T) {
+	e := ex.New(ex.ExTypeUnavailable|ex.FacetTransient, 5032, "Replica behind primary")
+	assert.False(t, errors.Is(e, ex.New(ex.ExTypeUnavailable, 5032, "")), "facets are part of the code")
+	assert.True(t, errors.Is(e, ex.New(ex.ExTypeUnavailable|ex.FacetTransient, 5032, "")))
+	code, _ := ex.CodeOf(e)
+	assert.Equal(t, ex.ExTypeUnavailable, code.Category())
+}
@@ -0,0 +1,342 @@
+package ex
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config is the configuration of a Factory. The zero value selects the
+// package defaults: stacks on request, no strict mode, and the built-in
+// classification for WrapAuto.
+type Config struct {
+	// StackPolicy decides when the factory captures stacks.
+	StackPolicy StackPolicy
+
+	// Strict makes the factory panic when given a code unknown to its
+	// LookupType, as SetStrict does for New.
+	Strict bool
+
+	// Classifiers are consulted by WrapAuto, in order, for errors whose
+	// chain holds no Errorer, as those added with RegisterClassifier are
+	// by the package-level WrapAuto.
+	Classifiers []Classifier
+
+	// Hooks are called with every exception the factory creates. Add
+	// more later with Factory.AddHook.
+	Hooks []Hook
+
+	// Types registers metadata for the factory's own codes, as
+	// RegisterType does for the package. The predefined codes are always
+	// known, with their built-in metadata unless overridden here.
+	Types map[ExType]TypeInfo
+
+	// Catalog is the factory's catalog. Nil gives it an empty one.
+	Catalog *Catalog
+
+	// Tracking gives the factory creation tracking of its own: every
+	// exception it creates is recorded until observed, as SetTracking
+	// does for the package, and Factory.Unobserved lists the ones never
+	// observed. Without it, factory exceptions are tracked by the package
+	// if the factory inherits and tracking is on there.
+	Tracking bool
+
+	// Inherit makes the factory fall back to the package defaults: the
+	// hooks added with AddHook run after the factory's own, creation
+	// tracking follows SetTracking unless Tracking is set, and codes and
+	// catalog entries the factory does not have are looked up with
+	// LookupType and in DefaultCatalog.
+	Inherit bool
+}
+
+// Factory creates exceptions from its own configuration rather than the
+// package-level one. It is meant for libraries that use ex internally:
+// the application importing them owns SetStackPolicy, SetStrict,
+// RegisterClassifier, and AddHook, and a library that set them too would
+// fight it for them. A library keeps a factory instead:
+//
+//	var exf = ex.NewFactory(ex.Config{StackPolicy: ex.StackOnFailure})
+//
+//	func (c *Client) Get(key string) ([]byte, error) {
+//	    ...
+//	    return nil, exf.Wrap(err, ex.ExTypeUnavailable, 5031, "Cache unreachable")
+//	}
+//
+// A factory also has hooks, a type registry, and a catalog of its own, so
+// each module of a modular monolith can own its error space, inheriting
+// the application's defaults if it asks to (see Config.Inherit):
+//
+//	var billing = ex.NewFactory(ex.Config{
+//	    Types:   map[ex.ExType]ex.TypeInfo{ExTypeCardDeclined: {Name: "CardDeclined", HTTPStatus: 402}},
+//	    Inherit: true,
+//	})
+//
+// Exceptions made by a factory are ordinary exceptions in every other
+// respect. Unless the factory inherits, they do not run the hooks
+// registered with AddHook or take part in the package's creation
+// tracking; a factory can track its own (see Config.Tracking). Their
+// metadata comes from the factory's registry (see TypeInfoOf), so the
+// adapters give a factory code the HTTP status and gRPC code the factory
+// registered for it. A Factory is safe for concurrent use.
+type Factory struct {
+	stackPolicy *StackPolicy
+	strict      bool
+	classifiers []Classifier
+	inherit     bool
+	hooks       hookSet
+	catalog     *Catalog
+	// tracker holds the factory's own creation records, or is nil.
+	tracker *tracker
+
+	typesMu sync.Mutex
+	// types is published copy-on-write, as the package registry is.
+	types atomic.Pointer[map[ExType]TypeInfo]
+}
+
+// NewFactory returns a Factory configured by cfg.
+func NewFactory(cfg Config) *Factory {
+	f := &Factory{strict: cfg.Strict, inherit: cfg.Inherit, catalog: cfg.Catalog}
+	if cfg.StackPolicy.mode != stackOnRequest {
+		p := cfg.StackPolicy
+		f.stackPolicy = &p
+	}
+	for _, c := range cfg.Classifiers {
+		if c != nil {
+			f.classifiers = append(f.classifiers, c)
+		}
+	}
+	for _, h := range cfg.Hooks {
+		f.hooks.add(h)
+	}
+	types := make(map[ExType]TypeInfo, len(cfg.Types))
+	for code, info := range cfg.Types {
+		if code != 0 {
+			types[code] = info
+		}
+	}
+	f.types.Store(&types)
+	if f.catalog == nil {
+		f.catalog = NewCatalog()
+	}
+	if cfg.Tracking {
+		f.tracker = &tracker{records: map[*creation]struct{}{}}
+	}
+	return f
+}
+
+// New is New with the factory's configuration.
+func (f *Factory) New(code ExType, id int, message string) Exception {
+	f.checkKnown(code)
+	e := Exception{code: code, id: id, message: message, factory: f, stack: policyStack(f.stackPolicy, f.LookupType, code, id, 1), sizeHint: len(message) + 1}
+	f.runHooks(e)
+	e.creation = f.trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// Wrap returns a new exception with code, id, and message wrapping err,
+// like f.New(code, id, message).WithInnerError(err).
+func (f *Factory) Wrap(err error, code ExType, id int, message string) Exception {
+	f.checkKnown(code)
+	err = limitDepth(err)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: err,
+		quiet:      quietInner(err),
+		stack:      policyStack(f.stackPolicy, f.LookupType, code, id, 1),
+		sizeHint:   sizeHintOf(message, err),
+		factory:    f,
+	}
+	f.runHooks(e)
+	e.creation = f.trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// WrapAuto is WrapAuto with the factory's configuration: errors without
+// an Errorer in their chain are classified by the factory's classifiers
+// only.
+func (f *Factory) WrapAuto(err error, id int, message string) Exception {
+	code := f.inferCode(err)
+	err = limitDepth(err)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: err,
+		quiet:      quietInner(err),
+		stack:      policyStack(f.stackPolicy, f.LookupType, code, id, 1),
+		sizeHint:   sizeHintOf(message, err),
+		factory:    f,
+	}
+	f.runHooks(e)
+	e.creation = f.trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// FromEntry creates an exception from a catalog entry, as Entry.New does,
+// through the factory. If the factory is strict, it panics when the
+// fields do not match the entry's Fields.
+func (f *Factory) FromEntry(entry Entry, fields ...Field) Exception {
+	f.checkKnown(entry.Code)
+	e := Exception{
+		code:       entry.Code,
+		id:         entry.ID,
+		message:    entry.Message,
+		codeString: entry.CodeString,
+		hint:       entry.Remediation,
+		docsURL:    entry.DocsURL,
+		stack:      policyStack(f.stackPolicy, f.LookupType, entry.Code, entry.ID, 1),
+		sizeHint:   len(entry.Message) + 1,
+		factory:    f,
+	}
+	if len(fields) > 0 {
+		e = e.WithFields(fields...)
+	}
+	checkFields(f.strict, entry, e.fields)
+	f.runHooks(e)
+	e.creation = f.trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// AddHook registers h to be called for every exception the factory
+// creates, as AddHook does for the package, and returns a function that
+// removes it.
+func (f *Factory) AddHook(h Hook) (remove func()) {
+	return f.hooks.add(h)
+}
+
+// runHooks calls the factory's hooks with e, then the package's if the
+// factory inherits them. Quiet exceptions run none.
+func (f *Factory) runHooks(e Exception) {
+	if e.quiet {
+		return
+	}
+	f.hooks.run(e)
+	if f.inherit {
+		runHooks(e)
+	}
+}
+
+// trackCreation returns the tracking record for a new exception: in the
+// factory's own tracker if it has one, in the package's if the factory
+// inherits, or nil.
+func (f *Factory) trackCreation(code ExType, id int, message string) *creation {
+	if f.tracker != nil {
+		return f.tracker.newCreation(code, id, message)
+	}
+	if f.inherit {
+		return trackCreation(code, id, message)
+	}
+	return nil
+}
+
+// Unobserved returns the exceptions the factory created at least minAge
+// ago that have not been observed since, oldest first, as Unobserved does
+// for the package. It returns nil unless the factory has its own tracking
+// (see Config.Tracking).
+func (f *Factory) Unobserved(minAge time.Duration) []Creation {
+	if f.tracker == nil {
+		return nil
+	}
+	return f.tracker.unobserved(minAge)
+}
+
+// RegisterType sets the metadata for code in the factory's registry,
+// replacing any previous registration there. The package registry is not
+// affected. Codes are checked as by the package-level RegisterType.
+func (f *Factory) RegisterType(code ExType, info TypeInfo) error {
+	if err := checkRegistrable(code); err != nil {
+		return err
+	}
+	f.typesMu.Lock()
+	defer f.typesMu.Unlock()
+	cur := *f.types.Load()
+	next := make(map[ExType]TypeInfo, len(cur)+1)
+	for k, v := range cur {
+		next[k] = v
+	}
+	next[code] = info
+	f.types.Store(&next)
+	return nil
+}
+
+// LookupType returns the metadata for code from the factory's registry,
+// falling back to LookupType if the factory inherits, and to the built-in
+// metadata of the predefined codes otherwise. Like LookupType, it resolves
+// faceted codes by their category.
+func (f *Factory) LookupType(code ExType) (TypeInfo, bool) {
+	if info, ok := (*f.types.Load())[code]; ok {
+		return info, true
+	}
+	if f.inherit {
+		return LookupType(code)
+	}
+	if info, ok := builtinTypes[code]; ok {
+		return info, true
+	}
+	if code.Facets() != 0 {
+		return f.LookupType(code.Category())
+	}
+	return TypeInfo{}, false
+}
+
+// TypeInfoOf is TypeInfoOf with metadata from f.LookupType.
+func (f *Factory) TypeInfoOf(err error) (TypeInfo, bool) {
+	return typeInfoOf(err, f.LookupType)
+}
+
+// Catalog returns the factory's catalog.
+func (f *Factory) Catalog() *Catalog {
+	return f.catalog
+}
+
+// Define registers entry in the factory's catalog and returns it,
+// panicking if it is invalid or already registered, as Define does.
+func (f *Factory) Define(entry Entry) Entry {
+	if err := f.catalog.Register(entry); err != nil {
+		panic(err)
+	}
+	return entry
+}
+
+// Lookup returns the entry for (code, id) from the factory's catalog,
+// falling back to DefaultCatalog if the factory inherits.
+func (f *Factory) Lookup(code ExType, id int) (Entry, bool) {
+	if entry, ok := f.catalog.Lookup(code, id); ok {
+		return entry, true
+	}
+	if f.inherit {
+		return Lookup(code, id)
+	}
+	return Entry{}, false
+}
+
+// inferCode is inferCode with the factory's classifiers.
+func (f *Factory) inferCode(err error) ExType {
+	if err == nil {
+		return ExTypeApplicationFailure
+	}
+	if e, ok := innermost(err); ok {
+		return e.Code()
+	}
+	for _, c := range f.classifiers {
+		if code, _ := c(err); code != 0 {
+			return code
+		}
+	}
+	code, _ := defaultClassify(err)
+	return code
+}
+
+// checkKnown panics if the factory is strict and code is not known to
+// f.LookupType.
+func (f *Factory) checkKnown(code ExType) {
+	if !f.strict {
+		return
+	}
+	if _, ok := f.LookupType(code); !ok {
+		panic(fmt.Sprintf("ex: strict factory: unregistered exception code %s", code))
+	}
+}
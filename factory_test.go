@@ -0,0 +1,215 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostileGlobals configures the package the way an application might,
+// in ways a factory must not pick up.
+func hostileGlobals(t *testing.T) (hooked *int) {
+	t.Helper()
+	saved := ex.CurrentStackPolicy()
+	savedStrict := ex.Strict()
+	ex.SetStackPolicy(ex.StackNever)
+	ex.SetStrict(true)
+	hooked = new(int)
+	removeHook := ex.AddHook(func(ex.Exception) { *hooked++ })
+	removeClassifier := ex.RegisterClassifier(func(error) (ex.ExType, int) { return ex.ExTypeConflict, 0 })
+	t.Cleanup(func() {
+		ex.SetStackPolicy(saved)
+		ex.SetStrict(savedStrict)
+		removeHook()
+		removeClassifier()
+	})
+	return hooked
+}
+
+func TestFactory_IgnoresGlobals(t *testing.T) {
+	hooked := hostileGlobals(t)
+	f := ex.NewFactory(ex.Config{StackPolicy: ex.StackAlways})
+
+	e := f.New(ex.ExType(4612), 1, "custom code")
+	assert.Equal(t, "custom code", e.Error(), "package strict mode does not apply")
+	require.True(t, e.HasStack(), "the factory's policy applies")
+	assert.Contains(t, e.StackTrace()[0].Function, "TestFactory_IgnoresGlobals")
+
+	auto := f.WrapAuto(errors.New("boom"), 2, "Failed")
+	assert.Equal(t, ex.ExTypeApplicationFailure, auto.Code(), "package classifiers do not apply")
+	assert.Zero(t, *hooked, "package hooks do not run")
+
+	assert.Equal(t, ex.ExTypeConflict, ex.WrapAuto(errors.New("boom"), 2, "Failed").Code())
+	assert.Equal(t, 1, *hooked)
+}
+
+func TestFactory_Wrap(t *testing.T) {
+	f := ex.NewFactory(ex.Config{})
+	root := errors.New("dial tcp: connection refused")
+	e := f.Wrap(root, ex.ExTypeUnavailable, 5031, "Cache unreachable")
+
+	assert.Equal(t, "Cache unreachable: dial tcp: connection refused", e.Error())
+	assert.ErrorIs(t, e, root)
+	assert.False(t, e.HasStack())
+	assert.Equal(t, len(e.Error()), e.Size())
+}
+
+func TestFactory_Classifiers(t *testing.T) {
+	errMiss := errors.New("cache miss")
+	f := ex.NewFactory(ex.Config{Classifiers: []ex.Classifier{
+		nil,
+		func(err error) (ex.ExType, int) {
+			if errors.Is(err, errMiss) {
+				return ex.ExTypeNotFound, 0
+			}
+			return 0, 0
+		},
+	}})
+
+	assert.Equal(t, ex.ExTypeNotFound, f.WrapAuto(fmt.Errorf("get: %w", errMiss), 1, "x").Code())
+	assert.Equal(t, ex.ExTypeApplicationFailure, f.WrapAuto(errors.New("other"), 1, "x").Code())
+	assert.Equal(t, ex.ExTypeTimeout, f.WrapAuto(ex.New(ex.ExTypeTimeout, 1, "slow"), 2, "x").Code(),
+		"inner exceptions win")
+}
+
+func TestFactory_Strict(t *testing.T) {
+	f := ex.NewFactory(ex.Config{Strict: true})
+	assert.NotPanics(t, func() { f.New(ex.ExTypeNotFound, 1, "x") })
+	assert.PanicsWithValue(t, "ex: strict factory: unregistered exception code Unknown(44)", func() {
+		f.Wrap(nil, ex.ExType(44), 1, "x")
+	})
+}
+
+func TestFactory_Hooks(t *testing.T) {
+	var global, own []int
+	t.Cleanup(ex.AddHook(func(e ex.Exception) { global = append(global, e.ID()) }))
+
+	isolated := ex.NewFactory(ex.Config{Hooks: []ex.Hook{func(e ex.Exception) { own = append(own, e.ID()) }}})
+	inheriting := ex.NewFactory(ex.Config{Inherit: true})
+	remove := inheriting.AddHook(func(e ex.Exception) { own = append(own, -e.ID()) })
+
+	isolated.New(ex.ExTypeNotFound, 1, "x")
+	inheriting.Wrap(nil, ex.ExTypeNotFound, 2, "x")
+	remove()
+	inheriting.WrapAuto(nil, 3, "x")
+
+	assert.Equal(t, []int{1, -2}, own)
+	assert.Equal(t, []int{2, 3}, global, "inheriting factories run the package hooks")
+}
+
+func TestFactory_Types(t *testing.T) {
+	const cardDeclined = ex.ExType(4620)
+	billing := ex.NewFactory(ex.Config{
+		Strict: true,
+		Types:  map[ex.ExType]ex.TypeInfo{cardDeclined: {Name: "CardDeclined", HTTPStatus: 402}},
+	})
+
+	e := billing.New(cardDeclined, 1, "Card declined")
+	info, ok := billing.TypeInfoOf(fmt.Errorf("charge: %w", e))
+	require.True(t, ok)
+	assert.Equal(t, 402, info.HTTPStatus)
+	_, ok = ex.LookupType(cardDeclined)
+	assert.False(t, ok, "the package registry is untouched")
+
+	info, ok = billing.LookupType(ex.ExTypeNotFound)
+	require.True(t, ok, "predefined codes are always known")
+	assert.Equal(t, 404, info.HTTPStatus)
+
+	const refundFailed = ex.ExType(4621)
+	assert.Panics(t, func() { billing.New(refundFailed, 1, "x") })
+	require.NoError(t, billing.RegisterType(refundFailed, ex.TypeInfo{Name: "RefundFailed", Severity: ex.SeverityError}))
+	assert.NotPanics(t, func() { billing.New(refundFailed, 1, "x") })
+	assert.ErrorIs(t, billing.RegisterType(0, ex.TypeInfo{}), ex.ErrInvalidType)
+}
+
+func TestFactory_TypesDriveAdapters(t *testing.T) {
+	const cardDeclined = ex.ExType(4624)
+	billing := ex.NewFactory(ex.Config{
+		Types: map[ex.ExType]ex.TypeInfo{cardDeclined: {Name: "CardDeclined", HTTPStatus: 402, GRPCCode: 9}},
+	})
+
+	e := billing.New(cardDeclined, 1, "Card declined")
+	info, ok := ex.TypeInfoOf(fmt.Errorf("charge: %w", e.WithField("card", "visa")))
+	require.True(t, ok)
+	assert.Equal(t, 402, info.HTTPStatus)
+	info, ok = e.Info()
+	require.True(t, ok)
+	assert.Equal(t, "CardDeclined", info.Name)
+	assert.True(t, ex.IsClientFault(e))
+
+	outer := ex.New(ex.ExTypeApplicationFailure, 500, "Checkout failed").WithInnerError(e)
+	info, _ = ex.TypeInfoOf(outer)
+	assert.Equal(t, 500, info.HTTPStatus, "a package-made level uses the package registry")
+}
+
+func TestFactory_InheritTypes(t *testing.T) {
+	const shared = ex.ExType(4622)
+	require.NoError(t, ex.RegisterType(shared, ex.TypeInfo{Name: "Shared", HTTPStatus: 418}))
+
+	_, ok := ex.NewFactory(ex.Config{}).LookupType(shared)
+	assert.False(t, ok)
+
+	f := ex.NewFactory(ex.Config{Inherit: true, Types: map[ex.ExType]ex.TypeInfo{ex.ExTypeNotFound: {HTTPStatus: 410}}})
+	info, ok := f.LookupType(shared)
+	require.True(t, ok)
+	assert.Equal(t, 418, info.HTTPStatus)
+	info, _ = f.LookupType(ex.ExTypeNotFound)
+	assert.Equal(t, 410, info.HTTPStatus, "the factory's own metadata wins")
+}
+
+func TestFactory_StackOnFailureUsesFactoryTypes(t *testing.T) {
+	const fatal = ex.ExType(4623)
+	f := ex.NewFactory(ex.Config{
+		StackPolicy: ex.StackOnFailure,
+		Types:       map[ex.ExType]ex.TypeInfo{fatal: {Severity: ex.SeverityCritical}},
+	})
+	assert.True(t, f.New(fatal, 1, "x").HasStack())
+	assert.False(t, f.New(ex.ExTypeNotFound, 1, "x").HasStack())
+}
+
+func TestFactory_Catalog(t *testing.T) {
+	f := ex.NewFactory(ex.Config{})
+	declined := f.Define(ex.Entry{Code: ex.ExTypeIncorrectData, ID: 4031, Message: "Card declined", Remediation: "Use another card."})
+	assert.Panics(t, func() { f.Define(declined) })
+
+	got, ok := f.Lookup(ex.ExTypeIncorrectData, 4031)
+	require.True(t, ok)
+	assert.Equal(t, declined, got)
+	_, ok = ex.Lookup(ex.ExTypeIncorrectData, 4031)
+	assert.False(t, ok, "DefaultCatalog is untouched")
+	assert.Len(t, f.Catalog().Entries(), 1)
+
+	e := f.FromEntry(declined)
+	assert.True(t, declined.Is(e))
+	assert.Equal(t, "Use another card.", e.Hint())
+
+	_, ok = f.Lookup(errCardDeclined.Code, errCardDeclined.ID)
+	assert.False(t, ok)
+	_, ok = ex.NewFactory(ex.Config{Inherit: true}).Lookup(errCardDeclined.Code, errCardDeclined.ID)
+	assert.True(t, ok, "inheriting factories fall back to DefaultCatalog")
+}
+
+func TestFactory_Tracking(t *testing.T) {
+	f := ex.NewFactory(ex.Config{Tracking: true})
+	_ = f.New(ex.ExTypeUnavailable, 5031, "Cache unreachable") // forgotten
+	returned := f.Wrap(errors.New("EOF"), ex.ExTypeUnavailable, 5032, "Cache read failed")
+	_ = returned.Error()
+
+	unobserved := f.Unobserved(0)
+	require.Len(t, unobserved, 1)
+	assert.Equal(t, 5031, unobserved[0].ID)
+	assert.True(t, strings.HasSuffix(unobserved[0].Function, "TestFactory_Tracking"), unobserved[0].Function)
+	assert.Nil(t, ex.NewFactory(ex.Config{}).Unobserved(0))
+
+	trackCreations(t)
+	_ = ex.NewFactory(ex.Config{Inherit: true}).New(ex.ExTypeUnavailable, 5033, "Inherited")
+	_ = ex.NewFactory(ex.Config{}).New(ex.ExTypeUnavailable, 5034, "Isolated")
+	inherited := ex.Unobserved(0)
+	require.Len(t, inherited, 1, "only inheriting factories join the package's tracking")
+	assert.Equal(t, 5033, inherited[0].ID)
+}
@@ -0,0 +1,58 @@
+package ex
+
+// IsClientFault reports whether err was caused by the caller, such as bad
+// input or missing credentials: the first Exception in its chain has a
+// code registered with a 4xx HTTP status (see RegisterType). Retrying a
+// client fault unchanged will fail again.
+func IsClientFault(err error) bool {
+	status := faultStatus(err)
+	return status >= 400 && status < 500
+}
+
+// IsServerFault reports whether err is a failure of the service itself:
+// the first Exception in its chain has a code registered with a 5xx HTTP
+// status, or err carries no registered status at all, as is the case for
+// plain errors and unregistered codes. It reports false for a nil error.
+func IsServerFault(err error) bool {
+	if err == nil {
+		return false
+	}
+	status := faultStatus(err)
+	return status == 0 || status >= 500
+}
+
+// IsInternal reports whether the outermost Errorer in err's chain has a
+// code carrying FacetInternal, so that its message and details must not
+// reach clients whatever its status. It reports false for a nil error.
+func IsInternal(err error) bool {
+	code, ok := CodeOf(err)
+	return ok && code.Internal()
+}
+
+// faultStatus returns the HTTP status registered for err's code, or 0.
+func faultStatus(err error) int {
+	info, _ := TypeInfoOf(err)
+	return info.HTTPStatus
+}
+
+// Retryable reports whether retrying the operation that failed with err,
+// unchanged, may succeed. Transient failures are retryable: codes carrying
+// FacetTransient, rate limits, timeouts, and unavailable dependencies (see
+// IsTimeout and IsUnavailable). Other client faults (see IsClientFault)
+// are not, and everything else is, as retry policies assume by default.
+// It reports false for a nil error.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if e, ok := outermost(err); ok {
+		code := e.Code()
+		if code.Transient() || code.Category() == ExTypeRateLimited {
+			return true
+		}
+	}
+	if IsTimeout(err) || IsUnavailable(err) {
+		return true
+	}
+	return !IsClientFault(err)
+}
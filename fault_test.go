@@ -0,0 +1,68 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFault(t *testing.T) {
+	lenient(t)
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4531), ex.TypeInfo{HTTPStatus: 429}))
+	require.NoError(t, ex.RegisterType(ex.ExType(4532), ex.TypeInfo{HTTPStatus: 503}))
+
+	tests := []struct {
+		name   string
+		err    error
+		client bool
+		server bool
+	}{
+		{"IncorrectData", ex.New(ex.ExTypeIncorrectData, 400, "x"), true, false},
+		{"LoginRequired", ex.New(ex.ExTypeLoginRequired, 401, "x"), true, false},
+		{"PermissionDenied", ex.New(ex.ExTypePermissionDenied, 403, "x"), true, false},
+		{"ApplicationFailure", ex.New(ex.ExTypeApplicationFailure, 500, "x"), false, true},
+		{"NotFound", ex.New(ex.ExTypeNotFound, 404, "x"), true, false},
+		{"Conflict", ex.New(ex.ExTypeConflict, 409, "x"), true, false},
+		{"custom 4xx", ex.New(ex.ExType(4531), 1, "x"), true, false},
+		{"custom 5xx", ex.New(ex.ExType(4532), 1, "x"), false, true},
+		{"unregistered", ex.New(ex.ExType(4533), 1, "x"), false, true},
+		{"wrapped", fmt.Errorf("ctx: %w", ex.New(ex.ExTypeIncorrectData, 400, "x")), true, false},
+		{"plain", errors.New("x"), false, true},
+		{"nil", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.client, ex.IsClientFault(tt.err), "IsClientFault")
+			assert.Equal(t, tt.server, ex.IsServerFault(tt.err), "IsServerFault")
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"IncorrectData", ex.New(ex.ExTypeIncorrectData, 400, "x"), false},
+		{"NotFound", ex.New(ex.ExTypeNotFound, 404, "x"), false},
+		{"RateLimited", ex.New(ex.ExTypeRateLimited, 429, "x"), true},
+		{"transient client fault", ex.New(ex.ExTypeConflict|ex.FacetTransient, 409, "x"), true},
+		{"Timeout", ex.New(ex.ExTypeTimeout, 504, "x"), true},
+		{"Unavailable", ex.New(ex.ExTypeUnavailable, 503, "x"), true},
+		{"ApplicationFailure", ex.New(ex.ExTypeApplicationFailure, 500, "x"), true},
+		{"wrapped", fmt.Errorf("ctx: %w", ex.New(ex.ExTypeRateLimited, 429, "x")), true},
+		{"plain", errors.New("x"), true},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, ex.Retryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package ex
+
+import "log/slog"
+
+// Field is a key/value pair of structured context attached to an Exception.
+//
+// Exceptions copy their field slices but not the values in them: a value
+// of a reference type, such as a map or a slice, is shared with whoever
+// attached it. Treat such values as read-only once attached, since the
+// exception may be read from other goroutines.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F is a shorthand constructor for a Field.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// FieldAt returns a field that is only rendered where output at level is
+// wanted, for context that is too verbose or too expensive to serialize
+// every time, such as a full SQL query or a request dump:
+//
+//	ex.New(ex.ExTypeApplicationFailure, 5001, "Query failed").
+//	    WithFields(ex.FieldAt(slog.LevelDebug, "query", q))
+//
+// slogx.Log and the Log action render it only if the logger is enabled at
+// level, otlpx only if level is at or above the exception's log level,
+// and Prune only if the profile's FieldLevel is at or below level, in
+// which case the pruned chain holds it as an ordinary field. Renderings
+// without a level, among them the JSON encoding, Fingerprint, Snapshot,
+// and slogx.Attrs, leave it out.
+//
+// The level travels in the field's Value, which holds a wrapper around
+// value: read it back with FieldValue or ResolveValue, which unwrap it.
+func FieldAt(level slog.Level, key string, value any) Field {
+	return Field{Key: key, Value: leveledValue{level: level, value: attachValue(value)}}
+}
+
+// leveledValue is the Value of a field made with FieldAt.
+type leveledValue struct {
+	level slog.Level
+	value any
+}
+
+// Level returns the level set by FieldAt, and false for ordinary fields,
+// which are rendered at every level.
+func (f Field) Level() (slog.Level, bool) {
+	lv, ok := f.Value.(leveledValue)
+	return lv.level, ok
+}
+
+// isLeveled reports whether f was made with FieldAt.
+func isLeveled(f Field) bool {
+	_, ok := f.Value.(leveledValue)
+	return ok
+}
+
+// unleveled returns fields without those made with FieldAt, for
+// renderings that have no level. It returns fields itself when nothing is
+// left out.
+func unleveled(fields []Field) []Field {
+	return filterFields(fields, func(Field) bool { return false })
+}
+
+// filterFields returns the ordinary fields in fields and the leveled ones
+// keep accepts. It returns fields itself when nothing is left out.
+func filterFields(fields []Field, keep func(Field) bool) []Field {
+	n := 0
+	for _, f := range fields {
+		if !isLeveled(f) || keep(f) {
+			n++
+		}
+	}
+	if n == len(fields) {
+		return fields
+	}
+	out := make([]Field, 0, n)
+	for _, f := range fields {
+		if !isLeveled(f) || keep(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// FieldsAtLevel returns the fields that output at level renders: ordinary
+// fields, and fields made with FieldAt whose level is at or above level.
+// It returns fields itself when nothing is left out.
+func FieldsAtLevel(fields []Field, level slog.Level) []Field {
+	return filterFields(fields, func(f Field) bool {
+		l, _ := f.Level()
+		return l >= level
+	})
+}
+
+// WithField returns a new Exception with the field key set to value. If
+// the key is already present its value is replaced in place, so keys stay
+// unique and keep their original order.
+func (e Exception) WithField(key string, value any) Exception {
+	return e.WithFields(Field{Key: key, Value: value})
+}
+
+// WithFields returns a new Exception with the given fields set, following
+// the same replace-in-place rule as WithField. The receiver's fields are
+// never modified.
+func (e Exception) WithFields(fields ...Field) Exception {
+	if len(fields) == 0 {
+		return e
+	}
+	merged := make([]Field, len(e.fields), len(e.fields)+len(fields))
+	copy(merged, e.fields)
+	for _, f := range fields {
+		f.Value = attachValue(f.Value)
+		if i := indexField(merged, f.Key); i >= 0 {
+			merged[i] = f
+			continue
+		}
+		merged = append(merged, f)
+	}
+	e.fields = merged
+	e.frozen = nil
+	return e
+}
+
+// Fields returns a copy of the exception's fields in insertion order, or
+// nil if there are none. Fields of inner exceptions are not included.
+func (e Exception) Fields() []Field {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	out := make([]Field, len(e.fields))
+	copy(out, e.fields)
+	return out
+}
+
+// FieldValue returns the value of the field key on this exception, as
+// attached; for a field made with FieldAt, that is the value passed to it.
+func (e Exception) FieldValue(key string) (any, bool) {
+	if i := indexField(e.fields, key); i >= 0 {
+		if lv, ok := e.fields[i].Value.(leveledValue); ok {
+			return lv.value, true
+		}
+		return e.fields[i].Value, true
+	}
+	return nil, false
+}
+
+// indexField returns the index of key in fields, or -1. Field sets are
+// small, so a linear scan beats a map on both time and allocations.
+func indexField(fields []Field, key string) int {
+	for i := range fields {
+		if fields[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,112 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestException_WithField(t *testing.T) {
+	base := ex.New(ex.ExTypeIncorrectData, 400, "Invalid input")
+	withField := base.WithField("field", "email")
+
+	assert.Nil(t, base.Fields(), "receiver must be left unchanged")
+	assert.Equal(t, []ex.Field{ex.F("field", "email")}, withField.Fields())
+
+	v, ok := withField.FieldValue("field")
+	assert.True(t, ok)
+	assert.Equal(t, "email", v)
+
+	_, ok = withField.FieldValue("missing")
+	assert.False(t, ok)
+}
+
+func TestException_WithFields_ReplacesInPlace(t *testing.T) {
+	exc := ex.New(ex.ExTypeIncorrectData, 400, "Invalid input").
+		WithFields(ex.F("a", 1), ex.F("b", 2)).
+		WithFields(ex.F("a", 10), ex.F("c", 3))
+
+	assert.Equal(t, []ex.Field{ex.F("a", 10), ex.F("b", 2), ex.F("c", 3)}, exc.Fields())
+	assert.Equal(t, exc, exc.WithFields(), "no fields is a no-op")
+}
+
+func TestException_Fields_NoAliasing(t *testing.T) {
+	// Two exceptions derived from the same parent must not share backing
+	// storage, even when the parent's slice has spare capacity.
+	parent := ex.New(ex.ExTypeIncorrectData, 400, "msg").WithFields(ex.F("a", 1))
+	left := parent.WithField("b", "left")
+	right := parent.WithField("b", "right")
+
+	lv, _ := left.FieldValue("b")
+	rv, _ := right.FieldValue("b")
+	assert.Equal(t, "left", lv)
+	assert.Equal(t, "right", rv)
+	assert.Len(t, parent.Fields(), 1)
+
+	// Mutating the returned copy does not affect the exception.
+	fields := left.Fields()
+	fields[0].Value = "changed"
+	v, _ := left.FieldValue("a")
+	assert.Equal(t, 1, v)
+}
+
+func TestException_FieldsJSON(t *testing.T) {
+	exc := ex.New(ex.ExTypeIncorrectData, 400, "Invalid input").
+		WithFields(ex.F("zeta", "z"), ex.F("alpha", 1))
+
+	data, err := exc.MarshalCanonical()
+	require.NoError(t, err)
+	assert.Equal(t, `{"code":1,"id":400,"message":"Invalid input","fields":{"alpha":1,"zeta":"z"}}`, string(data))
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []ex.Field{ex.F("alpha", float64(1)), ex.F("zeta", "z")}, decoded.Fields())
+}
+
+func TestFieldAt(t *testing.T) {
+	query := ex.FieldAt(slog.LevelDebug, "query", "SELECT 1")
+	level, ok := query.Level()
+	assert.True(t, ok)
+	assert.Equal(t, slog.LevelDebug, level)
+	_, ok = ex.F("table", "orders").Level()
+	assert.False(t, ok)
+
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "Query failed").
+		WithFields(ex.F("table", "orders"), query, ex.FieldAt(slog.LevelWarn, "plan", "seq scan"))
+	v, found := e.FieldValue("query")
+	assert.True(t, found)
+	assert.Equal(t, "SELECT 1", v)
+
+	fields := e.Fields()
+	assert.Equal(t, fields, ex.FieldsAtLevel(fields, slog.LevelDebug))
+	assert.Equal(t, []string{"table", "plan"}, fieldKeys(ex.FieldsAtLevel(fields, slog.LevelInfo)))
+	assert.Equal(t, []string{"table"}, fieldKeys(ex.FieldsAtLevel(fields, slog.LevelError)))
+
+	assert.Equal(t, "SELECT 1", ex.ResolveValue(fields[1].Value))
+	assert.Equal(t, "{table orders}", fmt.Sprint(fields[0]), "Field is a plain key/value pair")
+
+	// Leveled fields are left out of renderings without a level.
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Equal(t, `{"code":4,"type":"ApplicationFailure","id":500,"message":"Query failed","fields":{"table":"orders"}}`, string(data))
+	plain := ex.New(ex.ExTypeApplicationFailure, 500, "Query failed").WithFields(ex.F("table", "orders"))
+	assert.Equal(t, plain.Fingerprint(), e.Fingerprint())
+
+	// Replacing a field replaces its level too.
+	e = e.WithField("query", "SELECT 2")
+	assert.Equal(t, []string{"table", "query", "plan"}, fieldKeys(ex.FieldsAtLevel(e.Fields(), slog.LevelInfo)))
+}
+
+// fieldKeys returns the keys of fields in order.
+func fieldKeys(fields []ex.Field) []string {
+	keys := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = f.Key
+	}
+	return keys
+}
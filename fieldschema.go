@@ -0,0 +1,129 @@
+package ex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrFieldSchema is returned by ValidateFields for exceptions whose fields
+// do not match the schema of their catalog entry.
+var ErrFieldSchema = errors.New("ex: fields do not match the catalog schema")
+
+// FieldSpec describes a field that exceptions built from a catalog Entry
+// carry, so that the details machines read from them stay consistent
+// across the teams producing them:
+//
+//	var ErrCardDeclined = ex.Define(ex.Entry{
+//	    Code: ex.ExTypeIncorrectData, ID: 4021,
+//	    Message: "The card was declined",
+//	    Fields: []ex.FieldSpec{
+//	        ex.FieldOf[string]("card_last4"),
+//	        ex.FieldOf[int]("attempt"),
+//	    },
+//	})
+//
+//	return ErrCardDeclined.New(ex.F("card_last4", "4242"), ex.F("attempt", 2))
+//
+// In strict mode (see SetStrict), Entry.New panics when given fields that
+// do not match the entry's specs; ValidateFields checks any exception.
+type FieldSpec struct {
+	Key string `json:"key"`
+
+	// Type is the type values must be assignable to; nil accepts any
+	// value. Use FieldOf to set it.
+	Type reflect.Type `json:"-"`
+
+	// Optional fields may be left out.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// FieldOf returns the spec of a required field key holding values of
+// type T. Set Optional on the result for a field that may be left out.
+func FieldOf[T any](key string) FieldSpec {
+	return FieldSpec{Key: key, Type: reflect.TypeFor[T]()}
+}
+
+// typeName returns the name of the spec's type, or "" if it accepts any
+// value.
+func (s FieldSpec) typeName() string {
+	if s.Type == nil {
+		return ""
+	}
+	return s.Type.String()
+}
+
+// ValidateFields checks the fields of the first Exception in err's chain
+// against the FieldSpecs of its entry in DefaultCatalog, and returns an
+// error wrapping ErrFieldSchema that lists every missing or mistyped
+// field. Exceptions without an entry, or whose entry has no specs, always
+// pass, and so do errors without an Exception. Fields the specs do not
+// mention are allowed. It is meant for tests:
+//
+//	require.NoError(t, ex.ValidateFields(err))
+func ValidateFields(err error) error {
+	e, ok := As(err)
+	if !ok {
+		return nil
+	}
+	entry, ok := Lookup(e.code, e.id)
+	if !ok {
+		return nil
+	}
+	if problems := fieldProblems(entry.Fields, e.fields); len(problems) > 0 {
+		return fmt.Errorf("%w: %s/%d: %s", ErrFieldSchema, e.code, e.id, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// fieldProblems describes each way fields fail to match specs.
+func fieldProblems(specs []FieldSpec, fields []Field) []string {
+	var problems []string
+	for _, s := range specs {
+		i := indexField(fields, s.Key)
+		if i < 0 {
+			if !s.Optional {
+				problems = append(problems, fmt.Sprintf("missing field %q", s.Key))
+			}
+			continue
+		}
+		if !assignable(fields[i].Value, s.Type) {
+			problems = append(problems, fmt.Sprintf("field %q is %T, want %s", s.Key, fields[i].Value, s.Type))
+		}
+	}
+	return problems
+}
+
+// assignable reports whether v may be stored in a variable of type t. A
+// nil t accepts anything, and nil is accepted for the types that have it
+// as their zero value. Lazy values (see LazyValue) are accepted unresolved,
+// since resolving them here would defeat their purpose.
+func assignable(v any, t reflect.Type) bool {
+	if lv, ok := v.(leveledValue); ok {
+		v = lv.value
+	}
+	if t == nil || isLazy(v) {
+		return true
+	}
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return true
+		}
+		return false
+	}
+	return reflect.TypeOf(v).AssignableTo(t)
+}
+
+// checkFields panics if strict mode is on and fields do not match the
+// specs of entry.
+func checkFields(strictOn bool, entry Entry, fields []Field) {
+	if !strictOn || len(entry.Fields) == 0 {
+		return
+	}
+	if problems := fieldProblems(entry.Fields, fields); len(problems) > 0 {
+		panic(fmt.Sprintf("ex: strict mode: %s/%d: %s; see the Fields of its catalog entry",
+			entry.Code, entry.ID, strings.Join(problems, "; ")))
+	}
+}
@@ -0,0 +1,86 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRefundRejected = ex.Define(ex.Entry{
+	Code: ex.ExTypeIncorrectData, ID: 4221,
+	Message: "Refund rejected",
+	Fields: []ex.FieldSpec{
+		ex.FieldOf[string]("order"),
+		ex.FieldOf[int]("amount"),
+		{Key: "reason", Optional: true},
+		{Key: "cause", Type: reflect.TypeFor[error](), Optional: true},
+	},
+})
+
+func TestValidateFields(t *testing.T) {
+	lenient(t)
+
+	valid := errRefundRejected.New(ex.F("order", "o-7"), ex.F("amount", 1200))
+	require.NoError(t, ex.ValidateFields(valid))
+	require.NoError(t, ex.ValidateFields(valid.WithField("reason", 3).WithField("extra", true)),
+		"untyped and unlisted fields are accepted")
+	require.NoError(t, ex.ValidateFields(valid.WithField("cause", nil)))
+	require.NoError(t, ex.ValidateFields(valid.WithField("cause", errors.New("boom"))))
+
+	err := ex.ValidateFields(errRefundRejected.New(ex.F("amount", "1200")).WithField("cause", 1))
+	require.ErrorIs(t, err, ex.ErrFieldSchema)
+	assert.ErrorContains(t, err, `IncorrectData/4221: missing field "order"; field "amount" is string, want int; field "cause" is int, want error`)
+
+	assert.NoError(t, ex.ValidateFields(ex.New(ex.ExTypeIncorrectData, 4999, "Not in the catalog")))
+	assert.NoError(t, ex.ValidateFields(errors.New("plain")))
+	assert.NoError(t, ex.ValidateFields(nil))
+}
+
+func TestEntryNew_StrictFields(t *testing.T) {
+	lenient(t)
+	assert.NotPanics(t, func() { errRefundRejected.New() }, "unchecked outside strict mode")
+
+	ex.SetStrict(true)
+
+	assert.PanicsWithValue(t,
+		`ex: strict mode: IncorrectData/4221: missing field "amount"; see the Fields of its catalog entry`,
+		func() { errRefundRejected.New(ex.F("order", "o-7")) })
+	assert.NotPanics(t, func() { errRefundRejected.New(ex.F("order", "o-7"), ex.F("amount", 1200)) })
+
+	f := ex.NewFactory(ex.Config{Strict: true})
+	assert.Panics(t, func() { f.FromEntry(errRefundRejected, ex.F("order", 7), ex.F("amount", 1)) })
+	e := f.FromEntry(errRefundRejected, ex.F("order", "o-7"), ex.F("amount", 1))
+	assert.Equal(t, []ex.Field{ex.F("order", "o-7"), ex.F("amount", 1)}, e.Fields())
+}
+
+func TestEntryNew_HooksSeeCompleteException(t *testing.T) {
+	entry := ex.Entry{
+		Code: ex.ExTypeIncorrectData, ID: 4222, Message: "Refund too large",
+		CodeString: "REFUND_TOO_LARGE", Remediation: "Refund at most the order total",
+	}
+	var seen ex.Exception
+	remove := ex.AddHook(func(e ex.Exception) { seen = e })
+	t.Cleanup(remove)
+
+	entry.New(ex.F("order", "o-7"))
+	assert.Equal(t, []ex.Field{ex.F("order", "o-7")}, seen.Fields())
+	assert.Equal(t, "Refund at most the order total", seen.Hint())
+	cs, ok := ex.CodeStringOf(seen)
+	require.True(t, ok)
+	assert.Equal(t, "REFUND_TOO_LARGE", cs)
+}
+
+func TestCatalogSnapshot_Fields(t *testing.T) {
+	c := ex.NewCatalog()
+	require.NoError(t, c.Register(errRefundRejected))
+
+	data, err := json.Marshal(c.Snapshot())
+	require.NoError(t, err)
+	assert.Contains(t, string(data),
+		`"fields":[{"key":"order","type":"string"},{"key":"amount","type":"int"},{"key":"reason","optional":true},{"key":"cause","type":"error","optional":true}]`)
+}
@@ -0,0 +1,125 @@
+package ex
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// DefaultFrameCacheSize is the default number of program counters whose
+// symbolized frames are cached.
+const DefaultFrameCacheSize = 4096
+
+// frameCache maps program counters to their symbolized frames. A single
+// PC can expand to several frames when calls were inlined.
+var frameCache = struct {
+	mu    sync.RWMutex
+	limit int
+	m     map[uintptr][]Frame
+}{limit: DefaultFrameCacheSize}
+
+// SetFrameCacheSize bounds the cache of symbolized stack frames used by
+// StackTrace, discarding its current contents. Hot paths tend to produce
+// the same stacks over and over, so the cache spares most of the runtime
+// symbol lookups when they are rendered repeatedly. Once the cache is
+// full it is cleared and refilled. A size of zero or less disables it.
+func SetFrameCacheSize(n int) {
+	frameCache.mu.Lock()
+	defer frameCache.mu.Unlock()
+	frameCache.limit = max(n, 0)
+	frameCache.m = nil
+}
+
+// symbolize resolves pcs, as captured by callers, into frames, innermost
+// call first, with file paths mapped by the SetSourceRoots table.
+func symbolize(pcs []uintptr) []Frame {
+	out, ok := cachedFrames(pcs)
+	if !ok {
+		out = symbolizeAll(pcs)
+	}
+	if sourceRoots.Load() != nil {
+		for i := range out {
+			out[i].File = MapSourcePath(out[i].File)
+		}
+	}
+	return out
+}
+
+// cachedFrames returns the frames for pcs if every one of them is cached.
+func cachedFrames(pcs []uintptr) ([]Frame, bool) {
+	frameCache.mu.RLock()
+	defer frameCache.mu.RUnlock()
+	if frameCache.m == nil {
+		return nil, false
+	}
+	out := make([]Frame, 0, len(pcs))
+	for _, pc := range pcs {
+		frames, ok := frameCache.m[pc]
+		if !ok {
+			return nil, false
+		}
+		out = append(out, frames...)
+	}
+	return out, true
+}
+
+// symbolizeAll resolves pcs in a single runtime.CallersFrames pass, which
+// sees each PC in the context of the ones around it, as the runtime needs
+// to adjust PCs and expand inlined calls correctly, and caches the frames
+// of each PC.
+func symbolizeAll(pcs []uintptr) []Frame {
+	out := make([]Frame, 0, len(pcs))
+	if len(pcs) == 0 {
+		return out
+	}
+	// starts[i] is the index in out of the first frame of pcs[i].
+	starts := make([]int, len(pcs)+1)
+	next := 0
+	iter := runtime.CallersFrames(pcs)
+	for {
+		f, more := iter.Next()
+		// Frames report the PC of the call instruction, one before the
+		// return PC captured. Frames matching no PC, such as those of
+		// inlined calls the runtime expanded itself, belong to the PC
+		// before them, or to the first PC if there is none.
+		for j := next; j < len(pcs); j++ {
+			if f.PC+1 == pcs[j] || f.PC == pcs[j] {
+				for ; next <= j; next++ {
+					starts[next] = len(out)
+				}
+				break
+			}
+		}
+		if next == 0 {
+			next = 1
+		}
+		out = append(out, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	for ; next <= len(pcs); next++ {
+		starts[next] = len(out)
+	}
+	cacheFrames(pcs, out, starts)
+	return out
+}
+
+// cacheFrames stores the frames of each of pcs, out[starts[i]:starts[i+1]]
+// for pcs[i], clearing the cache first if it would overflow. out is
+// copied, since callers may modify it.
+func cacheFrames(pcs []uintptr, out []Frame, starts []int) {
+	frameCache.mu.Lock()
+	defer frameCache.mu.Unlock()
+	limit := frameCache.limit
+	if limit == 0 {
+		return
+	}
+	if frameCache.m == nil || len(frameCache.m)+len(pcs) > limit {
+		frameCache.m = make(map[uintptr][]Frame, min(limit, 256))
+	}
+	frames := slices.Clone(out)
+	for i, pc := range pcs {
+		frameCache.m[pc] = frames[starts[i]:starts[i+1]:starts[i+1]]
+	}
+}
@@ -0,0 +1,76 @@
+package ex
+
+import "slices"
+
+// frozenState holds the renderings of a frozen exception.
+type frozenState struct {
+	// plain and tagged are the Error() text under FormatPlain and
+	// FormatTagged, so SetErrorFormat applies to exceptions frozen before
+	// it was called.
+	plain       string
+	tagged      string
+	fingerprint string
+	// json is the MarshalJSON output, or nil if encoding failed. It
+	// depends on the service name and taxonomy version it was computed
+	// under, which are recorded so a change to either is noticed.
+	json     []byte
+	service  string
+	taxonomy int
+}
+
+// Freeze returns a copy of e with its Error() text, Fingerprint, and JSON
+// encoding computed once and stored, for exceptions shared as
+// package-level sentinels and rendered over and over:
+//
+//	var ErrQuotaExceeded = ex.New(ex.ExTypeRateLimited, 4290, "Quota exceeded").
+//	    WithHint("Try again tomorrow.").
+//	    Freeze()
+//
+// Exceptions are immutable, so a frozen exception behaves exactly like
+// the unfrozen one; it is only cheaper to read. Deriving a new exception
+// from it with any With* method yields an ordinary, unfrozen copy.
+//
+// The stored encoding is used only while the service name (see
+// SetServiceName) and taxonomy version (see SetTaxonomyVersion) are those
+// in effect when Freeze ran, and the text is stored in both error formats
+// (see SetErrorFormat), so freezing at package initialization is safe.
+// Freeze a sentinel only once it is fully built, and only if its inner
+// errors, if any, always render the same way.
+func (e Exception) Freeze() Exception {
+	e.frozen = nil
+	f := &frozenState{
+		plain:       e.plainError(),
+		tagged:      e.taggedError(),
+		fingerprint: e.Fingerprint(),
+		service:     ServiceName(),
+		taxonomy:    TaxonomyVersion(),
+	}
+	if data, err := e.MarshalJSON(); err == nil {
+		f.json = data
+	}
+	e.sizeHint = len(f.plain) + 1
+	e.frozen = f
+	return e
+}
+
+// Frozen reports whether e was returned by Freeze.
+func (e Exception) Frozen() bool {
+	return e.frozen != nil
+}
+
+// text returns the stored Error() text in the current error format.
+func (f *frozenState) text() string {
+	if tagged() {
+		return f.tagged
+	}
+	return f.plain
+}
+
+// encoded returns a copy of the stored JSON encoding if it is still
+// current. It is safe to call on a nil receiver.
+func (f *frozenState) encoded() ([]byte, bool) {
+	if f == nil || f.json == nil || f.service != ServiceName() || f.taxonomy != TaxonomyVersion() {
+		return nil, false
+	}
+	return slices.Clone(f.json), true
+}
@@ -0,0 +1,84 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func frozenSentinel() ex.Exception {
+	return ex.New(ex.ExTypeRateLimited, 4290, "Quota exceeded").
+		WithFields(ex.F("plan", "free")).
+		WithHint("Try again tomorrow.").
+		WithInnerError(ex.New(ex.ExTypeRateLimited, 4291, "Daily limit reached"))
+}
+
+func TestFreeze_RendersLikeUnfrozen(t *testing.T) {
+	plain := frozenSentinel()
+	frozen := plain.Freeze()
+	assert.True(t, frozen.Frozen())
+	assert.False(t, plain.Frozen())
+
+	assert.Equal(t, plain.Error(), frozen.Error())
+	assert.Equal(t, plain.Size(), frozen.Size())
+	assert.Equal(t, "> "+plain.Error(), string(frozen.AppendError([]byte("> "))))
+	assert.Equal(t, plain.Fingerprint(), frozen.Fingerprint())
+
+	want, err := json.Marshal(plain)
+	require.NoError(t, err)
+	got, err := json.Marshal(frozen)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// The stored encoding is handed out as a copy.
+	data, err := frozen.MarshalJSON()
+	require.NoError(t, err)
+	data[0] = 'X'
+	again, err := frozen.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, want, again)
+
+	assert.True(t, errors.Is(frozen, ex.New(ex.ExTypeRateLimited, 4291, "")))
+}
+
+func TestFreeze_DerivedCopiesAreUnfrozen(t *testing.T) {
+	frozen := frozenSentinel().Freeze()
+	derivations := map[string]ex.Exception{
+		"WithField":        frozen.WithField("user", "u-1"),
+		"WithInnerError":   frozen.WithInnerError(errors.New("other")),
+		"WithCodeString":   frozen.WithCodeString("QUOTA"),
+		"WithHint":         frozen.WithHint("Upgrade."),
+		"WithDocsURL":      frozen.WithDocsURL("https://example.com"),
+		"WithStack":        frozen.WithStack(),
+		"WithTraceContext": frozen.WithTraceContext(ex.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}),
+		"WithDetail":       ex.WithDetail(frozen, ex.Help{}),
+		"Redacted":         frozen.Redacted(),
+		"Compact":          ex.Compact(frozen),
+	}
+	// Redacting and compacting this chain change nothing.
+	frozenJSON, err := json.Marshal(frozen)
+	require.NoError(t, err)
+	for name, d := range derivations {
+		assert.False(t, d.Frozen(), name)
+		data, marshalErr := json.Marshal(d)
+		require.NoError(t, marshalErr, name)
+		if name != "Redacted" && name != "Compact" {
+			assert.NotEqual(t, string(frozenJSON), string(data), name)
+		}
+	}
+	assert.Equal(t, "Quota exceeded: other", derivations["WithInnerError"].Error())
+}
+
+func TestFreeze_NoticesServiceName(t *testing.T) {
+	frozen := frozenSentinel().Freeze()
+	ex.SetServiceName("billing")
+	defer ex.SetServiceName("")
+
+	data, err := json.Marshal(frozen)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"origin":"billing"`)
+}
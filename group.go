@@ -0,0 +1,246 @@
+package ex
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Branch is one labeled branch of a Group: when it ran and how it ended.
+type Branch struct {
+	Label string
+	Start time.Time
+	End   time.Time
+	// Err is the error the branch failed with, or nil.
+	Err error
+}
+
+// Duration returns how long the branch ran.
+func (b Branch) Duration() time.Duration {
+	return b.End.Sub(b.Start)
+}
+
+// Group runs the branches of a scatter-gather operation and records the
+// label, timing, and outcome of each, so that when several fail, the
+// error tells which failed first, often the cause of the others:
+//
+//	g := ex.NewGroup()
+//	g.Go("inventory", func() error { return inventory.Reserve(ctx, order) })
+//	g.Go("pricing", func() error { return pricing.Quote(ctx, order) })
+//	if err := g.Wait(); err != nil {
+//	    var ge *ex.GroupError
+//	    if errors.As(err, &ge) {
+//	        log.Print(ge.Timeline())
+//	    }
+//	    return err
+//	}
+//
+// Branches timed elsewhere, such as by an errgroup, are recorded with Add,
+// and errors collected without a Group are combined with Join. A Group is
+// safe for concurrent use; it must not be reused after Wait.
+type Group struct {
+	start time.Time
+	wg    sync.WaitGroup
+
+	mu       sync.Mutex
+	branches []Branch
+}
+
+// NewGroup returns an empty Group whose timeline starts now.
+func NewGroup() *Group {
+	return &Group{start: time.Now()}
+}
+
+// Go runs fn in a new goroutine as the branch named label. A panic in fn
+// is recovered and becomes the branch's error, made by FromPanic, so one
+// branch cannot crash the process.
+func (g *Group) Go(label string, fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		b := Branch{Label: label, Start: time.Now()}
+		defer func() {
+			if v := recover(); v != nil {
+				b.Err = FromPanic(v)
+			}
+			b.End = time.Now()
+			g.Add(b)
+		}()
+		b.Err = fn()
+	}()
+}
+
+// Add records a branch that ran outside the group. A zero Start is taken
+// as the start of the group and a zero End as now, and an End before
+// Start is moved up to Start, so the timeline has no bogus offsets.
+func (g *Group) Add(b Branch) {
+	b = b.normalize(g.start, time.Now())
+	g.mu.Lock()
+	g.branches = append(g.branches, b)
+	g.mu.Unlock()
+}
+
+// Wait waits for the branches started with Go and returns a *GroupError
+// holding every branch if any failed, or nil.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return groupError(g.start, slices.Clone(g.branches))
+}
+
+// Join is the labeled counterpart of errors.Join for branches timed
+// elsewhere: it returns a *GroupError holding every branch if any failed,
+// or nil. The timeline starts at the earliest Start; zero times are
+// filled in as by Group.Add, with the timeline start and now.
+//
+//	err := ex.Join(
+//	    ex.Branch{Label: "inventory", Start: t0, End: t1, Err: errInventory},
+//	    ex.Branch{Label: "pricing", Start: t0, End: t2, Err: errPricing},
+//	)
+func Join(branches ...Branch) error {
+	now := time.Now()
+	start := now
+	for _, b := range branches {
+		if !b.Start.IsZero() && b.Start.Before(start) {
+			start = b.Start
+		}
+	}
+	normalized := make([]Branch, len(branches))
+	for i, b := range branches {
+		normalized[i] = b.normalize(start, now)
+	}
+	return groupError(start, normalized)
+}
+
+// normalize fills in a zero Start with start and a zero End with now, and
+// keeps End from preceding Start.
+func (b Branch) normalize(start, now time.Time) Branch {
+	if b.Start.IsZero() {
+		b.Start = start
+	}
+	if b.End.IsZero() {
+		b.End = now
+	}
+	if b.End.Before(b.Start) {
+		b.End = b.Start
+	}
+	return b
+}
+
+// groupError orders branches by end time and returns them as a
+// *GroupError if any failed, or nil.
+func groupError(start time.Time, branches []Branch) error {
+	slices.SortStableFunc(branches, func(a, b Branch) int {
+		return a.End.Compare(b.End)
+	})
+	for _, b := range branches {
+		if b.Err != nil {
+			return &GroupError{start: start, branches: branches}
+		}
+	}
+	return nil
+}
+
+// GroupError is the error of a Group or Join with failed branches. Like
+// the errors of errors.Join, it unwraps to the errors of the failed
+// branches, so errors.Is, errors.As, and FromJoined see each of them.
+// Explain labels each branch, and the JSON encoding keeps the branches
+// with their labels and timings.
+type GroupError struct {
+	start time.Time
+	// branches is ordered by end time.
+	branches []Branch
+}
+
+// Error lists the failed branches in the order they failed, one per line,
+// each as its label and error.
+func (e *GroupError) Error() string {
+	var b strings.Builder
+	for _, br := range e.Failed() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(br.Label + ": " + br.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors of the failed branches, in the order they
+// failed.
+func (e *GroupError) Unwrap() []error {
+	var errs []error
+	for _, br := range e.Failed() {
+		errs = append(errs, br.Err)
+	}
+	return errs
+}
+
+// Branches returns every branch of the group, failed or not, in the order
+// they ended.
+func (e *GroupError) Branches() []Branch {
+	return slices.Clone(e.branches)
+}
+
+// Failed returns the failed branches, in the order they failed.
+func (e *GroupError) Failed() []Branch {
+	var out []Branch
+	for _, br := range e.branches {
+		if br.Err != nil {
+			out = append(out, br)
+		}
+	}
+	return out
+}
+
+// First returns the branch that failed first.
+func (e *GroupError) First() Branch {
+	for _, br := range e.branches {
+		if br.Err != nil {
+			return br
+		}
+	}
+	return Branch{}
+}
+
+// Timeline renders the branches as a table in the order they ended, with
+// their start and end relative to the start of the group, for logs and
+// incident notes:
+//
+//	START  END    BRANCH     RESULT
+//	0s     35ms   inventory  FAILED FIRST: Out of stock
+//	1ms    120ms  pricing    ok
+//	2ms    5s     shipping   FAILED: Quote timed out
+//
+// Times are rounded to the millisecond.
+func (e *GroupError) Timeline() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = w.Write([]byte("START\tEND\tBRANCH\tRESULT\n"))
+	first := true
+	for _, br := range e.branches {
+		result := "ok"
+		if br.Err != nil {
+			result = "FAILED: "
+			if first {
+				result, first = "FAILED FIRST: ", false
+			}
+			result += strings.ReplaceAll(br.Err.Error(), "\n", "; ")
+		}
+		_, _ = w.Write([]byte(offset(br.Start, e.start) + "\t" + offset(br.End, e.start) + "\t" + br.Label + "\t" + result + "\n"))
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
+// offset returns t relative to start, rounded to the millisecond.
+func offset(t, start time.Time) string {
+	return elapsed(t, start).String()
+}
+
+// elapsed returns the time from start to t, rounded to the millisecond.
+func elapsed(t, start time.Time) time.Duration {
+	return t.Sub(start).Round(time.Millisecond)
+}
@@ -0,0 +1,177 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_Go(t *testing.T) {
+	g := ex.NewGroup()
+	stock := ex.New(ex.ExTypeNotFound, 4041, "Out of stock")
+	release := make(chan struct{})
+	g.Go("inventory", func() error { return stock })
+	g.Go("pricing", func() error { return nil })
+	g.Go("shipping", func() error {
+		<-release
+		return fs.ErrNotExist
+	})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	err := g.Wait()
+	require.Error(t, err)
+	var ge *ex.GroupError
+	require.ErrorAs(t, err, &ge)
+
+	assert.Len(t, ge.Branches(), 3)
+	assert.Equal(t, "inventory", ge.First().Label)
+	assert.Equal(t, "inventory: Out of stock\nshipping: file does not exist", err.Error())
+	assert.ErrorIs(t, err, stock)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	for _, b := range ge.Branches() {
+		assert.False(t, b.End.Before(b.Start), b.Label)
+	}
+
+	exceptions := ex.FromJoined(err)
+	require.Len(t, exceptions, 2)
+	value := func(e ex.Exception, key string) any {
+		v, _ := e.FieldValue(key)
+		return v
+	}
+	assert.Equal(t, 4041, exceptions[0].ID())
+	assert.Equal(t, "inventory", value(exceptions[0], "group.branch"))
+	assert.Equal(t, 1, value(exceptions[0], "group.order"))
+	assert.Equal(t, "shipping", value(exceptions[1], "group.branch"))
+	assert.Equal(t, 2, value(exceptions[1], "group.order"))
+	assert.IsType(t, time.Duration(0), value(exceptions[1], "group.elapsed"))
+}
+
+func TestGroup_Panic(t *testing.T) {
+	g := ex.NewGroup()
+	g.Go("inventory", func() error { panic("out of bounds") })
+	g.Go("pricing", func() error { return nil })
+
+	var ge *ex.GroupError
+	require.ErrorAs(t, g.Wait(), &ge)
+	assert.Len(t, ge.Branches(), 2)
+	first := ge.First()
+	assert.Equal(t, "inventory", first.Label)
+	e, ok := ex.As(first.Err)
+	require.True(t, ok)
+	assert.Equal(t, ex.PanicID, e.ID())
+	v, ok := e.PanicValue()
+	require.True(t, ok)
+	assert.Equal(t, "out of bounds", v)
+	assert.False(t, first.End.Before(first.Start))
+}
+
+func TestGroup_AddZeroTimes(t *testing.T) {
+	g := ex.NewGroup()
+	g.Add(ex.Branch{Label: "cache", Err: errors.New("miss")})
+	end := time.Now()
+	g.Add(ex.Branch{Label: "pricing", Start: end.Add(time.Second), End: end, Err: errors.New("late")})
+
+	var ge *ex.GroupError
+	require.ErrorAs(t, g.Wait(), &ge)
+	for _, b := range ge.Branches() {
+		assert.False(t, b.Start.IsZero(), b.Label)
+		assert.False(t, b.End.IsZero(), b.Label)
+		assert.False(t, b.End.Before(b.Start), b.Label)
+	}
+	assert.NotContains(t, ge.Timeline(), "-", "no negative offsets")
+}
+
+func TestJoin(t *testing.T) {
+	assert.NoError(t, ex.Join())
+	assert.NoError(t, ex.Join(ex.Branch{Label: "pricing"}))
+
+	start := time.Now()
+	stock := ex.New(ex.ExTypeNotFound, 4041, "Out of stock")
+	err := ex.Join(
+		ex.Branch{Label: "shipping", Start: start, End: start.Add(5 * time.Second), Err: errors.New("quote timed out")},
+		ex.Branch{Label: "inventory", Start: start, End: start.Add(35 * time.Millisecond), Err: stock},
+		ex.Branch{Label: "pricing", Start: start.Add(time.Millisecond), End: start.Add(120 * time.Millisecond)},
+	)
+	var ge *ex.GroupError
+	require.ErrorAs(t, err, &ge)
+	assert.Equal(t, "inventory", ge.First().Label)
+	assert.ErrorIs(t, err, stock)
+	assert.Regexp(t, `0s\s+35ms\s+inventory\s+FAILED FIRST: Out of stock`, ge.Timeline())
+
+	assert.Equal(t, "1. What happened: 2 of 3 branches:\n"+
+		"   1.1. inventory, failed first at 35ms: Out of stock (NotFound/4041)\n"+
+		"   1.2. shipping, failed at 5s: quote timed out\n", ex.Explain(err))
+}
+
+func TestGroupError_JSON(t *testing.T) {
+	start := time.Now()
+	group := func(order ...int) error {
+		branches := []ex.Branch{
+			{Label: "inventory", Start: start, End: start.Add(35 * time.Millisecond), Err: ex.New(ex.ExTypeNotFound, 4041, "Out of stock")},
+			{Label: "pricing", Start: start, End: start.Add(120 * time.Millisecond)},
+			{Label: "shipping", Start: start, End: start.Add(5 * time.Second), Err: errors.New("quote timed out")},
+		}
+		var picked []ex.Branch
+		for _, i := range order {
+			picked = append(picked, branches[i])
+		}
+		return ex.Join(picked...)
+	}
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").WithInnerError(group(0, 1, 2))
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	var ge *ex.GroupError
+	require.ErrorAs(t, decoded, &ge)
+	require.Len(t, ge.Branches(), 3)
+	assert.Equal(t, "inventory", ge.First().Label)
+	assert.Equal(t, 35*time.Millisecond, ge.First().Duration())
+	assert.NoError(t, ge.Branches()[1].Err)
+	assert.Equal(t, ex.Explain(e), ex.Explain(decoded))
+	assert.Regexp(t, `pricing\s+ok`, ge.Timeline())
+
+	later := ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").WithInnerError(group(2, 1, 0))
+	assert.Equal(t, e.Fingerprint(), later.Fingerprint(), "timings and order do not change the fingerprint")
+}
+
+func TestGroup_NoFailures(t *testing.T) {
+	g := ex.NewGroup()
+	g.Go("pricing", func() error { return nil })
+	g.Add(ex.Branch{Label: "cache", Start: time.Now(), End: time.Now()})
+	assert.NoError(t, g.Wait())
+}
+
+func TestGroupError_Timeline(t *testing.T) {
+	g := ex.NewGroup()
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	g.Add(ex.Branch{Label: "shipping", Start: at(2), End: at(5000), Err: errors.New("quote timed out")})
+	g.Add(ex.Branch{Label: "pricing", Start: at(1), End: at(120)})
+	g.Add(ex.Branch{Label: "inventory", Start: at(0), End: at(35), Err: ex.New(ex.ExTypeNotFound, 4041, "Out of stock")})
+
+	var ge *ex.GroupError
+	require.ErrorAs(t, g.Wait(), &ge)
+	assert.Equal(t, []string{"inventory", "shipping"}, []string{ge.Failed()[0].Label, ge.Failed()[1].Label})
+	assert.Equal(t, 35*time.Millisecond, ge.Failed()[0].Duration())
+
+	timeline := ge.Timeline()
+	assert.Contains(t, timeline, "START")
+	assert.Regexp(t, `inventory\s+FAILED FIRST: Out of stock`, timeline)
+	assert.Regexp(t, `pricing\s+ok`, timeline)
+	assert.Regexp(t, `shipping\s+FAILED: quote timed out`, timeline)
+	assert.Less(t, strings.Index(timeline, "inventory"), strings.Index(timeline, "pricing"))
+	assert.Less(t, strings.Index(timeline, "pricing"), strings.Index(timeline, "shipping"))
+}
@@ -0,0 +1,281 @@
+// Package grpcx converts exceptions to gRPC statuses and back, and runs
+// server methods with panic recovery and an outcome callback.
+//
+// The module does not depend on grpc-go, so grpcx works on plain status
+// codes, messages, and metadata maps (metadata.MD is a
+// map[string][]string). Its interceptors take the few grpc-go calls they
+// need as functions, and binding them takes a line per interceptor:
+//
+//	server := grpcx.UnaryServerInterceptor(grpcx.ServerOptions{
+//	    Observe:    observe,
+//	    SetTrailer: func(ctx context.Context, md map[string][]string) error { return grpc.SetTrailer(ctx, md) },
+//	    Status:     func(code uint32, msg string) error { return status.Error(codes.Code(code), msg) },
+//	})
+//	srv := grpc.NewServer(grpc.UnaryInterceptor(
+//	    func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+//	        return server(ctx, info.FullMethod, func(ctx context.Context) (any, error) { return h(ctx, req) })
+//	    }))
+//
+//	client := grpcx.UnaryClientInterceptor(grpcx.ClientOptions{
+//	    StatusOf: func(err error) (uint32, string, bool) {
+//	        s, ok := status.FromError(err)
+//	        return uint32(s.Code()), s.Message(), ok
+//	    },
+//	})
+//	conn, err := grpc.NewClient(target, grpc.WithUnaryInterceptor(
+//	    func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+//	        return client(ctx, method, func() (map[string][]string, error) {
+//	            var md metadata.MD
+//	            err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&md))...)
+//	            return md, err
+//	        })
+//	    }))
+//
+// StreamServerInterceptor binds the same way around handler(srv, ss), and
+// StreamClientInterceptor converts the errors of a wrapped ClientStream's
+// RecvMsg using its Trailer method.
+package grpcx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// TrailerKey is the metadata key under which Trailer carries the encoded
+// exception. The "-bin" suffix makes gRPC transmit it as binary.
+const TrailerKey = "ex-error-bin"
+
+// gRPC status codes used by this package.
+const (
+	codeOK      uint32 = 0
+	codeUnknown uint32 = 2
+)
+
+// Code returns the gRPC status code for err: the GRPCCode registered for
+// the code of the first Exception in its chain (see ex.RegisterType), or
+// Unknown. A nil err yields OK.
+func Code(err error) uint32 {
+	if err == nil {
+		return codeOK
+	}
+	if info, ok := ex.TypeInfoOf(err); ok && info.GRPCCode != 0 {
+		return info.GRPCCode
+	}
+	return codeUnknown
+}
+
+// Message returns the status message for err. As with httpx.NewEnvelope,
+// only client faults (see ex.IsClientFault) report the exception's
+// message; server faults and codes carrying ex.FacetInternal report their
+// code name, since their messages may describe internals.
+func Message(err error) string {
+	e, ok := ex.AsErrorer(err)
+	if !ok {
+		return ex.ExTypeApplicationFailure.String()
+	}
+	if ex.IsClientFault(err) && !ex.IsInternal(err) && e.Message() != "" {
+		return e.Message()
+	}
+	return e.Code().String()
+}
+
+// Trailer returns the metadata carrying err in full, for FromStatus to
+// rebuild on the client: the JSON encoding of its redacted outermost
+// Exception, or of err promoted to one, with the type, taxonomy version,
+// origin, and trace context of every level. It returns nil for a nil err.
+//
+// The encoding includes every message of the chain, so only send it to
+// services that are trusted with them, as with the JSON encoding itself.
+func Trailer(err error) map[string][]string {
+	if err == nil {
+		return nil
+	}
+	e, ok := ex.As(err)
+	if !ok {
+		e = ex.Promote(err, nil)
+	}
+	data, mErr := e.Redacted().MarshalJSON()
+	if mErr != nil {
+		return nil
+	}
+	return map[string][]string{TrailerKey: {string(data)}}
+}
+
+// FromStatus rebuilds the error a server returned. If trailer carries an
+// encoded exception (see Trailer), it is decoded, with its fields, details,
+// and inner chain. Otherwise the exception is built from the status: its
+// code is the predefined code registered for the gRPC code, or
+// ExTypeApplicationFailure, and its ID is the gRPC code. An OK status
+// yields nil.
+func FromStatus(code uint32, message string, trailer map[string][]string) error {
+	if code == codeOK {
+		return nil
+	}
+	if values := trailer[TrailerKey]; len(values) > 0 {
+		var e ex.Exception
+		if json.Unmarshal([]byte(values[0]), &e) == nil {
+			return e
+		}
+	}
+	return ex.New(exTypeFor(code), int(code), message)
+}
+
+// exTypeFor returns the predefined code registered for the gRPC code.
+func exTypeFor(code uint32) ex.ExType {
+	for t := ex.ExTypeIncorrectData; t <= ex.ExTypeRateLimited; t++ {
+		if info, ok := ex.LookupType(t); ok && info.GRPCCode == code {
+			return t
+		}
+	}
+	return ex.ExTypeApplicationFailure
+}
+
+// Observer is called with the outcome of every method run by Serve, for
+// metrics: the full method name, the error it returned (nil on success),
+// and how long it took.
+type Observer func(method string, err error, elapsed time.Duration)
+
+// Serve runs a server method body. A panic in call is recovered as an
+// ex.FromPanic exception and returned like any other error, and observe,
+// if not nil, is called with the outcome before Serve returns.
+func Serve(method string, observe Observer, call func() error) (err error) {
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			err = ex.FromPanic(v)
+		}
+		if observe != nil {
+			observe(method, err, time.Since(start))
+		}
+	}()
+	return call()
+}
+
+// ServerOptions configures the server interceptors. Its functions stand
+// in for the grpc-go calls the interceptors need.
+type ServerOptions struct {
+	// Observe, if not nil, is called with the outcome of every call.
+	Observe Observer
+	// SetTrailer sends trailer metadata, as grpc.SetTrailer does. If nil,
+	// no trailer is sent and clients rebuild errors from the status alone.
+	SetTrailer func(ctx context.Context, md map[string][]string) error
+	// Status returns the error a handler returns for a status, as
+	// status.Error does. If nil, errors are returned unconverted.
+	Status func(code uint32, message string) error
+	// Profile prunes failures before they are converted (see ex.Prune).
+	// If nil, ex.ProfileClient is used, so clients get no trailer detail
+	// beyond the outermost level; services talking to trusted peers opt
+	// into more, for example with &ex.ProfileDebug for the full chain.
+	// Observe still sees the failure in full.
+	Profile *ex.Profile
+}
+
+// UnaryServerInterceptor returns the body of a unary server interceptor.
+// It runs handler like Serve, recovering panics and reporting the outcome
+// to Observe, and converts a failure to a status with its Trailer, so
+// that FromStatus rebuilds it on the client.
+func UnaryServerInterceptor(opts ServerOptions) func(ctx context.Context, method string, handler func(ctx context.Context) (any, error)) (any, error) {
+	return func(ctx context.Context, method string, handler func(ctx context.Context) (any, error)) (any, error) {
+		var resp any
+		err := Serve(method, opts.Observe, func() (callErr error) {
+			resp, callErr = handler(ctx)
+			return callErr
+		})
+		if err != nil {
+			return nil, opts.status(ctx, err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns the body of a stream server interceptor,
+// with the behavior of UnaryServerInterceptor. ctx is the stream's
+// context.
+func StreamServerInterceptor(opts ServerOptions) func(ctx context.Context, method string, handler func() error) error {
+	return func(ctx context.Context, method string, handler func() error) error {
+		if err := Serve(method, opts.Observe, handler); err != nil {
+			return opts.status(ctx, err)
+		}
+		return nil
+	}
+}
+
+// status sends err's trailer and returns its status error.
+func (o ServerOptions) status(ctx context.Context, err error) error {
+	profile := ex.ProfileClient
+	if o.Profile != nil {
+		profile = *o.Profile
+	}
+	if _, ok := ex.As(err); !ok {
+		err = ex.Promote(err, nil)
+	}
+	err = ex.Prune(err, profile)
+	if o.SetTrailer != nil {
+		_ = o.SetTrailer(ctx, Trailer(err))
+	}
+	if o.Status == nil {
+		return err
+	}
+	return o.Status(Code(err), Message(err))
+}
+
+// ClientOptions configures the client interceptors. Its functions stand
+// in for the grpc-go calls the interceptors need.
+type ClientOptions struct {
+	// Observe, if not nil, is called with the outcome of every call, after
+	// conversion.
+	Observe Observer
+	// StatusOf reports the code and message of a status error, as
+	// status.FromError does. If nil, errors are returned unconverted.
+	StatusOf func(err error) (code uint32, message string, ok bool)
+}
+
+// UnaryClientInterceptor returns the body of a unary client interceptor.
+// invoke makes the call and returns its trailer metadata and error; a
+// status error is rebuilt with FromStatus, so callers see the exception
+// the server returned.
+func UnaryClientInterceptor(opts ClientOptions) func(ctx context.Context, method string, invoke func() (map[string][]string, error)) error {
+	return func(_ context.Context, method string, invoke func() (map[string][]string, error)) error {
+		start := time.Now()
+		trailer, err := invoke()
+		err = opts.convert(err, trailer)
+		if opts.Observe != nil {
+			opts.Observe(method, err, time.Since(start))
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a function converting the errors of a
+// client stream, for a wrapped ClientStream's RecvMsg to call with the
+// stream's Trailer once RecvMsg fails. io.EOF, the end of the stream, is
+// returned as is. Streams have no single outcome, so Observe is not
+// called.
+func StreamClientInterceptor(opts ClientOptions) func(err error, trailer map[string][]string) error {
+	return func(err error, trailer map[string][]string) error {
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		return opts.convert(err, trailer)
+	}
+}
+
+// convert rebuilds the exception behind a status error.
+func (o ClientOptions) convert(err error, trailer map[string][]string) error {
+	if err == nil || o.StatusOf == nil {
+		return err
+	}
+	code, message, ok := o.StatusOf(err)
+	if !ok {
+		return err
+	}
+	if rebuilt := FromStatus(code, message, trailer); rebuilt != nil {
+		return rebuilt
+	}
+	return err
+}
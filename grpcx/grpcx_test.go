@@ -0,0 +1,244 @@
+package grpcx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/grpcx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeAndMessage(t *testing.T) {
+	notFound := fmt.Errorf("lookup: %w", ex.New(ex.ExTypeNotFound, 4041, "No such user"))
+	assert.Equal(t, uint32(5), grpcx.Code(notFound))
+	assert.Equal(t, "No such user", grpcx.Message(notFound))
+
+	internal := ex.New(ex.ExTypeApplicationFailure, 5001, "pq: deadlock detected")
+	assert.Equal(t, uint32(13), grpcx.Code(internal))
+	assert.Equal(t, "ApplicationFailure", grpcx.Message(internal))
+
+	hidden := ex.New(ex.ExTypeNotFound|ex.FacetInternal, 4041, "No row in shard 7")
+	assert.Equal(t, uint32(5), grpcx.Code(hidden))
+	assert.Equal(t, "NotFound|Internal", grpcx.Message(hidden))
+
+	assert.Equal(t, uint32(2), grpcx.Code(errors.New("plain")))
+	assert.Equal(t, "ApplicationFailure", grpcx.Message(errors.New("plain")))
+	assert.Equal(t, uint32(0), grpcx.Code(nil))
+}
+
+func TestTrailerRoundTrip(t *testing.T) {
+	sent := ex.New(ex.ExTypeIncorrectData, 4001, "Validation failed").
+		WithField("email", "bad").
+		WithField("password", "hunter2").
+		WithInnerError(ex.New(ex.ExTypeNotFound, 4041, "No such plan"))
+	sent = ex.WithDetail(sent, ex.BadRequest{FieldViolations: []ex.BadField{{Field: "email", Description: "invalid"}}})
+
+	trailer := grpcx.Trailer(fmt.Errorf("rpc: %w", sent))
+	require.Contains(t, trailer, grpcx.TrailerKey)
+
+	err := grpcx.FromStatus(grpcx.Code(sent), grpcx.Message(sent), trailer)
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.True(t, e.Remote())
+	assert.True(t, errors.Is(err, ex.New(ex.ExTypeNotFound, 4041, "")))
+	v, _ := e.FieldValue("email")
+	assert.Equal(t, "bad", v)
+	v, _ = e.FieldValue("password")
+	assert.Equal(t, ex.RedactedValue, v)
+	br, ok := ex.Detail[ex.BadRequest](err)
+	require.True(t, ok)
+	assert.Equal(t, "email", br.FieldViolations[0].Field)
+
+	assert.Nil(t, grpcx.Trailer(nil))
+	assert.NotNil(t, grpcx.Trailer(errors.New("plain")))
+}
+
+func TestFromStatus_WithoutTrailer(t *testing.T) {
+	assert.NoError(t, grpcx.FromStatus(0, "", nil))
+
+	err := grpcx.FromStatus(14, "connection reset", nil)
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeUnavailable, e.Code())
+	assert.Equal(t, 14, e.ID())
+	assert.Equal(t, "connection reset", e.Message())
+
+	e, ok = ex.As(grpcx.FromStatus(12, "unimplemented", map[string][]string{grpcx.TrailerKey: {"not json"}}))
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeApplicationFailure, e.Code())
+}
+
+func TestServe(t *testing.T) {
+	type outcome struct {
+		method string
+		err    error
+	}
+	var got []outcome
+	observe := func(method string, err error, elapsed time.Duration) {
+		assert.GreaterOrEqual(t, elapsed, time.Duration(0))
+		got = append(got, outcome{method, err})
+	}
+
+	assert.NoError(t, grpcx.Serve("/svc/Ok", observe, func() error { return nil }))
+
+	failure := ex.New(ex.ExTypeConflict, 4091, "Already exists")
+	assert.Equal(t, failure.Error(), grpcx.Serve("/svc/Fail", observe, func() error { return failure }).Error())
+
+	err := grpcx.Serve("/svc/Panic", observe, func() error { panic("boom") })
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	v, ok := e.PanicValue()
+	assert.True(t, ok)
+	assert.Equal(t, "boom", v)
+	assert.Equal(t, uint32(13), grpcx.Code(err))
+
+	require.Len(t, got, 3)
+	assert.Equal(t, "/svc/Ok", got[0].method)
+	assert.NoError(t, got[0].err)
+	assert.Equal(t, "/svc/Panic", got[2].method)
+	assert.Equal(t, err, got[2].err)
+
+	assert.NoError(t, grpcx.Serve("/svc/NoObserver", nil, func() error { return nil }))
+}
+
+func TestTrailerRoundTrip_Metadata(t *testing.T) {
+	ex.SetServiceName("billing")
+	ex.SetTaxonomyVersion(3)
+	t.Cleanup(func() {
+		ex.SetServiceName("")
+		ex.SetTaxonomyVersion(0)
+	})
+	tc := ex.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	sent := ex.New(ex.ExTypeConflict, 4091, "Already exists").WithTraceContext(tc)
+
+	e, ok := ex.As(grpcx.FromStatus(grpcx.Code(sent), grpcx.Message(sent), grpcx.Trailer(sent)))
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeConflict, e.Code())
+	assert.Equal(t, 3, e.Taxonomy())
+	assert.Equal(t, "billing", e.Origin())
+	got, ok := e.TraceContext()
+	require.True(t, ok)
+	assert.Equal(t, tc, got)
+}
+
+// statusError stands in for a grpc-go status error.
+type statusError struct {
+	code    uint32
+	message string
+}
+
+func (s *statusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", s.code, s.message)
+}
+
+func statusOf(err error) (uint32, string, bool) {
+	var s *statusError
+	if !errors.As(err, &s) {
+		return 0, "", false
+	}
+	return s.code, s.message, true
+}
+
+func TestInterceptors_Unary(t *testing.T) {
+	var observed []string
+	observe := func(method string, err error, _ time.Duration) {
+		observed = append(observed, fmt.Sprintf("%s %v", method, err))
+	}
+	var trailer map[string][]string
+	server := grpcx.UnaryServerInterceptor(grpcx.ServerOptions{
+		Observe:    observe,
+		SetTrailer: func(_ context.Context, md map[string][]string) error { trailer = md; return nil },
+		Status:     func(code uint32, message string) error { return &statusError{code, message} },
+		Profile:    &ex.ProfileDebug,
+	})
+	client := grpcx.UnaryClientInterceptor(grpcx.ClientOptions{Observe: observe, StatusOf: statusOf})
+	call := func(handler func(context.Context) (any, error)) (any, error) {
+		var resp any
+		err := client(context.Background(), "/svc/Get", func() (map[string][]string, error) {
+			var callErr error
+			resp, callErr = server(context.Background(), "/svc/Get", handler)
+			return trailer, callErr
+		})
+		return resp, err
+	}
+
+	resp, err := call(func(context.Context) (any, error) { return "ok", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	sent := ex.New(ex.ExTypeNotFound, 4041, "No such user").WithField("user", "ada")
+	_, err = call(func(context.Context) (any, error) { return nil, sent })
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.True(t, e.Remote())
+	assert.True(t, errors.Is(err, sent))
+	v, _ := e.FieldValue("user")
+	assert.Equal(t, "ada", v)
+
+	_, err = call(func(context.Context) (any, error) { panic("boom") })
+	e, ok = ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeApplicationFailure, e.Code())
+
+	assert.Len(t, observed, 6)
+	assert.Equal(t, "/svc/Get <nil>", observed[0])
+}
+
+func TestInterceptors_Stream(t *testing.T) {
+	var trailer map[string][]string
+	server := grpcx.StreamServerInterceptor(grpcx.ServerOptions{
+		SetTrailer: func(_ context.Context, md map[string][]string) error { trailer = md; return nil },
+		Status:     func(code uint32, message string) error { return &statusError{code, message} },
+	})
+	client := grpcx.StreamClientInterceptor(grpcx.ClientOptions{StatusOf: statusOf})
+
+	assert.NoError(t, server(context.Background(), "/svc/Watch", func() error { return nil }))
+
+	sent := ex.New(ex.ExTypeRateLimited, 4291, "Slow down")
+	err := server(context.Background(), "/svc/Watch", func() error { return sent })
+	var s *statusError
+	require.ErrorAs(t, err, &s)
+	assert.Equal(t, grpcx.Code(sent), s.code)
+	assert.True(t, errors.Is(client(err, trailer), sent))
+
+	assert.Equal(t, io.EOF, client(io.EOF, nil))
+	plain := errors.New("transport closed")
+	assert.Equal(t, plain, client(plain, nil))
+}
+
+func TestInterceptors_Profile(t *testing.T) {
+	var trailer map[string][]string
+	var observed error
+	server := grpcx.UnaryServerInterceptor(grpcx.ServerOptions{
+		Observe:    func(_ string, err error, _ time.Duration) { observed = err },
+		SetTrailer: func(_ context.Context, md map[string][]string) error { trailer = md; return nil },
+		Status:     func(code uint32, message string) error { return &statusError{code, message} },
+	})
+
+	sent := ex.New(ex.ExTypeNotFound, 4041, "No such user").
+		WithField("user", "ada").
+		WithInnerError(errors.New("sql: no rows in result set"))
+	_, err := server(context.Background(), "/svc/Get", func(context.Context) (any, error) { return nil, sent })
+	var s *statusError
+	require.ErrorAs(t, err, &s)
+	assert.Equal(t, "No such user", s.message)
+	assert.Equal(t, error(sent), observed, "Observe sees the failure in full")
+	require.NotEmpty(t, trailer[grpcx.TrailerKey], "ProfileClient is the default")
+	assert.NotContains(t, trailer[grpcx.TrailerKey][0], "ada")
+
+	got, ok := grpcx.FromStatus(s.code, s.message, trailer).(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, 4041, got.ID())
+	assert.Empty(t, got.Fields())
+	assert.Nil(t, got.InnerError())
+
+	_, err = server(context.Background(), "/svc/Get", func(context.Context) (any, error) { return nil, errors.New("pq: deadlock") })
+	require.ErrorAs(t, err, &s)
+	assert.NotContains(t, trailer[grpcx.TrailerKey][0], "deadlock")
+}
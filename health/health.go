@@ -0,0 +1,266 @@
+// Package health turns exception classification into health signals.
+//
+// Subsystems report the outcome of their last operation, or of a periodic
+// probe, to a Checker; the Checker derives each subsystem's status from
+// the code and severity of its last exception and aggregates them into the
+// readiness and liveness summary served to orchestrators. Subsystems
+// that are external dependencies, such as a database, are listed in
+// Config.Dependencies, so that a plain error from a driver takes the
+// service out of rotation without getting it restarted:
+//
+//	checker := health.New(health.Config{
+//	    StaleAfter:   time.Minute,
+//	    Dependencies: []string{"postgres"},
+//	})
+//	http.Handle("/readyz", checker.ReadinessHandler())
+//	http.Handle("/livez", checker.LivenessHandler())
+//
+//	func (p *Pool) ping(ctx context.Context) {
+//	    checker.Report("postgres", p.db.PingContext(ctx))
+//	}
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// Status is the health of a subsystem or of the whole service.
+type Status int
+
+const (
+	// StatusOK means fully working.
+	StatusOK Status = iota
+
+	// StatusDegraded means working, with failures: the service stays
+	// ready, but something deserves a look.
+	StatusDegraded
+
+	// StatusDown means not working: the service is not ready.
+	StatusDown
+)
+
+// String returns "ok", "degraded", or "down".
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusDegraded:
+		return "degraded"
+	default:
+		return "down"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so statuses encode as
+// their names.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Classify derives the status of a subsystem from its last error.
+func Classify(err error) Status {
+	if err == nil || ex.IsExpected(err) {
+		return StatusOK
+	}
+	switch ex.SeverityOf(err) {
+	case ex.SeverityCritical:
+		return StatusDown
+	case ex.SeverityError, 0:
+		if ex.IsUnavailable(err) {
+			return StatusDown
+		}
+		return StatusDegraded
+	default:
+		return StatusOK
+	}
+}
+
+// Config configures a Checker. Zero values select the defaults.
+type Config struct {
+	// Classify derives a subsystem's status from its last error.
+	// Defaults to Classify: nil and expected errors (see
+	// ex.Exception.WithExpected) are OK, and so are errors of lower
+	// severity than SeverityError, which are the caller's fault;
+	// critical errors and unavailable dependencies (see ex.IsUnavailable)
+	// are down, and any other error, including plain ones, is degraded,
+	// except as described for Dependencies.
+	Classify func(error) Status
+
+	// Dependencies names the subsystems that are external dependencies
+	// the service cannot serve without, such as its database. An error
+	// from one that is not an exception, such as a driver's, says
+	// nothing about its cause, so one Classify rates degraded counts as
+	// down. Errors from dependencies never make the service not live,
+	// since restarting it does not bring a dependency back.
+	Dependencies []string
+
+	// StaleAfter, if positive, is how long a report counts: a subsystem
+	// that has not reported for longer is OK again, so one that only
+	// reports failures recovers by itself. Zero keeps reports forever.
+	StaleAfter time.Duration
+
+	// Messages includes exception messages in summaries. Leave it off
+	// for endpoints reachable from outside, since server messages may
+	// describe internals.
+	Messages bool
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Summary is the health of the service, as served by the handlers.
+type Summary struct {
+	// Status is the worst status of any subsystem.
+	Status Status `json:"status"`
+
+	// Ready is false when any subsystem is down; Live is false when the
+	// last exception of a subsystem other than a dependency is critical
+	// and not an unavailable dependency (see ex.IsUnavailable), which a
+	// restart may fix.
+	Ready bool `json:"ready"`
+	Live  bool `json:"live"`
+
+	Subsystems map[string]Subsystem `json:"subsystems"`
+}
+
+// Subsystem is the health of one subsystem in a Summary. Code, ID, and
+// Message describe its last exception, if any.
+type Subsystem struct {
+	Status  Status    `json:"status"`
+	Code    string    `json:"code,omitempty"`
+	ID      int       `json:"id,omitempty"`
+	Message string    `json:"message,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// report is the last report of a subsystem.
+type report struct {
+	err error
+	at  time.Time
+}
+
+// Checker collects subsystem reports. It is safe for concurrent use.
+type Checker struct {
+	cfg  Config
+	deps map[string]bool
+
+	mu      sync.Mutex
+	reports map[string]report
+}
+
+// New returns a Checker configured by cfg.
+func New(cfg Config) *Checker {
+	if cfg.Classify == nil {
+		cfg.Classify = Classify
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	deps := make(map[string]bool, len(cfg.Dependencies))
+	for _, name := range cfg.Dependencies {
+		deps[name] = true
+	}
+	return &Checker{cfg: cfg, deps: deps, reports: map[string]report{}}
+}
+
+// Report records err as the outcome of subsystem's last operation,
+// replacing its previous report. A nil err reports success.
+func (c *Checker) Report(subsystem string, err error) {
+	now := c.cfg.Now()
+	c.mu.Lock()
+	c.reports[subsystem] = report{err: err, at: now}
+	c.mu.Unlock()
+}
+
+// Remove forgets subsystem, for example when it is shut down.
+func (c *Checker) Remove(subsystem string) {
+	c.mu.Lock()
+	delete(c.reports, subsystem)
+	c.mu.Unlock()
+}
+
+// Summary returns the health of every subsystem that has reported, and of
+// the service as a whole.
+func (c *Checker) Summary() Summary {
+	now := c.cfg.Now()
+	c.mu.Lock()
+	names := make([]string, 0, len(c.reports))
+	for name := range c.reports {
+		names = append(names, name)
+	}
+	reports := make([]report, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		reports[i] = c.reports[name]
+	}
+	c.mu.Unlock()
+
+	s := Summary{Ready: true, Live: true, Subsystems: make(map[string]Subsystem, len(names))}
+	for i, name := range names {
+		r := reports[i]
+		sub := Subsystem{At: r.at}
+		if c.cfg.StaleAfter > 0 && now.Sub(r.at) > c.cfg.StaleAfter {
+			s.Subsystems[name] = sub
+			continue
+		}
+		dep := c.deps[name]
+		sub.Status = c.cfg.Classify(r.err)
+		e, isEx := ex.AsErrorer(r.err)
+		if dep && !isEx && sub.Status == StatusDegraded {
+			sub.Status = StatusDown
+		}
+		if isEx {
+			sub.Code, sub.ID = e.Code().String(), e.ID()
+			if c.cfg.Messages {
+				sub.Message = e.Message()
+			}
+		} else if r.err != nil && c.cfg.Messages {
+			sub.Message = r.err.Error()
+		}
+		if sub.Status == StatusDown {
+			s.Ready = false
+		}
+		if r.err != nil && !dep && !ex.IsUnavailable(r.err) && ex.SeverityAtLeast(ex.SeverityCritical)(r.err) {
+			s.Live = false
+		}
+		s.Status = max(s.Status, sub.Status)
+		s.Subsystems[name] = sub
+	}
+	return s
+}
+
+// ReadinessHandler serves the Summary as JSON, with status 200 when the
+// service is ready and 503 when it is not.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s := c.Summary()
+		write(w, s, s.Ready)
+	})
+}
+
+// LivenessHandler serves the Summary as JSON, with status 200 when the
+// service is live and 503 when it is not.
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s := c.Summary()
+		write(w, s, s.Live)
+	})
+}
+
+// write writes s with status 200 if ok and 503 otherwise.
+func write(w http.ResponseWriter, s Summary, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,115 @@
+package health_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, health.StatusOK, health.Classify(nil))
+	assert.Equal(t, health.StatusOK, health.Classify(ex.New(ex.ExTypeNotFound, 4041, "No such order")))
+	assert.Equal(t, health.StatusOK, health.Classify(ex.New(ex.ExTypeUnavailable, 5031, "Lock held").WithExpected(true)))
+	assert.Equal(t, health.StatusDegraded, health.Classify(ex.New(ex.ExTypeTimeout, 5041, "Slow query")))
+	assert.Equal(t, health.StatusDegraded, health.Classify(errors.New("plain")))
+	assert.Equal(t, health.StatusDown, health.Classify(ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable")))
+	assert.Equal(t, health.StatusDown, health.Classify(ex.WrapAuto(ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable"), 5001, "Query failed")))
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4961), ex.TypeInfo{Severity: ex.SeverityCritical}))
+	assert.Equal(t, health.StatusDown, health.Classify(ex.New(ex.ExType(4961), 1, "Disk full")))
+}
+
+func TestChecker_Summary(t *testing.T) {
+	c := health.New(health.Config{})
+	c.Report("cache", ex.New(ex.ExTypeTimeout, 5041, "Slow"))
+	c.Report("queue", nil)
+
+	s := c.Summary()
+	assert.Equal(t, health.StatusDegraded, s.Status)
+	assert.True(t, s.Ready)
+	assert.True(t, s.Live)
+	assert.Equal(t, health.StatusDegraded, s.Subsystems["cache"].Status)
+	assert.Equal(t, "Timeout", s.Subsystems["cache"].Code)
+	assert.Equal(t, 5041, s.Subsystems["cache"].ID)
+	assert.Empty(t, s.Subsystems["cache"].Message, "messages are off by default")
+	assert.Equal(t, health.StatusOK, s.Subsystems["queue"].Status)
+
+	c.Report("postgres", ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable"))
+	s = c.Summary()
+	assert.Equal(t, health.StatusDown, s.Status)
+	assert.False(t, s.Ready)
+	assert.True(t, s.Live)
+
+	c.Report("postgres", nil)
+	c.Remove("cache")
+	s = c.Summary()
+	assert.Equal(t, health.StatusOK, s.Status)
+	assert.Len(t, s.Subsystems, 2)
+}
+
+func TestChecker_Dependencies(t *testing.T) {
+	require.NoError(t, ex.RegisterType(ex.ExType(4963), ex.TypeInfo{Severity: ex.SeverityCritical}))
+	c := health.New(health.Config{Dependencies: []string{"postgres", "vault"}})
+	c.Report("postgres", errors.New("dial tcp: connection refused"))
+	c.Report("cache", errors.New("evicted"))
+
+	s := c.Summary()
+	assert.Equal(t, health.StatusDown, s.Subsystems["postgres"].Status, "plain errors from a dependency are down")
+	assert.Equal(t, health.StatusDegraded, s.Subsystems["cache"].Status)
+	assert.False(t, s.Ready)
+	assert.True(t, s.Live)
+
+	c.Report("vault", ex.New(ex.ExType(4963), 1, "Sealed"))
+	s = c.Summary()
+	assert.Equal(t, health.StatusDown, s.Subsystems["vault"].Status)
+	assert.True(t, s.Live, "a dependency never fails liveness")
+
+	c.Report("postgres", ex.New(ex.ExTypeTimeout, 5041, "Slow query"))
+	assert.Equal(t, health.StatusDegraded, c.Summary().Subsystems["postgres"].Status, "exceptions keep their classification")
+}
+
+func TestChecker_StaleAndMessages(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := health.New(health.Config{StaleAfter: time.Minute, Messages: true, Now: func() time.Time { return now }})
+	c.Report("postgres", ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable"))
+
+	s := c.Summary()
+	assert.Equal(t, "Database unreachable", s.Subsystems["postgres"].Message)
+	assert.Equal(t, now, s.Subsystems["postgres"].At)
+
+	now = now.Add(2 * time.Minute)
+	s = c.Summary()
+	assert.Equal(t, health.StatusOK, s.Status, "stale reports no longer count")
+	assert.True(t, s.Ready)
+}
+
+func TestChecker_Handlers(t *testing.T) {
+	require.NoError(t, ex.RegisterType(ex.ExType(4962), ex.TypeInfo{Severity: ex.SeverityCritical}))
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := health.New(health.Config{Now: func() time.Time { return now }})
+	c.Report("disk", ex.New(ex.ExType(4962), 1, "Disk full"))
+
+	rec := httptest.NewRecorder()
+	c.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "down", body["status"])
+	assert.Equal(t, false, body["live"])
+
+	c.Report("disk", nil)
+	rec = httptest.NewRecorder()
+	c.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok","ready":true,"live":true,"subsystems":{"disk":{"status":"ok","at":"2026-01-02T03:04:05Z"}}}`, rec.Body.String())
+}
@@ -0,0 +1,80 @@
+package ex
+
+import "errors"
+
+// WithHint returns a copy of e carrying hint, a sentence telling the
+// reader what to do next, such as "Use a different payment method.".
+// Hints are meant for the people the error reaches, so unlike the message
+// they are shown to clients even for server faults: problem details
+// report them in a "hint" member, and Explain prints them.
+func (e Exception) WithHint(hint string) Exception {
+	e.hint = hint
+	e.frozen = nil
+	return e
+}
+
+// Hint returns the hint set with WithHint, or "".
+func (e Exception) Hint() string {
+	return e.hint
+}
+
+// WithDocsURL returns a copy of e linking to documentation about this
+// particular failure. It takes precedence over the DocsURL registered for
+// e's code (see TypeInfo), and problem details report it as the "type".
+func (e Exception) WithDocsURL(url string) Exception {
+	e.docsURL = url
+	e.frozen = nil
+	return e
+}
+
+// DocsURL returns the link set with WithDocsURL, or "".
+func (e Exception) DocsURL() string {
+	return e.docsURL
+}
+
+// HintOf returns the hint of the first Errorer in err's chain that has
+// one. Errorers other than Exception have one if they provide a
+// Hint() string method.
+func HintOf(err error) (string, bool) {
+	return chainString(err, func(e Errorer) string {
+		h, _ := e.(interface{ Hint() string })
+		if h == nil {
+			return ""
+		}
+		return h.Hint()
+	})
+}
+
+// DocsURLOf returns the documentation link for err: that of the first
+// Errorer in its chain with a DocsURL() string method returning one, and
+// failing that the DocsURL registered for the code of the outermost
+// Errorer.
+func DocsURLOf(err error) (string, bool) {
+	url, ok := chainString(err, func(e Errorer) string {
+		d, _ := e.(interface{ DocsURL() string })
+		if d == nil {
+			return ""
+		}
+		return d.DocsURL()
+	})
+	if ok {
+		return url, true
+	}
+	if info, found := TypeInfoOf(err); found && info.DocsURL != "" {
+		return info.DocsURL, true
+	}
+	return "", false
+}
+
+// chainString returns the first non-empty get(e) among the Errorers in
+// err's chain.
+func chainString(err error, get func(Errorer) string) (string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(Errorer); ok {
+			if s := get(e); s != "" {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
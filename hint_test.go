@@ -0,0 +1,74 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHintAndDocsURL(t *testing.T) {
+	inner := ex.New(ex.ExTypeIncorrectData, 4022, "Card expired").
+		WithHint("Update the card's expiry date.").
+		WithDocsURL("https://docs.example.com/errors/4022")
+	err := fmt.Errorf("charge: %w", ex.New(ex.ExTypeApplicationFailure, 500, "Checkout failed").WithInnerError(inner))
+
+	assert.Equal(t, "Update the card's expiry date.", inner.Hint())
+	assert.Equal(t, "https://docs.example.com/errors/4022", inner.DocsURL())
+
+	hint, ok := ex.HintOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, "Update the card's expiry date.", hint)
+	url, ok := ex.DocsURLOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, "https://docs.example.com/errors/4022", url)
+
+	_, ok = ex.HintOf(errors.New("plain"))
+	assert.False(t, ok)
+	_, ok = ex.DocsURLOf(ex.New(ex.ExTypeNotFound, 404, "x"))
+	assert.False(t, ok)
+}
+
+func TestDocsURLOf_FallsBackToType(t *testing.T) {
+	code := ex.ExType(7301)
+	require.NoError(t, ex.RegisterType(code, ex.TypeInfo{Name: "Documented", DocsURL: "https://docs.example.com/types/documented"}))
+
+	url, ok := ex.DocsURLOf(ex.New(code, 1, "x"))
+	assert.True(t, ok)
+	assert.Equal(t, "https://docs.example.com/types/documented", url)
+
+	url, _ = ex.DocsURLOf(ex.New(code, 1, "x").WithDocsURL("https://docs.example.com/errors/1"))
+	assert.Equal(t, "https://docs.example.com/errors/1", url)
+}
+
+func TestHint_JSONRoundTripAndCatalog(t *testing.T) {
+	e := ex.New(ex.ExTypeIncorrectData, 4022, "Card expired").
+		WithHint("Update the card.").
+		WithDocsURL("https://docs.example.com/errors/4022")
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"hint":"Update the card."`)
+	assert.Contains(t, string(data), `"docs_url":"https://docs.example.com/errors/4022"`)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "Update the card.", decoded.Hint())
+	assert.Equal(t, "https://docs.example.com/errors/4022", decoded.DocsURL())
+
+	entry := ex.Entry{Code: ex.ExTypeIncorrectData, ID: 4023, Remediation: "Try again.", DocsURL: "https://docs.example.com/errors/4023"}
+	assert.Equal(t, "Try again.", entry.New().Hint())
+	assert.Equal(t, "https://docs.example.com/errors/4023", entry.New().DocsURL())
+}
+
+func TestExplain_HintAndDocsURL(t *testing.T) {
+	e := ex.New(ex.ExTypeIncorrectData, 4024, "Card expired").
+		WithHint("Update the card.").
+		WithDocsURL("https://docs.example.com/errors/4024")
+	assert.Equal(t, "1. What happened: Card expired (IncorrectData/4024)\n"+
+		"   What to do: Update the card.\n"+
+		"   See: https://docs.example.com/errors/4024\n", ex.Explain(e))
+}
@@ -0,0 +1,102 @@
+package ex
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hook observes exceptions as they are created by New.
+//
+// Hooks run synchronously on the creating goroutine, so they must be fast
+// and safe for concurrent use. A hook that needs to do real work (network
+// I/O, encoding) should hand the exception off to a buffer or channel.
+type Hook func(Exception)
+
+// hookEntry gives each registration its own identity so the same func
+// value can be added twice and removed independently.
+type hookEntry struct {
+	fn Hook
+}
+
+// hookSet is a list of hooks published copy-on-write, so that running
+// them never locks.
+type hookSet struct {
+	mu    sync.Mutex
+	hooks atomic.Pointer[[]*hookEntry]
+}
+
+// hooks holds the hooks registered with AddHook.
+var hooks hookSet
+
+// AddHook registers h to be called for every Exception created by New and
+// returns a function that removes it. Calling remove more than once is
+// harmless.
+//
+// When no hooks are registered, New pays only a single atomic load.
+func AddHook(h Hook) (remove func()) {
+	return hooks.add(h)
+}
+
+// add registers h and returns a function that removes it.
+func (s *hookSet) add(h Hook) (remove func()) {
+	if h == nil {
+		return func() {}
+	}
+	entry := &hookEntry{fn: h}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next []*hookEntry
+	if cur := s.hooks.Load(); cur != nil {
+		next = append(next, *cur...)
+	}
+	next = append(next, entry)
+	s.hooks.Store(&next)
+
+	return func() { s.remove(entry) }
+}
+
+// remove unregisters entry if it is still registered.
+func (s *hookSet) remove(entry *hookEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.hooks.Load()
+	if cur == nil {
+		return
+	}
+	next := make([]*hookEntry, 0, len(*cur))
+	for _, e := range *cur {
+		if e != entry {
+			next = append(next, e)
+		}
+	}
+	if len(next) == 0 {
+		s.hooks.Store(nil)
+		return
+	}
+	s.hooks.Store(&next)
+}
+
+// active reports whether any hook is registered in s.
+func (s *hookSet) active() bool {
+	return s.hooks.Load() != nil
+}
+
+// run calls every hook in s with e.
+func (s *hookSet) run(e Exception) {
+	cur := s.hooks.Load()
+	if cur == nil {
+		return
+	}
+	for _, entry := range *cur {
+		entry.fn(e)
+	}
+}
+
+// runHooks calls every registered hook with e, unless e is quiet.
+func runHooks(e Exception) {
+	if e.quiet {
+		return
+	}
+	hooks.run(e)
+}
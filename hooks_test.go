@@ -0,0 +1,71 @@
+package ex_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddHook_CalledOnNew(t *testing.T) {
+	var seen []ex.Exception
+	remove := ex.AddHook(func(e ex.Exception) { seen = append(seen, e) })
+
+	ex.New(ex.ExTypeIncorrectData, 400, "first")
+	ex.New(ex.ExTypePermissionDenied, 403, "second")
+	remove()
+	ex.New(ex.ExTypeIncorrectData, 400, "after removal")
+
+	if assert.Len(t, seen, 2) {
+		assert.Equal(t, "first", seen[0].Message())
+		assert.Equal(t, ex.ExTypePermissionDenied, seen[1].Code())
+	}
+}
+
+func TestAddHook_IndependentRemoval(t *testing.T) {
+	var calls atomic.Int32
+	hook := func(ex.Exception) { calls.Add(1) }
+
+	removeFirst := ex.AddHook(hook)
+	removeSecond := ex.AddHook(hook)
+
+	ex.New(ex.ExTypeIncorrectData, 400, "both")
+	assert.Equal(t, int32(2), calls.Load())
+
+	removeFirst()
+	removeFirst() // removing twice is harmless
+	ex.New(ex.ExTypeIncorrectData, 400, "one")
+	assert.Equal(t, int32(3), calls.Load())
+
+	removeSecond()
+	ex.New(ex.ExTypeIncorrectData, 400, "none")
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestAddHook_Nil(t *testing.T) {
+	remove := ex.AddHook(nil)
+	assert.NotPanics(t, func() {
+		ex.New(ex.ExTypeIncorrectData, 400, "no hooks")
+		remove()
+	})
+}
+
+func TestAddHook_Concurrent(t *testing.T) {
+	var calls atomic.Int64
+	var wg sync.WaitGroup
+	const workers = 16
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			remove := ex.AddHook(func(ex.Exception) { calls.Add(1) })
+			ex.New(ex.ExTypeApplicationFailure, 500, "concurrent")
+			remove()
+		}()
+	}
+	wg.Wait()
+	// Each worker's own hook sees at least its own exception.
+	assert.GreaterOrEqual(t, calls.Load(), int64(workers))
+}
@@ -0,0 +1,122 @@
+package ex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+)
+
+// htmlLevel is the view model for one level of an error chain.
+type htmlLevel struct {
+	Title   string
+	Message string
+	Fields  []htmlField
+	Stack   string
+	Remote  bool
+}
+
+// htmlField is a field rendered as text.
+type htmlField struct {
+	Key   string
+	Value string
+}
+
+// htmlTemplate renders the chain as nested <details> elements so each
+// level can be collapsed independently. html/template escapes every
+// interpolated value.
+var htmlTemplate = template.Must(template.New("ex").Parse(
+	`<div class="ex-chain">` +
+		`{{range .}}<details open class="ex-level"><summary>{{.Title}}</summary>` +
+		`{{if .Message}}<p class="ex-message">{{.Message}}</p>{{end}}` +
+		`{{if .Fields}}<table class="ex-fields">{{range .Fields}}<tr><th>{{.Key}}</th><td>{{.Value}}</td></tr>{{end}}</table>{{end}}` +
+		`{{if .Stack}}<details class="ex-stack"><summary>{{if .Remote}}remote {{end}}stack</summary><pre>{{.Stack}}</pre></details>{{end}}` +
+		`{{end}}` +
+		`{{range .}}</details>{{end}}` +
+		`</div>`))
+
+// ToHTML renders err and its whole chain as an HTML fragment for internal
+// debug pages, in the spirit of net/http/pprof. Each chain level is a
+// collapsible <details> element showing the code, ID, message, fields,
+// and captured stack. All text is HTML-escaped and sensitive fields are
+// redacted (see SetSensitiveKeys).
+//
+// The fragment has no styling beyond the ex-* class names, so the host
+// page controls presentation. It returns "" for a nil error.
+func ToHTML(err error) template.HTML {
+	if err == nil {
+		return ""
+	}
+	var levels []htmlLevel
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		levels = append(levels, newHTMLLevel(cur))
+	}
+	var buf bytes.Buffer
+	if execErr := htmlTemplate.Execute(&buf, levels); execErr != nil {
+		return template.HTML(template.HTMLEscapeString(err.Error())) // #nosec G203 -- escaped above
+	}
+	return template.HTML(buf.String()) // #nosec G203 -- produced by html/template
+}
+
+// newHTMLLevel builds the view model for a single chain level.
+func newHTMLLevel(err error) htmlLevel {
+	if a, isAnnotation := err.(*annotation); isAnnotation {
+		level := htmlLevel{Title: "annotation"}
+		if a.op != "" {
+			level.Title += ": " + a.op
+		}
+		for _, f := range RedactFields(a.fields) {
+			level.Fields = append(level.Fields, htmlField{Key: f.Key, Value: fmt.Sprint(ResolveValue(f.Value))})
+		}
+		return level
+	}
+	e, ok := err.(Exception)
+	if !ok {
+		if other, isErrorer := err.(Errorer); isErrorer {
+			return errorerHTMLLevel(other)
+		}
+		if r, isRemote := err.(*RemoteError); isRemote {
+			return htmlLevel{Title: r.typeName + " (remote)", Message: r.message}
+		}
+		return htmlLevel{Title: fmt.Sprintf("%T", err), Message: err.Error()}
+	}
+	level := htmlLevel{
+		Title:   e.code.String() + " / " + strconv.Itoa(e.id),
+		Message: e.message,
+		Remote:  e.remote,
+	}
+	if e.codeString != "" {
+		level.Title += " / " + e.codeString
+	}
+	if e.remote {
+		level.Title += " (remote"
+		if e.origin != "" {
+			level.Title += ": " + e.origin
+		}
+		level.Title += ")"
+	}
+	for _, f := range RedactFields(e.fields) {
+		level.Fields = append(level.Fields, htmlField{Key: f.Key, Value: fmt.Sprint(ResolveValue(f.Value))})
+	}
+	if e.HasStack() {
+		level.Stack = FormatStack(e.StackTrace())
+	}
+	return level
+}
+
+// errorerHTMLLevel builds the view model for an Errorer other than
+// Exception from its read surface.
+func errorerHTMLLevel(e Errorer) htmlLevel {
+	level := htmlLevel{
+		Title:   e.Code().String() + " / " + strconv.Itoa(e.ID()),
+		Message: e.Message(),
+	}
+	for _, f := range RedactFields(e.Fields()) {
+		level.Fields = append(level.Fields, htmlField{Key: f.Key, Value: fmt.Sprint(ResolveValue(f.Value))})
+	}
+	if stack := e.StackTrace(); len(stack) > 0 {
+		level.Stack = FormatStack(stack)
+	}
+	return level
+}
@@ -0,0 +1,44 @@
+package ex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	inner := ex.New(ex.ExTypeIncorrectData, 400, "Bad <input>").
+		WithFields(ex.F("field", "email"), ex.F("password", "hunter2"))
+	err := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").
+		WithInnerError(inner.WithInnerError(errors.New("root & cause"))).
+		WithStack()
+
+	out := string(ex.ToHTML(err))
+
+	assert.True(t, strings.HasPrefix(out, `<div class="ex-chain">`))
+	assert.Equal(t, 3, strings.Count(out, `<details open class="ex-level">`))
+	assert.Equal(t, strings.Count(out, "<details"), strings.Count(out, "</details>"), "elements must balance")
+	assert.Contains(t, out, "<summary>ApplicationFailure / 500</summary>")
+	assert.Contains(t, out, "<summary>IncorrectData / 400</summary>")
+	assert.Contains(t, out, "<summary>*errors.errorString</summary>")
+
+	// Everything user-controlled is escaped.
+	assert.Contains(t, out, "Bad &lt;input&gt;")
+	assert.Contains(t, out, "root &amp; cause")
+	assert.NotContains(t, out, "<input>")
+
+	// Sensitive fields are redacted; others are shown.
+	assert.Contains(t, out, "<th>field</th><td>email</td>")
+	assert.Contains(t, out, ex.RedactedValue)
+	assert.NotContains(t, out, "hunter2")
+
+	assert.Contains(t, out, `<details class="ex-stack">`)
+	assert.Contains(t, out, "TestToHTML")
+}
+
+func TestToHTML_Nil(t *testing.T) {
+	assert.Equal(t, "", string(ex.ToHTML(nil)))
+}
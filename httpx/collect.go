@@ -0,0 +1,38 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bold-minds/ex"
+)
+
+// WithCollector returns a copy of ctx carrying a new ex.Collector, and the
+// collector itself. Errors passed to ex.Collect with the returned context
+// (or any context derived from it) accumulate in the collector.
+func WithCollector(ctx context.Context) (context.Context, *ex.Collector) {
+	c := ex.NewCollector()
+	return ex.ContextWithCollector(ctx, c), c
+}
+
+// Collecting is middleware that gives every request its own collector and
+// calls report with the request and the collected errors once next has
+// returned. report is not called when nothing was collected.
+//
+//	handler := httpx.Collecting(func(r *http.Request, errs []error) {
+//	    logger.Warn("request completed with errors", "path", r.URL.Path, "errors", errs)
+//	})(mux)
+func Collecting(report func(r *http.Request, errs []error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, c := WithCollector(r.Context())
+			r = r.WithContext(ctx)
+			defer func() {
+				if errs := c.Errors(); len(errs) > 0 && report != nil {
+					report(r, errs)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package httpx_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCollector(t *testing.T) {
+	ctx, c := httpx.WithCollector(context.Background())
+	assert.True(t, ex.Collect(ctx, errors.New("non-fatal")))
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestCollecting(t *testing.T) {
+	var reported []error
+	var reportedPath string
+	mw := httpx.Collecting(func(r *http.Request, errs []error) {
+		reportedPath = r.URL.Path
+		reported = errs
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ex.Collect(r.Context(), errors.New("cache miss storm"))
+		ex.Collect(r.Context(), ex.New(ex.ExTypeApplicationFailure, 500, "audit log unavailable"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "/orders", reportedPath)
+	require.Len(t, reported, 2)
+	assert.Equal(t, "audit log unavailable", reported[1].Error())
+}
+
+func TestCollecting_NothingCollected(t *testing.T) {
+	called := false
+	h := httpx.Collecting(func(*http.Request, []error) { called = true })(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, called)
+}
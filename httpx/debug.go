@@ -0,0 +1,104 @@
+package httpx
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// debugPage is the HTML inspector. Each entry's chain is rendered by
+// ex.ToHTML, which escapes and redacts its content.
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Recent errors</title></head>
+<body>
+<h1>Recent errors ({{len .}})</h1>
+{{range .}}<section class="ex-entry">
+<h2>{{.Time}}</h2>
+{{.HTML}}
+</section>
+{{else}}<p>No errors recorded.</p>
+{{end}}</body>
+</html>
+`))
+
+// debugEntry is the view model for one HTML entry.
+type debugEntry struct {
+	Time string
+	HTML template.HTML
+}
+
+// jsonEntry is the JSON representation of one entry. Errors that are not
+// exceptions are encoded with their message only.
+type jsonEntry struct {
+	Time  time.Time `json:"time"`
+	Error any       `json:"error"`
+}
+
+// DebugHandler serves the contents of store as an error inspector, in the
+// spirit of net/http/pprof. It is meant for staging environments without
+// full APM and should not be exposed publicly:
+//
+//	store := httpx.NewStore(200)
+//	defer ex.AddHook(store.Hook())()
+//	mux.Handle("/debug/errors", httpx.DebugHandler(store))
+//
+// Responses are HTML by default and JSON when the request has
+// ?format=json or prefers application/json. Entries are newest first, and
+// sensitive fields are redacted in both formats.
+func DebugHandler(store *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		entries := store.Entries()
+		if wantsJSON(r) {
+			writeDebugJSON(w, entries)
+			return
+		}
+		writeDebugHTML(w, entries)
+	})
+}
+
+// wantsJSON reports whether the client asked for JSON.
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeDebugJSON(w http.ResponseWriter, entries []Entry) {
+	out := make([]jsonEntry, len(entries))
+	for i, e := range entries {
+		out[i] = jsonEntry{Time: e.Time, Error: encodable(e.Err)}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func writeDebugHTML(w http.ResponseWriter, entries []Entry) {
+	out := make([]debugEntry, len(entries))
+	for i, e := range entries {
+		out[i] = debugEntry{Time: e.Time.Format(time.RFC3339Nano), HTML: ex.ToHTML(e.Err)}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = debugPage.Execute(w, out)
+}
+
+// encodable returns a JSON-encodable, redacted representation of err.
+func encodable(err error) any {
+	if e, ok := err.(ex.Exception); ok {
+		return e.Redacted()
+	}
+	return struct {
+		Message string `json:"message"`
+	}{err.Error()}
+}
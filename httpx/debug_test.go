@@ -0,0 +1,74 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebugStore() *httpx.Store {
+	store := httpx.NewStore(10)
+	store.Add(errors.New("plain <failure>"))
+	store.Add(ex.New(ex.ExTypePermissionDenied, 403, "Access denied").
+		WithFields(ex.F("user", "42"), ex.F("token", "secret-value")))
+	return store
+}
+
+func TestDebugHandler_HTML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httpx.DebugHandler(newDebugStore()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "Recent errors (2)")
+	assert.Contains(t, body, "PermissionDenied / 403")
+	assert.Contains(t, body, "plain &lt;failure&gt;")
+	assert.NotContains(t, body, "secret-value")
+	assert.Less(t, strings.Index(body, "PermissionDenied"), strings.Index(body, "plain &lt;failure&gt;"), "newest first")
+}
+
+func TestDebugHandler_JSON(t *testing.T) {
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/debug/errors?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/debug/errors", nil)
+			r.Header.Set("Accept", "application/json")
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		httpx.DebugHandler(newDebugStore()).ServeHTTP(rec, req)
+
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var entries []struct {
+			Error map[string]any `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "Access denied", entries[0].Error["message"])
+		assert.Equal(t, ex.RedactedValue, entries[0].Error["fields"].(map[string]any)["token"])
+		assert.Equal(t, "plain <failure>", entries[1].Error["message"])
+	}
+}
+
+func TestDebugHandler_MethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httpx.DebugHandler(httpx.NewStore(1)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/errors", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestDebugHandler_Empty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httpx.DebugHandler(httpx.NewStore(1)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+	assert.Contains(t, rec.Body.String(), "No errors recorded.")
+}
@@ -0,0 +1,2 @@
+// Package httpx integrates exceptions with net/http.
+package httpx
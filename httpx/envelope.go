@@ -0,0 +1,128 @@
+package httpx
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// Envelope is the standard JSON body written for error responses:
+//
+//	{"error":{"code":"PermissionDenied","id":403,"message":"Access denied"}}
+type Envelope struct {
+	Error EnvelopeError `json:"error"`
+}
+
+// EnvelopeError is the content of an Envelope. CodeString is the
+// symbolic code set with ex.Exception.WithCodeString, if any.
+type EnvelopeError struct {
+	Code       string `json:"code"`
+	ID         int    `json:"id"`
+	CodeString string `json:"code_string,omitempty"`
+	Message    string `json:"message"`
+}
+
+// NewEnvelope builds the envelope for err from the first Exception in its
+// chain. Errors that are not exceptions are reported as
+// ExTypeApplicationFailure.
+//
+// For server faults (status 500 and above), and for codes carrying
+// ex.FacetInternal, the message is the generic status text rather than
+// the exception's message, which may describe internals the client
+// should not see.
+func NewEnvelope(err error) Envelope {
+	return newEnvelope(err, StatusCode(err), false)
+}
+
+// newEnvelope is NewEnvelope for a response sent with status, keeping the
+// messages of server faults and internal codes if internal is set. A
+// fault is a server fault if either status or err's own status is.
+func newEnvelope(err error, status int, internal bool) Envelope {
+	server := status >= http.StatusInternalServerError || StatusCode(err) >= http.StatusInternalServerError
+	env := Envelope{Error: EnvelopeError{
+		Code: ex.ExTypeApplicationFailure.String(),
+		ID:   status,
+	}}
+	if e, ok := ex.AsErrorer(err); ok {
+		env.Error.Code = e.Code().String()
+		env.Error.ID = e.ID()
+		env.Error.Message = e.Message()
+		env.Error.CodeString, _ = ex.CodeStringOf(err)
+	}
+	if ((server || ex.IsInternal(err)) && !internal) || env.Error.Message == "" {
+		env.Error.Message = http.StatusText(status)
+	}
+	return env
+}
+
+// WriteError writes err as a JSON Envelope with the status given by
+// StatusCode.
+//
+// When err carries a quota (see ex.WithRateLimit), WriteError also sets
+// the RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers
+// and, once the reset time is known, Retry-After, both in seconds from
+// now.
+func WriteError(w http.ResponseWriter, err error) {
+	writeError(w, err, StatusCode(err), NewEnvelope(err))
+}
+
+// WriteErrorStatus is WriteError answering with status instead of the
+// status given by StatusCode, as an operator policy may ask for (see
+// policy.Engine.WriteError). A zero status keeps StatusCode(err). The
+// message is hidden as for a server fault if either status is 500 or
+// above.
+func WriteErrorStatus(w http.ResponseWriter, err error, status int) {
+	if status == 0 {
+		status = StatusCode(err)
+	}
+	writeError(w, err, status, newEnvelope(err, status, false))
+}
+
+// WriteErrorProfile is WriteError with the envelope built from err pruned
+// by p (see ex.Prune), for responses to the audience p describes. Profiles
+// keeping internal messages, such as ex.ProfileDebug for internal
+// callers, report the messages of server faults too. The status and
+// rate-limit headers still come from err.
+func WriteErrorProfile(w http.ResponseWriter, err error, p ex.Profile) {
+	status := StatusCode(err)
+	writeError(w, err, status, newEnvelope(ex.Prune(err, p), status, p.InternalMessages))
+}
+
+// writeError writes the response for err with status and the envelope
+// env.
+func writeError(w http.ResponseWriter, err error, status int, env Envelope) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	SetRateLimitHeaders(w.Header(), err)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(env)
+}
+
+// SetRateLimitHeaders sets the RateLimit-Limit, RateLimit-Remaining, and
+// RateLimit-Reset headers and, once the reset time is known, Retry-After
+// in h when err carries a quota (see ex.WithRateLimit), as WriteError
+// does. Handlers writing their own error responses call it before
+// WriteHeader.
+func SetRateLimitHeaders(h http.Header, err error) {
+	if rl, ok := ex.RateLimitOf(err); ok {
+		setRateLimitHeaders(h, rl, time.Now())
+	}
+}
+
+// setRateLimitHeaders describes rl in h, relative to now.
+func setRateLimitHeaders(h http.Header, rl ex.RateLimit, now time.Time) {
+	h.Set("RateLimit-Limit", strconv.Itoa(rl.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(rl.Remaining))
+	if rl.Reset.IsZero() {
+		return
+	}
+	// Round up so clients never retry before the window has reset.
+	secs := int(math.Ceil(rl.Reset.Sub(now).Seconds()))
+	secs = max(secs, 0)
+	h.Set("RateLimit-Reset", strconv.Itoa(secs))
+	h.Set("Retry-After", strconv.Itoa(secs))
+}
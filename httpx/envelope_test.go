@@ -0,0 +1,117 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	env := httpx.NewEnvelope(fmt.Errorf("handler: %w", ex.New(ex.ExTypePermissionDenied, 4031, "Access denied")))
+	assert.Equal(t, httpx.EnvelopeError{Code: "PermissionDenied", ID: 4031, Message: "Access denied"}, env.Error)
+
+	// Server faults hide internal messages.
+	env = httpx.NewEnvelope(ex.New(ex.ExTypeApplicationFailure, 5001, "pq: deadlock detected"))
+	assert.Equal(t, httpx.EnvelopeError{Code: "ApplicationFailure", ID: 5001, Message: "Internal Server Error"}, env.Error)
+
+	env = httpx.NewEnvelope(ex.New(ex.ExTypeNotFound|ex.FacetInternal, 4041, "No row in shard 7"))
+	assert.Equal(t, httpx.EnvelopeError{Code: "NotFound|Internal", ID: 4041, Message: "Not Found"}, env.Error, "so do internal codes")
+	assert.Equal(t, "NotFound|Internal", httpx.NewErrorFrame(ex.New(ex.ExTypeNotFound|ex.FacetInternal, 4041, "No row in shard 7")).Message)
+
+	env = httpx.NewEnvelope(errors.New("plain"))
+	assert.Equal(t, httpx.EnvelopeError{Code: "ApplicationFailure", ID: 500, Message: "Internal Server Error"}, env.Error)
+
+	env = httpx.NewEnvelope(ex.New(ex.ExTypeIncorrectData, 400, ""))
+	assert.Equal(t, "Bad Request", env.Error.Message)
+
+	env = httpx.NewEnvelope(ex.New(ex.ExTypeIncorrectData, 4021, "Card declined").WithCodeString("BILLING_CARD_DECLINED"))
+	assert.Equal(t, "BILLING_CARD_DECLINED", env.Error.CodeString)
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httpx.WriteError(rec, ex.New(ex.ExTypeLoginRequired, 401, "Login required"))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var env httpx.Envelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, "LoginRequired", env.Error.Code)
+	assert.Equal(t, "Login required", env.Error.Message)
+}
+
+func TestWriteErrorStatus(t *testing.T) {
+	err := ex.New(ex.ExTypeIncorrectData, 4001, "Email is taken")
+
+	rec := httptest.NewRecorder()
+	httpx.WriteErrorStatus(rec, err, http.StatusConflict)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var env httpx.Envelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, "Email is taken", env.Error.Message)
+
+	rec = httptest.NewRecorder()
+	httpx.WriteErrorStatus(rec, err, http.StatusServiceUnavailable)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, "Service Unavailable", env.Error.Message, "server statuses hide the message")
+
+	rec = httptest.NewRecorder()
+	httpx.WriteErrorStatus(rec, err, 0)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWriteError_RateLimited(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second)
+	err := ex.New(ex.ExTypeRateLimited, 4291, "Too many requests").
+		WithRateLimit(ex.RateLimit{Limit: 100, Remaining: 0, Reset: reset})
+
+	rec := httptest.NewRecorder()
+	httpx.WriteError(rec, err)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	retryAfter, convErr := strconv.Atoi(rec.Header().Get("Retry-After"))
+	require.NoError(t, convErr)
+	assert.InDelta(t, 30, retryAfter, 1)
+	assert.Equal(t, rec.Header().Get("Retry-After"), rec.Header().Get("RateLimit-Reset"))
+
+	plain := httptest.NewRecorder()
+	httpx.WriteError(plain, ex.New(ex.ExTypeRateLimited, 4291, "Too many requests"))
+	assert.Equal(t, http.StatusTooManyRequests, plain.Code)
+	assert.Empty(t, plain.Header().Get("Retry-After"))
+}
+
+func TestWriteErrorProfile(t *testing.T) {
+	err := ex.New(ex.ExTypeApplicationFailure, 5001, "pq: deadlock detected").
+		WithInnerError(errors.New("connection reset"))
+
+	rec := httptest.NewRecorder()
+	httpx.WriteErrorProfile(rec, err, ex.ProfileDebug)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var env httpx.Envelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, httpx.EnvelopeError{Code: "ApplicationFailure", ID: 5001, Message: "pq: deadlock detected"}, env.Error)
+
+	rec = httptest.NewRecorder()
+	httpx.WriteErrorProfile(rec, err, ex.ProfileClient)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, "Internal Server Error", env.Error.Message)
+
+	rec = httptest.NewRecorder()
+	httpx.WriteErrorProfile(rec, errors.New("plain"), ex.ProfileClient)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, httpx.EnvelopeError{Code: "ApplicationFailure", ID: 500, Message: "Internal Server Error"}, env.Error)
+}
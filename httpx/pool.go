@@ -0,0 +1,27 @@
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/bold-minds/ex"
+)
+
+// Pooling is middleware that gives every request its own ex.Pool,
+// available to handlers through ex.PoolFrom(r.Context()), and releases it
+// once next has returned. It is for extreme-throughput gateways only; read
+// the safety rules on ex.Pool before using it, since any exception built
+// through the pool is invalid once the request ends.
+//
+//	handler := httpx.Pooling(mux)
+//
+//	func lookup(ctx context.Context, id string) error {
+//	    pool := ex.PoolFrom(ctx)
+//	    return pool.WithFields(ex.New(ex.ExTypeNotFound, 4041, "No such order"), ex.F("order", id))
+//	}
+func Pooling(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pool := ex.AcquirePool()
+		defer pool.Release()
+		next.ServeHTTP(w, r.WithContext(ex.ContextWithPool(r.Context(), pool)))
+	})
+}
@@ -0,0 +1,24 @@
+package httpx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPooling(t *testing.T) {
+	var got *ex.Pool
+	handler := httpx.Pooling(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ex.PoolFrom(r.Context())
+		httpx.WriteError(w, got.WithFields(ex.New(ex.ExTypeNotFound, 4041, "No such order"), ex.F("order", "A-1")))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotNil(t, got)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,180 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bold-minds/ex"
+)
+
+// Limits of the realtime error encodings, in bytes.
+const (
+	// MaxCloseReason is the longest reason a WebSocket close frame can
+	// carry: its 125-byte payload less the 2-byte status code.
+	MaxCloseReason = 123
+
+	// MaxSSEData is the longest data line WriteSSEError writes.
+	MaxSSEData = 4096
+)
+
+// SSEEvent is the event name WriteSSEError gives error events.
+const SSEEvent = "error"
+
+// WebSocket close codes used by CloseCode and ParseCloseReason (RFC 6455
+// section 7.4.1 and the IANA registry).
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	ClosePolicyViolation = 1008
+	CloseInternalError   = 1011
+	CloseTryAgainLater   = 1013
+)
+
+// ErrorFrame is the payload realtime services send in place of an HTTP
+// error response: the JSON reason of a WebSocket close frame and the data
+// of an SSE error event. Code is numeric so that clients parse it without
+// knowing the server's registered code names.
+type ErrorFrame struct {
+	Code    ex.ExType `json:"code"`
+	ID      int       `json:"id"`
+	Message string    `json:"message,omitempty"`
+}
+
+// NewErrorFrame builds the frame for err from the first Errorer in its
+// chain; errors without one are reported as ExTypeApplicationFailure. As
+// with NewEnvelope, only client faults report the exception's message;
+// server faults and codes carrying ex.FacetInternal report their code
+// name, since their messages may describe internals.
+func NewErrorFrame(err error) ErrorFrame {
+	f := ErrorFrame{Code: ex.ExTypeApplicationFailure}
+	e, ok := ex.AsErrorer(err)
+	if ok {
+		f.Code, f.ID = e.Code(), e.ID()
+	}
+	f.Message = f.Code.String()
+	if ok && ex.IsClientFault(err) && !ex.IsInternal(err) && e.Message() != "" {
+		f.Message = e.Message()
+	}
+	return f
+}
+
+// Exception returns the exception the frame describes. Frames come from
+// peers, so it is decoded as ex.Exception.UnmarshalJSON would decode it:
+// the result is remote (see ex.Exception.Remote), runs no hooks, and
+// keeps codes this process has not registered, even in strict mode.
+func (f ErrorFrame) Exception() ex.Exception {
+	var e ex.Exception
+	data, _ := json.Marshal(f)
+	_ = e.UnmarshalJSON(data)
+	return e
+}
+
+// encode returns the compact JSON encoding of f, shortening the message,
+// on a UTF-8 boundary, until it fits in limit bytes.
+func (f ErrorFrame) encode(limit int) []byte {
+	for {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(f)
+		data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		if len(data) <= limit || f.Message == "" {
+			return data
+		}
+		n := max(len(f.Message)-(len(data)-limit), 0)
+		for n > 0 && !utf8.RuneStart(f.Message[n]) {
+			n--
+		}
+		f.Message = f.Message[:n]
+	}
+}
+
+// CloseCode returns the WebSocket close code for err: CloseNormal for nil,
+// CloseTryAgainLater for errors worth retrying (timeouts, unavailability,
+// and rate limiting), ClosePolicyViolation for other client faults, and
+// CloseInternalError for everything else.
+func CloseCode(err error) int {
+	code, _ := ex.CodeOf(err)
+	switch {
+	case err == nil:
+		return CloseNormal
+	case ex.IsTimeout(err) || ex.IsUnavailable(err) || code.Category() == ex.ExTypeRateLimited:
+		return CloseTryAgainLater
+	case ex.IsClientFault(err):
+		return ClosePolicyViolation
+	default:
+		return CloseInternalError
+	}
+}
+
+// CloseReason returns the reason to send with CloseCode(err) when closing
+// a WebSocket: the ErrorFrame for err as JSON, its message shortened so
+// that the reason fits in MaxCloseReason bytes. It returns "" for nil.
+//
+//	_ = conn.Close(websocket.StatusCode(httpx.CloseCode(err)), httpx.CloseReason(err))
+func CloseReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	return string(NewErrorFrame(err).encode(MaxCloseReason))
+}
+
+// ParseCloseReason rebuilds the error a server closed a WebSocket with.
+// Normal and going-away closes yield nil. A reason made by CloseReason is
+// decoded; any other reason becomes the message of an exception with the
+// code the close code suggests and the close code as its ID. Either way,
+// the result is decoded as by ErrorFrame.Exception.
+func ParseCloseReason(code int, reason string) error {
+	if code == CloseNormal || code == CloseGoingAway {
+		return nil
+	}
+	if f, ok := parseFrame(reason); ok {
+		return f.Exception()
+	}
+	exType := ex.ExTypeApplicationFailure
+	switch code {
+	case ClosePolicyViolation:
+		exType = ex.ExTypeIncorrectData
+	case CloseTryAgainLater:
+		exType = ex.ExTypeUnavailable
+	}
+	return ErrorFrame{Code: exType, ID: code, Message: reason}.Exception()
+}
+
+// WriteSSEError writes err to a server-sent event stream as an event
+// named SSEEvent whose data is the ErrorFrame for err as JSON, its message
+// shortened to keep the data within MaxSSEData bytes:
+//
+//	event: error
+//	data: {"code":7,"id":4041,"message":"No such order"}
+//
+// Flush the stream afterwards as for any other event.
+func WriteSSEError(w io.Writer, err error) error {
+	data := NewErrorFrame(err).encode(MaxSSEData)
+	_, wErr := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", SSEEvent, data)
+	return wErr
+}
+
+// ParseSSEError rebuilds the error carried by the data of an SSE error
+// event written by WriteSSEError, decoded as by ErrorFrame.Exception.
+// Data not in that form becomes the message of an
+// ExTypeApplicationFailure.
+func ParseSSEError(data string) error {
+	if f, ok := parseFrame(data); ok {
+		return f.Exception()
+	}
+	return ErrorFrame{Code: ex.ExTypeApplicationFailure, Message: data}.Exception()
+}
+
+// parseFrame decodes an ErrorFrame, reporting whether s held one.
+func parseFrame(s string) (ErrorFrame, bool) {
+	var f ErrorFrame
+	if !strings.HasPrefix(s, "{") || json.Unmarshal([]byte(s), &f) != nil || f.Code == 0 {
+		return ErrorFrame{}, false
+	}
+	return f, true
+}
@@ -0,0 +1,107 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseCode(t *testing.T) {
+	assert.Equal(t, httpx.CloseNormal, httpx.CloseCode(nil))
+	assert.Equal(t, httpx.ClosePolicyViolation, httpx.CloseCode(ex.New(ex.ExTypeNotFound, 4041, "No such order")))
+	assert.Equal(t, httpx.CloseTryAgainLater, httpx.CloseCode(ex.New(ex.ExTypeRateLimited, 4291, "Slow down")))
+	assert.Equal(t, httpx.CloseTryAgainLater, httpx.CloseCode(ex.New(ex.ExTypeUnavailable, 5031, "Draining")))
+	assert.Equal(t, httpx.CloseTryAgainLater, httpx.CloseCode(ex.New(ex.ExTypeUnavailable|ex.FacetTransient, 5032, "Replica lag")))
+	assert.Equal(t, httpx.CloseInternalError, httpx.CloseCode(errors.New("boom")))
+}
+
+func TestCloseReason_RoundTrip(t *testing.T) {
+	reason := httpx.CloseReason(ex.New(ex.ExTypeNotFound, 4041, "No such <order>"))
+	assert.Equal(t, `{"code":7,"id":4041,"message":"No such <order>"}`, reason)
+
+	err := httpx.ParseCloseReason(httpx.ClosePolicyViolation, reason)
+	assert.ErrorIs(t, err, ex.New(ex.ExTypeNotFound, 4041, ""))
+	assert.Equal(t, "No such <order>", err.Error())
+
+	assert.Empty(t, httpx.CloseReason(nil))
+	assert.NoError(t, httpx.ParseCloseReason(httpx.CloseNormal, ""))
+	assert.NoError(t, httpx.ParseCloseReason(httpx.CloseGoingAway, "bye"))
+}
+
+func TestCloseReason_Bounded(t *testing.T) {
+	long := strings.Repeat("é", 200)
+	reason := httpx.CloseReason(ex.New(ex.ExTypeIncorrectData, 4001, long))
+	assert.LessOrEqual(t, len(reason), httpx.MaxCloseReason)
+	assert.True(t, utf8.ValidString(reason))
+
+	err := httpx.ParseCloseReason(httpx.ClosePolicyViolation, reason)
+	require.ErrorIs(t, err, ex.New(ex.ExTypeIncorrectData, 4001, ""))
+	assert.True(t, strings.HasPrefix(long, err.Error()))
+}
+
+func TestCloseReason_ServerFaultRedacted(t *testing.T) {
+	reason := httpx.CloseReason(ex.New(ex.ExTypeApplicationFailure, 5001, "db-7 refused the connection"))
+	assert.NotContains(t, reason, "db-7")
+	assert.Contains(t, reason, `"message":"ApplicationFailure"`)
+}
+
+func TestParseCloseReason_Foreign(t *testing.T) {
+	err := httpx.ParseCloseReason(httpx.CloseTryAgainLater, "server restarting")
+	assert.ErrorIs(t, err, ex.New(ex.ExTypeUnavailable, httpx.CloseTryAgainLater, ""))
+	assert.Equal(t, "server restarting", err.Error())
+
+	err = httpx.ParseCloseReason(4000, "{not json")
+	assert.ErrorIs(t, err, ex.New(ex.ExTypeApplicationFailure, 4000, ""))
+}
+
+func TestParseFrames_Remote(t *testing.T) {
+	saved := ex.Strict()
+	ex.SetStrict(true)
+	t.Cleanup(func() { ex.SetStrict(saved) })
+	var hooked int
+	t.Cleanup(ex.AddHook(func(ex.Exception) { hooked++ }))
+
+	reason := `{"code":4777,"id":1,"message":"Peer-only code"}`
+	for _, err := range []error{
+		httpx.ParseCloseReason(httpx.ClosePolicyViolation, reason),
+		httpx.ParseSSEError(reason),
+		httpx.ParseCloseReason(httpx.CloseTryAgainLater, "server restarting"),
+		httpx.ParseSSEError("oops"),
+	} {
+		var e ex.Exception
+		require.ErrorAs(t, err, &e)
+		assert.True(t, e.Remote(), "%v", err)
+	}
+	e, ok := httpx.ParseSSEError(reason).(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExType(4777), e.Code(), "unknown codes survive strict mode")
+	assert.Zero(t, hooked, "peer errors run no local hooks")
+}
+
+func TestWriteSSEError(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, httpx.WriteSSEError(&b, ex.New(ex.ExTypeNotFound, 4041, "No such order")))
+	assert.Equal(t, "event: error\ndata: {\"code\":7,\"id\":4041,\"message\":\"No such order\"}\n\n", b.String())
+
+	data := strings.TrimPrefix(strings.Split(b.String(), "\n")[1], "data: ")
+	err := httpx.ParseSSEError(data)
+	assert.ErrorIs(t, err, ex.New(ex.ExTypeNotFound, 4041, ""))
+
+	b.Reset()
+	// Decoded, because exstrict builds reject such messages in New.
+	var long ex.Exception
+	require.NoError(t, json.Unmarshal([]byte(`{"code":1,"id":4001,"message":"`+strings.Repeat(`line\n`, 2000)+`"}`), &long))
+	require.NoError(t, httpx.WriteSSEError(&b, long))
+	lines := strings.Split(b.String(), "\n")
+	require.Len(t, lines, 4, "newlines in the message are escaped")
+	assert.LessOrEqual(t, len(strings.TrimPrefix(lines[1], "data: ")), httpx.MaxSSEData)
+
+	assert.ErrorIs(t, httpx.ParseSSEError("oops"), ex.New(ex.ExTypeApplicationFailure, 0, ""))
+}
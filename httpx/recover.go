@@ -0,0 +1,66 @@
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/bold-minds/ex"
+)
+
+// Recoverer is middleware that converts panics in next into
+// ExTypeApplicationFailure exceptions (see ex.FromPanic) carrying the
+// panicking goroutine's stack, and answers with a 500 Envelope.
+//
+// Registered hooks see the exception as it is created, which is the place
+// to log or report it; its PanicValue method returns the original value.
+// If the handler had already started writing the response, the partial
+// response is left as it is. http.ErrAbortHandler is re-panicked so that
+// net/http can abort the connection as intended.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			exc := ex.FromPanic(v)
+			if !rw.wroteHeader {
+				WriteError(w, exc)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// responseWriter records whether the response has been started.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher for handlers that stream, flushing the
+// underlying writer if it supports it. Flushing starts the response.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,83 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverer(t *testing.T) {
+	var seen []ex.Exception
+	remove := ex.AddHook(func(e ex.Exception) { seen = append(seen, e) })
+	defer remove()
+
+	h := httpx.Recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("nil pointer somewhere")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var env httpx.Envelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	assert.Equal(t, "ApplicationFailure", env.Error.Code)
+	assert.Equal(t, "Internal Server Error", env.Error.Message)
+
+	require.Len(t, seen, 1, "hooks see the recovered panic")
+	assert.Equal(t, "panic: nil pointer somewhere", seen[0].Error())
+	require.True(t, seen[0].HasStack())
+	assert.True(t, strings.Contains(seen[0].StackTrace()[0].Function, "TestRecoverer"))
+	v, ok := seen[0].PanicValue()
+	assert.True(t, ok)
+	assert.Equal(t, "nil pointer somewhere", v, "the original panic value is preserved")
+}
+
+func TestRecoverer_NoPanic(t *testing.T) {
+	h := httpx.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
+
+func TestRecoverer_AfterWrite(t *testing.T) {
+	h := httpx.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		panic("late")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "partial", rec.Body.String())
+}
+
+func TestRecoverer_Flush(t *testing.T) {
+	h := httpx.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		f, ok := w.(http.Flusher)
+		require.True(t, ok, "streaming handlers can flush")
+		f.Flush()
+		panic("late")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String(), "a flushed response is left as it is")
+}
+
+func TestRecoverer_AbortHandler(t *testing.T) {
+	h := httpx.Recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,17 @@
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/bold-minds/ex"
+)
+
+// StatusCode returns the HTTP status for err: the HTTPStatus registered
+// for the code of the first Exception in its chain (see ex.RegisterType),
+// or 500 Internal Server Error when there is none.
+func StatusCode(err error) int {
+	if info, ok := ex.TypeInfoOf(err); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
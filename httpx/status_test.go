@@ -0,0 +1,49 @@
+package httpx_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCode(t *testing.T) {
+	// exstrict builds turn strict mode on, which rejects the unregistered code.
+	saved := ex.Strict()
+	ex.SetStrict(false)
+	t.Cleanup(func() { ex.SetStrict(saved) })
+
+	assert.Equal(t, http.StatusBadRequest, httpx.StatusCode(ex.New(ex.ExTypeIncorrectData, 1, "bad")))
+	assert.Equal(t, http.StatusUnauthorized, httpx.StatusCode(ex.New(ex.ExTypeLoginRequired, 1, "login")))
+	assert.Equal(t, http.StatusForbidden, httpx.StatusCode(
+		fmt.Errorf("wrapped: %w", ex.New(ex.ExTypePermissionDenied, 1, "denied"))))
+	assert.Equal(t, http.StatusInternalServerError, httpx.StatusCode(errors.New("plain")))
+	assert.Equal(t, http.StatusInternalServerError, httpx.StatusCode(ex.New(ex.ExType(4242), 1, "unregistered")))
+}
+
+func TestStatusCode_FactoryType(t *testing.T) {
+	const cardDeclined = ex.ExType(4625)
+	billing := ex.NewFactory(ex.Config{
+		Types: map[ex.ExType]ex.TypeInfo{cardDeclined: {Name: "CardDeclined", HTTPStatus: http.StatusPaymentRequired}},
+	})
+	assert.Equal(t, http.StatusPaymentRequired, httpx.StatusCode(billing.New(cardDeclined, 1, "Card declined")))
+}
+
+func TestStatusCode_RegisteredType(t *testing.T) {
+	const paymentRequired = ex.ExType(4021)
+	require.NoError(t, ex.RegisterType(paymentRequired, ex.TypeInfo{Name: "PaymentRequired", HTTPStatus: http.StatusPaymentRequired}))
+	assert.Equal(t, http.StatusPaymentRequired, httpx.StatusCode(ex.New(paymentRequired, 1, "pay up")))
+}
+
+func TestStatusCode_WrapKeepStatus(t *testing.T) {
+	notFound := ex.New(ex.ExTypeNotFound, 4041, "No such order")
+	assert.Equal(t, http.StatusNotFound, httpx.StatusCode(
+		ex.WrapKeepStatus(notFound, ex.ExTypeApplicationFailure, 5003, "Loading order failed")))
+	assert.Equal(t, http.StatusInternalServerError, httpx.StatusCode(
+		ex.New(ex.ExTypeApplicationFailure, 5003, "Loading order failed").WithInnerError(notFound)))
+}
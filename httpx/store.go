@@ -0,0 +1,83 @@
+package httpx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// DefaultStoreSize is the capacity used when NewStore is given a
+// non-positive size.
+const DefaultStoreSize = 100
+
+// Entry is an error remembered by a Store.
+type Entry struct {
+	Time time.Time
+	Err  error
+}
+
+// Store is a fixed-size ring buffer of recent errors. Once full, each new
+// error evicts the oldest. It is safe for concurrent use.
+type Store struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+	now     func() time.Time
+}
+
+// NewStore returns a Store that keeps the most recent size errors.
+func NewStore(size int) *Store {
+	if size <= 0 {
+		size = DefaultStoreSize
+	}
+	return &Store{entries: make([]Entry, size), now: time.Now}
+}
+
+// Add records err. Nil errors are ignored.
+func (s *Store) Add(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[s.next] = Entry{Time: s.now(), Err: err}
+	s.next++
+	if s.next == len(s.entries) {
+		s.next = 0
+		s.full = true
+	}
+}
+
+// Hook returns an ex.Hook that records every exception created by ex.New.
+// Register it with ex.AddHook.
+func (s *Store) Hook() ex.Hook {
+	return func(e ex.Exception) { s.Add(e) }
+}
+
+// Entries returns the remembered errors, newest first.
+func (s *Store) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.next
+	if s.full {
+		n = len(s.entries)
+	}
+	out := make([]Entry, 0, n)
+	for i := 1; i <= n; i++ {
+		idx := (s.next - i + len(s.entries)) % len(s.entries)
+		out = append(out, s.entries[idx])
+	}
+	return out
+}
+
+// Len returns the number of remembered errors.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.full {
+		return len(s.entries)
+	}
+	return s.next
+}
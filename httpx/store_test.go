@@ -0,0 +1,43 @@
+package httpx_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_RingBuffer(t *testing.T) {
+	store := httpx.NewStore(3)
+	assert.Empty(t, store.Entries())
+
+	for i := range 5 {
+		store.Add(errors.New("err " + strconv.Itoa(i)))
+	}
+	store.Add(nil)
+
+	entries := store.Entries()
+	assert.Equal(t, 3, store.Len())
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "err 4", entries[0].Err.Error())
+		assert.Equal(t, "err 3", entries[1].Err.Error())
+		assert.Equal(t, "err 2", entries[2].Err.Error())
+		assert.False(t, entries[0].Time.IsZero())
+	}
+}
+
+func TestStore_Hook(t *testing.T) {
+	store := httpx.NewStore(0)
+	remove := ex.AddHook(store.Hook())
+	ex.New(ex.ExTypeIncorrectData, 400, "recorded")
+	remove()
+	ex.New(ex.ExTypeIncorrectData, 400, "not recorded")
+
+	entries := store.Entries()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "recorded", entries[0].Err.Error())
+	}
+}
@@ -0,0 +1,210 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bold-minds/ex"
+)
+
+// Transport is an http.RoundTripper for calling other services that turns
+// requests failing in transit into exceptions, so they can be handled like
+// every other error in the program:
+//
+//	client := &http.Client{Transport: &httpx.Transport{Capture: &httpx.DefaultCapture}}
+//
+// A request that fails in transit yields an ExTypeTimeout exception with
+// ID 504 if it timed out, and an ExTypeUnavailable exception with ID 502
+// otherwise, wrapping the transport's error. http.Client wraps the
+// exception in a *url.Error; errors.As and the helpers of package ex see
+// through it.
+//
+// As the http.RoundTripper contract requires, responses are returned
+// whatever their status; turn those of 400 and above into exceptions with
+// ResponseError.
+//
+// If Capture is set, the exception also carries a snapshot of the call
+// for debugging third-party failures; see CapturePolicy.
+type Transport struct {
+	// Base performs the requests. Nil means http.DefaultTransport.
+	Base http.RoundTripper
+
+	// Capture, if non-nil, selects what is recorded about failed calls.
+	Capture *CapturePolicy
+}
+
+// CapturePolicy controls the request and response snapshot a Transport
+// attaches to the exceptions it creates, as these fields:
+//
+//	http.method            request method
+//	http.url               request URL, sanitized
+//	http.request_headers   map[string]string of captured request headers
+//	http.response_headers  map[string]string of captured response headers
+//	http.response_body     the start of the response body
+//
+// URLs lose their user info, and the values of query parameters and
+// headers for which Redact reports true are replaced by ex.RedactedValue;
+// by default that is every name ex.IsSensitiveKey matches, such as
+// Authorization, X-Api-Key, and api-key.
+// Response bodies are captured as sent; do not capture bodies from APIs
+// that may echo secrets back.
+type CapturePolicy struct {
+	// Headers lists the headers to capture, by name. Nil captures all of
+	// them; an empty, non-nil slice captures none.
+	Headers []string
+
+	// BodyLimit is the maximum number of response body bytes captured.
+	// Zero captures no body.
+	BodyLimit int
+
+	// Redact reports whether the value of a header or query parameter
+	// must be hidden. Nil means ex.IsSensitiveKey.
+	Redact func(name string) bool
+}
+
+// DefaultCapture captures every header, redacted with ex.IsSensitiveKey,
+// and the first KiB of the response body.
+var DefaultCapture = CapturePolicy{BodyLimit: 1024}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		code, id := ex.ExTypeUnavailable, http.StatusBadGateway
+		if ex.IsTimeout(err) {
+			code, id = ex.ExTypeTimeout, http.StatusGatewayTimeout
+		}
+		exc := ex.New(code, id, "Upstream request failed").WithInnerError(err)
+		return nil, t.Capture.attach(exc, req, nil, nil)
+	}
+	return resp, nil
+}
+
+// ResponseError returns the exception for a response with a status of 400
+// or above, and nil for any other. The response is then consumed and
+// closed. The exception's ID is the status and its code the predefined
+// code registered for it (ExTypeIncorrectData or ExTypeApplicationFailure
+// for unmapped 4xx and 5xx statuses). If capture is not nil, it also
+// carries a snapshot of the call, as with Transport.Capture:
+//
+//	resp, err := client.Do(req)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := httpx.ResponseError(resp, &httpx.DefaultCapture); err != nil {
+//	    return err
+//	}
+//	defer resp.Body.Close()
+func ResponseError(resp *http.Response, capture *CapturePolicy) error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	var body []byte
+	if capture != nil && capture.BodyLimit > 0 {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, int64(capture.BodyLimit)))
+	}
+	_ = resp.Body.Close()
+	exc := ex.New(codeForStatus(resp.StatusCode), resp.StatusCode, "Upstream responded "+resp.Status)
+	return capture.attach(exc, resp.Request, resp, body)
+}
+
+// codeForStatus returns the predefined code whose registered HTTP status
+// is status, or a generic code for its class.
+func codeForStatus(status int) ex.ExType {
+	for code := ex.ExTypeIncorrectData; code <= ex.ExTypeRateLimited; code++ {
+		if info, ok := ex.LookupType(code); ok && info.HTTPStatus == status {
+			return code
+		}
+	}
+	if status < http.StatusInternalServerError {
+		return ex.ExTypeIncorrectData
+	}
+	return ex.ExTypeApplicationFailure
+}
+
+// attach adds the snapshot selected by p to exc. A nil p attaches
+// nothing.
+func (p *CapturePolicy) attach(exc ex.Exception, req *http.Request, resp *http.Response, body []byte) ex.Exception {
+	if p == nil {
+		return exc
+	}
+	var fields []ex.Field
+	if req != nil {
+		fields = append(fields, ex.F("http.method", req.Method), ex.F("http.url", p.sanitizeURL(req.URL)))
+		if h := p.headers(req.Header); h != nil {
+			fields = append(fields, ex.F("http.request_headers", h))
+		}
+	}
+	if resp != nil {
+		if h := p.headers(resp.Header); h != nil {
+			fields = append(fields, ex.F("http.response_headers", h))
+		}
+		if len(body) > 0 {
+			fields = append(fields, ex.F("http.response_body", strings.ToValidUTF8(string(body), "�")))
+		}
+	}
+	return exc.WithFields(fields...)
+}
+
+// redact reports whether the value of name must be hidden.
+func (p *CapturePolicy) redact(name string) bool {
+	if p.Redact != nil {
+		return p.Redact(name)
+	}
+	return ex.IsSensitiveKey(name)
+}
+
+// sanitizeURL renders u without user info and with sensitive query
+// parameter values redacted.
+func (p *CapturePolicy) sanitizeURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	clean := *u
+	clean.User = nil
+	if clean.RawQuery != "" {
+		q := clean.Query()
+		for name, values := range q {
+			if p.redact(name) {
+				for i := range values {
+					values[i] = ex.RedactedValue
+				}
+			}
+		}
+		clean.RawQuery = q.Encode()
+	}
+	return clean.String()
+}
+
+// headers returns the captured headers of h, or nil if there are none.
+func (p *CapturePolicy) headers(h http.Header) map[string]string {
+	names := p.Headers
+	if names == nil {
+		names = make([]string, 0, len(h))
+		for name := range h {
+			names = append(names, name)
+		}
+	}
+	var out map[string]string
+	for _, name := range names {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(names))
+		}
+		value := strings.Join(values, ", ")
+		if p.redact(name) {
+			value = ex.RedactedValue
+		}
+		out[http.CanonicalHeaderKey(name)] = value
+	}
+	return out
+}
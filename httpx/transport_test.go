@@ -0,0 +1,170 @@
+package httpx_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_PassesSuccessfulResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &httpx.Transport{Capture: &httpx.DefaultCapture}}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestTransport_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("X-Request-Id", "r-9")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer srv.Close()
+
+	capture := httpx.CapturePolicy{BodyLimit: 10}
+	client := &http.Client{Transport: &httpx.Transport{Capture: &capture}}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/users/7?api_token=s3cr3t&page=2", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	err = httpx.ResponseError(resp, &capture)
+	require.Error(t, err)
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeNotFound, e.Code())
+	assert.Equal(t, 404, e.ID())
+	assert.Equal(t, "Upstream responded 404 Not Found", e.Message())
+
+	fields := map[string]any{}
+	for _, f := range e.Fields() {
+		fields[f.Key] = f.Value
+	}
+	assert.Equal(t, "GET", fields["http.method"])
+	assert.Equal(t, srv.URL+"/users/7?api_token=%5BREDACTED%5D&page=2", fields["http.url"])
+	assert.Equal(t, map[string]string{"Authorization": ex.RedactedValue, "Accept": "application/json"}, fields["http.request_headers"])
+	respHeaders, ok := fields["http.response_headers"].(map[string]string)
+	require.True(t, ok)
+	assert.Equal(t, ex.RedactedValue, respHeaders["Set-Cookie"])
+	assert.Equal(t, "r-9", respHeaders["X-Request-Id"])
+	assert.Equal(t, strings.Repeat("x", 10), fields["http.response_body"])
+}
+
+func TestTransport_NoCapture(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	resp, err := (&http.Client{Transport: &httpx.Transport{}}).Get(srv.URL)
+	require.NoError(t, err)
+	e, ok := ex.As(httpx.ResponseError(resp, nil))
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeIncorrectData, e.Code())
+	assert.Equal(t, http.StatusTeapot, e.ID())
+	assert.Empty(t, e.Fields())
+}
+
+func TestTransport_SelectedHeadersAndCustomRedaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	capture := httpx.CapturePolicy{
+		Headers: []string{"x-api-key", "X-Tenant"},
+		Redact:  func(name string) bool { return strings.EqualFold(name, "X-Api-Key") },
+	}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Api-Key", "k")
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := (&http.Client{Transport: &httpx.Transport{Capture: &capture}}).Do(req)
+	require.NoError(t, err)
+	e, ok := ex.As(httpx.ResponseError(resp, &capture))
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeUnavailable, e.Code())
+	headers, _ := e.FieldValue("http.request_headers")
+	assert.Equal(t, map[string]string{"X-Api-Key": ex.RedactedValue, "X-Tenant": "acme"}, headers)
+	_, hasResponseHeaders := e.FieldValue("http.response_headers")
+	assert.False(t, hasResponseHeaders)
+}
+
+func TestTransport_DefaultRedactionHyphenated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1?api-key=s3cr3t&page=2", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Api-Key", "s3cr3t")
+	req.Header.Set("Api-Key", "s3cr3t")
+
+	resp, err := (&http.Client{Transport: &httpx.Transport{}}).Do(req)
+	require.NoError(t, err)
+	e, ok := ex.As(httpx.ResponseError(resp, &httpx.DefaultCapture))
+	require.True(t, ok)
+	url, _ := e.FieldValue("http.url")
+	assert.Equal(t, srv.URL+"/v1?api-key=%5BREDACTED%5D&page=2", url)
+	headers, _ := e.FieldValue("http.request_headers")
+	assert.Equal(t, map[string]string{"X-Api-Key": ex.RedactedValue, "Api-Key": ex.RedactedValue}, headers)
+}
+
+func TestResponseError_SuccessIsNil(t *testing.T) {
+	assert.NoError(t, httpx.ResponseError(&http.Response{StatusCode: http.StatusNoContent}, &httpx.DefaultCapture))
+	assert.NoError(t, httpx.ResponseError(nil, nil))
+}
+
+func TestTransport_TransportErrors(t *testing.T) {
+	failing := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	_, err := (&http.Client{Transport: &httpx.Transport{Base: failing}}).Get("http://user:pw@upstream.invalid/")
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeUnavailable, e.Code())
+	assert.Equal(t, http.StatusBadGateway, e.ID())
+	assert.Equal(t, "Upstream request failed: connection refused", e.Error())
+
+	slow := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://user:pw@upstream.invalid/", nil)
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: &httpx.Transport{Base: slow, Capture: &httpx.DefaultCapture}}).Do(req)
+	e, ok = ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeTimeout, e.Code())
+	assert.Equal(t, http.StatusGatewayTimeout, e.ID())
+	url, _ := e.FieldValue("http.url")
+	assert.Equal(t, "http://upstream.invalid/", url)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
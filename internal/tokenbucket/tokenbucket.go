@@ -0,0 +1,41 @@
+// Package tokenbucket implements the token bucket rate limiting the
+// exporters and sinks of ex share.
+package tokenbucket
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket. It is safe for concurrent use.
+type Bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// New returns a full Bucket refilled at rate tokens per second, holding
+// at most burst tokens, reading the time from now.
+func New(rate float64, burst int, now func() time.Time) *Bucket {
+	return &Bucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now(), now: now}
+}
+
+// Allow takes a token if one is available.
+func (b *Bucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,27 @@
+package tokenbucket_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex/internal/tokenbucket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucket_Allow(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := tokenbucket.New(1, 2, func() time.Time { return now })
+
+	assert.True(t, b.Allow())
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow(), "the burst is spent")
+
+	now = now.Add(time.Second)
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+
+	now = now.Add(time.Hour)
+	assert.True(t, b.Allow())
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow(), "tokens accumulate up to the burst only")
+}
@@ -0,0 +1,64 @@
+package ex
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrInvalidAlias is returned by RegisterIsAlias for a nil or
+// non-comparable target, which errors.Is could never match.
+var ErrInvalidAlias = errors.New("ex: invalid errors.Is alias")
+
+var (
+	aliasesMu sync.Mutex
+	// aliases is published copy-on-write; Is loads it without locking.
+	aliases atomic.Pointer[map[error][]CodeID]
+)
+
+// RegisterIsAlias makes errors.Is(e, target) report true for every
+// Exception e with the given code and ID, whether or not target is still
+// in e's chain. Exceptions classified from a sentinel usually wrap it, but
+// the sentinel does not survive serialization; an alias keeps the check
+// working on the other side:
+//
+//	ex.RegisterIsAlias(io.EOF, ex.ExTypeIncorrectData, 4002)
+//	ex.RegisterIsAlias(sql.ErrNoRows, ex.ExTypeNotFound, 4041)
+//
+//	errors.Is(decoded, sql.ErrNoRows) // true for a decoded NotFound/4041
+//
+// A target may be aliased to several (code, ID) pairs. Register aliases
+// during initialization, since they change what errors.Is reports.
+func RegisterIsAlias(target error, code ExType, id int) error {
+	if target == nil || !reflect.TypeOf(target).Comparable() {
+		return ErrInvalidAlias
+	}
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	var cur map[error][]CodeID
+	if p := aliases.Load(); p != nil {
+		cur = *p
+	}
+	key := CodeID{Code: code, ID: id}
+	if slices.Contains(cur[target], key) {
+		return nil
+	}
+	next := make(map[error][]CodeID, len(cur)+1)
+	for k, v := range cur {
+		next[k] = v
+	}
+	next[target] = append(slices.Clip(cur[target]), key)
+	aliases.Store(&next)
+	return nil
+}
+
+// isAlias reports whether target is aliased to (code, id).
+func isAlias(code ExType, id int, target error) bool {
+	p := aliases.Load()
+	if p == nil || target == nil || !reflect.TypeOf(target).Comparable() {
+		return false
+	}
+	return slices.Contains((*p)[target], CodeID{Code: code, ID: id})
+}
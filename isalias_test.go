@@ -0,0 +1,48 @@
+package ex_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uncomparableErr cannot be used as an errors.Is target by identity.
+type uncomparableErr []string
+
+func (uncomparableErr) Error() string { return "uncomparable" }
+
+func TestRegisterIsAlias(t *testing.T) {
+	t.Cleanup(ex.ResetIsAliases)
+
+	require.NoError(t, ex.RegisterIsAlias(sql.ErrNoRows, ex.ExTypeNotFound, 4041))
+	require.NoError(t, ex.RegisterIsAlias(sql.ErrNoRows, ex.ExTypeNotFound, 4042))
+	require.NoError(t, ex.RegisterIsAlias(sql.ErrNoRows, ex.ExTypeNotFound, 4041))
+
+	// The sentinel is lost in transit, but the alias keeps the check working.
+	data, err := json.Marshal(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithInnerError(sql.ErrNoRows))
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, errors.Is(decoded, sql.ErrNoRows))
+	assert.True(t, errors.Is(fmt.Errorf("load: %w", ex.New(ex.ExTypeNotFound, 4042, "")), sql.ErrNoRows))
+
+	assert.False(t, errors.Is(ex.New(ex.ExTypeNotFound, 4043, ""), sql.ErrNoRows))
+	assert.False(t, errors.Is(ex.New(ex.ExTypeNotFound, 4041, ""), io.EOF))
+	assert.False(t, errors.Is(ex.New(ex.ExTypeNotFound, 4041, ""), uncomparableErr{"x"}))
+
+	ex.ResetIsAliases()
+	assert.False(t, errors.Is(decoded, sql.ErrNoRows))
+}
+
+func TestRegisterIsAlias_InvalidTargets(t *testing.T) {
+	t.Cleanup(ex.ResetIsAliases)
+	assert.ErrorIs(t, ex.RegisterIsAlias(nil, ex.ExTypeNotFound, 1), ex.ErrInvalidAlias)
+	assert.ErrorIs(t, ex.RegisterIsAlias(uncomparableErr{"x"}, ex.ExTypeNotFound, 1), ex.ErrInvalidAlias)
+}
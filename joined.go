@@ -0,0 +1,79 @@
+package ex
+
+// FromJoined unpacks err into a flat list of exceptions, one per member,
+// for reporting a batch of failures uniformly:
+//
+//	for _, e := range ex.FromJoined(collector.Err()) {
+//	    report(e.Code(), e.ID(), e.Error())
+//	}
+//
+// Errors that aggregate several errors, such as those made by errors.Join
+// or by fmt.Errorf with more than one %w, go.uber.org/multierr, and
+// github.com/hashicorp/go-multierror, are replaced by their members,
+// recursively, so nested joins come out flat; the text a fmt.Errorf
+// wrapper adds around its members is lost. Members that are
+// Exceptions are returned as they are, and any other member is wrapped as
+// WrapAuto(member, 0, "") would, keeping its text and chain and inferring
+// its code. An err that wraps a single error is one member. FromJoined
+// returns nil for a nil err, and skips nil members.
+//
+// The members of a *GroupError carry their branch as fields:
+// "group.branch" holds its label, "group.order" its place in the order
+// the branches failed (1 for the first), and "group.elapsed" the time
+// from the start of the group to its failure, as a time.Duration.
+func FromJoined(err error) []Exception {
+	var out []Exception
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if e, ok := err.(Exception); ok {
+			out = append(out, e)
+			return
+		}
+		if ge, ok := err.(*GroupError); ok {
+			for i, br := range ge.Failed() {
+				from := len(out)
+				walk(br.Err)
+				for j := from; j < len(out); j++ {
+					out[j] = out[j].WithFields(
+						F("group.branch", br.Label),
+						F("group.order", i+1),
+						F("group.elapsed", elapsed(br.End, ge.start)),
+					)
+				}
+			}
+			return
+		}
+		if errs, ok := members(err); ok {
+			for _, member := range errs {
+				walk(member)
+			}
+			return
+		}
+		out = append(out, WrapAuto(err, 0, ""))
+	}
+	walk(err)
+	return out
+}
+
+// members returns the errors err aggregates, if it aggregates several.
+// Besides the standard Unwrap() []error, it recognizes the containers of
+// go.uber.org/multierr and github.com/hashicorp/go-multierror by the
+// methods they export, so their members are not mistaken for one opaque
+// error. The go-multierror Unwrap returns a chain through the members
+// rather than the members themselves.
+func members(err error) ([]error, bool) {
+	switch v := err.(type) {
+	case interface{ Unwrap() []error }:
+		return v.Unwrap(), true
+	case interface{ WrappedErrors() []error }:
+		// github.com/hashicorp/go-multierror.
+		return v.WrappedErrors(), true
+	case interface{ Errors() []error }:
+		// go.uber.org/multierr before v1.9, which added Unwrap() []error.
+		return v.Errors(), true
+	}
+	return nil, false
+}
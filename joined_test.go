@@ -0,0 +1,96 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromJoined(t *testing.T) {
+	notFound := ex.New(ex.ExTypeNotFound, 4041, "No such order")
+	annotated := ex.Annotate(ex.New(ex.ExTypeConflict, 4091, "Order locked"), ex.F("order", 7))
+	joined := errors.Join(
+		notFound,
+		fmt.Errorf("reading manifest: %w", context.DeadlineExceeded),
+		nil,
+		errors.Join(annotated, errors.New("boom")),
+	)
+
+	got := ex.FromJoined(joined)
+	require.Len(t, got, 4)
+
+	assert.Equal(t, notFound.Error(), got[0].Error())
+	assert.Equal(t, 4041, got[0].ID())
+
+	assert.Equal(t, ex.ExTypeTimeout, got[1].Code(), "code inferred as by WrapAuto")
+	assert.Equal(t, "reading manifest: context deadline exceeded", got[1].Error())
+	assert.ErrorIs(t, got[1], context.DeadlineExceeded)
+
+	assert.Equal(t, ex.ExTypeConflict, got[2].Code())
+	assert.Equal(t, []ex.Field{ex.F("order", 7)}, ex.FieldsOf(got[2]))
+
+	assert.Equal(t, ex.ExTypeApplicationFailure, got[3].Code())
+	assert.Equal(t, "boom", got[3].Error())
+}
+
+func TestFromJoined_Single(t *testing.T) {
+	assert.Nil(t, ex.FromJoined(nil))
+
+	e := ex.New(ex.ExTypeIncorrectData, 4001, "Bad input")
+	assert.Equal(t, []ex.Exception{e}, ex.FromJoined(e))
+
+	wrapped := ex.FromJoined(fmt.Errorf("validating: %w", e))
+	require.Len(t, wrapped, 1)
+	assert.Equal(t, ex.ExTypeIncorrectData, wrapped[0].Code())
+	assert.Equal(t, "validating: Bad input", wrapped[0].Error())
+}
+
+func TestFromJoined_MultiWrap(t *testing.T) {
+	a := ex.New(ex.ExTypeNotFound, 1, "a")
+	b := ex.New(ex.ExTypeConflict, 2, "b")
+	got := ex.FromJoined(fmt.Errorf("both: %w, %w", a, b))
+	require.Len(t, got, 2)
+	assert.Equal(t, ex.ExTypeNotFound, got[0].Code())
+	assert.Equal(t, ex.ExTypeConflict, got[1].Code())
+}
+
+// hashicorpMulti mimics github.com/hashicorp/go-multierror's *Error, whose
+// Unwrap returns a chain through the members.
+type hashicorpMulti struct{ errs []error }
+
+func (m *hashicorpMulti) Error() string          { return fmt.Sprintf("%d errors occurred", len(m.errs)) }
+func (m *hashicorpMulti) WrappedErrors() []error { return m.errs }
+func (m *hashicorpMulti) Unwrap() error          { return m.errs[0] }
+
+// uberMulti mimics go.uber.org/multierr before v1.9, which exposed its
+// members through Errors only.
+type uberMulti struct{ errs []error }
+
+func (m uberMulti) Error() string   { return "multiple errors" }
+func (m uberMulti) Errors() []error { return m.errs }
+
+func TestFromJoined_ThirdPartyContainers(t *testing.T) {
+	notFound := ex.New(ex.ExTypeNotFound, 4041, "No such order")
+	timeout := ex.New(ex.ExTypeTimeout, 5041, "Ledger timed out")
+	err := &hashicorpMulti{errs: []error{
+		notFound,
+		uberMulti{errs: []error{errors.New("boom"), timeout}},
+	}}
+
+	got := ex.FromJoined(fmt.Errorf("batch: %w", err))
+	require.Len(t, got, 1, "a single-error wrapper is one member")
+
+	got = ex.FromJoined(err)
+	require.Len(t, got, 3)
+	assert.Equal(t, 4041, got[0].ID())
+	assert.Equal(t, "boom", got[1].Error())
+	assert.Equal(t, 5041, got[2].ID())
+
+	assert.True(t, ex.IsTimeout(err), "branches of both containers are searched")
+	assert.False(t, ex.IsUnavailable(err))
+}
@@ -0,0 +1,404 @@
+package ex
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+// wireError is the JSON representation of one level of an error chain.
+//
+// Exceptions always carry a code; plain (non-Exception) errors are encoded
+// with only their message, which is how the decoder tells the two apart.
+//
+// Member order in the encoding follows the struct field order, so every
+// marshaler built on wireError is deterministic. Any map-valued member
+// added here must rely on encoding/json's sorted-key map encoding (or
+// sort explicitly) to keep that guarantee.
+type wireError struct {
+	Code       *ExType        `json:"code,omitempty"`
+	Type       string         `json:"type,omitempty"`
+	ID         int            `json:"id,omitempty"`
+	CodeString string         `json:"code_string,omitempty"`
+	KeepStatus bool           `json:"keep_status,omitempty"`
+	Expected   bool           `json:"expected,omitempty"`
+	Taxonomy   int            `json:"taxonomy,omitempty"`
+	Message    string         `json:"message"`
+	GoType     string         `json:"go_type,omitempty"`
+	Hint       string         `json:"hint,omitempty"`
+	DocsURL    string         `json:"docs_url,omitempty"`
+	Origin     string         `json:"origin,omitempty"`
+	Trace      *TraceContext  `json:"trace,omitempty"`
+	Ops        []string       `json:"ops,omitempty"`
+	Fields     map[string]any `json:"fields,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+	Stack      []Frame        `json:"stack,omitempty"`
+	StackDump  string         `json:"stack_dump,omitempty"`
+	Inner      *wireError     `json:"inner,omitempty"`
+	Group      *wireGroup     `json:"group,omitempty"`
+}
+
+// wireGroup is the wire representation of a *GroupError.
+type wireGroup struct {
+	Start    time.Time    `json:"start,omitzero"`
+	Branches []wireBranch `json:"branches"`
+}
+
+// wireBranch is the wire representation of one Branch.
+type wireBranch struct {
+	Label string     `json:"label"`
+	Start time.Time  `json:"start,omitzero"`
+	End   time.Time  `json:"end,omitzero"`
+	Error *wireError `json:"error,omitempty"`
+}
+
+// toWire converts err and everything it wraps into the wire representation.
+func toWire(err error) *wireError {
+	return encodeWire(err, ResolveValue)
+}
+
+// encodeWire is toWire with field values rendered by value.
+func encodeWire(err error, value func(any) any) *wireError {
+	if err == nil {
+		return nil
+	}
+	if a, ok := err.(*annotation); ok {
+		return a.toWire(value)
+	}
+	e, ok := err.(Exception)
+	if !ok {
+		if other, isErrorer := err.(Errorer); isErrorer {
+			return errorerToWire(other, value)
+		}
+		if r, isRemote := err.(*RemoteError); isRemote {
+			return &wireError{Message: r.message, GoType: r.typeName}
+		}
+		if ge, isGroup := err.(*GroupError); isGroup {
+			return ge.toWire(value)
+		}
+		return &wireError{Message: err.Error(), GoType: plainTypeName(err)}
+	}
+	e.creation.observe()
+	code := e.code
+	origin := e.origin
+	if !e.remote {
+		origin = ServiceName()
+	}
+	return &wireError{
+		Code:       &code,
+		Type:       code.String(),
+		ID:         e.id,
+		CodeString: e.codeString,
+		KeepStatus: e.keepStatus,
+		Expected:   e.expected,
+		Taxonomy:   e.Taxonomy(),
+		Message:    e.message,
+		Hint:       e.hint,
+		DocsURL:    e.docsURL,
+		Origin:     origin,
+		Trace:      e.trace,
+		Ops:        e.ops,
+		Fields:     fieldMap(e.fields, value),
+		Details:    fieldMap(e.details, value),
+		Stack:      e.StackTrace(),
+		StackDump:  e.stackDump,
+		Inner:      encodeWire(e.innerError, value),
+	}
+}
+
+// errorerToWire encodes an Errorer other than Exception through its read
+// surface. It decodes as an Exception.
+func errorerToWire(e Errorer, value func(any) any) *wireError {
+	code := e.Code()
+	codeString, _ := codeStringOf(e)
+	return &wireError{
+		Code:       &code,
+		Type:       code.String(),
+		ID:         e.ID(),
+		CodeString: codeString,
+		Taxonomy:   TaxonomyVersion(),
+		Message:    e.Message(),
+		Origin:     ServiceName(),
+		Fields:     fieldMap(e.Fields(), value),
+		Stack:      e.StackTrace(),
+		Inner:      encodeWire(e.Unwrap(), value),
+	}
+}
+
+// toWire encodes the annotated chain with the annotation folded into its
+// first level, since annotations are not chain levels of their own. As in
+// FieldsOf, the outermost value of a key wins, so the annotation's fields
+// replace those the level already has. Annotations on plain errors have
+// nowhere to go and are dropped.
+func (a *annotation) toWire(value func(any) any) *wireError {
+	w := encodeWire(a.err, value)
+	if w.Code == nil {
+		return w
+	}
+	if a.op != "" {
+		w.Ops = append([]string{a.op}, w.Ops...)
+	}
+	for _, f := range unleveled(a.fields) {
+		if w.Fields == nil {
+			w.Fields = make(map[string]any, len(a.fields))
+		}
+		w.Fields[f.Key] = value(f.Value)
+	}
+	return w
+}
+
+// toWire encodes the group with every branch, failed or not, in the
+// order they ended.
+func (e *GroupError) toWire(value func(any) any) *wireError {
+	g := &wireGroup{Start: e.start, Branches: make([]wireBranch, len(e.branches))}
+	for i, br := range e.branches {
+		g.Branches[i] = wireBranch{Label: br.Label, Start: br.Start, End: br.End, Error: encodeWire(br.Err, value)}
+	}
+	return &wireError{Message: e.Error(), GoType: "*ex.GroupError", Group: g}
+}
+
+// groupError rebuilds the *GroupError encoded by w.
+func (w *wireGroup) groupError() *GroupError {
+	ge := &GroupError{start: w.Start, branches: make([]Branch, len(w.Branches))}
+	for i, br := range w.Branches {
+		ge.branches[i] = Branch{Label: br.Label, Start: br.Start, End: br.End, Err: fromWire(br.Error)}
+	}
+	return ge
+}
+
+// canonicalize strips w and everything below it of the members the
+// canonical encoding omits (see MarshalCanonical).
+func (w *wireError) canonicalize() {
+	for level := w; level != nil; level = level.Inner {
+		level.Type = ""
+		level.Taxonomy = 0
+		level.Origin = ""
+		level.Trace = nil
+		level.Stack = nil
+		level.StackDump = ""
+		level.GoType = ""
+		if g := level.Group; g != nil {
+			g.Start = time.Time{}
+			for i := range g.Branches {
+				g.Branches[i].Start, g.Branches[i].End = time.Time{}, time.Time{}
+				g.Branches[i].Error.canonicalize()
+			}
+			// The order branches end in varies between occurrences.
+			sort.SliceStable(g.Branches, func(i, j int) bool { return g.Branches[i].Label < g.Branches[j].Label })
+		}
+	}
+}
+
+// fieldMap converts fields to a map for encoding, with their values
+// rendered by value. Fields made with FieldAt are left out, since the
+// encodings have no level. encoding/json sorts map keys, which keeps the
+// encoding deterministic.
+func fieldMap(fields []Field, value func(any) any) map[string]any {
+	fields = unleveled(fields)
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(fields))
+	for _, f := range fields {
+		m[f.Key] = value(f.Value)
+	}
+	return m
+}
+
+// canonicalValue renders a field value for the canonical encoding: lazy
+// values (see LazyValue) are left unresolved and encoded as null, so that
+// fingerprinting an exception never computes them.
+func canonicalValue(v any) any {
+	if isLazy(v) {
+		return nil
+	}
+	return ResolveValue(v)
+}
+
+// mapFields converts decoded fields back to a slice ordered by key.
+func mapFields(m map[string]any) []Field {
+	if len(m) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, len(m))
+	for k, v := range m {
+		fields = append(fields, Field{Key: k, Value: v})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// fromWire rebuilds an error chain from its wire representation.
+func fromWire(w *wireError) error {
+	if w == nil {
+		return nil
+	}
+	if w.Code == nil {
+		if w.Group != nil {
+			return w.Group.groupError()
+		}
+		if w.GoType != "" {
+			return &RemoteError{typeName: w.GoType, message: w.Message}
+		}
+		return errors.New(w.Message)
+	}
+	return w.exception()
+}
+
+// exception rebuilds the Exception at this level, including its inner chain.
+func (w *wireError) exception() Exception {
+	var code ExType
+	if w.Code != nil {
+		code = *w.Code
+	}
+	code, id, taxonomy := migrate(code, w.ID, w.Taxonomy)
+	var trace *TraceContext
+	if w.Trace != nil && w.Trace.Valid() {
+		trace = w.Trace
+	}
+	return Exception{
+		code:       code,
+		id:         id,
+		codeString: w.CodeString,
+		keepStatus: w.KeepStatus,
+		expected:   w.Expected,
+		message:    w.Message,
+		hint:       w.Hint,
+		docsURL:    w.DocsURL,
+		innerError: fromWire(w.Inner),
+		fields:     mapFields(w.Fields),
+		details:    decodeDetails(w.Details),
+		frames:     w.Stack,
+		stackDump:  w.StackDump,
+		remote:     true,
+		origin:     w.Origin,
+		trace:      trace,
+		ops:        w.Ops,
+		taxonomy:   taxonomy,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// The encoding is a nested object per chain level:
+//
+//	{"code":4,"type":"ApplicationFailure","id":500,"message":"...","inner":{...}}
+//
+// Inner Exceptions are encoded recursively. Inner errors of any other type
+// are encoded as {"message":"..."} using their Error() string, plus a
+// "go_type" member with their Go type name unless they come from
+// errors.New or fmt.Errorf; such levels decode as a *RemoteError reporting
+// that name. The "type" member is informational and ignored when
+// decoding.
+//
+// The "origin" member records the service that created each level: the
+// name set with SetServiceName for local exceptions, or the recorded
+// origin for remote ones. Decoded exceptions report Remote() == true.
+//
+// Annotations (see Annotate) are folded into the next level: their fields
+// join its "fields" and their operations are listed in its "ops" array.
+//
+// A trace context attached with WithTraceContext is encoded as a "trace"
+// object holding "traceparent" and "tracestate", and re-attached on
+// decode if it is still well-formed.
+//
+// When a taxonomy version is set (see SetTaxonomyVersion), each level
+// carries the version its code and ID belong to in a "taxonomy" member.
+// On decode, levels tagged with an older version than this service's are
+// upgraded by the registered migrations (see RegisterMigration).
+//
+// A *GroupError (see Group and Join) is encoded with its message and a
+// "group" object holding its "start" and its "branches", each with its
+// "label", "start", "end", and, if it failed, its "error", encoded as a
+// chain of its own; it decodes as a *GroupError again.
+//
+// A symbolic code set with WithCodeString is encoded as "code_string".
+// Levels made with WrapKeepStatus are marked "keep_status":true.
+//
+// Typed details (see WithDetail) are encoded in a "details" object keyed
+// by detail name.
+//
+// A captured stack is encoded as a "stack" array of frames and comes back
+// already symbolized, so StackTrace works on decoded exceptions too. The
+// dump kept by FromStackDump is encoded as "stack_dump".
+//
+// Fields are encoded as a "fields" object. Field values must themselves be
+// JSON-encodable; after decoding they hold the generic encoding/json types
+// (float64, string, map[string]any, ...) and are ordered by key.
+func (e Exception) MarshalJSON() ([]byte, error) {
+	if data, ok := e.frozen.encoded(); ok {
+		return data, nil
+	}
+	return json.Marshal(toWire(e))
+}
+
+// MarshalCanonical returns the canonical JSON encoding of the exception.
+//
+// The canonical form is byte-for-byte stable for equal exceptions: members
+// appear in a fixed order, map keys are sorted, there is no insignificant
+// whitespace, and HTML characters are not escaped. Derived, informational
+// members such as "type" and "taxonomy" are omitted so that renaming a
+// code or bumping the taxonomy version does not change the encoding, and
+// so are per-occurrence members ("origin", "trace", and the timings of
+// groups, whose branches are ordered by label), the Go type names of
+// plain errors, and stacks and stack dumps, which change with every edit
+// to the surrounding code. Lazy field values (see LazyValue) are not
+// resolved: their fields are encoded by key only, with a null value. It
+// is the basis of Fingerprint and is suitable for golden-file
+// comparisons.
+func (e Exception) MarshalCanonical() ([]byte, error) {
+	w := encodeWire(e, canonicalValue)
+	w.canonicalize()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(w); err != nil {
+		return nil, err
+	}
+	// Encode always terminates the value with a newline.
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// Fingerprint returns a short, stable identifier for the exception,
+// derived from a SHA-256 digest of its canonical encoding. Exceptions with
+// identical chains share a fingerprint across processes and releases,
+// which makes it suitable for grouping and deduplication.
+func (e Exception) Fingerprint() string {
+	if e.frozen != nil {
+		return e.frozen.fingerprint
+	}
+	data, err := e.MarshalCanonical()
+	if err != nil {
+		// Only a field value that encoding/json rejects can fail here;
+		// fall back to the Error() text so the fingerprint stays usable.
+		data = []byte(e.Error())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// Exception is otherwise immutable; UnmarshalJSON is the one method that
+// writes through its receiver, and only to populate a freshly declared
+// value. Non-Exception inner errors are rebuilt as plain errors carrying
+// the original message.
+func (e *Exception) UnmarshalJSON(data []byte) error {
+	var w wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	*e = w.exception()
+	return nil
+}
+
+// Compile-time checks that Exception satisfies the JSON interfaces.
+var (
+	_ json.Marshaler   = Exception{}
+	_ json.Unmarshaler = (*Exception)(nil)
+)
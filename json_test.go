@@ -0,0 +1,105 @@
+package ex_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestException_MarshalJSON(t *testing.T) {
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Service unavailable").
+		WithInnerError(ex.New(ex.ExTypeIncorrectData, 400, "Bad input").
+			WithInnerError(errors.New("root cause")))
+
+	data, err := json.Marshal(exc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"code": 4, "type": "ApplicationFailure", "id": 500, "message": "Service unavailable",
+		"inner": {
+			"code": 1, "type": "IncorrectData", "id": 400, "message": "Bad input",
+			"inner": {"message": "root cause"}
+		}
+	}`, string(data))
+}
+
+func TestException_JSONRoundTrip(t *testing.T) {
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Service unavailable").
+		WithInnerError(ex.New(ex.ExTypePermissionDenied, 403, "Access denied").
+			WithInnerError(errors.New("root cause")))
+
+	data, err := json.Marshal(exc)
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, exc.Error(), decoded.Error())
+	assert.Equal(t, ex.ExTypeApplicationFailure, decoded.Code())
+	assert.Equal(t, 500, decoded.ID())
+	assert.True(t, errors.Is(decoded, ex.New(ex.ExTypePermissionDenied, 403, "")))
+
+	// The plain leaf is rebuilt as an opaque error with the same message.
+	var inner ex.Exception
+	require.True(t, errors.As(decoded.InnerError(), &inner))
+	assert.Equal(t, "root cause", inner.InnerError().Error())
+}
+
+func TestException_UnmarshalJSON_Invalid(t *testing.T) {
+	var decoded ex.Exception
+	assert.Error(t, json.Unmarshal([]byte(`{"code":"nope"}`), &decoded))
+}
+
+func TestException_JSONZeroCode(t *testing.T) {
+	lenient(t)
+
+	// ExType(0) is invalid but must still round-trip as an Exception
+	// rather than being mistaken for a plain error.
+	data, err := json.Marshal(ex.New(ex.ExType(0), 1, "zero"))
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, ex.ExType(0), decoded.Code())
+	assert.Equal(t, "zero", decoded.Message())
+}
+
+func TestException_MarshalCanonical_Golden(t *testing.T) {
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Service <unavailable> & degraded").
+		WithInnerError(ex.New(ex.ExTypeIncorrectData, 400, "Bad input").
+			WithInnerError(errors.New("root cause")))
+
+	got, err := exc.MarshalCanonical()
+	require.NoError(t, err)
+
+	want, err := os.ReadFile(filepath.Join("testdata", "canonical.golden"))
+	require.NoError(t, err)
+	assert.Equal(t, string(bytes.TrimSpace(want)), string(got))
+
+	// Repeated encodings are byte-identical.
+	for range 10 {
+		again, againErr := exc.MarshalCanonical()
+		require.NoError(t, againErr)
+		assert.Equal(t, got, again)
+	}
+}
+
+func TestException_Fingerprint(t *testing.T) {
+	build := func(rootMsg string) ex.Exception {
+		return ex.New(ex.ExTypeApplicationFailure, 500, "Service unavailable").
+			WithInnerError(errors.New(rootMsg))
+	}
+
+	fp := build("timeout").Fingerprint()
+	assert.Len(t, fp, 16)
+	assert.Equal(t, fp, build("timeout").Fingerprint())
+	assert.NotEqual(t, fp, build("refused").Fingerprint())
+	assert.NotEqual(t, fp, ex.New(ex.ExTypeApplicationFailure, 501, "Service unavailable").
+		WithInnerError(errors.New("timeout")).Fingerprint())
+}
@@ -0,0 +1,144 @@
+package ex
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// Label values LabelGuard substitutes for what it will not pass through.
+const (
+	// LabelOther stands for codes and IDs outside the guard's allowlist.
+	LabelOther = "other"
+
+	// LabelNone is the code and ID of an error that carries neither.
+	LabelNone = "none"
+)
+
+// LabelConfig configures a LabelGuard.
+type LabelConfig struct {
+	// IDs lists the code and ID pairs whose IDs are used as label values
+	// verbatim.
+	IDs []CodeID
+
+	// Catalog also allows every ID registered in DefaultCatalog, as of the
+	// moment each error is labeled.
+	Catalog bool
+
+	// Buckets, if positive, hashes IDs outside the allowlist into that
+	// many values, "other-0" to "other-<Buckets-1>", so that a flood of
+	// unexpected IDs still shows up spread out rather than as one line.
+	// Zero maps them all to LabelOther.
+	Buckets int
+
+	// Tenants lists the tenants (see WithTenant) whose IDs Tenant passes
+	// through as label values, for metrics partitioned per tenant; any
+	// other tenant becomes LabelOther.
+	Tenants []string
+}
+
+// LabelGuard turns errors into code and ID label values for metrics
+// without letting the number of distinct values grow unbounded: a bug that
+// puts random numbers in exception IDs would otherwise create one time
+// series per error and take the metrics backend down with it.
+//
+// Codes are passed through if registered (see RegisterType) and become
+// LabelOther if not. IDs are passed through only if allowed by the
+// LabelConfig, and bucketed otherwise:
+//
+//	guard := ex.NewLabelGuard(ex.LabelConfig{Catalog: true, Buckets: 8})
+//	ex.AddHook(func(e ex.Exception) {
+//	    code, id := guard.Labels(e)
+//	    errorsTotal.WithLabelValues(code, id).Inc()
+//	})
+//
+// For metrics partitioned per tenant, add the Tenant label value, which
+// is bounded by LabelConfig.Tenants the same way.
+//
+// A LabelGuard is safe for concurrent use.
+type LabelGuard struct {
+	ids      map[CodeID]struct{}
+	tenants  map[string]struct{}
+	catalog  bool
+	buckets  int
+	overflow atomic.Uint64
+}
+
+// NewLabelGuard returns a LabelGuard configured by cfg.
+func NewLabelGuard(cfg LabelConfig) *LabelGuard {
+	g := &LabelGuard{
+		ids:     make(map[CodeID]struct{}, len(cfg.IDs)),
+		tenants: make(map[string]struct{}, len(cfg.Tenants)),
+		catalog: cfg.Catalog,
+		buckets: max(cfg.Buckets, 0),
+	}
+	for _, k := range cfg.IDs {
+		g.ids[k] = struct{}{}
+	}
+	for _, t := range cfg.Tenants {
+		g.tenants[t] = struct{}{}
+	}
+	return g
+}
+
+// Labels returns the code and ID label values for the outermost Exception
+// (or other Errorer) in err's chain. Errors without one, including nil,
+// are labeled LabelNone twice.
+func (g *LabelGuard) Labels(err error) (code, id string) {
+	e, ok := outermost(err)
+	if !ok {
+		return LabelNone, LabelNone
+	}
+	c, n := e.Code(), e.ID()
+	if _, ok := LookupType(c); !ok {
+		g.overflow.Add(1)
+		return LabelOther, g.bucket(n)
+	}
+	if g.allowed(c, n) {
+		return c.String(), strconv.Itoa(n)
+	}
+	g.overflow.Add(1)
+	return c.String(), g.bucket(n)
+}
+
+// Tenant returns the tenant label value for err (see TenantOf): the
+// tenant itself if listed in LabelConfig.Tenants, LabelOther if not, and
+// LabelNone if err has no tenant.
+func (g *LabelGuard) Tenant(err error) string {
+	id, ok := TenantOf(err)
+	if !ok {
+		return LabelNone
+	}
+	if _, allowed := g.tenants[id]; allowed {
+		return id
+	}
+	g.overflow.Add(1)
+	return LabelOther
+}
+
+// Overflow returns the number of times Labels or Tenant has replaced a
+// value so far; a steady climb is worth an alert of its own.
+func (g *LabelGuard) Overflow() uint64 {
+	return g.overflow.Load()
+}
+
+// allowed reports whether id may be used verbatim for code.
+func (g *LabelGuard) allowed(code ExType, id int) bool {
+	if _, ok := g.ids[CodeID{Code: code, ID: id}]; ok {
+		return true
+	}
+	if g.catalog {
+		_, ok := Lookup(code, id)
+		return ok
+	}
+	return false
+}
+
+// bucket returns the label value for an ID outside the allowlist.
+func (g *LabelGuard) bucket(id int) string {
+	if g.buckets == 0 {
+		return LabelOther
+	}
+	// A multiplicative hash spreads consecutive IDs across the buckets.
+	h := uint64(id) * 0x9e3779b97f4a7c15
+	return LabelOther + "-" + strconv.FormatUint(h%uint64(g.buckets), 10)
+}
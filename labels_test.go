@@ -0,0 +1,83 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelGuard_Allowlist(t *testing.T) {
+	g := ex.NewLabelGuard(ex.LabelConfig{IDs: []ex.CodeID{{Code: ex.ExTypeNotFound, ID: 4041}}})
+
+	code, id := g.Labels(fmt.Errorf("handler: %w", ex.New(ex.ExTypeNotFound, 4041, "Account not found")))
+	assert.Equal(t, "NotFound", code)
+	assert.Equal(t, "4041", id)
+	assert.Zero(t, g.Overflow())
+
+	code, id = g.Labels(ex.New(ex.ExTypeNotFound, 918273, "random"))
+	assert.Equal(t, "NotFound", code, "known codes are kept")
+	assert.Equal(t, ex.LabelOther, id)
+
+	code, id = g.Labels(ex.New(ex.ExTypeConflict, 4041, "same ID, other code"))
+	assert.Equal(t, "Conflict", code)
+	assert.Equal(t, ex.LabelOther, id, "IDs are allowed per code")
+	assert.Equal(t, uint64(2), g.Overflow())
+}
+
+func TestLabelGuard_UnregisteredCode(t *testing.T) {
+	lenient(t)
+
+	g := ex.NewLabelGuard(ex.LabelConfig{IDs: []ex.CodeID{{Code: ex.ExType(4611), ID: 1}}})
+	code, id := g.Labels(ex.New(ex.ExType(4611), 1, "x"))
+	assert.Equal(t, ex.LabelOther, code)
+	assert.Equal(t, ex.LabelOther, id)
+}
+
+func TestLabelGuard_Catalog(t *testing.T) {
+	g := ex.NewLabelGuard(ex.LabelConfig{Catalog: true})
+	code, id := g.Labels(errCardDeclined.New())
+	assert.Equal(t, "IncorrectData", code)
+	assert.Equal(t, "1133", id)
+
+	_, id = g.Labels(ex.New(ex.ExTypeIncorrectData, 1134, "x"))
+	assert.Equal(t, ex.LabelOther, id)
+}
+
+func TestLabelGuard_Buckets(t *testing.T) {
+	g := ex.NewLabelGuard(ex.LabelConfig{Buckets: 4})
+	seen := map[string]bool{}
+	for i := range 1000 {
+		_, id := g.Labels(ex.New(ex.ExTypeApplicationFailure, 1_000_000+i, "random ID"))
+		seen[id] = true
+	}
+	assert.Equal(t, map[string]bool{"other-0": true, "other-1": true, "other-2": true, "other-3": true}, seen)
+
+	_, first := g.Labels(ex.New(ex.ExTypeApplicationFailure, 77, "x"))
+	_, again := g.Labels(ex.New(ex.ExTypeApplicationFailure, 77, "x"))
+	assert.Equal(t, first, again, "bucketing is deterministic")
+	_, negative := g.Labels(ex.New(ex.ExTypeApplicationFailure, -5, "x"))
+	assert.Contains(t, seen, negative)
+}
+
+func TestLabelGuard_NoErrorer(t *testing.T) {
+	g := ex.NewLabelGuard(ex.LabelConfig{})
+	for _, err := range []error{nil, errors.New("plain")} {
+		code, id := g.Labels(err)
+		assert.Equal(t, ex.LabelNone, code)
+		assert.Equal(t, ex.LabelNone, id)
+	}
+	assert.Zero(t, g.Overflow())
+}
+
+func TestLabelGuard_Tenant(t *testing.T) {
+	g := ex.NewLabelGuard(ex.LabelConfig{Tenants: []string{"acme"}})
+
+	assert.Equal(t, "acme", g.Tenant(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("acme")))
+	assert.Equal(t, ex.LabelOther, g.Tenant(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("globex")))
+	assert.Equal(t, ex.LabelNone, g.Tenant(ex.New(ex.ExTypeNotFound, 4041, "No such order")))
+	assert.Equal(t, ex.LabelNone, g.Tenant(nil))
+	assert.Equal(t, uint64(1), g.Overflow())
+}
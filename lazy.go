@@ -0,0 +1,106 @@
+package ex
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxResolve bounds the chain of lazy values ResolveValue follows, so a
+// value that resolves to itself cannot hang rendering.
+const maxResolve = 8
+
+// LazyValue is a field value computed when the field is rendered rather
+// than when it is attached, for context that is expensive to build, such
+// as a serialized request body, and wasted on the many errors that are
+// handled without ever being logged:
+//
+//	ex.New(ex.ExTypeApplicationFailure, 5001, "Upstream rejected the request").
+//	    WithField("request", ex.Lazy(func() any { return dump(req) }))
+//
+// The JSON encoding, Snapshot, ToHTML, UserMessage, the Log action, and
+// the subpackages render fields through ResolveValue; FieldsOf and
+// FieldValue return the value as attached. MarshalCanonical, and so
+// Fingerprint, encode lazy fields by key only and never resolve them. A
+// func() any value attached with WithField, WithFields, or Annotate is
+// wrapped with Lazy, so it is called at most once however often the
+// exception is rendered. Catalog field schemas accept lazy values without
+// resolving them.
+//
+// Resolve may be called from several goroutines and more than once; wrap
+// the function with Lazy to compute the value only once.
+type LazyValue interface {
+	Resolve() any
+}
+
+// Lazy returns a LazyValue that calls fn the first time it is resolved
+// and returns the same result every time after. It also implements
+// slog.LogValuer, so loggers resolve it wherever it ends up.
+func Lazy(fn func() any) LazyValue {
+	return &lazyValue{fn: fn}
+}
+
+// lazyValue is the LazyValue returned by Lazy.
+type lazyValue struct {
+	once sync.Once
+	fn   func() any
+	v    any
+}
+
+// Resolve implements LazyValue.
+func (l *lazyValue) Resolve() any {
+	l.once.Do(func() {
+		l.v, l.fn = l.fn(), nil
+	})
+	return l.v
+}
+
+// LogValue implements slog.LogValuer.
+func (l *lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(ResolveValue(l))
+}
+
+// ResolveValue returns the value a field holding v renders as: the value
+// given to FieldAt if v comes from one, the result of resolving v if it
+// is a LazyValue or a func() any, repeatedly, and
+// for a []byte, its text if it holds valid UTF-8 and its standard base64
+// encoding otherwise. Any other value is returned as it is.
+func ResolveValue(v any) any {
+	for range maxResolve {
+		switch lv := v.(type) {
+		case leveledValue:
+			v = lv.value
+		case LazyValue:
+			v = lv.Resolve()
+		case func() any:
+			v = lv()
+		case []byte:
+			if utf8.Valid(lv) {
+				return string(lv)
+			}
+			return base64.StdEncoding.EncodeToString(lv)
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+// attachValue returns the value a field stores when v is attached: a
+// func() any wrapped with Lazy, or v itself.
+func attachValue(v any) any {
+	if fn, ok := v.(func() any); ok && fn != nil {
+		return Lazy(fn)
+	}
+	return v
+}
+
+// isLazy reports whether v is resolved by ResolveValue before rendering.
+func isLazy(v any) bool {
+	switch v.(type) {
+	case LazyValue, func() any:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package ex_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy_ResolvedOnRender(t *testing.T) {
+	var calls atomic.Int32
+	body := ex.Lazy(func() any {
+		calls.Add(1)
+		return map[string]any{"sku": "A-1"}
+	})
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Upstream rejected the request").WithField("request", body)
+	assert.Equal(t, int32(0), calls.Load())
+
+	v, ok := e.FieldValue("request")
+	require.True(t, ok)
+	assert.Equal(t, body, v)
+	assert.Equal(t, int32(0), calls.Load())
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"request":{"sku":"A-1"}`)
+	_, err = json.Marshal(e)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLazy_NotFingerprinted(t *testing.T) {
+	var calls atomic.Int32
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Upstream rejected the request").
+		WithField("request", ex.Lazy(func() any { calls.Add(1); return "POST /orders" }))
+
+	canonical, err := e.MarshalCanonical()
+	require.NoError(t, err)
+	assert.Contains(t, string(canonical), `"fields":{"request":null}`)
+	other := ex.New(ex.ExTypeApplicationFailure, 5001, "Upstream rejected the request").
+		WithField("request", ex.Lazy(func() any { return "PUT /orders" }))
+	assert.Equal(t, e.Fingerprint(), other.Fingerprint())
+	assert.Equal(t, int32(0), calls.Load())
+}
+
+func TestLazy_FuncCalledOnce(t *testing.T) {
+	var calls atomic.Int32
+	fn := func() any { calls.Add(1); return "POST /orders" }
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Upstream rejected the request").WithField("request", fn)
+	annotated := ex.Annotate(e, ex.F("retry", func() any { calls.Add(1); return 2 }))
+
+	for range 3 {
+		_, err := json.Marshal(annotated)
+		require.NoError(t, err)
+		_ = ex.ToHTML(annotated)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "POST /orders", e.Snapshot().Fields["request"])
+}
+
+func TestResolveValue(t *testing.T) {
+	assert.Equal(t, 42, ex.ResolveValue(func() any { return 42 }))
+	assert.Equal(t, "nested", ex.ResolveValue(ex.Lazy(func() any { return func() any { return "nested" } })))
+	assert.Equal(t, `{"id":7}`, ex.ResolveValue([]byte(`{"id":7}`)))
+	assert.Equal(t, "/w==", ex.ResolveValue([]byte{0xff}))
+	assert.Equal(t, 3.5, ex.ResolveValue(3.5))
+	assert.Nil(t, ex.ResolveValue(nil))
+}
+
+func TestLazy_Renderers(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Upstream rejected the request").
+		WithFields(ex.F("request", ex.Lazy(func() any { return "POST /orders" })), ex.F("body", []byte("{}")))
+
+	assert.Equal(t, map[string]any{"request": "POST /orders", "body": "{}"}, e.Snapshot().Fields)
+	assert.Contains(t, string(ex.ToHTML(e)), "POST /orders")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("failed", "request", ex.Lazy(func() any { return "POST /orders" }))
+	assert.Contains(t, buf.String(), `"request":"POST /orders"`)
+}
+
+var errBadAmount = ex.Define(ex.Entry{
+	Code:    ex.ExTypeIncorrectData,
+	ID:      4222,
+	Message: "Bad amount",
+	Fields:  []ex.FieldSpec{ex.FieldOf[int]("amount")},
+})
+
+func TestLazy_AcceptedByFieldSchemas(t *testing.T) {
+	e := errBadAmount.New(ex.F("amount", ex.Lazy(func() any { return 12 })))
+	assert.NoError(t, ex.ValidateFields(e))
+}
@@ -0,0 +1,191 @@
+// Package logfile appends exceptions to a file as newline-delimited JSON
+// (NDJSON) and reads them back.
+//
+// It is intended for small services that have no log pipeline: every
+// record is a single self-contained JSON object on its own line, so the
+// file can be tailed, grepped, or shipped later without any tooling
+// beyond this package.
+//
+// Rotation is left to the caller. The Writer tracks how many bytes have
+// been written and reports via ShouldRotate when the configured size has
+// been reached; the caller decides how to rotate (rename, compress,
+// reopen) and when.
+package logfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// Record is a single line of an NDJSON error log.
+type Record struct {
+	Time      time.Time    `json:"time"`
+	Exception ex.Exception `json:"exception"`
+}
+
+// Options configures a Writer.
+type Options struct {
+	// RotateSize is the size in bytes at which ShouldRotate starts
+	// reporting true. Zero disables the hint.
+	RotateSize int64
+
+	// Now returns the timestamp recorded for each entry. Defaults to
+	// time.Now.
+	Now func() time.Time
+}
+
+// Writer appends exceptions as NDJSON records. It is safe for concurrent
+// use; each record is written with a single Write call on the underlying
+// writer so lines never interleave.
+type Writer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	size int64
+	opts Options
+}
+
+// NewWriter returns a Writer that appends records to w.
+func NewWriter(w io.Writer, opts Options) *Writer {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Writer{w: w, opts: opts}
+}
+
+// OpenFile opens (creating if necessary) the file at path for appending
+// and returns a Writer for it. The file's existing size counts towards
+// the rotation hint. Close the Writer to close the file.
+func OpenFile(path string, opts Options) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 -- path is caller-supplied by design
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	w := NewWriter(f, opts)
+	w.size = info.Size()
+	return w, nil
+}
+
+// Write appends e as a single NDJSON record.
+func (w *Writer) Write(e ex.Exception) error {
+	line, err := json.Marshal(Record{Time: w.opts.Now(), Exception: e})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.w.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// Size returns the number of bytes written so far, including any content
+// the file already held when it was opened with OpenFile.
+func (w *Writer) Size() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// ShouldRotate reports whether the written size has reached the
+// configured RotateSize. It always returns false when RotateSize is zero.
+func (w *Writer) ShouldRotate() bool {
+	if w.opts.RotateSize <= 0 {
+		return false
+	}
+	return w.Size() >= w.opts.RotateSize
+}
+
+// Close closes the underlying writer if it implements io.Closer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if c, ok := w.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Reader parses NDJSON records written by Writer.
+type Reader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewReader returns a Reader that parses records from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next record. Blank lines are skipped. It returns io.EOF
+// once the input is exhausted. A malformed line yields a *SyntaxError;
+// reading may continue past it with another call to Next.
+func (r *Reader) Next() (Record, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return Record{}, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return Record{}, err
+			}
+			continue
+		}
+		var rec Record
+		if jerr := json.Unmarshal(line, &rec); jerr != nil {
+			return Record{}, &SyntaxError{Line: r.line, Err: jerr}
+		}
+		return rec, nil
+	}
+}
+
+// ReadAll parses every record from r. It stops at the first malformed
+// line and returns the records read so far along with the error.
+func ReadAll(r io.Reader) ([]Record, error) {
+	var out []Record
+	rd := NewReader(r)
+	for {
+		rec, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, rec)
+	}
+}
+
+// SyntaxError reports a line that could not be parsed as a record.
+type SyntaxError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return "logfile: line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying JSON error.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,101 @@
+package logfile_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/logfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixedTime = time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func fixedNow() time.Time { return fixedTime }
+
+func TestWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := logfile.NewWriter(&buf, logfile.Options{Now: fixedNow})
+
+	first := ex.New(ex.ExTypeIncorrectData, 400, "Invalid email")
+	second := ex.New(ex.ExTypeApplicationFailure, 500, "Database operation failed").
+		WithInnerError(errors.New("connection timeout"))
+	require.NoError(t, w.Write(first))
+	require.NoError(t, w.Write(second))
+
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+	assert.Equal(t, int64(buf.Len()), w.Size())
+
+	records, err := logfile.ReadAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.True(t, fixedTime.Equal(records[0].Time))
+	assert.Equal(t, first.Error(), records[0].Exception.Error())
+	assert.Equal(t, ex.ExTypeApplicationFailure, records[1].Exception.Code())
+	assert.Equal(t, "Database operation failed: connection timeout", records[1].Exception.Error())
+}
+
+func TestWriter_ShouldRotate(t *testing.T) {
+	var buf bytes.Buffer
+	w := logfile.NewWriter(&buf, logfile.Options{RotateSize: 64, Now: fixedNow})
+	assert.False(t, w.ShouldRotate())
+
+	require.NoError(t, w.Write(ex.New(ex.ExTypeIncorrectData, 400, "first")))
+	require.NoError(t, w.Write(ex.New(ex.ExTypeIncorrectData, 400, "second")))
+	assert.True(t, w.ShouldRotate())
+
+	unbounded := logfile.NewWriter(io.Discard, logfile.Options{})
+	require.NoError(t, unbounded.Write(ex.New(ex.ExTypeIncorrectData, 400, "x")))
+	assert.False(t, unbounded.ShouldRotate())
+}
+
+func TestOpenFile_AppendsAndCountsExistingSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.ndjson")
+
+	w, err := logfile.OpenFile(path, logfile.Options{Now: fixedNow})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(ex.New(ex.ExTypeIncorrectData, 400, "first")))
+	firstSize := w.Size()
+	require.NoError(t, w.Close())
+
+	w, err = logfile.OpenFile(path, logfile.Options{RotateSize: firstSize + 1, Now: fixedNow})
+	require.NoError(t, err)
+	assert.Equal(t, firstSize, w.Size())
+	require.NoError(t, w.Write(ex.New(ex.ExTypeLoginRequired, 401, "second")))
+	assert.True(t, w.ShouldRotate())
+	require.NoError(t, w.Close())
+}
+
+func TestReader_SkipsBlankLinesAndReportsSyntaxErrors(t *testing.T) {
+	input := "\n" +
+		`{"time":"2025-01-10T12:00:00Z","exception":{"code":1,"id":400,"message":"ok"}}` + "\n" +
+		"not json\n" +
+		`{"time":"2025-01-10T12:00:00Z","exception":{"code":2,"id":401,"message":"tail"}}`
+
+	r := logfile.NewReader(strings.NewReader(input))
+
+	rec, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", rec.Exception.Message())
+
+	_, err = r.Next()
+	var syntaxErr *logfile.SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	assert.Equal(t, 3, syntaxErr.Line)
+
+	// Reading continues past a malformed line; the final record has no
+	// trailing newline.
+	rec, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, ex.ExTypeLoginRequired, rec.Exception.Code())
+
+	_, err = r.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
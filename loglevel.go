@@ -0,0 +1,59 @@
+package ex
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// LevelCritical is the slog level LogLevel uses for SeverityCritical by
+// default. slog has no level above Error, so it follows the package's
+// advice of leaving gaps of four between levels.
+const LevelCritical = slog.LevelError + 4
+
+// levelMapping maps severities to log levels; see SetLevelMapping.
+var levelMapping atomic.Pointer[map[Severity]slog.Level]
+
+func init() {
+	SetLevelMapping(nil)
+}
+
+// SetLevelMapping replaces the severity-to-level mapping used by LogLevel.
+// Severities missing from m keep their default level:
+//
+//	SeverityDebug    → slog.LevelDebug
+//	SeverityInfo     → slog.LevelInfo
+//	SeverityWarning  → slog.LevelWarn
+//	SeverityError    → slog.LevelError
+//	SeverityCritical → LevelCritical
+//
+// A nil m restores the defaults.
+func SetLevelMapping(m map[Severity]slog.Level) {
+	next := map[Severity]slog.Level{
+		SeverityDebug:    slog.LevelDebug,
+		SeverityInfo:     slog.LevelInfo,
+		SeverityWarning:  slog.LevelWarn,
+		SeverityError:    slog.LevelError,
+		SeverityCritical: LevelCritical,
+	}
+	for s, l := range m {
+		next[s] = l
+	}
+	levelMapping.Store(&next)
+}
+
+// LogLevel returns the level err should be logged at, derived from the
+// registered severity of the outermost Exception in its chain (see
+// SeverityOf and RegisterType). With the predefined codes, user errors
+// such as bad input land at Warn and application failures at Error.
+// Errors with no registered severity, including plain errors, are logged
+// at Error, and a nil error at Info. Expected errors (see WithExpected)
+// are logged no higher than SeverityInfo.
+func LogLevel(err error) slog.Level {
+	if err == nil {
+		return slog.LevelInfo
+	}
+	if level, ok := (*levelMapping.Load())[AlertSeverity(err)]; ok {
+		return level
+	}
+	return slog.LevelError
+}
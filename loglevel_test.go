@@ -0,0 +1,37 @@
+package ex_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevel(t *testing.T) {
+	lenient(t)
+
+	assert.Equal(t, slog.LevelWarn, ex.LogLevel(ex.New(ex.ExTypeIncorrectData, 400, "bad input")))
+	assert.Equal(t, slog.LevelWarn, ex.LogLevel(ex.New(ex.ExTypePermissionDenied, 403, "denied")))
+	assert.Equal(t, slog.LevelError, ex.LogLevel(ex.New(ex.ExTypeApplicationFailure, 500, "down")))
+	assert.Equal(t, slog.LevelError, ex.LogLevel(errors.New("plain")))
+	assert.Equal(t, slog.LevelError, ex.LogLevel(ex.New(ex.ExType(4501), 1, "unregistered")))
+	assert.Equal(t, slog.LevelInfo, ex.LogLevel(nil))
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4502), ex.TypeInfo{Severity: ex.SeverityCritical}))
+	assert.Equal(t, ex.LevelCritical, ex.LogLevel(ex.New(ex.ExType(4502), 1, "critical")))
+}
+
+func TestSetLevelMapping(t *testing.T) {
+	t.Cleanup(func() { ex.SetLevelMapping(nil) })
+
+	ex.SetLevelMapping(map[ex.Severity]slog.Level{ex.SeverityWarning: slog.LevelInfo})
+	assert.Equal(t, slog.LevelInfo, ex.LogLevel(ex.New(ex.ExTypeIncorrectData, 400, "bad input")))
+	assert.Equal(t, slog.LevelError, ex.LogLevel(ex.New(ex.ExTypeApplicationFailure, 500, "down")),
+		"unmapped severities keep their defaults")
+
+	ex.SetLevelMapping(nil)
+	assert.Equal(t, slog.LevelWarn, ex.LogLevel(ex.New(ex.ExTypeIncorrectData, 400, "bad input")))
+}
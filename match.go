@@ -0,0 +1,196 @@
+package ex
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMatchSpec is returned by ParseMatchSpec for malformed specs.
+var ErrInvalidMatchSpec = errors.New("ex: invalid match spec")
+
+// MatchSpec describes a class of errors declaratively, for table tests
+// and config-driven policies. The zero value of each member matches
+// anything, and a spec matches when all of its non-zero members do. Code
+// and IDIn inspect the outermost Errorer in the chain, as predicates do.
+type MatchSpec struct {
+	// Code is the required code.
+	Code ExType
+	// IDIn lists the accepted IDs.
+	IDIn []int
+	// MessageContains must occur in the error's text, err.Error(), which
+	// includes every level of the chain.
+	MessageContains string
+}
+
+// Match reports whether err matches spec. A nil err matches nothing.
+//
+//	if ex.Match(err, ex.MatchSpec{Code: ex.ExTypePermissionDenied, IDIn: []int{403, 4031}}) { ... }
+func Match(err error, spec MatchSpec) bool {
+	if err == nil {
+		return false
+	}
+	if spec.Code != 0 || len(spec.IDIn) > 0 {
+		e, ok := outermost(err)
+		if !ok {
+			return false
+		}
+		if spec.Code != 0 && e.Code() != spec.Code {
+			return false
+		}
+		if len(spec.IDIn) > 0 && !slices.Contains(spec.IDIn, e.ID()) {
+			return false
+		}
+	}
+	return spec.MessageContains == "" || strings.Contains(err.Error(), spec.MessageContains)
+}
+
+// Predicate returns a Predicate matching what spec matches, for use with
+// Router and Filter.
+func (s MatchSpec) Predicate() Predicate {
+	return func(err error) bool { return Match(err, s) }
+}
+
+// ParseMatchSpec parses the string form of a MatchSpec: space-separated
+// key=value terms, in any order, each key at most once.
+//
+//	code=PermissionDenied id=403
+//	code=NotFound id=404,4041 message="no such user"
+//
+// The code is a name as printed by ExType.String, including the names of
+// registered custom codes, or a number. IDs are a comma-separated list,
+// and the message may be quoted with Go syntax to include spaces. An empty
+// string parses to the zero MatchSpec.
+func ParseMatchSpec(s string) (MatchSpec, error) {
+	var spec MatchSpec
+	seen := map[string]bool{}
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		key, value, tail, err := nextMatchTerm(rest)
+		if err != nil {
+			return MatchSpec{}, err
+		}
+		rest = strings.TrimSpace(tail)
+		if seen[key] {
+			return MatchSpec{}, fmt.Errorf("%w: %q given twice", ErrInvalidMatchSpec, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "code":
+			code, ok := parseExType(value)
+			if !ok {
+				return MatchSpec{}, fmt.Errorf("%w: unknown code %q", ErrInvalidMatchSpec, value)
+			}
+			spec.Code = code
+		case "id":
+			for _, part := range strings.Split(value, ",") {
+				id, convErr := strconv.Atoi(part)
+				if convErr != nil {
+					return MatchSpec{}, fmt.Errorf("%w: bad id %q", ErrInvalidMatchSpec, part)
+				}
+				spec.IDIn = append(spec.IDIn, id)
+			}
+		case "message":
+			spec.MessageContains = value
+		default:
+			return MatchSpec{}, fmt.Errorf("%w: unknown key %q", ErrInvalidMatchSpec, key)
+		}
+	}
+	return spec, nil
+}
+
+// String returns the spec in the form ParseMatchSpec accepts.
+func (s MatchSpec) String() string {
+	var terms []string
+	if s.Code != 0 {
+		name := s.Code.String()
+		if c, ok := parseExType(name); !ok || c != s.Code {
+			name = strconv.Itoa(int(s.Code))
+		}
+		terms = append(terms, "code="+name)
+	}
+	if len(s.IDIn) > 0 {
+		ids := make([]string, len(s.IDIn))
+		for i, id := range s.IDIn {
+			ids[i] = strconv.Itoa(id)
+		}
+		terms = append(terms, "id="+strings.Join(ids, ","))
+	}
+	if s.MessageContains != "" {
+		terms = append(terms, "message="+strconv.Quote(s.MessageContains))
+	}
+	return strings.Join(terms, " ")
+}
+
+// MarshalText implements encoding.TextMarshaler using String.
+func (s MatchSpec) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseMatchSpec,
+// so specs can be read directly from JSON and other configuration
+// formats.
+func (s *MatchSpec) UnmarshalText(text []byte) error {
+	spec, err := ParseMatchSpec(string(text))
+	if err != nil {
+		return err
+	}
+	*s = spec
+	return nil
+}
+
+// nextMatchTerm splits the first key=value term off s, which starts with
+// a non-space character, unquoting a quoted value.
+func nextMatchTerm(s string) (key, value, rest string, err error) {
+	key, after, ok := strings.Cut(s, "=")
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", "", fmt.Errorf("%w: expected key=value at %q", ErrInvalidMatchSpec, s)
+	}
+	if strings.HasPrefix(after, `"`) {
+		quoted, qerr := strconv.QuotedPrefix(after)
+		if qerr != nil {
+			return "", "", "", fmt.Errorf("%w: bad quoted value at %q", ErrInvalidMatchSpec, after)
+		}
+		rest = after[len(quoted):]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			return "", "", "", fmt.Errorf("%w: missing space after %s", ErrInvalidMatchSpec, quoted)
+		}
+		value, _ = strconv.Unquote(quoted)
+		return key, value, rest, nil
+	}
+	end := strings.IndexAny(after, " \t")
+	if end < 0 {
+		end = len(after)
+	}
+	if end == 0 {
+		return "", "", "", fmt.Errorf("%w: empty value for %q", ErrInvalidMatchSpec, key)
+	}
+	return key, after[:end], after[end:], nil
+}
+
+// parseExType resolves a code name as printed by ExType.String, or a
+// decimal code.
+func parseExType(name string) (ExType, bool) {
+	if n, err := strconv.Atoi(name); err == nil {
+		return ExType(n), n != 0
+	}
+	if category, names, ok := strings.Cut(name, "|"); ok {
+		code, codeOK := parseExType(category)
+		facets, facetsOK := parseFacets(names)
+		return code | facets, codeOK && facetsOK
+	}
+	for code := ExTypeIncorrectData; code <= ExTypeRateLimited; code++ {
+		if code.String() == name {
+			return code, true
+		}
+	}
+	for code, info := range *types.Load() {
+		if info.Name == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
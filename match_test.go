@@ -0,0 +1,102 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	denied := fmt.Errorf("handler: %w", ex.New(ex.ExTypePermissionDenied, 403, "Access denied"))
+
+	tests := []struct {
+		name string
+		err  error
+		spec ex.MatchSpec
+		want bool
+	}{
+		{"zero spec", denied, ex.MatchSpec{}, true},
+		{"code", denied, ex.MatchSpec{Code: ex.ExTypePermissionDenied}, true},
+		{"wrong code", denied, ex.MatchSpec{Code: ex.ExTypeNotFound}, false},
+		{"id in", denied, ex.MatchSpec{IDIn: []int{401, 403}}, true},
+		{"id not in", denied, ex.MatchSpec{IDIn: []int{401}}, false},
+		{"message", denied, ex.MatchSpec{MessageContains: "handler: Access"}, true},
+		{"all", denied, ex.MatchSpec{Code: ex.ExTypePermissionDenied, IDIn: []int{403}, MessageContains: "denied"}, true},
+		{"plain error by message", errors.New("EOF"), ex.MatchSpec{MessageContains: "EOF"}, true},
+		{"plain error by code", errors.New("EOF"), ex.MatchSpec{Code: ex.ExTypeApplicationFailure}, false},
+		{"nil", nil, ex.MatchSpec{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ex.Match(tt.err, tt.spec))
+			assert.Equal(t, tt.want, tt.spec.Predicate()(tt.err))
+		})
+	}
+}
+
+func TestParseMatchSpec(t *testing.T) {
+	code := ex.ExType(7401)
+	require.NoError(t, ex.RegisterType(code, ex.TypeInfo{Name: "MatchQuota"}))
+
+	tests := []struct {
+		in   string
+		want ex.MatchSpec
+	}{
+		{"", ex.MatchSpec{}},
+		{"code=PermissionDenied id=403", ex.MatchSpec{Code: ex.ExTypePermissionDenied, IDIn: []int{403}}},
+		{`  id=404,4041   message="no such user" code=NotFound `, ex.MatchSpec{Code: ex.ExTypeNotFound, IDIn: []int{404, 4041}, MessageContains: "no such user"}},
+		{"code=MatchQuota", ex.MatchSpec{Code: code}},
+		{"code=7 message=timeout", ex.MatchSpec{Code: ex.ExTypeNotFound, MessageContains: "timeout"}},
+		{"code=Unavailable|Transient", ex.MatchSpec{Code: ex.ExTypeUnavailable | ex.FacetTransient}},
+	}
+	for _, tt := range tests {
+		got, err := ex.ParseMatchSpec(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+
+		again, err := ex.ParseMatchSpec(got.String())
+		require.NoError(t, err)
+		assert.Equal(t, got, again, "String round-trips %q", tt.in)
+	}
+}
+
+func TestParseMatchSpec_Errors(t *testing.T) {
+	for _, in := range []string{
+		"code",
+		"=403",
+		"code=Bogus",
+		"code=Timeout|Bogus",
+		"code=Timeout|Facet(3)",
+		"code=0",
+		"id=abc",
+		"id=1,",
+		"color=red",
+		"id=1 id=2",
+		`message="unterminated`,
+		`message="a"id=1`,
+		"id=",
+	} {
+		_, err := ex.ParseMatchSpec(in)
+		assert.ErrorIs(t, err, ex.ErrInvalidMatchSpec, in)
+	}
+}
+
+func TestMatchSpec_Text(t *testing.T) {
+	var policy struct {
+		Page []ex.MatchSpec `json:"page"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"page": ["code=Unavailable", "code=Timeout id=5041"]}`), &policy))
+	require.Len(t, policy.Page, 2)
+	assert.True(t, ex.Match(ex.New(ex.ExTypeTimeout, 5041, "x"), policy.Page[1]))
+
+	data, err := json.Marshal(policy)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"page": ["code=Unavailable", "code=Timeout id=5041"]}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"page": ["code=Nope"]}`), &policy))
+}
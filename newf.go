@@ -0,0 +1,65 @@
+package ex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Newf creates an exception like New with a message formatted as by
+// fmt.Errorf, including its %w verbs, so that migrating
+//
+//	return fmt.Errorf("opening %s: %w", path, err)
+//
+// to
+//
+//	return ex.Newf(ex.ExTypeApplicationFailure, 5001, "opening %s: %w", path, err)
+//
+// keeps both the Error() text and what errors.Is and errors.As find. A
+// single %w at the end of the format, after ": ", is the usual case: its
+// operand becomes the inner error and the rest of the text the message,
+// as for WithInnerError. Any other use of %w, including several of them,
+// keeps the error fmt.Errorf would have returned as the inner error,
+// with an empty message, as Promote does for the errors it cannot split;
+// FromJoined expands several %w operands into their members.
+//
+// Like New, Newf follows the stack policy, runs the hooks, and is checked
+// in strict mode. Exceptions wrapping a quiet exception are quiet; see
+// Quiet.
+func Newf(code ExType, id int, format string, args ...any) Exception {
+	checkNew(code, id, format)
+	message, inner := splitWrapped(fmt.Errorf(format, args...))
+	inner = limitDepth(inner)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: inner,
+		quiet:      quietInner(inner),
+		stack:      autoStack(code, id, 1),
+		sizeHint:   sizeHintOf(message, inner),
+	}
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// splitWrapped splits the result of fmt.Errorf into an exception message
+// and inner error whose rendering together is the same text.
+func splitWrapped(err error) (string, error) {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		inner := u.Unwrap()
+		if inner == nil {
+			// A %w verb given nil: nothing to wrap.
+			return err.Error(), nil
+		}
+		if own, ok := strings.CutSuffix(err.Error(), ": "+inner.Error()); ok && own != "" {
+			return own, inner
+		}
+		return "", err
+	case interface{ Unwrap() []error }:
+		return "", err
+	default:
+		return err.Error(), nil
+	}
+}
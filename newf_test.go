@@ -0,0 +1,69 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewf_TrailingWrap(t *testing.T) {
+	err := fmt.Errorf("stat: %w", fs.ErrNotExist)
+	e := ex.Newf(ex.ExTypeNotFound, 4041, "opening %s: %w", "app.yaml", err)
+
+	assert.Equal(t, fmt.Errorf("opening %s: %w", "app.yaml", err).Error(), e.Error())
+	assert.Equal(t, "opening app.yaml", e.Message())
+	assert.Equal(t, err, e.InnerError())
+	assert.ErrorIs(t, e, fs.ErrNotExist)
+	assert.Equal(t, len(e.Error()), e.Size())
+}
+
+func TestNewf_WrapsException(t *testing.T) {
+	inner := ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable")
+	e := ex.Newf(ex.ExTypeApplicationFailure, 5001, "loading order %d: %w", 7, inner)
+
+	assert.Equal(t, "loading order 7: Database unreachable", e.Error())
+	assert.True(t, ex.IsUnavailable(e))
+	got, ok := e.InnerError().(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, 5031, got.ID())
+}
+
+func TestNewf_NoWrap(t *testing.T) {
+	e := ex.Newf(ex.ExTypeIncorrectData, 4001, "quantity %d is negative", -3)
+	assert.Equal(t, "quantity -3 is negative", e.Message())
+	assert.Nil(t, e.InnerError())
+
+	e = ex.Newf(ex.ExTypeIncorrectData, 4001, "no cause: %w", nil)
+	assert.Equal(t, "no cause: %!w(<nil>)", e.Error())
+	assert.Nil(t, e.InnerError())
+}
+
+func TestNewf_OtherWraps(t *testing.T) {
+	errA, errB := errors.New("disk full"), errors.New("network down")
+
+	mid := ex.Newf(ex.ExTypeApplicationFailure, 5001, "%w while saving", errA)
+	assert.Equal(t, "disk full while saving", mid.Error())
+	assert.Empty(t, mid.Message())
+	assert.ErrorIs(t, mid, errA)
+
+	multi := ex.Newf(ex.ExTypeApplicationFailure, 5002, "saving: %w; cleanup: %w", errA, errB)
+	assert.Equal(t, fmt.Errorf("saving: %w; cleanup: %w", errA, errB).Error(), multi.Error())
+	assert.ErrorIs(t, multi, errA)
+	assert.ErrorIs(t, multi, errB)
+	assert.Len(t, ex.FromJoined(multi.InnerError()), 2)
+}
+
+func TestNewf_Hooks(t *testing.T) {
+	var hooked []string
+	remove := ex.AddHook(func(e ex.Exception) { hooked = append(hooked, e.Error()) })
+	t.Cleanup(remove)
+
+	ex.Newf(ex.ExTypeApplicationFailure, 5001, "step %d failed", 2)
+	ex.Newf(ex.ExTypeApplicationFailure, 5001, "probe: %w", ex.QuietNew(ex.ExTypeUnavailable, 5034, "Not ready"))
+	assert.Equal(t, []string{"step 2 failed"}, hooked, "wrapping a quiet exception is quiet")
+}
@@ -0,0 +1,312 @@
+// Package notify turns exceptions into alert payloads for chat and paging
+// services: a title from the code and ID, a body from ex.Explain, and
+// labels from the fields, rendered for Slack, PagerDuty, or any webhook.
+//
+// A Formatter builds the alerts; fed the errors a service handles, from
+// a Router route for instance, it hands the alerts for severe enough ones
+// to a delivery function:
+//
+//	f := notify.New(notify.Config{Service: "billing"})
+//	alerts := make(chan notify.Alert, 100)
+//	router.Route(ex.IsServerFault, ex.Report(f.Report(func(a notify.Alert) {
+//	    select {
+//	    case alerts <- a:
+//	    default: // never block the request
+//	    }
+//	})))
+//
+//	for a := range alerts {
+//	    payload, _ := notify.Slack(a)
+//	    post(slackWebhookURL, payload)
+//	}
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bold-minds/ex"
+)
+
+// Limits of the rendered payloads, in bytes, below the limits of the
+// services.
+const (
+	// MaxTitle is the longest title Slack and PagerDuty render.
+	MaxTitle = 150
+
+	// MaxBody is the longest body Slack and PagerDuty render.
+	MaxBody = 3000
+)
+
+// Alert is an exception summarized for people on call. Encoded as JSON, it
+// is also the payload for generic webhooks.
+type Alert struct {
+	// Title names the exception: its code name and ID, its message, and
+	// the service, if configured, as in
+	// "billing: ApplicationFailure/5001: Checkout failed".
+	Title string `json:"title"`
+
+	// Body is the chain narrated by ex.Explain.
+	Body string `json:"body"`
+
+	// Severity is the name of the exception's severity; expected errors
+	// (see ex.Exception.WithExpected) are at most "Info".
+	Severity string `json:"severity"`
+
+	// Labels holds the fields of the chain (see ex.FieldsOf), formatted
+	// with fmt and redacted as ex.RedactFields does unless Config.Profile
+	// says otherwise, and the code and ID as "ex.code" and "ex.id".
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Fingerprint identifies the exception; see ex.Exception.Fingerprint.
+	// It changes with field values, so alerts deduplicate on Key instead.
+	Fingerprint string `json:"fingerprint"`
+
+	// Key groups the alerts of one failure for deduplication: ex.GroupKey
+	// of the exception, or the Key of the ex.Aggregate it was built from.
+	Key string `json:"key"`
+
+	// Count is the number of occurrences the alert stands for, when built
+	// from an ex.Aggregate; zero otherwise.
+	Count int `json:"count,omitempty"`
+}
+
+// Config configures a Formatter. Zero values select the defaults.
+type Config struct {
+	// Service prefixes the titles of alerts, if set.
+	Service string
+
+	// Labels, if not nil, lists the field keys that become labels; the
+	// default makes every field one.
+	Labels []string
+
+	// Filter selects the exceptions the hook delivers alerts for.
+	// Defaults to ex.SeverityAtLeast(ex.SeverityError), which leaves out
+	// client faults and expected errors.
+	Filter func(error) bool
+
+	// Profile, if set, prunes every error before it is formatted (see
+	// ex.Prune), so alerts posted to a shared channel show no more than
+	// it allows. Labels are then redacted only if the profile says so.
+	Profile *ex.Profile
+}
+
+// Formatter builds alerts from exceptions. It is safe for concurrent use.
+type Formatter struct {
+	cfg    Config
+	labels map[string]bool
+}
+
+// New returns a Formatter configured by cfg.
+func New(cfg Config) *Formatter {
+	if cfg.Filter == nil {
+		cfg.Filter = ex.SeverityAtLeast(ex.SeverityError)
+	}
+	f := &Formatter{cfg: cfg}
+	if cfg.Labels != nil {
+		f.labels = make(map[string]bool, len(cfg.Labels))
+		for _, key := range cfg.Labels {
+			f.labels[key] = true
+		}
+	}
+	return f
+}
+
+// Format returns the alert for err. The title of errors that are not
+// exceptions is that of their promotion (see ex.Promote). A nil err yields
+// the zero Alert.
+func (f *Formatter) Format(err error) Alert {
+	if err == nil {
+		return Alert{}
+	}
+	e, ok := ex.As(err)
+	if !ok {
+		e = ex.Promote(err, nil)
+	}
+	fingerprint, key, redact := e.Fingerprint(), ex.GroupKey(e), true
+	if f.cfg.Profile != nil {
+		// Promoting first keeps errors the profile would drop entirely.
+		err = ex.Prune(e, *f.cfg.Profile)
+		e, _ = ex.As(err)
+		redact = f.cfg.Profile.RedactFields
+	}
+	title := fmt.Sprintf("%s/%d", e.Code(), e.ID())
+	if e.Message() != "" {
+		title += ": " + e.Message()
+	}
+	if f.cfg.Service != "" {
+		title = f.cfg.Service + ": " + title
+	}
+	a := Alert{
+		Title:       title,
+		Body:        strings.TrimSuffix(ex.Explain(err), "\n"),
+		Severity:    ex.AlertSeverity(err).String(),
+		Labels:      map[string]string{"ex.code": e.Code().String(), "ex.id": fmt.Sprint(e.ID())},
+		Fingerprint: fingerprint,
+		Key:         key,
+	}
+	fields := ex.FieldsOf(err)
+	if redact {
+		fields = ex.RedactFields(fields)
+	}
+	for _, field := range fields {
+		if f.labels == nil || f.labels[field.Key] {
+			a.Labels[field.Key] = fmt.Sprint(ex.ResolveValue(field.Value))
+		}
+	}
+	return a
+}
+
+// FormatAggregate returns the alert for the exceptions grouped in agg by
+// an ex.Reporter: that of its sample, with its count and key.
+func (f *Formatter) FormatAggregate(agg ex.Aggregate) Alert {
+	a := f.Format(agg.Sample)
+	a.Count = agg.Count
+	if agg.Key != "" {
+		a.Key = agg.Key
+	}
+	return a
+}
+
+// Report returns a function that formats every error selected by the
+// filter and passes the alert to deliver, for ex.Report and other places
+// where errors are handled with their full context.
+func (f *Formatter) Report(deliver func(Alert)) func(error) {
+	return func(err error) {
+		if err != nil && f.cfg.Filter(err) {
+			deliver(f.Format(err))
+		}
+	}
+}
+
+// Hook returns an ex.Hook that formats every exception selected by the
+// filter and passes the alert to deliver. Hooks run inside New, on the
+// goroutine creating the exception and before the caller adds fields or
+// inner errors, so their alerts have only the "ex.code" and "ex.id"
+// labels and a one-step body, and deliver must hand the alert off rather
+// than send it. Use Report where alerts need that context.
+func (f *Formatter) Hook(deliver func(Alert)) ex.Hook {
+	return func(e ex.Exception) {
+		if f.cfg.Filter(e) {
+			deliver(f.Format(e))
+		}
+	}
+}
+
+// Attach registers f.Hook(deliver) with ex.AddHook and returns the
+// function that removes it. Its alerts have the limits described at Hook.
+func (f *Formatter) Attach(deliver func(Alert)) (remove func()) {
+	return ex.AddHook(f.Hook(deliver))
+}
+
+// Slack returns the payload posting a to a Slack incoming webhook: the
+// title as a header, the body as preformatted text, and the severity,
+// count, and labels as context, with the title as the notification text.
+func Slack(a Alert) ([]byte, error) {
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type block struct {
+		Type     string `json:"type"`
+		Text     *text  `json:"text,omitempty"`
+		Elements []text `json:"elements,omitempty"`
+	}
+	title := truncate(a.Title, MaxTitle)
+	blocks := []block{
+		{Type: "header", Text: &text{Type: "plain_text", Text: title}},
+		{Type: "section", Text: &text{Type: "mrkdwn", Text: "```" + truncate(a.Body, MaxBody-6) + "```"}},
+	}
+	if labels := sortedLabels(a.Labels); len(labels) > 0 {
+		var b strings.Builder
+		b.WriteString("*" + a.Severity + "*")
+		if a.Count > 1 {
+			b.WriteString(" · ×" + strconv.Itoa(a.Count))
+		}
+		for _, kv := range labels {
+			b.WriteString(" · `" + kv[0] + "`: " + kv[1])
+		}
+		blocks = append(blocks, block{Type: "context", Elements: []text{{Type: "mrkdwn", Text: truncate(b.String(), MaxBody)}}})
+	}
+	return json.Marshal(struct {
+		Text   string  `json:"text"`
+		Blocks []block `json:"blocks"`
+	}{title, blocks})
+}
+
+// PagerDuty returns the payload triggering an incident for a with the
+// PagerDuty Events API v2, routed by routingKey. The fingerprint is the
+// dedup key, so repeats of an exception update one incident, and source
+// names the component, as the API requires.
+func PagerDuty(a Alert, routingKey, source string) ([]byte, error) {
+	details := make(map[string]string, len(a.Labels)+1)
+	for k, v := range a.Labels {
+		details[k] = v
+	}
+	details["explanation"] = truncate(a.Body, MaxBody)
+	type payload struct {
+		Summary       string            `json:"summary"`
+		Source        string            `json:"source"`
+		Severity      string            `json:"severity"`
+		CustomDetails map[string]string `json:"custom_details"`
+	}
+	return json.Marshal(struct {
+		RoutingKey  string  `json:"routing_key"`
+		EventAction string  `json:"event_action"`
+		DedupKey    string  `json:"dedup_key,omitempty"`
+		Payload     payload `json:"payload"`
+	}{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		DedupKey:    a.Key,
+		Payload: payload{
+			Summary:       truncate(a.Title, 1024),
+			Source:        source,
+			Severity:      pagerDutySeverity(a.Severity),
+			CustomDetails: details,
+		},
+	})
+}
+
+// pagerDutySeverity maps a severity name to one of the four PagerDuty
+// accepts.
+func pagerDutySeverity(name string) string {
+	switch name {
+	case ex.SeverityCritical.String():
+		return "critical"
+	case ex.SeverityError.String():
+		return "error"
+	case ex.SeverityWarning.String():
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// sortedLabels returns labels as key and value pairs, sorted by key.
+func sortedLabels(labels map[string]string) [][2]string {
+	out := make([][2]string, 0, len(labels))
+	for k, v := range labels {
+		out = append(out, [2]string{k, v})
+	}
+	slices.SortFunc(out, func(a, b [2]string) int { return strings.Compare(a[0], b[0]) })
+	return out
+}
+
+// truncate shortens s to at most limit bytes, on a UTF-8 boundary, marking
+// the cut with an ellipsis.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	const ellipsis = "…"
+	n := max(limit-len(ellipsis), 0)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + ellipsis
+}
@@ -0,0 +1,168 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkoutFailure() ex.Exception {
+	cause := ex.New(ex.ExTypeUnavailable, 5031, "Gateway unreachable").WithField("gateway", "acme")
+	return ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").
+		WithInnerError(cause).
+		WithFields(ex.F("order", 42), ex.F("password", "hunter2"))
+}
+
+func TestFormatter_Format(t *testing.T) {
+	e := checkoutFailure()
+	a := notify.New(notify.Config{Service: "billing"}).Format(e)
+
+	assert.Equal(t, "billing: ApplicationFailure/5001: Checkout failed", a.Title)
+	assert.Equal(t, strings.TrimSuffix(ex.Explain(e), "\n"), a.Body)
+	assert.Equal(t, "Error", a.Severity)
+	assert.Equal(t, e.Fingerprint(), a.Fingerprint)
+	assert.Equal(t, ex.GroupKey(e), a.Key)
+	assert.Equal(t, map[string]string{
+		"ex.code":  "ApplicationFailure",
+		"ex.id":    "5001",
+		"order":    "42",
+		"password": "[REDACTED]",
+		"gateway":  "acme",
+	}, a.Labels)
+}
+
+func TestFormatter_FormatOptions(t *testing.T) {
+	f := notify.New(notify.Config{Labels: []string{"order"}})
+
+	a := f.Format(checkoutFailure())
+	assert.Equal(t, map[string]string{"ex.code": "ApplicationFailure", "ex.id": "5001", "order": "42"}, a.Labels)
+
+	a = f.Format(ex.New(ex.ExTypeApplicationFailure, 5002, "Nightly sync failed").WithExpected(true))
+	assert.Equal(t, "Info", a.Severity)
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4943), ex.TypeInfo{}))
+	a = f.Format(ex.New(ex.ExType(4943), 1, "Cache miss").WithExpected(true))
+	assert.Equal(t, "Info", a.Severity, "expected errors are Info without a registered severity")
+
+	a = f.Format(errors.New("boom"))
+	assert.Equal(t, "ApplicationFailure/0", a.Title)
+	assert.Equal(t, "1. What happened: boom", a.Body)
+
+	assert.Equal(t, notify.Alert{}, f.Format(nil))
+}
+
+func TestFormatter_Attach(t *testing.T) {
+	var alerts []notify.Alert
+	remove := notify.New(notify.Config{}).Attach(func(a notify.Alert) { alerts = append(alerts, a) })
+	t.Cleanup(remove)
+
+	_ = ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed")
+	_ = ex.New(ex.ExTypeNotFound, 4041, "No such order")
+	_ = ex.QuietNew(ex.ExTypeApplicationFailure, 5002, "Probe failed")
+	remove()
+	_ = ex.New(ex.ExTypeApplicationFailure, 5003, "After removal")
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "ApplicationFailure/5001: Checkout failed", alerts[0].Title)
+}
+
+func TestFormatter_Report(t *testing.T) {
+	f := notify.New(notify.Config{})
+	var hooked, reported []notify.Alert
+
+	remove := f.Attach(func(a notify.Alert) { hooked = append(hooked, a) })
+	e := checkoutFailure()
+	remove()
+	router := ex.NewRouter().Fallback(ex.Report(f.Report(func(a notify.Alert) { reported = append(reported, a) })))
+	_ = router.Handle(e)
+	_ = router.Handle(ex.New(ex.ExTypeNotFound, 4041, "No such order"))
+	f.Report(func(a notify.Alert) { reported = append(reported, a) })(nil)
+
+	require.Len(t, hooked, 2, "the inner error and the outer exception")
+	assert.Equal(t, map[string]string{"ex.code": "ApplicationFailure", "ex.id": "5001"}, hooked[1].Labels,
+		"hooks run before the fields are added")
+
+	require.Len(t, reported, 1)
+	assert.Equal(t, "42", reported[0].Labels["order"])
+	assert.Equal(t, "acme", reported[0].Labels["gateway"])
+	assert.Equal(t, strings.TrimSuffix(ex.Explain(e), "\n"), reported[0].Body)
+}
+
+func TestSlack(t *testing.T) {
+	a := notify.New(notify.Config{}).Format(checkoutFailure())
+	a.Title = strings.Repeat("é", 100)
+	data, err := notify.Slack(a)
+	require.NoError(t, err)
+
+	var msg struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type     string `json:"type"`
+			Text     struct{ Text string }
+			Elements []struct{ Text string }
+		} `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(data, &msg))
+	assert.LessOrEqual(t, len(msg.Text), notify.MaxTitle)
+	assert.True(t, strings.HasSuffix(msg.Text, "é…"))
+	require.Len(t, msg.Blocks, 3)
+	assert.Equal(t, "header", msg.Blocks[0].Type)
+	assert.Equal(t, "```"+a.Body+"```", msg.Blocks[1].Text.Text)
+	require.Len(t, msg.Blocks[2].Elements, 1)
+	assert.Equal(t, "*Error* · `ex.code`: ApplicationFailure · `ex.id`: 5001 · `gateway`: acme · `order`: 42 · `password`: [REDACTED]",
+		msg.Blocks[2].Elements[0].Text)
+}
+
+func TestPagerDuty(t *testing.T) {
+	a := notify.New(notify.Config{}).Format(checkoutFailure())
+	data, err := notify.PagerDuty(a, "R0UT1NG", "billing-7f9c")
+	require.NoError(t, err)
+
+	var event struct {
+		RoutingKey  string `json:"routing_key"`
+		EventAction string `json:"event_action"`
+		DedupKey    string `json:"dedup_key"`
+		Payload     struct {
+			Summary       string            `json:"summary"`
+			Source        string            `json:"source"`
+			Severity      string            `json:"severity"`
+			CustomDetails map[string]string `json:"custom_details"`
+		} `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "R0UT1NG", event.RoutingKey)
+	assert.Equal(t, "trigger", event.EventAction)
+	assert.Equal(t, a.Key, event.DedupKey)
+
+	other, err := notify.PagerDuty(notify.New(notify.Config{}).Format(checkoutFailure().WithField("order", 43)), "R0UT1NG", "billing-7f9c")
+	require.NoError(t, err)
+	assert.Contains(t, string(other), `"dedup_key":"`+a.Key+`"`, "field values do not split incidents")
+	assert.Equal(t, a.Title, event.Payload.Summary)
+	assert.Equal(t, "billing-7f9c", event.Payload.Source)
+	assert.Equal(t, "error", event.Payload.Severity)
+	assert.Equal(t, a.Body, event.Payload.CustomDetails["explanation"])
+	assert.Equal(t, "42", event.Payload.CustomDetails["order"])
+}
+
+func TestFormatter_Profile(t *testing.T) {
+	e := checkoutFailure()
+	a := notify.New(notify.Config{Profile: &ex.ProfileClient}).Format(e)
+
+	assert.Equal(t, "ApplicationFailure/5001: ApplicationFailure", a.Title)
+	assert.Equal(t, "1. What happened: ApplicationFailure (ApplicationFailure/5001)", a.Body)
+	assert.Equal(t, map[string]string{"ex.code": "ApplicationFailure", "ex.id": "5001"}, a.Labels)
+	assert.Equal(t, e.Fingerprint(), a.Fingerprint, "alerts still identify the full exception")
+	assert.Equal(t, ex.GroupKey(e), a.Key)
+
+	a = notify.New(notify.Config{Profile: &ex.ProfileDebug}).Format(e)
+	assert.Equal(t, "hunter2", a.Labels["password"])
+
+	a = notify.New(notify.Config{Profile: &ex.ProfileClient}).Format(errors.New("boom"))
+	assert.Equal(t, "ApplicationFailure/0: ApplicationFailure", a.Title)
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/internal/tokenbucket"
+)
+
+// Default Webhook configuration values.
+const (
+	DefaultRateLimit = 1.0 / 6 // ten posts a minute
+	DefaultBurst     = 10
+)
+
+// WebhookConfig configures a Webhook. Zero values select the defaults.
+type WebhookConfig struct {
+	// URL is the webhook to post to, such as a Slack incoming webhook.
+	URL string
+
+	// Headers are added to every request (e.g. authentication).
+	Headers map[string]string
+
+	// Client sends the requests. Defaults to a client with a 10s timeout.
+	Client *http.Client
+
+	// Formatter builds the alerts and, with its filter, selects the
+	// aggregates worth posting; see Config.Filter. Defaults to New(Config{}),
+	// which posts errors of SeverityError and above.
+	Formatter *Formatter
+
+	// Render encodes an alert as the request body. Defaults to Slack; use
+	// json.Marshal for webhooks that take the Alert itself.
+	Render func(Alert) ([]byte, error)
+
+	// RateLimit caps posts per second, and Burst the number of posts
+	// allowed at once before it applies. Past them, aggregates are
+	// dropped and counted. Negative RateLimit means unlimited.
+	RateLimit float64
+	Burst     int
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Webhook posts the aggregates of an ex.Reporter to a webhook, one post
+// per group of identical exceptions, so that a failure repeated thousands
+// of times between flushes is one message with a count:
+//
+//	hook := notify.NewWebhook(notify.WebhookConfig{URL: slackURL})
+//	r := ex.NewReporter(ex.ReporterConfig{Sink: hook.Sink})
+//	defer r.Shutdown(context.Background())
+//	router.Route(ex.IsServerFault, ex.Report(r.Report))
+//
+// A Webhook is safe for concurrent use.
+type Webhook struct {
+	cfg     WebhookConfig
+	limiter *tokenbucket.Bucket
+	dropped atomic.Uint64
+}
+
+// NewWebhook returns a Webhook configured by cfg.
+func NewWebhook(cfg WebhookConfig) *Webhook {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.Formatter == nil {
+		cfg.Formatter = New(Config{})
+	}
+	if cfg.Render == nil {
+		cfg.Render = Slack
+	}
+	if cfg.RateLimit == 0 {
+		cfg.RateLimit = DefaultRateLimit
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = DefaultBurst
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	w := &Webhook{cfg: cfg}
+	if cfg.RateLimit > 0 {
+		w.limiter = tokenbucket.New(cfg.RateLimit, cfg.Burst, cfg.Now)
+	}
+	return w
+}
+
+// Sink posts the aggregates in batch that pass the formatter's filter, in
+// order. If posts fail, it returns an *ex.SinkError listing their
+// aggregates, with their errors joined, so the reporter counts only those
+// as dropped. It has the signature of ex.ReporterConfig.Sink.
+func (w *Webhook) Sink(ctx context.Context, batch []ex.Aggregate) error {
+	var (
+		failed []ex.Aggregate
+		errs   []error
+	)
+	for _, agg := range batch {
+		if !w.cfg.Formatter.cfg.Filter(agg.Sample) {
+			continue
+		}
+		if w.limiter != nil && !w.limiter.Allow() {
+			w.dropped.Add(1)
+			continue
+		}
+		if err := w.post(ctx, w.cfg.Formatter.FormatAggregate(agg)); err != nil {
+			w.dropped.Add(1)
+			failed = append(failed, agg)
+			errs = append(errs, err)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &ex.SinkError{Failed: failed, Err: errors.Join(errs...)}
+}
+
+// Dropped returns the number of aggregates rejected by the rate limit or
+// lost to failed posts.
+func (w *Webhook) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// post renders a and posts it.
+func (w *Webhook) post(ctx context.Context, a Alert) error {
+	body, err := w.cfg.Render(a)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("notify: webhook responded " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a webhook endpoint that records the bodies posted to it.
+type recorder struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	status int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.bodies = append(r.bodies, body)
+	status := r.status
+	r.mu.Unlock()
+	if status != 0 {
+		w.WriteHeader(status)
+	}
+}
+
+func aggregate(e ex.Exception, count int) ex.Aggregate {
+	return ex.Aggregate{Fingerprint: e.Fingerprint(), Sample: e, Count: count}
+}
+
+func TestWebhook_Sink(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+
+	hook := notify.NewWebhook(notify.WebhookConfig{URL: srv.URL, Render: func(a notify.Alert) ([]byte, error) { return json.Marshal(a) }})
+	err := hook.Sink(context.Background(), []ex.Aggregate{
+		aggregate(ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed"), 37),
+		aggregate(ex.New(ex.ExTypeNotFound, 4041, "No such order"), 3),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, rec.bodies, 1)
+	var a notify.Alert
+	require.NoError(t, json.Unmarshal(rec.bodies[0], &a))
+	assert.Equal(t, "ApplicationFailure/5001: Checkout failed", a.Title)
+	assert.Equal(t, 37, a.Count)
+}
+
+func TestWebhook_SlackByDefault(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed")
+	require.NoError(t, notify.NewWebhook(notify.WebhookConfig{URL: srv.URL}).Sink(context.Background(), []ex.Aggregate{aggregate(e, 2)}))
+
+	a := notify.New(notify.Config{}).FormatAggregate(aggregate(e, 2))
+	want, err := notify.Slack(a)
+	require.NoError(t, err)
+	require.Len(t, rec.bodies, 1)
+	assert.JSONEq(t, string(want), string(rec.bodies[0]))
+	assert.Contains(t, string(rec.bodies[0]), "×2")
+}
+
+func TestFormatter_FormatAggregate(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed")
+	agg := aggregate(e, 3)
+	agg.Key = "checkout"
+
+	a := notify.New(notify.Config{}).FormatAggregate(agg)
+	assert.Equal(t, 3, a.Count)
+	assert.Equal(t, "checkout", a.Key, "the reporter's key wins")
+	assert.Equal(t, ex.GroupKey(e), notify.New(notify.Config{}).FormatAggregate(aggregate(e, 1)).Key)
+}
+
+func TestWebhook_RateLimit(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	hook := notify.NewWebhook(notify.WebhookConfig{URL: srv.URL, RateLimit: 1, Burst: 2, Now: func() time.Time { return now }})
+
+	var batch []ex.Aggregate
+	for id := range 5 {
+		batch = append(batch, aggregate(ex.New(ex.ExTypeApplicationFailure, 5001+id, "Checkout failed"), 1))
+	}
+	require.NoError(t, hook.Sink(context.Background(), batch))
+	assert.Len(t, rec.bodies, 2)
+	assert.Equal(t, uint64(3), hook.Dropped())
+
+	now = now.Add(time.Second)
+	require.NoError(t, hook.Sink(context.Background(), batch[:2]))
+	assert.Len(t, rec.bodies, 3)
+}
+
+func TestWebhook_Failure(t *testing.T) {
+	rec := &recorder{status: http.StatusForbidden}
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+
+	hook := notify.NewWebhook(notify.WebhookConfig{URL: srv.URL, RateLimit: -1})
+	err := hook.Sink(context.Background(), []ex.Aggregate{aggregate(ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed"), 1)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Equal(t, uint64(1), hook.Dropped())
+
+	var partial *ex.SinkError
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, partial.Failed, 1)
+	assert.Equal(t, 5001, partial.Failed[0].Sample.ID())
+}
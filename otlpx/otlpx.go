@@ -0,0 +1,486 @@
+// Package otlpx ships exceptions to an OpenTelemetry collector as OTLP log
+// records, using the OTLP/HTTP JSON encoding.
+//
+// It depends only on the standard library, so teams without an error
+// tracking service still get centralized error visibility by pointing it
+// at any OTLP-compatible collector:
+//
+//	exp := otlpx.NewExporter(otlpx.Config{
+//	    Endpoint:    "http://otel-collector:4318/v1/logs",
+//	    ServiceName: "billing",
+//	})
+//	defer exp.Shutdown(context.Background())
+//
+//	exp.Export(err)
+//
+// Records are queued and sent in batches from a background goroutine.
+// Export never blocks: when the rate limit is exceeded or the queue is
+// full the record is dropped and counted (see Dropped).
+package otlpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/internal/tokenbucket"
+)
+
+// Default configuration values.
+const (
+	DefaultBatchSize     = 100
+	DefaultQueueSize     = 1000
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// scopeName identifies this package as the instrumentation scope.
+const scopeName = "github.com/bold-minds/ex/otlpx"
+
+// OTLP SeverityNumber values for the start of each severity range.
+const (
+	severityDebug = 5
+	severityInfo  = 9
+	severityWarn  = 13
+	severityError = 17
+	severityFatal = 21
+)
+
+// ErrShutdown is returned by Flush after the exporter has been shut down.
+var ErrShutdown = errors.New("otlpx: exporter is shut down")
+
+// Config configures an Exporter. Zero values select the defaults.
+type Config struct {
+	// Endpoint is the full OTLP/HTTP logs URL, usually ending in /v1/logs.
+	Endpoint string
+
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+
+	// Headers are added to every export request (e.g. authentication).
+	Headers map[string]string
+
+	// Client sends export requests. Defaults to a client with a 10s timeout.
+	Client *http.Client
+
+	// BatchSize is the maximum number of records per export request.
+	BatchSize int
+
+	// QueueSize bounds the number of records waiting to be exported.
+	QueueSize int
+
+	// FlushInterval is how often a partial batch is sent.
+	FlushInterval time.Duration
+
+	// RateLimit caps accepted records per second; zero means unlimited.
+	RateLimit float64
+
+	// Burst is the number of records that may be accepted at once before
+	// RateLimit applies. Defaults to BatchSize.
+	Burst int
+
+	// Profile, if set, prunes every error before export (see ex.Prune),
+	// for example to ex.ProfileLog to redact sensitive fields. Errors it
+	// prunes away entirely are not exported.
+	Profile *ex.Profile
+
+	// PerTenant partitions each export request by tenant (see
+	// ex.WithTenant): records of each tenant get a resource of their own,
+	// carrying the tenant as the tenant.id attribute, so collectors can
+	// route them to separate tenants' backends.
+	PerTenant bool
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Exporter batches exceptions and sends them as OTLP log records. It is
+// safe for concurrent use.
+type Exporter struct {
+	cfg     Config
+	queue   chan logRecord
+	flushes chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	limiter *tokenbucket.Bucket
+	dropped atomic.Uint64
+	// unregister removes the exporter from the ex.Shutdown registry.
+	unregister func()
+}
+
+// NewExporter returns an Exporter for cfg and starts its background
+// sender. Call its Shutdown method, or ex.Shutdown, to flush and stop it.
+func NewExporter(cfg Config) *Exporter {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.BatchSize
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	x := &Exporter{
+		cfg:     cfg,
+		queue:   make(chan logRecord, cfg.QueueSize),
+		flushes: make(chan chan error),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if cfg.RateLimit > 0 {
+		x.limiter = tokenbucket.New(cfg.RateLimit, cfg.Burst, cfg.Now)
+	}
+	x.unregister = ex.OnShutdown(x)
+	go x.run()
+	return x
+}
+
+// Export queues err for export and reports whether it was accepted. Errors
+// that are not exceptions are exported with their message only. Export
+// never blocks.
+func (x *Exporter) Export(err error) bool {
+	if ex.IsQuiet(err) {
+		return false
+	}
+	if err != nil && x.cfg.Profile != nil {
+		err = ex.Prune(err, *x.cfg.Profile)
+	}
+	if err == nil {
+		return false
+	}
+	select {
+	case <-x.stop:
+		x.dropped.Add(1)
+		return false
+	default:
+	}
+	if x.limiter != nil && !x.limiter.Allow() {
+		x.dropped.Add(1)
+		return false
+	}
+	select {
+	case x.queue <- newLogRecord(err, x.cfg.Now()):
+		return true
+	default:
+		x.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns the number of records rejected by the rate limit, a full
+// queue, or a shut-down exporter, plus records lost to failed exports.
+func (x *Exporter) Dropped() uint64 {
+	return x.dropped.Load()
+}
+
+// Flush sends every queued record and waits for the export to finish or
+// ctx to be done.
+func (x *Exporter) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case x.flushes <- reply:
+	case <-x.done:
+		return ErrShutdown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown flushes queued records and stops the background sender. Records
+// exported after Shutdown are dropped. It is safe to call more than once.
+func (x *Exporter) Shutdown(ctx context.Context) error {
+	err := x.Flush(ctx)
+	if errors.Is(err, ErrShutdown) {
+		err = nil
+	}
+	x.once.Do(func() {
+		close(x.stop)
+		x.unregister()
+	})
+	select {
+	case <-x.done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// run is the background sender loop.
+func (x *Exporter) run() {
+	defer close(x.done)
+	ticker := time.NewTicker(x.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]logRecord, 0, x.cfg.BatchSize)
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := x.send(context.Background(), batch)
+		if err != nil {
+			x.dropped.Add(uint64(len(batch)))
+		}
+		batch = batch[:0]
+		return err
+	}
+	drain := func() error {
+		var errs []error
+		for {
+			select {
+			case rec := <-x.queue:
+				batch = append(batch, rec)
+				if len(batch) >= x.cfg.BatchSize {
+					errs = append(errs, send())
+				}
+			default:
+				errs = append(errs, send())
+				return errors.Join(errs...)
+			}
+		}
+	}
+
+	for {
+		select {
+		case rec := <-x.queue:
+			batch = append(batch, rec)
+			if len(batch) >= x.cfg.BatchSize {
+				_ = send()
+			}
+		case <-ticker.C:
+			_ = send()
+		case reply := <-x.flushes:
+			reply <- drain()
+		case <-x.stop:
+			_ = drain()
+			return
+		}
+	}
+}
+
+// send posts one batch to the collector.
+func (x *Exporter) send(ctx context.Context, batch []logRecord) error {
+	body, err := json.Marshal(x.payload(batch))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range x.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := x.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("otlpx: collector responded " + resp.Status)
+	}
+	return nil
+}
+
+// payload wraps a batch in the OTLP ExportLogsServiceRequest envelope,
+// with one resource per tenant, in the order the tenants were first seen,
+// if PerTenant is set.
+func (x *Exporter) payload(batch []logRecord) exportRequest {
+	if !x.cfg.PerTenant {
+		return exportRequest{ResourceLogs: []resourceLogs{x.resourceLogs("", batch)}}
+	}
+	var tenants []string
+	byTenant := map[string][]logRecord{}
+	for _, rec := range batch {
+		if _, seen := byTenant[rec.tenant]; !seen {
+			tenants = append(tenants, rec.tenant)
+		}
+		byTenant[rec.tenant] = append(byTenant[rec.tenant], rec)
+	}
+	var req exportRequest
+	for _, tenant := range tenants {
+		req.ResourceLogs = append(req.ResourceLogs, x.resourceLogs(tenant, byTenant[tenant]))
+	}
+	return req
+}
+
+// resourceLogs wraps the records of one resource.
+func (x *Exporter) resourceLogs(tenant string, records []logRecord) resourceLogs {
+	var resource resource
+	if x.cfg.ServiceName != "" {
+		resource.Attributes = []keyValue{stringAttr("service.name", x.cfg.ServiceName)}
+	}
+	if tenant != "" {
+		resource.Attributes = append(resource.Attributes, stringAttr("tenant.id", tenant))
+	}
+	return resourceLogs{
+		Resource: resource,
+		ScopeLogs: []scopeLogs{{
+			Scope:      scope{Name: scopeName},
+			LogRecords: records,
+		}},
+	}
+}
+
+// newLogRecord converts err into an OTLP log record. Attribute names follow
+// the OpenTelemetry exception semantic conventions where one exists.
+func newLogRecord(err error, now time.Time) logRecord {
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+	rec := logRecord{
+		TimeUnixNano:         ts,
+		ObservedTimeUnixNano: ts,
+		Body:                 anyValue{StringValue: ptr(err.Error())},
+	}
+	rec.SeverityNumber, rec.SeverityText = severityOf(err)
+	rec.tenant, _ = ex.TenantOf(err)
+
+	e, ok := ex.AsErrorer(err)
+	if !ok {
+		rec.Attributes = []keyValue{stringAttr("exception.message", err.Error())}
+		return rec
+	}
+	rec.Attributes = []keyValue{
+		stringAttr("exception.type", e.Code().String()),
+		stringAttr("exception.message", err.Error()),
+		intAttr("ex.code", int64(e.Code())),
+		intAttr("ex.id", int64(e.ID())),
+	}
+	if cs, ok := ex.CodeStringOf(e); ok {
+		rec.Attributes = append(rec.Attributes, stringAttr("ex.code_string", cs))
+	}
+	if exc, isExc := e.(ex.Exception); isExc {
+		rec.Attributes = append(rec.Attributes, stringAttr("ex.fingerprint", exc.Fingerprint()))
+		if tc, hasTrace := exc.TraceContext(); hasTrace {
+			// Valid traceparents always hold the IDs at these offsets.
+			rec.TraceID, rec.SpanID = tc.TraceParent[3:35], tc.TraceParent[36:52]
+		}
+	}
+	if stack := e.StackTrace(); len(stack) > 0 {
+		rec.Attributes = append(rec.Attributes, stringAttr("exception.stacktrace", ex.FormatStack(stack)))
+	}
+	for _, f := range ex.FieldsAtLevel(e.Fields(), ex.LogLevel(e)) {
+		rec.Attributes = append(rec.Attributes, fieldAttr("ex.field."+f.Key, ex.ResolveValue(f.Value)))
+	}
+	return rec
+}
+
+// severityOf maps ex.AlertSeverity of err onto the OTLP scale. Errors
+// without a registered severity are reported as ERROR.
+func severityOf(err error) (int, string) {
+	switch ex.AlertSeverity(err) {
+	case ex.SeverityDebug:
+		return severityDebug, "DEBUG"
+	case ex.SeverityInfo:
+		return severityInfo, "INFO"
+	case ex.SeverityWarning:
+		return severityWarn, "WARN"
+	case ex.SeverityCritical:
+		return severityFatal, "FATAL"
+	default:
+		return severityError, "ERROR"
+	}
+}
+
+// OTLP/JSON wire types. Only the members this exporter populates are
+// declared. 64-bit integers are encoded as strings, as the OTLP JSON
+// mapping requires.
+type (
+	exportRequest struct {
+		ResourceLogs []resourceLogs `json:"resourceLogs"`
+	}
+	resourceLogs struct {
+		Resource  resource    `json:"resource"`
+		ScopeLogs []scopeLogs `json:"scopeLogs"`
+	}
+	resource struct {
+		Attributes []keyValue `json:"attributes,omitempty"`
+	}
+	scopeLogs struct {
+		Scope      scope       `json:"scope"`
+		LogRecords []logRecord `json:"logRecords"`
+	}
+	scope struct {
+		Name string `json:"name"`
+	}
+	logRecord struct {
+		TimeUnixNano         string     `json:"timeUnixNano"`
+		ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+		SeverityNumber       int        `json:"severityNumber"`
+		SeverityText         string     `json:"severityText"`
+		Body                 anyValue   `json:"body"`
+		TraceID              string     `json:"traceId,omitempty"`
+		SpanID               string     `json:"spanId,omitempty"`
+		Attributes           []keyValue `json:"attributes,omitempty"`
+		// tenant is the record's tenant, used by PerTenant.
+		tenant string
+	}
+	keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	anyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+	}
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func stringAttr(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: ptr(value)}}
+}
+
+func intAttr(key string, value int64) keyValue {
+	return keyValue{Key: key, Value: anyValue{IntValue: ptr(strconv.FormatInt(value, 10))}}
+}
+
+// fieldAttr maps a field value onto the closest OTLP value type.
+func fieldAttr(key string, value any) keyValue {
+	switch v := value.(type) {
+	case string:
+		return stringAttr(key, v)
+	case bool:
+		return keyValue{Key: key, Value: anyValue{BoolValue: ptr(v)}}
+	case int:
+		return intAttr(key, int64(v))
+	case int32:
+		return intAttr(key, int64(v))
+	case int64:
+		return intAttr(key, v)
+	case float64:
+		return keyValue{Key: key, Value: anyValue{DoubleValue: ptr(v)}}
+	case fmt.Stringer:
+		return stringAttr(key, v.String())
+	default:
+		return stringAttr(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,286 @@
+package otlpx_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/otlpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collector records the OTLP requests it receives.
+type collector struct {
+	mu       sync.Mutex
+	requests []map[string]any
+	headers  []http.Header
+	status   int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	c.mu.Lock()
+	c.requests = append(c.requests, body)
+	c.headers = append(c.headers, r.Header.Clone())
+	status := c.status
+	c.mu.Unlock()
+	if status != 0 {
+		w.WriteHeader(status)
+	}
+}
+
+// records returns every log record received, across all requests.
+func (c *collector) records() []map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []map[string]any
+	for _, req := range c.requests {
+		for _, rl := range req["resourceLogs"].([]any) {
+			for _, sl := range rl.(map[string]any)["scopeLogs"].([]any) {
+				for _, lr := range sl.(map[string]any)["logRecords"].([]any) {
+					out = append(out, lr.(map[string]any))
+				}
+			}
+		}
+	}
+	return out
+}
+
+// attrs flattens a record's attributes into key → value-object.
+func attrs(rec map[string]any) map[string]map[string]any {
+	out := map[string]map[string]any{}
+	for _, a := range rec["attributes"].([]any) {
+		kv := a.(map[string]any)
+		out[kv["key"].(string)] = kv["value"].(map[string]any)
+	}
+	return out
+}
+
+func TestExporter_ExportsBatch(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{
+		Endpoint:      srv.URL + "/v1/logs",
+		ServiceName:   "billing",
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		FlushInterval: time.Hour,
+	})
+
+	exc := ex.New(ex.ExTypePermissionDenied, 403, "Access denied").
+		WithField("user", "42").
+		WithStack().
+		WithTraceContext(ex.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+	require.True(t, exp.Export(exc))
+	require.True(t, exp.Export(errors.New("plain failure")))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	recs := c.records()
+	require.Len(t, recs, 2)
+	require.Len(t, c.requests, 1, "both records should travel in one batch")
+	assert.Equal(t, "Bearer token", c.headers[0].Get("Authorization"))
+
+	first := attrs(recs[0])
+	assert.Equal(t, "PermissionDenied", first["exception.type"]["stringValue"])
+	assert.Equal(t, "403", first["ex.id"]["intValue"])
+	assert.Equal(t, exc.Fingerprint(), first["ex.fingerprint"]["stringValue"])
+	assert.Equal(t, "42", first["ex.field.user"]["stringValue"])
+	assert.Contains(t, first["exception.stacktrace"]["stringValue"], "TestExporter_ExportsBatch")
+	// PermissionDenied is registered as a warning; plain errors default to ERROR.
+	assert.Equal(t, "WARN", recs[0]["severityText"])
+	assert.Equal(t, "ERROR", recs[1]["severityText"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", recs[0]["traceId"])
+	assert.Equal(t, "00f067aa0ba902b7", recs[0]["spanId"])
+	assert.NotContains(t, recs[1], "traceId")
+
+	second := attrs(recs[1])
+	assert.Equal(t, "plain failure", second["exception.message"]["stringValue"])
+	assert.NotContains(t, second, "ex.code")
+
+	res := c.requests[0]["resourceLogs"].([]any)[0].(map[string]any)["resource"].(map[string]any)
+	assert.Equal(t, "service.name", res["attributes"].([]any)[0].(map[string]any)["key"])
+}
+
+func TestExporter_Profile(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, Profile: &ex.ProfileClient, FlushInterval: time.Hour})
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Query on db-7 failed").
+		WithField("password", "hunter2").
+		WithStack()
+	require.True(t, exp.Export(exc))
+	assert.False(t, exp.Export(errors.New("plain failure")), "pruned away entirely")
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	recs := c.records()
+	require.Len(t, recs, 1)
+	got := attrs(recs[0])
+	assert.Equal(t, "ApplicationFailure", got["exception.message"]["stringValue"])
+	assert.NotContains(t, got, "exception.stacktrace")
+	assert.NotContains(t, got, "ex.field.password")
+}
+
+func TestExporter_SplitsBatches(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, BatchSize: 2, FlushInterval: time.Hour})
+	for range 5 {
+		exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "boom"))
+	}
+	require.NoError(t, exp.Flush(context.Background()))
+
+	assert.Len(t, c.records(), 5)
+	assert.Len(t, c.requests, 3)
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
+
+func TestExporter_RateLimit(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	exp := otlpx.NewExporter(otlpx.Config{
+		Endpoint:      srv.URL,
+		RateLimit:     1,
+		Burst:         2,
+		FlushInterval: time.Hour,
+		Now:           func() time.Time { return now },
+	})
+	defer func() { _ = exp.Shutdown(context.Background()) }()
+
+	assert.True(t, exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "1")))
+	assert.True(t, exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "2")))
+	assert.False(t, exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "3")))
+	assert.Equal(t, uint64(1), exp.Dropped())
+}
+
+func TestExporter_FailedExportCountsAsDropped(t *testing.T) {
+	c := &collector{status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, FlushInterval: time.Hour})
+	exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "boom"))
+	err := exp.Flush(context.Background())
+	assert.ErrorContains(t, err, "503")
+	assert.Equal(t, uint64(1), exp.Dropped())
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
+
+func TestExporter_AfterShutdown(t *testing.T) {
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: "http://127.0.0.1:0", FlushInterval: time.Hour})
+	require.NoError(t, exp.Shutdown(context.Background()))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	assert.False(t, exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "late")))
+	assert.ErrorIs(t, exp.Flush(context.Background()), otlpx.ErrShutdown)
+	assert.False(t, exp.Export(nil))
+}
+
+func TestExporter_FlushedByExShutdown(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, FlushInterval: time.Hour})
+	require.True(t, exp.Export(errors.New("pending at shutdown")))
+	require.NoError(t, ex.Shutdown(context.Background()))
+
+	require.Len(t, c.records(), 1)
+	assert.False(t, exp.Export(errors.New("late")))
+}
+
+func TestExporter_SkipsQuiet(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, FlushInterval: time.Hour})
+	defer func() { _ = exp.Shutdown(context.Background()) }()
+
+	assert.False(t, exp.Export(ex.QuietNew(ex.ExTypeUnavailable, 5034, "Probe failed")))
+	assert.True(t, exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "boom")))
+	require.NoError(t, exp.Flush(context.Background()))
+	assert.Len(t, c.records(), 1)
+	assert.Zero(t, exp.Dropped())
+}
+
+func TestExporter_ExpectedAtInfo(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, FlushInterval: time.Hour})
+	require.True(t, exp.Export(ex.New(ex.ExTypeApplicationFailure, 500, "Version conflict").WithExpected(true)))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	recs := c.records()
+	require.Len(t, recs, 1)
+	assert.Equal(t, "INFO", recs[0]["severityText"])
+	assert.EqualValues(t, 9, recs[0]["severityNumber"])
+}
+
+func TestExporter_PerTenant(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp := otlpx.NewExporter(otlpx.Config{Endpoint: srv.URL, ServiceName: "billing", PerTenant: true, FlushInterval: time.Hour})
+	require.True(t, exp.Export(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("acme")))
+	require.True(t, exp.Export(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("globex")))
+	require.True(t, exp.Export(ex.New(ex.ExTypeNotFound, 4042, "No such invoice").WithTenant("acme")))
+	require.True(t, exp.Export(errors.New("plain failure")))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.requests, 1)
+	resourceLogs, ok := c.requests[0]["resourceLogs"].([]any)
+	require.True(t, ok)
+	require.Len(t, resourceLogs, 3)
+
+	var tenants []string
+	var counts []int
+	for _, rl := range resourceLogs {
+		rlm, isMap := rl.(map[string]any)
+		require.True(t, isMap)
+		tenant := ""
+		resource, hasResource := rlm["resource"].(map[string]any)
+		require.True(t, hasResource)
+		resourceAttrs, hasAttrs := resource["attributes"].([]any)
+		require.True(t, hasAttrs)
+		for _, a := range resourceAttrs {
+			kv, isKV := a.(map[string]any)
+			require.True(t, isKV)
+			if kv["key"] == "tenant.id" {
+				value, isValue := kv["value"].(map[string]any)
+				require.True(t, isValue)
+				tenant, _ = value["stringValue"].(string)
+			}
+		}
+		tenants = append(tenants, tenant)
+		scopeLogs, hasScope := rlm["scopeLogs"].([]any)
+		require.True(t, hasScope)
+		sl, isSL := scopeLogs[0].(map[string]any)
+		require.True(t, isSL)
+		records, hasRecords := sl["logRecords"].([]any)
+		require.True(t, hasRecords)
+		counts = append(counts, len(records))
+	}
+	assert.Equal(t, []string{"acme", "globex", ""}, tenants)
+	assert.Equal(t, []int{2, 1, 1}, counts)
+}
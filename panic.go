@@ -0,0 +1,89 @@
+package ex
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// PanicID is the ID of exceptions created by FromPanic. It matches the
+// HTTP status of ExTypeApplicationFailure.
+const PanicID = 500
+
+// FromPanic converts a value recovered from a panic into an
+// ExTypeApplicationFailure exception carrying the panicking goroutine's
+// stack. Call it from the deferred function that recovered:
+//
+//	defer func() {
+//	    if v := recover(); v != nil {
+//	        err = ex.FromPanic(v)
+//	    }
+//	}()
+//
+// If the panic value is an error it becomes the inner error, so
+// errors.Is and errors.As see through to it. Hooks run as for New.
+func FromPanic(v any) Exception {
+	e := Exception{
+		code:     ExTypeApplicationFailure,
+		id:       PanicID,
+		message:  "panic: " + fmt.Sprint(v),
+		stack:    panicStack(),
+		panicked: &panicValue{v: v},
+	}
+	if err, ok := v.(error); ok {
+		e.message = "panic"
+		e.innerError = err
+	}
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// panicStack captures the stack of the goroutine that is panicking,
+// starting at the frame that called panic. The runtime frames between
+// gopanic and user code, such as runtime.panicmem and runtime.sigpanic
+// for a nil dereference or runtime.goPanicIndex for an index out of
+// range, are skipped. When called outside a panic it returns the stack of
+// FromPanic's caller.
+func panicStack() []uintptr {
+	if stacksDisabled() {
+		return nil
+	}
+	pcs := callers(4)
+	for i, pc := range pcs {
+		if strings.HasPrefix(funcName(pc), "runtime.gopanic") {
+			rest := pcs[i+1:]
+			for len(rest) > 0 && strings.HasPrefix(funcName(rest[0]), "runtime.") {
+				rest = rest[1:]
+			}
+			return rest
+		}
+	}
+	return pcs
+}
+
+// funcName returns the name of the function holding the return address
+// pc, or "" if it is unknown.
+func funcName(pc uintptr) string {
+	if fn := runtime.FuncForPC(pc - 1); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// panicValue boxes a recovered value so that a nil box can mean "not a
+// panic" even for panic(nil)-style values.
+type panicValue struct {
+	v any
+}
+
+// PanicValue returns the original value passed to panic, of whatever
+// type, for exceptions built by FromPanic, so handlers can tell a
+// runtime.Error from a custom sentinel panic. It reports false for all
+// other exceptions. The value is not part of the JSON encoding.
+func (e Exception) PanicValue() (any, bool) {
+	if e.panicked == nil {
+		return nil, false
+	}
+	return e.panicked.v, true
+}
@@ -0,0 +1,125 @@
+package ex_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panicky() {
+	panic("something broke")
+}
+
+func recoverFrom(f func()) (exc ex.Exception) {
+	defer func() {
+		if v := recover(); v != nil {
+			exc = ex.FromPanic(v)
+		}
+	}()
+	f()
+	return exc
+}
+
+func TestFromPanic(t *testing.T) {
+	exc := recoverFrom(panicky)
+
+	assert.Equal(t, ex.ExTypeApplicationFailure, exc.Code())
+	assert.Equal(t, ex.PanicID, exc.ID())
+	assert.Equal(t, "panic: something broke", exc.Error())
+
+	frames := exc.StackTrace()
+	require.NotEmpty(t, frames)
+	assert.True(t, strings.HasSuffix(frames[0].Function, "ex_test.panicky"),
+		"stack should start at the panicking function, got %s", frames[0].Function)
+}
+
+func TestFromPanic_RuntimeError(t *testing.T) {
+	for name, f := range map[string]func(){
+		"nil dereference": nilDereference,
+		"index":           indexOutOfRange,
+		"division":        divideByZero,
+	} {
+		t.Run(name, func(t *testing.T) {
+			exc := recoverFrom(f)
+			v, ok := exc.PanicValue()
+			require.True(t, ok)
+			assert.Implements(t, (*runtime.Error)(nil), v)
+
+			frames := exc.StackTrace()
+			require.NotEmpty(t, frames)
+			assert.False(t, strings.HasPrefix(frames[0].Function, "runtime."),
+				"runtime frames should be skipped, got %s", frames[0].Function)
+			assert.Contains(t, frames[0].Function, "ex_test.")
+			assert.Equal(t, frames[:2], recoverFrom(f).StackTrace()[:2], "cached frames match")
+		})
+	}
+}
+
+//go:noinline
+func nilDereference() {
+	var p *int
+	_ = *p
+}
+
+//go:noinline
+func indexOutOfRange() {
+	s := []int{1}
+	i := 1
+	_ = s[i]
+}
+
+//go:noinline
+func divideByZero() {
+	a, b := 1, 0
+	_ = a / b
+}
+
+func TestFromPanic_ErrorValue(t *testing.T) {
+	cause := errors.New("nil map write")
+	exc := recoverFrom(func() { panic(cause) })
+
+	assert.Equal(t, "panic: nil map write", exc.Error())
+	assert.True(t, errors.Is(exc, cause))
+}
+
+func TestFromPanic_RunsHooks(t *testing.T) {
+	var seen []ex.Exception
+	remove := ex.AddHook(func(e ex.Exception) { seen = append(seen, e) })
+	defer remove()
+
+	recoverFrom(panicky)
+	require.Len(t, seen, 1)
+	assert.True(t, seen[0].HasStack())
+}
+
+func TestFromPanic_OutsidePanic(t *testing.T) {
+	exc := ex.FromPanic(42)
+	assert.Equal(t, "panic: 42", exc.Error())
+	assert.True(t, exc.HasStack())
+}
+
+type sentinelPanic struct{ reason string }
+
+func TestPanicValue(t *testing.T) {
+	custom := recoverFrom(func() { panic(sentinelPanic{reason: "invariant"}) })
+	v, ok := custom.PanicValue()
+	require.True(t, ok)
+	assert.Equal(t, sentinelPanic{reason: "invariant"}, v)
+
+	runtimeErr := recoverFrom(func() {
+		var m map[string]int
+		m["boom"]++
+	})
+	v, ok = runtimeErr.PanicValue()
+	require.True(t, ok)
+	_, isRuntime := v.(runtime.Error)
+	assert.True(t, isRuntime)
+
+	_, ok = ex.New(ex.ExTypeApplicationFailure, 500, "not a panic").PanicValue()
+	assert.False(t, ok)
+}
@@ -0,0 +1,84 @@
+package ex
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// PersistedException stores an exception in a database column, for
+// services that keep last-error state alongside their records. It
+// implements driver.Valuer and sql.Scanner, storing the JSON encoding as
+// text, so it fits TEXT, JSON, and JSONB columns:
+//
+//	_, err := db.ExecContext(ctx, `UPDATE jobs SET last_error = $1 WHERE id = $2`, ex.Persist(runErr), id)
+//
+//	var last ex.PersistedException
+//	err := db.QueryRowContext(ctx, `SELECT last_error FROM jobs WHERE id = $1`, id).Scan(&last)
+//	if last.Valid { ... last.Exception ... }
+//
+// As with sql.NullString, Valid is false for SQL NULL. Exceptions read
+// back are decoded like any other (see UnmarshalJSON) and report
+// Remote() == true.
+type PersistedException struct {
+	Exception Exception
+	Valid     bool
+}
+
+// Persist prepares err for storage: a nil err becomes NULL, and errors
+// other than Exceptions are promoted to one (see Promote).
+func Persist(err error) PersistedException {
+	if err == nil {
+		return PersistedException{}
+	}
+	e, ok := As(err)
+	if !ok {
+		e = Promote(err, nil)
+	}
+	return PersistedException{Exception: e, Valid: true}
+}
+
+// Err returns the stored exception, or nil for NULL.
+func (p PersistedException) Err() error {
+	if !p.Valid {
+		return nil
+	}
+	return p.Exception
+}
+
+// Value implements driver.Valuer. The exception is redacted (see
+// Exception.Redacted) before it is encoded, so sensitive field values
+// never reach the database.
+func (p PersistedException) Value() (driver.Value, error) {
+	if !p.Valid {
+		return nil, nil
+	}
+	data, err := json.Marshal(p.Exception.Redacted())
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner for text and byte columns holding the JSON
+// encoding, and for NULL.
+func (p *PersistedException) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*p = PersistedException{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("ex: cannot scan %T into PersistedException", src)
+	}
+	var e Exception
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	*p = PersistedException{Exception: e, Valid: true}
+	return nil
+}
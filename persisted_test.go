@@ -0,0 +1,69 @@
+package ex_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time checks that PersistedException plugs into database/sql.
+var (
+	_ driver.Valuer = ex.PersistedException{}
+	_ sql.Scanner   = (*ex.PersistedException)(nil)
+)
+
+func TestPersistedException_RoundTrip(t *testing.T) {
+	stored := ex.New(ex.ExTypeUnavailable, 5031, "Payment provider down").
+		WithField("job", 42).
+		WithField("token", "s3cr3t").
+		WithInnerError(errors.New("dial tcp: connection refused"))
+
+	v, err := ex.Persist(stored).Value()
+	require.NoError(t, err)
+	text, ok := v.(string)
+	require.True(t, ok)
+	assert.NotContains(t, text, "s3cr3t")
+
+	for _, src := range []any{text, []byte(text)} {
+		var p ex.PersistedException
+		require.NoError(t, p.Scan(src))
+		require.True(t, p.Valid)
+		assert.True(t, errors.Is(p.Err(), stored))
+		assert.Equal(t, stored.Error(), p.Exception.Error())
+		assert.True(t, p.Exception.Remote())
+		job, _ := p.Exception.FieldValue("job")
+		assert.Equal(t, 42.0, job)
+	}
+}
+
+func TestPersistedException_Null(t *testing.T) {
+	p := ex.Persist(nil)
+	assert.False(t, p.Valid)
+	v, err := p.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	assert.NoError(t, p.Err())
+
+	p = ex.Persist(errors.New("plain"))
+	require.NoError(t, p.Scan(nil))
+	assert.False(t, p.Valid)
+	assert.NoError(t, p.Err())
+}
+
+func TestPersistedException_ScanErrors(t *testing.T) {
+	var p ex.PersistedException
+	assert.Error(t, p.Scan(42))
+	assert.Error(t, p.Scan("not json"))
+	assert.False(t, p.Valid)
+}
+
+func TestPersist_PromotesPlainErrors(t *testing.T) {
+	p := ex.Persist(errors.New("EOF"))
+	require.True(t, p.Valid)
+	assert.Equal(t, "EOF", p.Exception.Error())
+}
@@ -0,0 +1,296 @@
+// Package policy loads error-handling policies from a JSON file so
+// operators can tune how errors are treated without redeploying.
+//
+// A policy is an ordered list of rules. Each rule matches exceptions by
+// code, ID, minimum severity, and an ex.MatchSpec in its string form, and
+// says what to do with them: log at a different level, answer with a
+// different HTTP status, or suppress them entirely. The first matching
+// rule wins.
+//
+//	{
+//	  "rules": [
+//	    {"match": {"ids": [4041]}, "suppress": true},
+//	    {"match": {"codes": [4], "ids": [5031]}, "level": "warn", "status": 503},
+//	    {"match": {"spec": "code=NotFound message=\"no such user\""}, "level": "debug"},
+//	    {"match": {"minSeverity": "error"}, "level": "error"}
+//	  ]
+//	}
+//
+// An Engine holds the active policy. Load and LoadFile validate a new
+// policy in full before swapping it in, so a bad edit never replaces a
+// good policy, and Watch reloads the file whenever it changes:
+//
+//	engine := policy.New()
+//	if err := engine.LoadFile("errors.json"); err != nil { ... }
+//	go engine.Watch(ctx, "errors.json", 10*time.Second, func(err error) {
+//	    logger.Error("error policy reload failed", "err", err)
+//	})
+//
+//	router := ex.NewRouter().Fallback(engine.Action(logger))
+//
+// Handlers answer with the status the policy decides through WriteError:
+//
+//	engine.WriteError(w, err)
+//
+// Only JSON is supported, keeping the module free of third-party
+// dependencies; YAML users can convert their file at deploy time.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+)
+
+// File is the JSON document a policy is loaded from.
+type File struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is one entry of a policy. Every populated member of Match must
+// match for the rule to apply; a rule with an empty Match applies to every
+// exception.
+type Rule struct {
+	Match Match `json:"match"`
+
+	// Level overrides the log level: "debug", "info", "warn", or "error".
+	Level string `json:"level,omitempty"`
+
+	// Status overrides the HTTP status code.
+	Status int `json:"status,omitempty"`
+
+	// Suppress drops matching errors altogether.
+	Suppress bool `json:"suppress,omitempty"`
+}
+
+// Match selects the exceptions a Rule applies to. Spec is an ex.MatchSpec
+// in the form ex.ParseMatchSpec accepts, such as
+// "code=PermissionDenied id=403", so the same specs serve in policies and
+// in code.
+type Match struct {
+	Codes       []ex.ExType `json:"codes,omitempty"`
+	IDs         []int       `json:"ids,omitempty"`
+	MinSeverity string      `json:"minSeverity,omitempty"`
+	Spec        string      `json:"spec,omitempty"`
+}
+
+// Decision is the outcome of evaluating a policy against an error.
+type Decision struct {
+	// Matched reports whether any rule applied. When it is false the other
+	// members hold their defaults.
+	Matched bool
+
+	// Level is the log level to use. Without an override it is
+	// ex.LogLevel(err).
+	Level slog.Level
+
+	// Status is the overriding HTTP status code, or zero. Engine.WriteError
+	// answers with it.
+	Status int
+
+	// Suppress reports whether the error should be dropped.
+	Suppress bool
+}
+
+// compiled is a validated, ready-to-evaluate policy.
+type compiled struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	match    ex.Predicate
+	level    *slog.Level
+	status   int
+	suppress bool
+}
+
+// Engine evaluates errors against the active policy. The zero value is
+// not usable; create one with New. An Engine is safe for concurrent use,
+// including concurrent reloads.
+type Engine struct {
+	active atomic.Pointer[compiled]
+
+	// loaded records the file last read by LoadFile, so Watch can pick
+	// up changes made between LoadFile and the start of watching.
+	loaded atomic.Pointer[fileStamp]
+}
+
+// fileStamp identifies a version of a policy file.
+type fileStamp struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+func stampOf(path string, info os.FileInfo) *fileStamp {
+	return &fileStamp{path: path, modTime: info.ModTime(), size: info.Size()}
+}
+
+func (s *fileStamp) same(other *fileStamp) bool {
+	return s != nil && other != nil && s.path == other.path &&
+		s.modTime.Equal(other.modTime) && s.size == other.size
+}
+
+// New returns an Engine with an empty policy, under which no rule matches.
+func New() *Engine {
+	e := &Engine{}
+	e.active.Store(&compiled{})
+	return e
+}
+
+// Load parses and validates a policy from r and, if it is valid, makes it
+// the active policy. On error the active policy is left unchanged.
+func (e *Engine) Load(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var f File
+	if err := dec.Decode(&f); err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	c, err := compile(f)
+	if err != nil {
+		return err
+	}
+	e.active.Store(c)
+	return nil
+}
+
+// LoadFile loads the policy in the file at path; see Load.
+func (e *Engine) LoadFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is caller-supplied by design
+	if err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	// Record the version even when it fails to load, so Watch reports a
+	// bad file once rather than on every poll.
+	e.loaded.Store(stampOf(path, info))
+	return e.Load(bytes.NewReader(data))
+}
+
+// Decide evaluates err against the active policy.
+func (e *Engine) Decide(err error) Decision {
+	d := Decision{Level: ex.LogLevel(err)}
+	for _, r := range e.active.Load().rules {
+		if !r.match(err) {
+			continue
+		}
+		d.Matched = true
+		if r.level != nil {
+			d.Level = *r.level
+		}
+		d.Status = r.status
+		d.Suppress = r.suppress
+		break
+	}
+	return d
+}
+
+// Action returns an ex.Action that applies the policy: suppressed errors
+// end the route, and everything else is logged to logger at the decided
+// level.
+func (e *Engine) Action(logger *slog.Logger) ex.Action {
+	return func(err error) error {
+		d := e.Decide(err)
+		if d.Suppress {
+			return nil
+		}
+		return ex.Log(logger, d.Level)(err)
+	}
+}
+
+// WriteError writes err as an httpx Envelope with the status the policy
+// decides, or the status given by httpx.StatusCode if no matching rule
+// overrides it (see httpx.WriteErrorStatus). Suppressed errors are
+// written too, since the request still needs an answer.
+func (e *Engine) WriteError(w http.ResponseWriter, err error) {
+	httpx.WriteErrorStatus(w, err, e.Decide(err).Status)
+}
+
+// compile validates f, reporting every problem found rather than just the
+// first.
+func compile(f File) (*compiled, error) {
+	c := &compiled{rules: make([]compiledRule, 0, len(f.Rules))}
+	var errs []error
+	for i, r := range f.Rules {
+		cr, err := compileRule(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("policy: rule %d: %w", i, err))
+			continue
+		}
+		c.rules = append(c.rules, cr)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return c, nil
+}
+
+func compileRule(r Rule) (compiledRule, error) {
+	var preds []ex.Predicate
+	for _, code := range r.Match.Codes {
+		if code <= 0 {
+			return compiledRule{}, fmt.Errorf("invalid code %d", int(code))
+		}
+	}
+	if len(r.Match.Codes) > 0 {
+		preds = append(preds, ex.CodeIs(r.Match.Codes...))
+	}
+	if len(r.Match.IDs) > 0 {
+		preds = append(preds, ex.IDIn(r.Match.IDs...))
+	}
+	if r.Match.MinSeverity != "" {
+		s, ok := parseSeverity(r.Match.MinSeverity)
+		if !ok {
+			return compiledRule{}, fmt.Errorf("unknown severity %q", r.Match.MinSeverity)
+		}
+		preds = append(preds, ex.SeverityAtLeast(s))
+	}
+	if r.Match.Spec != "" {
+		spec, err := ex.ParseMatchSpec(r.Match.Spec)
+		if err != nil {
+			return compiledRule{}, err
+		}
+		preds = append(preds, spec.Predicate())
+	}
+
+	cr := compiledRule{match: ex.And(preds...), status: r.Status, suppress: r.Suppress}
+	if r.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(r.Level)); err != nil {
+			return compiledRule{}, fmt.Errorf("unknown level %q", r.Level)
+		}
+		cr.level = &level
+	}
+	if r.Status != 0 && http.StatusText(r.Status) == "" {
+		return compiledRule{}, fmt.Errorf("invalid status %d", r.Status)
+	}
+	if r.Suppress && (r.Level != "" || r.Status != 0) {
+		return compiledRule{}, errors.New("suppress cannot be combined with level or status")
+	}
+	return cr, nil
+}
+
+// parseSeverity parses a severity name such as "warning", case-insensitively.
+func parseSeverity(name string) (ex.Severity, bool) {
+	for s := ex.SeverityDebug; s <= ex.SeverityCritical; s++ {
+		if strings.EqualFold(s.String(), name) {
+			return s, true
+		}
+	}
+	return 0, false
+}
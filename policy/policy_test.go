@@ -0,0 +1,157 @@
+package policy_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const basePolicy = `{
+  "rules": [
+    {"match": {"ids": [4041]}, "suppress": true},
+    {"match": {"codes": [4], "ids": [5031]}, "level": "warn", "status": 503},
+    {"match": {"minSeverity": "error"}, "level": "error"}
+  ]
+}`
+
+func TestEngine_Decide(t *testing.T) {
+	engine := policy.New()
+	require.NoError(t, engine.Load(strings.NewReader(basePolicy)))
+
+	d := engine.Decide(ex.New(ex.ExTypeIncorrectData, 4041, "noisy"))
+	assert.Equal(t, policy.Decision{Matched: true, Level: slog.LevelWarn, Suppress: true}, d)
+
+	d = engine.Decide(ex.New(ex.ExTypeApplicationFailure, 5031, "upstream flapping"))
+	assert.Equal(t, policy.Decision{Matched: true, Level: slog.LevelWarn, Status: 503}, d)
+
+	d = engine.Decide(ex.New(ex.ExTypeApplicationFailure, 500, "Database down"))
+	assert.Equal(t, policy.Decision{Matched: true, Level: slog.LevelError}, d)
+
+	// Unmatched errors get a level derived from their severity.
+	d = engine.Decide(ex.New(ex.ExTypeIncorrectData, 400, "bad input"))
+	assert.Equal(t, policy.Decision{Level: slog.LevelWarn}, d)
+	assert.Equal(t, slog.LevelError, engine.Decide(errors.New("plain")).Level)
+}
+
+func TestEngine_Spec(t *testing.T) {
+	engine := policy.New()
+	require.NoError(t, engine.Load(strings.NewReader(`{"rules": [
+		{"match": {"spec": "code=NotFound id=404,4041 message=\"no such user\""}, "level": "debug"}
+	]}`)))
+
+	d := engine.Decide(ex.New(ex.ExTypeNotFound, 4041, "no such user"))
+	assert.Equal(t, policy.Decision{Matched: true, Level: slog.LevelDebug}, d)
+	assert.False(t, engine.Decide(ex.New(ex.ExTypeNotFound, 4041, "no such order")).Matched)
+	assert.False(t, engine.Decide(ex.New(ex.ExTypeNotFound, 4042, "no such user")).Matched)
+}
+
+func TestEngine_LoadValidation(t *testing.T) {
+	engine := policy.New()
+	require.NoError(t, engine.Load(strings.NewReader(basePolicy)))
+
+	err := engine.Load(strings.NewReader(`{"rules": [
+		{"match": {"minSeverity": "loud"}},
+		{"match": {"codes": [0]}},
+		{"level": "shout"},
+		{"status": 999},
+		{"suppress": true, "status": 500},
+		{"match": {"spec": "code=Nope"}}
+	]}`))
+	require.Error(t, err)
+	for _, want := range []string{
+		"rule 0: unknown severity", "rule 1: invalid code 0", "rule 2: unknown level",
+		"rule 3: invalid status 999", "rule 4: suppress cannot be combined",
+		"rule 5: ex: invalid match spec: unknown code",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	assert.Error(t, engine.Load(strings.NewReader(`{"rulez": []}`)))
+	assert.Error(t, engine.LoadFile(filepath.Join(t.TempDir(), "missing.json")))
+
+	// The previous policy stays active after failed loads.
+	assert.True(t, engine.Decide(ex.New(ex.ExTypeIncorrectData, 4041, "noisy")).Suppress)
+}
+
+func TestEngine_Action(t *testing.T) {
+	engine := policy.New()
+	require.NoError(t, engine.Load(strings.NewReader(basePolicy)))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	router := ex.NewRouter().Fallback(engine.Action(logger))
+
+	assert.NoError(t, router.Handle(ex.New(ex.ExTypeIncorrectData, 4041, "noisy")))
+	assert.Empty(t, buf.String())
+
+	upstream := ex.New(ex.ExTypeApplicationFailure, 5031, "upstream flapping")
+	assert.Equal(t, upstream.Error(), router.Handle(upstream).Error())
+	assert.Contains(t, buf.String(), "level=WARN")
+}
+
+func TestEngine_WriteError(t *testing.T) {
+	engine := policy.New()
+	require.NoError(t, engine.Load(strings.NewReader(basePolicy)))
+
+	rec := httptest.NewRecorder()
+	engine.WriteError(rec, ex.New(ex.ExTypeApplicationFailure, 5031, "upstream flapping"))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"error":{"code":"ApplicationFailure","id":5031,"message":"Service Unavailable"}}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	engine.WriteError(rec, ex.New(ex.ExTypeIncorrectData, 4041, "noisy"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "suppressed errors keep their status")
+}
+
+func TestEngine_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules": []}`), 0o600))
+
+	engine := policy.New()
+	require.NoError(t, engine.LoadFile(path))
+	noisy := ex.New(ex.ExTypeIncorrectData, 4041, "noisy")
+	assert.False(t, engine.Decide(noisy).Suppress)
+
+	var (
+		mu       sync.Mutex
+		failures []error
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- engine.Watch(ctx, path, 5*time.Millisecond, func(err error) {
+			mu.Lock()
+			failures = append(failures, err)
+			mu.Unlock()
+		})
+	}()
+
+	require.NoError(t, os.WriteFile(path, []byte(basePolicy), 0o600))
+	assert.Eventually(t, func() bool { return engine.Decide(noisy).Suppress }, 2*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules": [{"level": "shout"}]}`), 0o600))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(failures) > 0
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.True(t, engine.Decide(noisy).Suppress)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Error(t, engine.Watch(context.Background(), path, 0, nil))
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Watch polls the file at path every interval and reloads the policy
+// whenever its modification time or size differs from the version last
+// read by LoadFile, including changes made before Watch was called.
+// Reload failures, including a missing file, are passed to onError, if
+// non-nil, and leave the previous policy active.
+//
+// Watch blocks until ctx is done and then returns ctx.Err().
+func (e *Engine) Watch(ctx context.Context, path string, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("policy: non-positive watch interval %v", interval)
+	}
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			report(fmt.Errorf("policy: %w", err))
+			continue
+		}
+		if stampOf(path, info).same(e.loaded.Load()) {
+			continue
+		}
+		if loadErr := e.LoadFile(path); loadErr != nil {
+			report(loadErr)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package ex
+
+import (
+	"context"
+	"sync"
+)
+
+// poolChunk is the number of elements in each chunk of a Pool.
+const poolChunk = 256
+
+// Pool is a request-scoped allocator for the storage exceptions attach to
+// themselves: field slices and captured stacks. Exceptions built through a
+// Pool share its memory, and Release hands that memory back all at once
+// for the next request, so a gateway that attaches fields or stacks to
+// many errors per request stops allocating for them once warmed up.
+//
+// Pooling is unsafe by design and strictly opt-in: after Release, every
+// exception built through the pool may see its fields and stack
+// overwritten. Only use it when no exception outlives the request, which
+// rules out storing them, sending them to a background goroutine, or
+// handing them to a hook that does either. Encode an exception, for
+// example with MarshalJSON, to keep a copy that is independent of the
+// pool.
+//
+// A nil *Pool is valid and allocates normally, so code can use the pool
+// from the context (see PoolFrom) without checking for one. A Pool is
+// safe for concurrent use.
+type Pool struct {
+	store chunkStore
+}
+
+// pools recycles released Pools; see AcquirePool.
+var pools = sync.Pool{New: func() any { return newPool() }}
+
+// poolKey is the context key under which a Pool is stored.
+type poolKey struct{}
+
+// AcquirePool returns an empty Pool, reusing a released one if possible.
+// Return it with Release.
+func AcquirePool() *Pool {
+	p, _ := pools.Get().(*Pool)
+	if p == nil {
+		p = newPool()
+	}
+	return p
+}
+
+// newPool returns an empty Pool.
+func newPool() *Pool {
+	return &Pool{store: chunkStore{size: poolChunk}}
+}
+
+// Release invalidates every exception built through p and returns p for
+// reuse by AcquirePool. p must not be used afterwards. Releasing a nil
+// Pool does nothing.
+func (p *Pool) Release() {
+	if p == nil {
+		return
+	}
+	p.store.reset()
+	pools.Put(p)
+}
+
+// WithFields is e.WithFields with the merged field slice taken from p.
+func (p *Pool) WithFields(e Exception, fields ...Field) Exception {
+	if p == nil {
+		return e.WithFields(fields...)
+	}
+	return p.store.withFields(e, fields)
+}
+
+// WithStack is e.WithStack with the program counters stored in p.
+func (p *Pool) WithStack(e Exception) Exception {
+	if p == nil || stacksDisabled() {
+		return e.WithStack()
+	}
+	return p.store.withStack(e)
+}
+
+// ContextWithPool returns a copy of ctx carrying p.
+func ContextWithPool(ctx context.Context, p *Pool) context.Context {
+	return context.WithValue(ctx, poolKey{}, p)
+}
+
+// PoolFrom returns the Pool carried by ctx, or nil, which allocates
+// normally.
+func PoolFrom(ctx context.Context) *Pool {
+	p, _ := ctx.Value(poolKey{}).(*Pool)
+	return p
+}
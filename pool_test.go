@@ -0,0 +1,64 @@
+package ex_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_WithFields(t *testing.T) {
+	p := ex.AcquirePool()
+	defer p.Release()
+
+	base := ex.New(ex.ExTypeIncorrectData, 400, "Bad input").WithField("a", 1)
+	e := p.WithFields(base, ex.F("a", 2), ex.F("b", 3))
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 2}, {Key: "b", Value: 3}}, e.Fields())
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 1}}, base.Fields())
+
+	// Exceptions carved from the same slab do not clobber each other.
+	other := p.WithFields(base, ex.F("c", 4))
+	again := p.WithFields(e, ex.F("d", 5))
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 1}, {Key: "c", Value: 4}}, other.Fields())
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 2}, {Key: "b", Value: 3}, {Key: "d", Value: 5}}, again.Fields())
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 2}, {Key: "b", Value: 3}}, e.Fields())
+}
+
+func TestPool_GrowsPastOneChunk(t *testing.T) {
+	p := ex.AcquirePool()
+	defer p.Release()
+
+	var all []ex.Exception
+	for i := range 300 {
+		all = append(all, p.WithFields(ex.New(ex.ExTypeIncorrectData, i, "x"), ex.F("i", i)))
+	}
+	for i, e := range all {
+		v, ok := e.FieldValue("i")
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestPool_WithStack(t *testing.T) {
+	p := ex.AcquirePool()
+	defer p.Release()
+
+	e := p.WithStack(ex.New(ex.ExTypeApplicationFailure, 500, "Failed"))
+	require.True(t, e.HasStack())
+	assert.Contains(t, e.StackTrace()[0].Function, "TestPool_WithStack")
+}
+
+func TestPool_Nil(t *testing.T) {
+	var p *ex.Pool
+	e := p.WithFields(ex.New(ex.ExTypeIncorrectData, 400, "x"), ex.F("a", 1))
+	assert.Equal(t, []ex.Field{{Key: "a", Value: 1}}, e.Fields())
+	assert.True(t, p.WithStack(e).HasStack())
+	p.Release()
+
+	assert.Nil(t, ex.PoolFrom(context.Background()))
+	pool := ex.AcquirePool()
+	defer pool.Release()
+	assert.Same(t, pool, ex.PoolFrom(ex.ContextWithPool(context.Background(), pool)))
+}
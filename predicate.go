@@ -0,0 +1,104 @@
+package ex
+
+// Predicate reports whether an error matches some condition. Predicates
+// are composed with And, Or, and Not and used by hooks, samplers, and
+// middleware to decide declaratively how an error is routed.
+//
+// Unless stated otherwise, predicates inspect the outermost Exception (or
+// other Errorer) in the chain, as found by errors.As; errors without one
+// never match.
+type Predicate func(error) bool
+
+// outermost returns the first Errorer in err's chain.
+func outermost(err error) (Errorer, bool) {
+	return AsErrorer(err)
+}
+
+// CodeIs matches errors whose code is one of codes.
+func CodeIs(codes ...ExType) Predicate {
+	return func(err error) bool {
+		e, ok := outermost(err)
+		if !ok {
+			return false
+		}
+		for _, c := range codes {
+			if e.Code() == c {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IDIn matches errors whose ID is one of ids.
+func IDIn(ids ...int) Predicate {
+	return func(err error) bool {
+		e, ok := outermost(err)
+		if !ok {
+			return false
+		}
+		for _, id := range ids {
+			if e.ID() == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SeverityAtLeast matches errors whose registered severity is floor or
+// higher. Errors with no registered severity never match, and expected
+// errors (see WithExpected) count as SeverityInfo at most.
+func SeverityAtLeast(floor Severity) Predicate {
+	return func(err error) bool {
+		s := AlertSeverity(err)
+		return s != 0 && s >= floor
+	}
+}
+
+// SeverityOf returns the registered severity of the outermost Exception in
+// err's chain, or zero when there is none.
+func SeverityOf(err error) Severity {
+	info, _ := TypeInfoOf(err)
+	return info.Severity
+}
+
+// And matches when every predicate matches. And() matches everything.
+func And(preds ...Predicate) Predicate {
+	return func(err error) bool {
+		for _, p := range preds {
+			if !p(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches when any predicate matches. Or() matches nothing.
+func Or(preds ...Predicate) Predicate {
+	return func(err error) bool {
+		for _, p := range preds {
+			if p(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts p.
+func Not(p Predicate) Predicate {
+	return func(err error) bool { return !p(err) }
+}
+
+// Filter returns a Hook that calls h only for exceptions matching p.
+//
+//	ex.AddHook(ex.Filter(ex.SeverityAtLeast(ex.SeverityError), alert))
+func Filter(p Predicate, h Hook) Hook {
+	return func(e Exception) {
+		if p(e) {
+			h(e)
+		}
+	}
+}
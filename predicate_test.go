@@ -0,0 +1,58 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredicates(t *testing.T) {
+	badInput := ex.New(ex.ExTypeIncorrectData, 400, "Invalid email")
+	failure := fmt.Errorf("saving order: %w", ex.New(ex.ExTypeApplicationFailure, 500, "Database down"))
+	plain := errors.New("plain")
+
+	tests := []struct {
+		name string
+		pred ex.Predicate
+		want [3]bool // badInput, failure, plain
+	}{
+		{"CodeIs", ex.CodeIs(ex.ExTypeApplicationFailure), [3]bool{false, true, false}},
+		{"CodeIs any", ex.CodeIs(ex.ExTypeIncorrectData, ex.ExTypeApplicationFailure), [3]bool{true, true, false}},
+		{"IDIn", ex.IDIn(400, 404), [3]bool{true, false, false}},
+		{"SeverityAtLeast", ex.SeverityAtLeast(ex.SeverityError), [3]bool{false, true, false}},
+		{"And", ex.And(ex.CodeIs(ex.ExTypeIncorrectData), ex.IDIn(401)), [3]bool{false, false, false}},
+		{"And empty", ex.And(), [3]bool{true, true, true}},
+		{"Or", ex.Or(ex.IDIn(400), ex.IDIn(500)), [3]bool{true, true, false}},
+		{"Or empty", ex.Or(), [3]bool{false, false, false}},
+		{"Not", ex.Not(ex.CodeIs(ex.ExTypeIncorrectData)), [3]bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := [3]bool{tt.pred(badInput), tt.pred(failure), tt.pred(plain)}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	lenient(t)
+
+	assert.Equal(t, ex.SeverityWarning, ex.SeverityOf(ex.New(ex.ExTypeIncorrectData, 400, "x")))
+	assert.Equal(t, ex.Severity(0), ex.SeverityOf(ex.New(ex.ExType(999), 1, "x")))
+	assert.Equal(t, ex.Severity(0), ex.SeverityOf(errors.New("plain")))
+}
+
+func TestFilter(t *testing.T) {
+	var seen []int
+	remove := ex.AddHook(ex.Filter(ex.SeverityAtLeast(ex.SeverityError), func(e ex.Exception) {
+		seen = append(seen, e.ID())
+	}))
+	defer remove()
+
+	ex.New(ex.ExTypeIncorrectData, 400, "ignored")
+	ex.New(ex.ExTypeApplicationFailure, 500, "kept")
+	assert.Equal(t, []int{500}, seen)
+}
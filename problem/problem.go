@@ -0,0 +1,140 @@
+// Package problem renders exceptions as RFC 9457 problem details
+// ("application/problem+json"), for APIs that standardize on that format
+// rather than the httpx envelope.
+//
+// The standard detail types of package ex are mapped onto extension
+// members: ex.BadRequest becomes the "invalid-params" list used in the
+// RFC's own example, and ex.PreconditionFailure, ex.QuotaFailure, and
+// ex.Help become "preconditions", "quota-violations", and "help".
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/httpx"
+)
+
+// ContentType is the media type of a problem details document.
+const ContentType = "application/problem+json"
+
+// Details is an RFC 9457 problem details document.
+type Details struct {
+	// Type is a URI identifying the problem type: the exception's docs
+	// link (see ex.DocsURLOf), or "about:blank".
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance identifies this occurrence. New leaves it empty for the
+	// caller to fill in, for example with a request ID.
+	Instance string `json:"instance,omitempty"`
+
+	// Code and ID carry the exception's classification, and CodeString
+	// its symbolic code, if any (see ex.Exception.WithCodeString).
+	Code       string `json:"code"`
+	ID         int    `json:"id"`
+	CodeString string `json:"code_string,omitempty"`
+	// Hint tells the client what to do next (see ex.Exception.WithHint).
+	// Unlike Detail, it is reported for server faults too.
+	Hint string `json:"hint,omitempty"`
+
+	InvalidParams   []InvalidParam             `json:"invalid-params,omitempty"`
+	Preconditions   []ex.PreconditionViolation `json:"preconditions,omitempty"`
+	QuotaViolations []ex.QuotaViolation        `json:"quota-violations,omitempty"`
+	Help            []ex.HelpLink              `json:"help,omitempty"`
+}
+
+// InvalidParam is one entry of the "invalid-params" extension.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// New builds the problem details for err. The status comes from
+// httpx.StatusCode and the title is its status text.
+//
+// As with httpx.NewEnvelope, the exception's message is reported as
+// Detail only for client faults; server faults (status 500 and above) get
+// the status text, since their messages may describe internals. Codes
+// carrying ex.FacetInternal get the status text too, and their typed
+// details are left out.
+func New(err error) Details {
+	return newDetails(err, false)
+}
+
+// newDetails is New, keeping the messages of server faults, and the
+// messages and typed details of internal codes, if internal is set.
+func newDetails(err error, internal bool) Details {
+	status := httpx.StatusCode(err)
+	d := Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   ex.ExTypeApplicationFailure.String(),
+		ID:     status,
+	}
+	if url, ok := ex.DocsURLOf(err); ok {
+		d.Type = url
+	}
+	d.Hint, _ = ex.HintOf(err)
+	if e, ok := ex.AsErrorer(err); ok {
+		d.Code = e.Code().String()
+		d.ID = e.ID()
+		d.Detail = e.Message()
+		d.CodeString, _ = ex.CodeStringOf(err)
+	}
+	hidden := ex.IsInternal(err) && !internal
+	if hidden || (status >= http.StatusInternalServerError && (!internal || d.Detail == "")) {
+		d.Detail = d.Title
+	}
+	if hidden {
+		return d
+	}
+
+	if br, ok := ex.Detail[ex.BadRequest](err); ok {
+		for _, v := range br.FieldViolations {
+			d.InvalidParams = append(d.InvalidParams, InvalidParam{Name: v.Field, Reason: v.Description})
+		}
+	}
+	if pf, ok := ex.Detail[ex.PreconditionFailure](err); ok {
+		d.Preconditions = pf.Violations
+	}
+	if qf, ok := ex.Detail[ex.QuotaFailure](err); ok {
+		d.QuotaViolations = qf.Violations
+	}
+	if h, ok := ex.Detail[ex.Help](err); ok {
+		d.Help = h.Links
+	}
+	return d
+}
+
+// Write writes err as a problem details document with the status given
+// by httpx.StatusCode. When err carries a quota (see ex.WithRateLimit),
+// Write also sets the rate-limit and Retry-After headers, as
+// httpx.WriteError does.
+func Write(w http.ResponseWriter, err error) {
+	write(w, err, New(err))
+}
+
+// WriteProfile is Write with the document built from err pruned by p (see
+// ex.Prune), for responses to the audience p describes: profiles without
+// fields, such as ex.ProfileClient, leave out the detail extensions, and
+// profiles keeping internal messages report those of server faults as
+// Detail. The status and rate-limit headers still come from err.
+func WriteProfile(w http.ResponseWriter, err error, p ex.Profile) {
+	d := newDetails(ex.Prune(err, p), p.InternalMessages)
+	d.Status = httpx.StatusCode(err)
+	d.Title = http.StatusText(d.Status)
+	write(w, err, d)
+}
+
+// write writes d, the document for err, with its status.
+func write(w http.ResponseWriter, err error, d Details) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	httpx.SetRateLimitHeaders(w.Header(), err)
+	w.WriteHeader(d.Status)
+	_ = json.NewEncoder(w).Encode(d)
+}
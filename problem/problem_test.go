@@ -0,0 +1,148 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_StandardDetails(t *testing.T) {
+	e := ex.New(ex.ExTypeIncorrectData, 4001, "Validation failed")
+	e = ex.WithDetail(e, ex.BadRequest{FieldViolations: []ex.BadField{
+		{Field: "email", Description: "must be a valid address"},
+	}})
+	e = ex.WithDetail(e, ex.PreconditionFailure{Violations: []ex.PreconditionViolation{
+		{Type: "TOS", Subject: "user:42", Description: "terms not accepted"},
+	}})
+	e = ex.WithDetail(e, ex.QuotaFailure{Violations: []ex.QuotaViolation{
+		{Subject: "project:7", Description: "daily upload limit"},
+	}})
+	e = ex.WithDetail(e, ex.Help{Links: []ex.HelpLink{
+		{Description: "Validation rules", URL: "https://docs.example.com/validation"},
+	}})
+
+	data, err := json.Marshal(problem.New(fmt.Errorf("handler: %w", e)))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "about:blank", "title": "Bad Request", "status": 400,
+		"detail": "Validation failed", "code": "IncorrectData", "id": 4001,
+		"invalid-params": [{"name": "email", "reason": "must be a valid address"}],
+		"preconditions": [{"type": "TOS", "subject": "user:42", "description": "terms not accepted"}],
+		"quota-violations": [{"subject": "project:7", "description": "daily upload limit"}],
+		"help": [{"description": "Validation rules", "url": "https://docs.example.com/validation"}]
+	}`, string(data))
+}
+
+func TestNew_CodeString(t *testing.T) {
+	d := problem.New(ex.New(ex.ExTypeIncorrectData, 4021, "Card declined").WithCodeString("BILLING_CARD_DECLINED"))
+	assert.Equal(t, "BILLING_CARD_DECLINED", d.CodeString)
+}
+
+func TestNew_HintAndDocsURL(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 5003, "pq: deadlock detected").
+		WithHint("Retry the request in a few seconds.").
+		WithDocsURL("https://docs.example.com/errors/5003")
+	d := problem.New(fmt.Errorf("handler: %w", e))
+	assert.Equal(t, "https://docs.example.com/errors/5003", d.Type)
+	assert.Equal(t, "Retry the request in a few seconds.", d.Hint, "hints are shown for server faults")
+	assert.Equal(t, "Internal Server Error", d.Detail)
+}
+
+func TestNew_ServerFaultsAndPlainErrors(t *testing.T) {
+	d := problem.New(ex.New(ex.ExTypeApplicationFailure, 5001, "pq: deadlock detected"))
+	assert.Equal(t, "Internal Server Error", d.Detail)
+	assert.Equal(t, 5001, d.ID)
+
+	d = problem.New(errors.New("plain"))
+	assert.Equal(t, problem.Details{
+		Type: "about:blank", Title: "Internal Server Error", Status: 500,
+		Detail: "Internal Server Error", Code: "ApplicationFailure", ID: 500,
+	}, d)
+}
+
+func TestNew_Internal(t *testing.T) {
+	e := ex.New(ex.ExTypeIncorrectData|ex.FacetInternal, 4001, "Shard 7 rejected the row")
+	e = ex.WithDetail(e, ex.BadRequest{FieldViolations: []ex.BadField{{Field: "shard_key", Description: "hot partition"}}})
+
+	d := problem.New(e)
+	assert.Equal(t, 400, d.Status)
+	assert.Equal(t, "Bad Request", d.Detail)
+	assert.Empty(t, d.InvalidParams, "typed details of internal codes are not for clients")
+}
+
+func TestNew_DocsURLAsType(t *testing.T) {
+	code := ex.ExType(7101)
+	require.NoError(t, ex.RegisterType(code, ex.TypeInfo{
+		Name: "QuotaExceeded", HTTPStatus: http.StatusForbidden, DocsURL: "https://docs.example.com/errors/quota",
+	}))
+
+	d := problem.New(ex.New(code, 1, "Out of quota"))
+	assert.Equal(t, "https://docs.example.com/errors/quota", d.Type)
+	assert.Equal(t, "QuotaExceeded", d.Code)
+}
+
+func TestWrite_RateLimited(t *testing.T) {
+	err := ex.New(ex.ExTypeRateLimited, 4291, "Too many requests").
+		WithRateLimit(ex.RateLimit{Limit: 100, Remaining: 0, Reset: time.Now().Add(30 * time.Second)})
+
+	rec := httptest.NewRecorder()
+	problem.Write(rec, err)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	retryAfter, convErr := strconv.Atoi(rec.Header().Get("Retry-After"))
+	require.NoError(t, convErr)
+	assert.InDelta(t, 30, retryAfter, 1)
+
+	rec = httptest.NewRecorder()
+	problem.WriteProfile(rec, err, ex.ProfileClient)
+	assert.Equal(t, "100", rec.Header().Get("RateLimit-Limit"))
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	problem.Write(rec, ex.New(ex.ExTypeNotFound, 4041, "Order not found"))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	var d problem.Details
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &d))
+	assert.Equal(t, "Order not found", d.Detail)
+}
+
+func TestWriteProfile(t *testing.T) {
+	e := ex.WithDetail(ex.New(ex.ExTypeIncorrectData, 4001, "Validation failed"), ex.BadRequest{FieldViolations: []ex.BadField{
+		{Field: "email", Description: "must be a valid address"},
+	}})
+
+	rec := httptest.NewRecorder()
+	problem.WriteProfile(rec, e, ex.ProfileClient)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var d problem.Details
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &d))
+	assert.Equal(t, "Validation failed", d.Detail)
+	assert.Empty(t, d.InvalidParams, "ProfileClient drops details")
+
+	rec = httptest.NewRecorder()
+	problem.WriteProfile(rec, ex.New(ex.ExTypeApplicationFailure, 5001, "pq: deadlock detected"), ex.ProfileDebug)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &d))
+	assert.Equal(t, "pq: deadlock detected", d.Detail)
+
+	rec = httptest.NewRecorder()
+	problem.WriteProfile(rec, errors.New("plain"), ex.ProfileClient)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &d))
+	assert.Equal(t, "Internal Server Error", d.Detail)
+}
@@ -0,0 +1,224 @@
+package ex
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Profile selects which parts of an exception chain are rendered for an
+// audience. Apply one with Prune: the pruned chain is an ordinary error,
+// so the JSON encoding, ToHTML, Explain, and every adapter render it the
+// same way, and one exception can be sent to a client, a log, and a debug
+// page without any of them seeing more than it should.
+type Profile struct {
+	// Name identifies the profile; see LookupProfile.
+	Name string `json:"name"`
+
+	// Stacks keeps captured stacks.
+	Stacks bool `json:"stacks"`
+
+	// Fields keeps fields and typed details. RedactFields additionally
+	// replaces the values of sensitive fields, as Redacted does.
+	Fields       bool `json:"fields"`
+	RedactFields bool `json:"redact_fields"`
+
+	// FieldLevel is the verbosity of the rendering: fields made with
+	// FieldAt for a lower level are dropped. At slog.LevelDebug or below,
+	// every field is kept.
+	FieldLevel slog.Level `json:"field_level"`
+
+	// InternalMessages keeps the messages of server-fault levels (see
+	// IsServerFault) and of levels whose code carries FacetInternal, and
+	// the root cause if it is not an Exception. Without it, those levels
+	// report their code name instead, and a plain root cause is dropped.
+	InternalMessages bool `json:"internal_messages"`
+
+	// MaxDepth is the number of levels kept, outermost first; zero keeps
+	// them all.
+	MaxDepth int `json:"max_depth,omitempty"`
+}
+
+// The predefined profiles.
+var (
+	// ProfileDebug keeps everything, for debug pages and post-mortems.
+	ProfileDebug = Profile{Name: "debug", Stacks: true, Fields: true, FieldLevel: slog.LevelDebug, InternalMessages: true}
+
+	// ProfileLog keeps everything but the values of sensitive fields and
+	// debug-level fields, for logs and telemetry.
+	ProfileLog = Profile{Name: "log", Stacks: true, Fields: true, RedactFields: true, InternalMessages: true}
+
+	// ProfileClient keeps only the outermost level's code, ID, and
+	// client-facing text, for responses to callers outside the service.
+	ProfileClient = Profile{Name: "client", MaxDepth: 1}
+)
+
+var (
+	profilesMu sync.Mutex
+	// profiles is published copy-on-write; lookups do not lock.
+	profiles atomic.Pointer[map[string]Profile]
+)
+
+func init() {
+	builtin := map[string]Profile{
+		ProfileDebug.Name:  ProfileDebug,
+		ProfileLog.Name:    ProfileLog,
+		ProfileClient.Name: ProfileClient,
+	}
+	profiles.Store(&builtin)
+}
+
+// RegisterProfile makes p available to LookupProfile under p.Name,
+// replacing any profile of that name, including the predefined ones.
+// Operators can then choose profiles by name in configuration.
+func RegisterProfile(p Profile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	cur := *profiles.Load()
+	next := make(map[string]Profile, len(cur)+1)
+	for k, v := range cur {
+		next[k] = v
+	}
+	next[p.Name] = p
+	profiles.Store(&next)
+}
+
+// LookupProfile returns the profile registered under name: "debug",
+// "log", "client", or one added with RegisterProfile.
+func LookupProfile(name string) (Profile, bool) {
+	p, ok := (*profiles.Load())[name]
+	return p, ok
+}
+
+// Prune returns err's chain reduced to what p allows. Exception levels
+// are copied with their stacks, fields, and internal messages removed as
+// p directs, and levels beyond p.MaxDepth are cut off. Annotations (see
+// Annotate) are kept if p keeps fields. Other wrappers, such as
+// fmt.Errorf levels, are dropped, since their text repeats that of the
+// levels below. err itself is never modified, and a profile that keeps
+// everything, such as ProfileDebug, returns it as is unless it holds
+// fields made with FieldAt, which the pruned chain holds as ordinary
+// fields.
+//
+// Prune returns nil for a nil err, and for an err holding no Exception
+// when p drops internal messages.
+func Prune(err error, p Profile) error {
+	if p.Stacks && p.Fields && !p.RedactFields && p.FieldLevel <= slog.LevelDebug && p.InternalMessages && p.MaxDepth == 0 &&
+		!slices.ContainsFunc(FieldsOf(err), isLeveled) {
+		return err
+	}
+	var levels []error
+	var root error
+	depth := 0
+	for cur := err; cur != nil; {
+		switch v := cur.(type) {
+		case Exception:
+			if p.MaxDepth > 0 && depth == p.MaxDepth {
+				cur = nil
+				continue
+			}
+			depth++
+			levels = append(levels, v)
+			cur = v.innerError
+			continue
+		case *annotation:
+			if p.Fields {
+				levels = append(levels, v)
+			}
+			cur = v.err
+			continue
+		}
+		if next := errors.Unwrap(cur); next != nil {
+			cur = next
+			continue
+		}
+		root = cur
+		break
+	}
+	if depth == 0 {
+		if p.InternalMessages {
+			return err
+		}
+		return nil
+	}
+	if !p.InternalMessages {
+		root = nil
+	}
+
+	out := root
+	for i := len(levels) - 1; i >= 0; i-- {
+		switch v := levels[i].(type) {
+		case Exception:
+			out = pruneLevel(v, out, p)
+		case *annotation:
+			out = pruneAnnotation(v, out, p)
+		}
+	}
+	return out
+}
+
+// pruneLevel returns a copy of e wrapping inner, reduced to what p allows.
+func pruneLevel(e Exception, inner error, p Profile) Exception {
+	e.innerError = inner
+	e.sizeHint = 0
+	e.frozen = nil
+	if !p.Stacks {
+		e.stack, e.frames = nil, nil
+	}
+	if p.Fields {
+		e.fields = profileFields(e.fields, p)
+	} else {
+		e.fields, e.details = nil, nil
+	}
+	if !p.InternalMessages && (IsServerFault(e) || e.code.Internal()) {
+		e.message = e.code.String()
+		e.ops = nil
+	}
+	return e
+}
+
+// pruneAnnotation returns a copy of a wrapping inner, reduced to what p
+// allows. Prune keeps annotations only if p keeps fields.
+func pruneAnnotation(a *annotation, inner error, p Profile) error {
+	if inner == nil {
+		return nil
+	}
+	out := &annotation{err: inner, op: a.op, fields: profileFields(a.fields, p)}
+	if !p.InternalMessages {
+		out.op = ""
+	}
+	return out
+}
+
+// profileFields returns the fields p keeps, redacted if p says so. Kept
+// fields made with FieldAt become ordinary fields, so that renderings
+// without a level, such as the JSON encoding, include them.
+func profileFields(fields []Field, p Profile) []Field {
+	if p.FieldLevel > slog.LevelDebug {
+		fields = FieldsAtLevel(fields, p.FieldLevel)
+	}
+	cloned := false
+	for i, f := range fields {
+		lv, ok := f.Value.(leveledValue)
+		if !ok {
+			continue
+		}
+		if !cloned {
+			fields, cloned = slices.Clone(fields), true
+		}
+		fields[i].Value = lv.value
+	}
+	if p.RedactFields {
+		fields = RedactFields(fields)
+	}
+	return fields
+}
+
+// MarshalProfile returns the JSON encoding of err pruned by p (see
+// Prune), the form to send to p's audience.
+func MarshalProfile(err error, p Profile) ([]byte, error) {
+	return json.Marshal(toWire(Prune(err, p)))
+}
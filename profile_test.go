@@ -0,0 +1,177 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profileChain returns a three-level chain with a stack, sensitive and
+// ordinary fields, an annotation, and a plain root cause.
+func profileChain() error {
+	root := errors.New("dial tcp 10.0.0.7:5432: connection refused")
+	db := ex.New(ex.ExTypeApplicationFailure, 5001, "Loading account 42 failed").
+		WithInnerError(root).
+		WithStack()
+	annotated := ex.AnnotateOp(db, "repo.Load", ex.F("table", "accounts"))
+	return ex.New(ex.ExTypeNotFound, 4041, "Account not found").
+		WithInnerError(fmt.Errorf("lookup: %w", annotated)).
+		WithField("api_token", "s3cret").
+		WithField("account", 42)
+}
+
+// prunedLevels returns the Exception levels of err, outermost first.
+func prunedLevels(err error) []ex.Exception {
+	var levels []ex.Exception
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(ex.Exception); ok {
+			levels = append(levels, e)
+		}
+	}
+	return levels
+}
+
+// hasStack reports whether any Exception level of err carries a stack.
+func hasStack(err error) bool {
+	for _, e := range prunedLevels(err) {
+		if len(e.StackTrace()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPrune_Debug(t *testing.T) {
+	err := profileChain()
+	pruned := ex.Prune(err, ex.ProfileDebug)
+
+	assert.Equal(t, err, pruned)
+	assert.Equal(t, "s3cret", ex.FieldsOf(pruned)[0].Value)
+	assert.True(t, hasStack(pruned))
+}
+
+func TestPrune_Log(t *testing.T) {
+	err := profileChain()
+	pruned := ex.Prune(err, ex.ProfileLog)
+
+	fields := map[string]any{}
+	for _, f := range ex.FieldsOf(pruned) {
+		fields[f.Key] = f.Value
+	}
+	assert.Equal(t, ex.RedactedValue, fields["api_token"])
+	assert.Equal(t, 42, fields["account"])
+	assert.Equal(t, "accounts", fields["table"], "annotations are kept")
+	assert.True(t, hasStack(pruned))
+	assert.Equal(t, "Account not found: Loading account 42 failed: dial tcp 10.0.0.7:5432: connection refused", pruned.Error(),
+		"wrappers other than exceptions and annotations are dropped")
+
+	assert.Equal(t, "s3cret", ex.FieldsOf(err)[0].Value, "input must not be modified")
+}
+
+func TestPrune_Client(t *testing.T) {
+	pruned := ex.Prune(profileChain(), ex.ProfileClient)
+
+	require.Len(t, prunedLevels(pruned), 1)
+	assert.Equal(t, "Account not found", pruned.Error())
+	assert.Empty(t, ex.FieldsOf(pruned))
+	assert.False(t, hasStack(pruned))
+	id, ok := ex.IDOf(pruned)
+	require.True(t, ok)
+	assert.Equal(t, 4041, id)
+}
+
+func TestPrune_ServerFaultMessage(t *testing.T) {
+	err := ex.New(ex.ExTypeApplicationFailure, 5001, "Loading account 42 failed").
+		WithInnerError(errors.New("connection refused"))
+	pruned := ex.Prune(err, ex.ProfileClient)
+
+	assert.Equal(t, "ApplicationFailure", pruned.Error())
+	assert.True(t, errors.Is(pruned, ex.New(ex.ExTypeApplicationFailure, 5001, "")), "code and ID are kept")
+}
+
+func TestPrune_InternalFacet(t *testing.T) {
+	err := ex.New(ex.ExTypeNotFound|ex.FacetInternal, 4041, "No row in shard 7")
+
+	assert.Equal(t, "NotFound|Internal", ex.Prune(err, ex.ProfileClient).Error())
+	assert.Equal(t, "No row in shard 7", ex.Prune(err, ex.ProfileLog).Error())
+}
+
+func TestPrune_MaxDepth(t *testing.T) {
+	p := ex.Profile{Name: "shallow", Fields: true, InternalMessages: true, MaxDepth: 2}
+	pruned := ex.Prune(profileChain(), p)
+
+	levels := prunedLevels(pruned)
+	require.Len(t, levels, 2)
+	assert.Equal(t, "Loading account 42 failed", levels[1].Message())
+	assert.False(t, hasStack(pruned))
+}
+
+func TestPrune_PlainErrors(t *testing.T) {
+	plain := errors.New("boom")
+	assert.Equal(t, plain, ex.Prune(plain, ex.ProfileDebug))
+	assert.Nil(t, ex.Prune(plain, ex.ProfileClient))
+	assert.Nil(t, ex.Prune(nil, ex.ProfileDebug))
+}
+
+func TestPrune_FieldLevel(t *testing.T) {
+	err := ex.New(ex.ExTypeApplicationFailure, 5001, "Query failed").
+		WithFields(ex.F("table", "orders"), ex.FieldAt(slog.LevelDebug, "query", "SELECT 1"))
+
+	assert.Equal(t, []ex.Field{ex.F("table", "orders")}, ex.FieldsOf(ex.Prune(err, ex.ProfileLog)))
+	assert.Len(t, ex.FieldsOf(ex.Prune(err, ex.ProfileDebug)), 2)
+
+	verbose := ex.ProfileLog
+	verbose.FieldLevel = slog.LevelDebug
+	assert.Len(t, ex.FieldsOf(ex.Prune(err, verbose)), 2)
+
+	// Kept leveled fields become ordinary ones, which the encoding renders.
+	data, mErr := json.Marshal(ex.Prune(err, ex.ProfileDebug))
+	require.NoError(t, mErr)
+	assert.Contains(t, string(data), `"query":"SELECT 1"`)
+	_, leveled := ex.FieldsOf(ex.Prune(err, ex.ProfileDebug))[1].Level()
+	assert.False(t, leveled)
+}
+
+func TestPrune_Frozen(t *testing.T) {
+	frozen := ex.New(ex.ExTypeApplicationFailure, 5001, "Database down").Freeze()
+	pruned := ex.Prune(frozen, ex.ProfileClient)
+
+	assert.Equal(t, "ApplicationFailure", pruned.Error())
+	exc, ok := pruned.(ex.Exception)
+	require.True(t, ok)
+	assert.False(t, exc.Frozen())
+}
+
+func TestMarshalProfile(t *testing.T) {
+	data, err := ex.MarshalProfile(profileChain(), ex.ProfileClient)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "Account not found", decoded["message"])
+	assert.NotContains(t, decoded, "inner")
+	assert.NotContains(t, decoded, "fields")
+	assert.NotContains(t, string(data), "s3cret")
+}
+
+func TestLookupProfile(t *testing.T) {
+	p, ok := ex.LookupProfile("client")
+	require.True(t, ok)
+	assert.Equal(t, ex.ProfileClient, p)
+
+	_, ok = ex.LookupProfile("partner")
+	assert.False(t, ok)
+
+	partner := ex.Profile{Name: "partner", Fields: true, RedactFields: true, MaxDepth: 2}
+	t.Cleanup(ex.SaveProfiles())
+	ex.RegisterProfile(partner)
+	p, ok = ex.LookupProfile("partner")
+	require.True(t, ok)
+	assert.Equal(t, partner, p)
+}
@@ -0,0 +1,130 @@
+package ex
+
+import (
+	"errors"
+	"strings"
+)
+
+// Classifier assigns a code and ID to an error that is not yet an
+// Exception.
+type Classifier func(err error) (ExType, int)
+
+// Promote converts an existing standard-library wrap chain, such as one
+// built with fmt.Errorf("...: %w", err), into an equivalent Exception
+// chain. It is meant for migrating legacy code to ex one layer at a time.
+//
+// Each wrapping level becomes an Exception whose message is that level's
+// own contribution: for "loading config: open app.yaml: no such file" the
+// outer level's message is "loading config". classify is called with each
+// level's original error to pick its code and ID; a nil classify gives
+// each level the code and ID of the first Errorer in its chain, and
+// otherwise tries the classifiers added with RegisterClassifier and, for
+// levels none of them recognizes, assigns ExTypeTimeout to timeouts (see
+// IsTimeout) and ExTypeApplicationFailure to the rest, both with ID 0.
+//
+// A level that is an Errorer other than an Exception becomes an Exception
+// with its code, ID, code string, message, fields, and stack, so helpers
+// that promote what ex.As does not find treat it as they would an
+// Exception.
+//
+// The innermost error is kept verbatim as the inner error of the deepest
+// Exception, so errors.Is checks against sentinels such as sql.ErrNoRows
+// or fs.ErrNotExist keep working after promotion. Promotion stops early at
+// an error that is already an Exception, and at errors that wrap several
+// errors at once (errors.Join), which are likewise kept verbatim.
+//
+// The resulting Error() string matches the original whenever each level
+// formatted its inner error as a ": %w" suffix. Promote returns the zero
+// Exception for a nil error and err itself if it is already an Exception.
+// Hooks run once, for the returned outermost Exception.
+func Promote(err error, classify Classifier) Exception {
+	if err == nil {
+		return Exception{}
+	}
+	if e, ok := err.(Exception); ok {
+		return e
+	}
+	if classify == nil {
+		classify = classifyPromoted
+	}
+	e := promote(err, classify)
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// promote converts one level and, recursively, the levels beneath it.
+func promote(err error, classify Classifier) Exception {
+	if other, ok := err.(Errorer); ok {
+		return fromErrorer(other, classify)
+	}
+	code, id := classify(err)
+	inner := errors.Unwrap(err)
+	if inner == nil {
+		// A leaf: keep it as-is so sentinel identity survives. The empty
+		// message makes Error() render the leaf's own text.
+		return Exception{code: code, id: id, innerError: err}
+	}
+
+	e := Exception{code: code, id: id, message: ownMessage(err.Error(), inner.Error())}
+	if innerExc, ok := inner.(Exception); ok {
+		e.innerError = innerExc
+		return e
+	}
+	e.innerError = promote(inner, classify)
+	return e
+}
+
+// fromErrorer converts an Errorer level, and the levels beneath it.
+func fromErrorer(other Errorer, classify Classifier) Exception {
+	codeString, _ := codeStringOf(other)
+	e := Exception{
+		code:       other.Code(),
+		id:         other.ID(),
+		codeString: codeString,
+		message:    other.Message(),
+		fields:     other.Fields(),
+		frames:     other.StackTrace(),
+	}
+	switch inner := other.Unwrap().(type) {
+	case nil:
+	case Exception:
+		e.innerError = inner
+	default:
+		e.innerError = promote(inner, classify)
+	}
+	return e
+}
+
+// classifyPromoted is the Classifier of Promote when it is given none: the
+// code and ID of the first Errorer in err's chain or, failing that,
+// classifyRegistered.
+func classifyPromoted(err error) (ExType, int) {
+	if e, ok := AsErrorer(err); ok {
+		return e.Code(), e.ID()
+	}
+	return classifyRegistered(err)
+}
+
+// ownMessage strips the wrapped error's text from a wrapper's message,
+// leaving what the wrapping level itself contributed.
+func ownMessage(msg, innerMsg string) string {
+	if innerMsg == "" {
+		return msg
+	}
+	if own, ok := strings.CutSuffix(msg, ": "+innerMsg); ok {
+		return own
+	}
+	if own, ok := strings.CutSuffix(msg, innerMsg); ok {
+		return strings.TrimRight(own, ": ")
+	}
+	return msg
+}
+
+// defaultClassify classifies errors no registered classifier recognizes.
+func defaultClassify(err error) (ExType, int) {
+	if isStdTimeout(err) {
+		return ExTypeTimeout, 0
+	}
+	return ExTypeApplicationFailure, 0
+}
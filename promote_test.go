@@ -0,0 +1,93 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromote_ConvertsWrapChain(t *testing.T) {
+	leaf := fs.ErrNotExist
+	mid := fmt.Errorf("open app.yaml: %w", leaf)
+	top := fmt.Errorf("loading config: %w", mid)
+
+	classify := func(err error) (ex.ExType, int) {
+		if errors.Is(err, fs.ErrNotExist) && err != top {
+			return ex.ExTypeIncorrectData, 404
+		}
+		return ex.ExTypeApplicationFailure, 500
+	}
+	promoted := ex.Promote(top, classify)
+
+	assert.Equal(t, top.Error(), promoted.Error())
+	assert.Equal(t, "loading config", promoted.Message())
+	assert.Equal(t, ex.ExTypeApplicationFailure, promoted.Code())
+	assert.Equal(t, 500, promoted.ID())
+
+	second, ok := promoted.InnerError().(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, "open app.yaml", second.Message())
+	assert.Equal(t, 404, second.ID())
+
+	leafLevel, ok := second.InnerError().(ex.Exception)
+	require.True(t, ok)
+	assert.Equal(t, "", leafLevel.Message())
+	assert.Equal(t, leaf.Error(), leafLevel.Error())
+
+	// Sentinel identity survives promotion.
+	assert.True(t, errors.Is(promoted, fs.ErrNotExist))
+}
+
+func TestPromote_DefaultClassifier(t *testing.T) {
+	promoted := ex.Promote(errors.New("boom"), nil)
+	assert.Equal(t, ex.ExTypeApplicationFailure, promoted.Code())
+	assert.Equal(t, 0, promoted.ID())
+	assert.Equal(t, "boom", promoted.Error())
+}
+
+func TestPromote_StopsAtExceptionsAndJoins(t *testing.T) {
+	existing := ex.New(ex.ExTypePermissionDenied, 403, "Access denied")
+	assert.Equal(t, existing, ex.Promote(existing, nil))
+
+	wrapped := fmt.Errorf("handler: %w", existing)
+	promoted := ex.Promote(wrapped, nil)
+	assert.Equal(t, "handler", promoted.Message())
+	assert.Equal(t, existing, promoted.InnerError())
+
+	joined := errors.Join(errors.New("a"), errors.New("b"))
+	promoted = ex.Promote(fmt.Errorf("batch: %w", joined), nil)
+	assert.Equal(t, joined, promoted.InnerError().(ex.Exception).InnerError())
+}
+
+func TestPromote_UnusualFormats(t *testing.T) {
+	leaf := errors.New("timeout")
+
+	// Bare %w contributes nothing of its own.
+	promoted := ex.Promote(fmt.Errorf("%w", leaf), nil)
+	assert.Equal(t, "", promoted.Message())
+	assert.Equal(t, "timeout", promoted.Error())
+
+	// A prefix without the conventional ": " separator is still isolated.
+	promoted = ex.Promote(fmt.Errorf("retry failed - %w", leaf), nil)
+	assert.Equal(t, "retry failed -", promoted.Message())
+
+	// Inner text not at the end: the whole message is kept.
+	promoted = ex.Promote(fmt.Errorf("%w while saving", leaf), nil)
+	assert.Equal(t, "timeout while saving", promoted.Message())
+
+	assert.Equal(t, ex.Exception{}, ex.Promote(nil, nil))
+}
+
+func TestPromote_RunsHooksOnce(t *testing.T) {
+	var calls int
+	remove := ex.AddHook(func(ex.Exception) { calls++ })
+	defer remove()
+
+	ex.Promote(fmt.Errorf("a: %w", fmt.Errorf("b: %w", errors.New("c"))), nil)
+	assert.Equal(t, 1, calls)
+}
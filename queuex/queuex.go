@@ -0,0 +1,135 @@
+// Package queuex carries exceptions in message headers, so asynchronous
+// consumers can hand typed failure information on to dead-letter queues
+// and retry processors.
+//
+// Headers are read and written through a Carrier, in the manner of
+// OpenTelemetry's propagators, which keeps the package independent of any
+// broker client. MapCarrier covers brokers with string headers; for
+// others, a carrier is a few lines, as for Kafka headers:
+//
+//	type kafkaCarrier struct{ msg *kafka.Message }
+//
+//	func (c kafkaCarrier) Set(key, value string) {
+//	    c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+//	}
+//
+//	func (c kafkaCarrier) Get(key string) string {
+//	    for _, h := range c.msg.Headers {
+//	        if h.Key == key {
+//	            return string(h.Value)
+//	        }
+//	    }
+//	    return ""
+//	}
+package queuex
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/bold-minds/ex"
+)
+
+// Header names written by Inject. HeaderType, HeaderCode, and HeaderID
+// let routers inspect a failure without decoding it; HeaderError holds the
+// full encoding. HeaderEncoding is set by InjectCompressed when it
+// compresses HeaderError, and cleared by every other injection.
+const (
+	HeaderType     = "ex-type"
+	HeaderCode     = "ex-code"
+	HeaderID       = "ex-id"
+	HeaderError    = "ex-error"
+	HeaderEncoding = "ex-encoding"
+)
+
+// EncodingGzip is the HeaderEncoding of a HeaderError holding the
+// base64-encoded output of ex.Exception.MarshalCompressed.
+const EncodingGzip = "gzip+base64"
+
+// Carrier is read and write access to a message's headers.
+type Carrier interface {
+	// Get returns the value of the header key, or "".
+	Get(key string) string
+	// Set sets the header key to value.
+	Set(key, value string)
+}
+
+// MapCarrier is a Carrier backed by a map.
+type MapCarrier map[string]string
+
+// Get implements Carrier.
+func (c MapCarrier) Get(key string) string { return c[key] }
+
+// Set implements Carrier.
+func (c MapCarrier) Set(key, value string) { c[key] = value }
+
+// Inject writes err into the headers of c: the type name, code, and ID of
+// its outermost Exception, and its redacted JSON encoding (see
+// ex.Exception.MarshalJSON), which keeps the type, taxonomy version,
+// origin, trace context, fields, details, and stack of every level of the
+// chain. Errors other than Exceptions are promoted to one first (see
+// ex.Promote). A nil err writes nothing.
+func Inject(c Carrier, err error) {
+	inject(c, err, ex.Exception.MarshalJSON)
+}
+
+// InjectCompressed is Inject for brokers with message-size limits that
+// field-rich chains can exceed. If the encoding is longer than threshold
+// bytes (see ex.Exception.MarshalCompressed) it is gzip-compressed,
+// base64-encoded, and marked with HeaderEncoding. Extract decodes either
+// form.
+func InjectCompressed(c Carrier, err error, threshold int) {
+	inject(c, err, func(e ex.Exception) ([]byte, error) { return e.MarshalCompressed(threshold) })
+}
+
+// inject writes err into c with its redacted outermost Exception encoded
+// by encode.
+func inject(c Carrier, err error, encode func(ex.Exception) ([]byte, error)) {
+	if err == nil {
+		return
+	}
+	e, ok := ex.As(err)
+	if !ok {
+		e = ex.Promote(err, nil)
+	}
+	data, mErr := encode(e.Redacted())
+	if mErr != nil {
+		return
+	}
+	c.Set(HeaderType, e.Code().String())
+	c.Set(HeaderCode, strconv.Itoa(int(e.Code())))
+	c.Set(HeaderID, strconv.Itoa(e.ID()))
+	if ex.IsCompressed(data) {
+		c.Set(HeaderEncoding, EncodingGzip)
+		c.Set(HeaderError, base64.StdEncoding.EncodeToString(data))
+		return
+	}
+	// Carriers are reused for retries, so clear an encoding left by an
+	// earlier, compressed injection.
+	c.Set(HeaderEncoding, "")
+	c.Set(HeaderError, string(data))
+}
+
+// Extract rebuilds the exception written by Inject or InjectCompressed.
+// It reports false if c carries none or the encoding cannot be decoded.
+// Decoded exceptions report Remote() == true.
+func Extract(c Carrier) (ex.Exception, bool) {
+	data := c.Get(HeaderError)
+	if data == "" {
+		return ex.Exception{}, false
+	}
+	if c.Get(HeaderEncoding) == EncodingGzip {
+		raw, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return ex.Exception{}, false
+		}
+		e, err := ex.UnmarshalCompressed(raw)
+		return e, err == nil
+	}
+	var e ex.Exception
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return ex.Exception{}, false
+	}
+	return e, true
+}
@@ -0,0 +1,132 @@
+package queuex_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/queuex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectExtract(t *testing.T) {
+	sent := ex.New(ex.ExTypeUnavailable, 5031, "Payment provider down").
+		WithField("order", "A-17").
+		WithField("api_token", "s3cr3t").
+		WithInnerError(errors.New("dial tcp: connection refused"))
+
+	headers := queuex.MapCarrier{}
+	queuex.Inject(headers, fmt.Errorf("consume: %w", sent))
+	assert.Equal(t, "Unavailable", headers[queuex.HeaderType])
+	assert.Equal(t, "6", headers[queuex.HeaderCode])
+	assert.Equal(t, "5031", headers[queuex.HeaderID])
+	assert.NotContains(t, headers[queuex.HeaderError], "s3cr3t")
+
+	got, ok := queuex.Extract(headers)
+	require.True(t, ok)
+	assert.True(t, got.Remote())
+	assert.True(t, errors.Is(got, sent))
+	assert.Equal(t, sent.Error(), got.Error())
+	v, _ := got.FieldValue("order")
+	assert.Equal(t, "A-17", v)
+}
+
+func TestInjectExtract_Metadata(t *testing.T) {
+	ex.SetServiceName("orders")
+	ex.SetTaxonomyVersion(2)
+	t.Cleanup(func() {
+		ex.SetServiceName("")
+		ex.SetTaxonomyVersion(0)
+	})
+	tc := ex.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	sent := ex.New(ex.ExTypeTimeout, 5041, "Charge timed out").WithTraceContext(tc)
+
+	for name, inject := range map[string]func(queuex.Carrier, error){
+		"plain":      queuex.Inject,
+		"compressed": func(c queuex.Carrier, err error) { queuex.InjectCompressed(c, err, -1) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			headers := queuex.MapCarrier{}
+			inject(headers, sent)
+			got, ok := queuex.Extract(headers)
+			require.True(t, ok)
+			assert.Equal(t, ex.ExTypeTimeout, got.Code())
+			assert.Equal(t, 2, got.Taxonomy())
+			assert.Equal(t, "orders", got.Origin())
+			gotTC, hasTC := got.TraceContext()
+			require.True(t, hasTC)
+			assert.Equal(t, tc, gotTC)
+		})
+	}
+}
+
+func TestInject_PlainAndNil(t *testing.T) {
+	headers := queuex.MapCarrier{}
+	queuex.Inject(headers, nil)
+	assert.Empty(t, headers)
+
+	queuex.Inject(headers, errors.New("EOF"))
+	got, ok := queuex.Extract(headers)
+	require.True(t, ok)
+	assert.Equal(t, "EOF", got.Error())
+}
+
+func TestInjectCompressed(t *testing.T) {
+	sent := ex.New(ex.ExTypeApplicationFailure, 5001, "Import failed").
+		WithField("rows", strings.Repeat("r", 8000)).
+		WithField("api_token", "s3cr3t").
+		WithStack()
+
+	headers := queuex.MapCarrier{}
+	queuex.InjectCompressed(headers, sent, 0)
+	assert.Equal(t, queuex.EncodingGzip, headers[queuex.HeaderEncoding])
+	assert.Equal(t, "5001", headers[queuex.HeaderID])
+	assert.Less(t, len(headers[queuex.HeaderError]), 2000)
+
+	got, ok := queuex.Extract(headers)
+	require.True(t, ok)
+	assert.True(t, got.Remote())
+	assert.True(t, errors.Is(got, sent))
+	assert.NotEmpty(t, got.StackTrace(), "stacks are kept")
+	v, _ := got.FieldValue("api_token")
+	assert.Equal(t, ex.RedactedValue, v)
+
+	small := queuex.MapCarrier{}
+	queuex.InjectCompressed(small, ex.New(ex.ExTypeNotFound, 4041, "Missing"), 0)
+	assert.Empty(t, small[queuex.HeaderEncoding])
+	got, ok = queuex.Extract(small)
+	require.True(t, ok)
+	assert.Equal(t, "Missing", got.Error())
+
+	queuex.InjectCompressed(small, nil, 0)
+	_, ok = queuex.Extract(queuex.MapCarrier{queuex.HeaderEncoding: queuex.EncodingGzip, queuex.HeaderError: "not base64!"})
+	assert.False(t, ok)
+}
+
+func TestInject_ReusedCarrier(t *testing.T) {
+	headers := queuex.MapCarrier{}
+	large := ex.New(ex.ExTypeApplicationFailure, 5001, "Import failed").WithField("rows", strings.Repeat("r", 8000))
+	queuex.InjectCompressed(headers, large, 0)
+	require.Equal(t, queuex.EncodingGzip, headers[queuex.HeaderEncoding])
+
+	queuex.Inject(headers, ex.New(ex.ExTypeNotFound, 4041, "Missing"))
+	got, ok := queuex.Extract(headers)
+	require.True(t, ok)
+	assert.Equal(t, "Missing", got.Error())
+
+	queuex.InjectCompressed(headers, large, 0)
+	queuex.InjectCompressed(headers, ex.New(ex.ExTypeNotFound, 4042, "Also missing"), 0)
+	got, ok = queuex.Extract(headers)
+	require.True(t, ok)
+	assert.Equal(t, "Also missing", got.Error())
+}
+
+func TestExtract_Missing(t *testing.T) {
+	_, ok := queuex.Extract(queuex.MapCarrier{})
+	assert.False(t, ok)
+	_, ok = queuex.Extract(queuex.MapCarrier{queuex.HeaderError: "{"})
+	assert.False(t, ok)
+}
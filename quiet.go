@@ -0,0 +1,90 @@
+package ex
+
+// Quiet returns a copy of e marked as quiet: an exception that is part of
+// expected operation, such as a failed health-check probe or a rejected
+// control-flow path, and must not pollute metrics and reporters. Quiet
+// exceptions are skipped by Reporter, the otlpx exporter, and the slo
+// tracker, and exceptions that wrap one directly (through annotations at
+// most) with WrapAuto, WrapKeepStatus, Newf, or a Factory are quiet too and
+// do not run the hooks.
+//
+// Hooks run when an exception is created, before Quiet can mark it, so
+// Quiet only suits exceptions that hooks have already seen or must see.
+// To keep hooks from seeing one at all, create it quiet with QuietNew,
+// QuietWrap, or QuietWrapAuto. Quietness is local to the process and is
+// not encoded.
+func Quiet(e Exception) Exception {
+	e.quiet = true
+	return e
+}
+
+// QuietNew creates a quiet exception (see Quiet) like New, following the
+// stack policy but without running the hooks:
+//
+//	return ex.QuietNew(ex.ExTypeUnavailable, 5034, "Dependency not ready")
+func QuietNew(code ExType, id int, message string) Exception {
+	checkNew(code, id, message)
+	e := Exception{code: code, id: id, message: message, quiet: true, stack: autoStack(code, id, 1), sizeHint: len(message) + 1}
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// QuietWrap creates a quiet exception (see Quiet) wrapping err, like
+// New(code, id, message).WithInnerError(err), following the stack policy
+// but without running the hooks.
+func QuietWrap(err error, code ExType, id int, message string) Exception {
+	checkNew(code, id, message)
+	err = limitDepth(err)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: err,
+		quiet:      true,
+		stack:      autoStack(code, id, 1),
+		sizeHint:   sizeHintOf(message, err),
+	}
+	observeErr(err)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// QuietWrapAuto creates a quiet exception (see Quiet) like WrapAuto,
+// following the stack policy but without running the hooks:
+//
+//	if err := db.PingContext(ctx); err != nil {
+//	    return ex.QuietWrapAuto(err, 5034, "Probe failed")
+//	}
+func QuietWrapAuto(err error, id int, message string) Exception {
+	code := inferCode(err)
+	if buildStrict {
+		checkMisuse(code, id, message, 2)
+	}
+	err = limitDepth(err)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: err,
+		quiet:      true,
+		stack:      autoStack(code, id, 1),
+		sizeHint:   sizeHintOf(message, err),
+	}
+	observeErr(err)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// IsQuiet reports whether the first Exception in err's chain is quiet;
+// see Quiet.
+func IsQuiet(err error) bool {
+	e, ok := As(err)
+	return ok && e.quiet
+}
+
+// quietInner reports whether err is a quiet exception, possibly under
+// annotations, whose quietness a new exception wrapping it inherits.
+func quietInner(err error) bool {
+	e, ok := nextLevel(err)
+	return ok && e.quiet
+}
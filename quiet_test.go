@@ -0,0 +1,107 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietNew_SkipsHooks(t *testing.T) {
+	var hooked int
+	remove := ex.AddHook(func(ex.Exception) { hooked++ })
+	t.Cleanup(remove)
+
+	e := ex.QuietNew(ex.ExTypeUnavailable, 5034, "Dependency not ready")
+	assert.Zero(t, hooked)
+	assert.True(t, ex.IsQuiet(e))
+	assert.Equal(t, "Dependency not ready", e.Error())
+
+	ex.New(ex.ExTypeUnavailable, 5034, "Dependency not ready")
+	assert.Equal(t, 1, hooked)
+}
+
+func TestQuietWrap_SkipsHooks(t *testing.T) {
+	var hooked int
+	remove := ex.AddHook(func(ex.Exception) { hooked++ })
+	t.Cleanup(remove)
+
+	cause := errors.New("dial tcp: connection refused")
+	auto := ex.QuietWrapAuto(cause, 5034, "Probe failed")
+	wrapped := ex.QuietWrap(cause, ex.ExTypeUnavailable, 5034, "Probe failed")
+	assert.Zero(t, hooked)
+
+	for _, e := range []ex.Exception{auto, wrapped} {
+		assert.True(t, ex.IsQuiet(e))
+		assert.Equal(t, "Probe failed: dial tcp: connection refused", e.Error())
+		assert.True(t, errors.Is(e, cause))
+	}
+	assert.Equal(t, ex.ExTypeApplicationFailure, auto.Code())
+	assert.Equal(t, ex.ExTypeUnavailable, wrapped.Code())
+
+	ex.Quiet(ex.WrapAuto(cause, 5034, "Probe failed"))
+	assert.Equal(t, 1, hooked, "Quiet marks an exception after the hooks saw it")
+}
+
+func TestQuiet(t *testing.T) {
+	e := ex.New(ex.ExTypeUnavailable, 5034, "Probe failed")
+	assert.False(t, ex.IsQuiet(e))
+
+	quiet := ex.Quiet(e)
+	assert.True(t, ex.IsQuiet(quiet))
+	assert.True(t, ex.IsQuiet(fmt.Errorf("probe: %w", quiet)))
+	assert.False(t, ex.IsQuiet(e), "the original is not modified")
+	assert.True(t, errors.Is(quiet, e))
+	assert.False(t, ex.IsQuiet(errors.New("plain")))
+	assert.False(t, ex.IsQuiet(nil))
+}
+
+func TestQuiet_InheritedByWrap(t *testing.T) {
+	var hooked int
+	remove := ex.AddHook(func(ex.Exception) { hooked++ })
+	t.Cleanup(remove)
+
+	probe := ex.QuietNew(ex.ExTypeUnavailable, 5034, "Dependency not ready")
+	annotated := ex.Annotate(probe, ex.F("dep", "db"))
+
+	assert.True(t, ex.IsQuiet(ex.WrapAuto(annotated, 5035, "Health check failed")))
+	assert.True(t, ex.IsQuiet(ex.WrapKeepStatus(probe, ex.ExTypeApplicationFailure, 5036, "Readiness failed")))
+	assert.True(t, ex.IsQuiet(ex.NewFactory(ex.Config{}).Wrap(probe, ex.ExTypeUnavailable, 5037, "Probe failed")))
+	assert.Zero(t, hooked)
+
+	assert.False(t, ex.IsQuiet(ex.WrapAuto(fmt.Errorf("probe: %w", probe), 5035, "Health check failed")),
+		"quietness is inherited through annotations only")
+	assert.False(t, ex.IsQuiet(ex.New(ex.ExTypeApplicationFailure, 500, "Failed").WithInnerError(probe)),
+		"WithInnerError does not make an exception quiet")
+}
+
+func TestQuiet_Factory(t *testing.T) {
+	var hooked int
+	f := ex.NewFactory(ex.Config{Hooks: []ex.Hook{func(ex.Exception) { hooked++ }}})
+
+	f.WrapAuto(ex.QuietNew(ex.ExTypeUnavailable, 5034, "Dependency not ready"), 5035, "Health check failed")
+	assert.Zero(t, hooked)
+	f.WrapAuto(errors.New("boom"), 5035, "Health check failed")
+	assert.Equal(t, 1, hooked)
+}
+
+func TestQuiet_Reporter(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	defer r.Shutdown(context.Background())
+
+	r.Report(ex.Quiet(ex.New(ex.ExTypeUnavailable, 5034, "Probe failed")))
+	r.Report(ex.New(ex.ExTypeApplicationFailure, 500, "Failed"))
+
+	require.NoError(t, r.Flush(context.Background()))
+	batches := s.all()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, 500, batches[0][0].Sample.ID())
+	assert.Zero(t, r.Dropped())
+}
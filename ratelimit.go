@@ -0,0 +1,78 @@
+package ex
+
+import "time"
+
+// Field keys under which WithRateLimit stores quota metadata.
+const (
+	RateLimitLimitKey     = "ratelimit.limit"
+	RateLimitRemainingKey = "ratelimit.remaining"
+	RateLimitResetKey     = "ratelimit.reset"
+)
+
+// RateLimit describes the quota a throttled request ran into.
+type RateLimit struct {
+	// Limit is the number of requests allowed per window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window,
+	// usually zero for a throttled request.
+	Remaining int
+
+	// Reset is when the window resets and requests may be retried. The
+	// zero time means unknown.
+	Reset time.Time
+}
+
+// WithRateLimit returns a new Exception carrying rl as fields, so the
+// quota survives encoding and every adapter can report it consistently:
+// httpx, for instance, answers with 429 and the RateLimit-* and
+// Retry-After headers. It is meant for ExTypeRateLimited exceptions:
+//
+//	return ex.New(ex.ExTypeRateLimited, 4291, "Too many requests").
+//	    WithRateLimit(ex.RateLimit{Limit: 100, Remaining: 0, Reset: windowEnd})
+func (e Exception) WithRateLimit(rl RateLimit) Exception {
+	fields := []Field{
+		{Key: RateLimitLimitKey, Value: rl.Limit},
+		{Key: RateLimitRemainingKey, Value: rl.Remaining},
+	}
+	if !rl.Reset.IsZero() {
+		fields = append(fields, Field{Key: RateLimitResetKey, Value: rl.Reset.UTC().Format(time.RFC3339)})
+	}
+	return e.WithFields(fields...)
+}
+
+// RateLimitOf returns the quota attached with WithRateLimit anywhere in
+// err's chain, including on exceptions decoded from JSON.
+func RateLimitOf(err error) (RateLimit, bool) {
+	var rl RateLimit
+	found := false
+	for _, f := range FieldsOf(err) {
+		switch f.Key {
+		case RateLimitLimitKey:
+			rl.Limit, found = intValue(f.Value), true
+		case RateLimitRemainingKey:
+			rl.Remaining, found = intValue(f.Value), true
+		case RateLimitResetKey:
+			if s, ok := f.Value.(string); ok {
+				rl.Reset, _ = time.Parse(time.RFC3339, s)
+			}
+			found = true
+		}
+	}
+	return rl, found
+}
+
+// intValue converts the integer representations a field may hold, before
+// and after a JSON round trip, to int.
+func intValue(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
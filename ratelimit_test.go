@@ -0,0 +1,47 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	reset := time.Date(2025, 1, 10, 12, 0, 30, 0, time.UTC)
+	rl := ex.RateLimit{Limit: 100, Remaining: 3, Reset: reset}
+	err := ex.New(ex.ExTypeRateLimited, 4291, "Too many requests").WithRateLimit(rl)
+
+	got, ok := ex.RateLimitOf(fmt.Errorf("calling billing: %w", err))
+	require.True(t, ok)
+	assert.Equal(t, rl, got)
+
+	// The quota survives a JSON round trip.
+	data, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	got, ok = ex.RateLimitOf(decoded)
+	require.True(t, ok)
+	assert.Equal(t, 100, got.Limit)
+	assert.Equal(t, 3, got.Remaining)
+	assert.True(t, reset.Equal(got.Reset))
+
+	assert.True(t, ex.IsClientFault(err))
+}
+
+func TestRateLimitOf_Missing(t *testing.T) {
+	_, ok := ex.RateLimitOf(ex.New(ex.ExTypeRateLimited, 4291, "Too many requests"))
+	assert.False(t, ok)
+	_, ok = ex.RateLimitOf(errors.New("plain"))
+	assert.False(t, ok)
+
+	rl, ok := ex.RateLimitOf(ex.New(ex.ExTypeRateLimited, 4291, "x").WithRateLimit(ex.RateLimit{Limit: 5}))
+	require.True(t, ok)
+	assert.True(t, rl.Reset.IsZero())
+}
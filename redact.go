@@ -0,0 +1,198 @@
+package ex
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// RedactedValue replaces the value of a sensitive field wherever
+// exceptions are rendered for humans.
+const RedactedValue = "[REDACTED]"
+
+// defaultSensitiveKeys are matched case-insensitively as substrings of
+// field keys, with hyphens read as underscores.
+var defaultSensitiveKeys = []string{
+	"password", "passwd", "secret", "token", "authorization",
+	"cookie", "api_key", "apikey", "private_key", "credential",
+}
+
+var sensitiveKeys atomic.Pointer[[]string]
+
+func init() {
+	keys := defaultSensitiveKeys
+	sensitiveKeys.Store(&keys)
+}
+
+// SetSensitiveKeys replaces the set of key fragments that mark a field as
+// sensitive. Matching is a case-insensitive substring test that treats
+// hyphens and underscores alike, so "token" also covers "access_token"
+// and "X-Auth-Token", and "api_key" covers "X-Api-Key". Calling it with no
+// arguments disables redaction.
+func SetSensitiveKeys(keys ...string) {
+	lowered := make([]string, len(keys))
+	for i, k := range keys {
+		lowered[i] = normalizeKey(k)
+	}
+	sensitiveKeys.Store(&lowered)
+}
+
+// SensitiveKeys returns a copy of the current sensitive key fragments.
+func SensitiveKeys() []string {
+	cur := *sensitiveKeys.Load()
+	out := make([]string, len(cur))
+	copy(out, cur)
+	return out
+}
+
+// IsSensitiveKey reports whether a field with this key must be redacted.
+func IsSensitiveKey(key string) bool {
+	lowered := normalizeKey(key)
+	for _, fragment := range *sensitiveKeys.Load() {
+		if fragment != "" && strings.Contains(lowered, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey lowers key and replaces its hyphens with underscores, the
+// form sensitive key fragments are matched in.
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
+
+// RedactFields returns a copy of fields with the values of sensitive keys
+// replaced by RedactedValue.
+func RedactFields(fields []Field) []Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	out := make([]Field, len(fields))
+	for i, f := range fields {
+		if lv, ok := f.Value.(leveledValue); ok && IsSensitiveKey(f.Key) {
+			lv.value = RedactedValue
+			f.Value = lv
+		} else if IsSensitiveKey(f.Key) {
+			f.Value = RedactedValue
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// Redacted returns a copy of the exception in which the values of
+// sensitive fields are replaced by RedactedValue, at every level of the
+// chain whose fields FieldsOf reports: Exceptions, annotations, and other
+// Errorers. Levels holding or wrapping sensitive fields that are not
+// Exceptions or annotations are replaced by stand-ins that keep their
+// text and their identity for errors.Is, but not for errors.As, which
+// would hand out the unredacted original.
+func (e Exception) Redacted() Exception {
+	e.fields = RedactFields(e.fields)
+	e.innerError = redactChain(e.innerError)
+	e.frozen = nil
+	return e
+}
+
+// redactChain redacts the fields of every level of err's chain.
+func redactChain(err error) error {
+	switch v := err.(type) {
+	case nil:
+		return nil
+	case Exception:
+		return v.Redacted()
+	case *annotation:
+		return &annotation{err: redactChain(v.err), op: v.op, fields: RedactFields(v.fields)}
+	}
+	if !hasSensitiveFields(err) {
+		return err
+	}
+	if e, ok := err.(Errorer); ok {
+		return &redactedErrorer{Errorer: e, inner: redactChain(e.Unwrap())}
+	}
+	return &redactedWrapper{orig: err, inner: redactChain(errors.Unwrap(err))}
+}
+
+// hasSensitiveFields reports whether any level of err's chain has a
+// sensitive field.
+func hasSensitiveFields(err error) bool {
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		var fields []Field
+		switch v := cur.(type) {
+		case *annotation:
+			fields = v.fields
+		case Exception:
+			fields = v.fields
+		case Errorer:
+			fields = v.Fields()
+		}
+		for _, f := range fields {
+			if IsSensitiveKey(f.Key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactedErrorer stands in for an Errorer other than Exception in a
+// redacted chain, reporting its fields redacted.
+type redactedErrorer struct {
+	Errorer
+	inner error
+}
+
+// Fields implements Errorer.
+func (r *redactedErrorer) Fields() []Field {
+	return RedactFields(r.Errorer.Fields())
+}
+
+// Unwrap implements Errorer.
+func (r *redactedErrorer) Unwrap() error {
+	return r.inner
+}
+
+// Is reports whether the original level is target.
+func (r *redactedErrorer) Is(target error) bool {
+	return isLevel(r.Errorer, target)
+}
+
+// CodeString reports the original's symbolic code, if it has one.
+func (r *redactedErrorer) CodeString() string {
+	cs, _ := codeStringOf(r.Errorer)
+	return cs
+}
+
+// redactedWrapper stands in for a wrapper of another type in a redacted
+// chain, with the same text, wrapping the redacted rest of the chain.
+type redactedWrapper struct {
+	orig  error
+	inner error
+}
+
+func (r *redactedWrapper) Error() string {
+	return r.orig.Error()
+}
+
+func (r *redactedWrapper) Unwrap() error {
+	return r.inner
+}
+
+// Is reports whether the original level is target.
+func (r *redactedWrapper) Is(target error) bool {
+	return isLevel(r.orig, target)
+}
+
+// isLevel reports whether err itself, rather than anything it wraps, is
+// target, as errors.Is decides for a single level.
+func isLevel(err, target error) bool {
+	if target != nil && reflect.TypeOf(target).Comparable() && err == target {
+		return true
+	}
+	if x, ok := err.(interface{ Is(error) bool }); ok {
+		return x.Is(target)
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSensitiveKey(t *testing.T) {
+	assert.True(t, ex.IsSensitiveKey("password"))
+	assert.True(t, ex.IsSensitiveKey("X-Auth-Token"))
+	assert.True(t, ex.IsSensitiveKey("db.Password"))
+	assert.False(t, ex.IsSensitiveKey("user_id"))
+}
+
+func TestSetSensitiveKeys(t *testing.T) {
+	saved := ex.SensitiveKeys()
+	t.Cleanup(func() { ex.SetSensitiveKeys(saved...) })
+
+	ex.SetSensitiveKeys("SSN")
+	assert.True(t, ex.IsSensitiveKey("customer_ssn"))
+	assert.False(t, ex.IsSensitiveKey("password"))
+
+	ex.SetSensitiveKeys()
+	assert.False(t, ex.IsSensitiveKey("customer_ssn"))
+}
+
+func TestRedactFields(t *testing.T) {
+	fields := []ex.Field{ex.F("user", "42"), ex.F("api_key", "abc123")}
+	redacted := ex.RedactFields(fields)
+
+	assert.Equal(t, []ex.Field{ex.F("user", "42"), ex.F("api_key", ex.RedactedValue)}, redacted)
+	assert.Equal(t, "abc123", fields[1].Value, "input must not be modified")
+	assert.Nil(t, ex.RedactFields(nil))
+}
+
+func TestException_Redacted(t *testing.T) {
+	inner := ex.New(ex.ExTypeLoginRequired, 401, "Login required").WithField("session_token", "t0k")
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Failed").
+		WithFields(ex.F("password", "p"), ex.F("user", "42")).
+		WithInnerError(inner)
+
+	redacted := exc.Redacted()
+
+	v, _ := redacted.FieldValue("password")
+	assert.Equal(t, ex.RedactedValue, v)
+	v, _ = redacted.FieldValue("user")
+	assert.Equal(t, "42", v)
+	v, _ = redacted.InnerError().(ex.Exception).FieldValue("session_token")
+	assert.Equal(t, ex.RedactedValue, v)
+
+	// The original is untouched.
+	v, _ = exc.FieldValue("password")
+	assert.Equal(t, "p", v)
+	v, _ = inner.FieldValue("session_token")
+	assert.Equal(t, "t0k", v)
+}
+
+// vaultError is an Errorer other than Exception, as a library might
+// define.
+type vaultError struct {
+	token string
+	inner error
+}
+
+func (v *vaultError) Error() string   { return "vault sealed" }
+func (v *vaultError) Code() ex.ExType { return ex.ExTypeUnavailable }
+func (v *vaultError) ID() int         { return 5032 }
+func (v *vaultError) Message() string { return "vault sealed" }
+func (v *vaultError) Fields() []ex.Field {
+	return []ex.Field{ex.F("vault_token", v.token), ex.F("vault", "primary")}
+}
+func (v *vaultError) StackTrace() []ex.Frame { return nil }
+func (v *vaultError) Unwrap() error          { return v.inner }
+
+func TestException_Redacted_ErrorerAndWrappers(t *testing.T) {
+	deep := ex.New(ex.ExTypeLoginRequired, 401, "Login required").WithField("session_token", "t0k")
+	cause := &vaultError{token: "s.hvs123", inner: fmt.Errorf("unsealing: %w", deep)}
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Failed").WithInnerError(fmt.Errorf("loading secrets: %w", cause))
+
+	redacted := exc.Redacted()
+
+	fields := map[string]any{}
+	for _, f := range ex.FieldsOf(redacted) {
+		fields[f.Key] = f.Value
+	}
+	assert.Equal(t, map[string]any{"vault_token": ex.RedactedValue, "vault": "primary", "session_token": ex.RedactedValue}, fields)
+	assert.Equal(t, exc.Error(), redacted.Error())
+	assert.ErrorIs(t, redacted, cause)
+	assert.ErrorIs(t, redacted, deep)
+
+	data, err := json.Marshal(redacted)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s.hvs123")
+	assert.NotContains(t, string(data), "t0k")
+
+	// Chains without sensitive fields are kept as they are.
+	plain := fmt.Errorf("loading secrets: %w", ex.New(ex.ExTypeNotFound, 4041, "No such secret"))
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "Failed").WithInnerError(plain)
+	assert.Same(t, plain, e.Redacted().InnerError())
+}
@@ -0,0 +1,64 @@
+package ex
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Registry is the serializable view of the metadata a process has
+// registered, returned by RegistrySnapshot for alerting-as-code pipelines
+// and other tooling that should follow what the binary actually does
+// rather than a hand-maintained copy.
+//
+// Service and Taxonomy are the service name (see SetServiceName) and
+// taxonomy version (see SetTaxonomyVersion) in effect. Entries are those
+// of DefaultCatalog, as in CatalogSnapshot.
+type Registry struct {
+	Service  string           `json:"service,omitempty"`
+	Taxonomy int              `json:"taxonomy,omitempty"`
+	Types    []RegisteredType `json:"types"`
+	Entries  []SnapshotEntry  `json:"entries"`
+}
+
+// RegisteredType is the metadata registered for one code (see
+// RegisterType), with the severity spelled out.
+type RegisteredType struct {
+	Code       ExType `json:"code"`
+	Type       string `json:"type"`
+	Severity   string `json:"severity,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	GRPCCode   uint32 `json:"grpc_code,omitempty"`
+	DocsURL    string `json:"docs_url,omitempty"`
+}
+
+// RegistrySnapshot returns every registered code, predefined ones
+// included, ordered by code, together with the default catalog's entries.
+// Encode it with encoding/json to export it from a running process:
+//
+//	mux.HandleFunc("/debug/errors/registry", func(w http.ResponseWriter, r *http.Request) {
+//	    json.NewEncoder(w).Encode(ex.RegistrySnapshot())
+//	})
+func RegistrySnapshot() Registry {
+	registered := *types.Load()
+	reg := Registry{
+		Service:  ServiceName(),
+		Taxonomy: TaxonomyVersion(),
+		Types:    make([]RegisteredType, 0, len(registered)),
+		Entries:  CatalogSnapshot().Entries,
+	}
+	for code, info := range registered {
+		t := RegisteredType{
+			Code:       code,
+			Type:       code.String(),
+			HTTPStatus: info.HTTPStatus,
+			GRPCCode:   info.GRPCCode,
+			DocsURL:    info.DocsURL,
+		}
+		if info.Severity != 0 {
+			t.Severity = info.Severity.String()
+		}
+		reg.Types = append(reg.Types, t)
+	}
+	slices.SortFunc(reg.Types, func(a, b RegisteredType) int { return cmp.Compare(a.Code, b.Code) })
+	return reg
+}
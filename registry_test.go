@@ -0,0 +1,50 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrySnapshot(t *testing.T) {
+	require.NoError(t, ex.RegisterType(ex.ExType(4701), ex.TypeInfo{
+		Name:       "QuotaExceeded",
+		Severity:   ex.SeverityCritical,
+		HTTPStatus: 429,
+		GRPCCode:   8,
+		DocsURL:    "https://docs.example.com/errors/quota",
+	}))
+
+	reg := ex.RegistrySnapshot()
+	assert.True(t, sortedByCode(reg.Types), "types are ordered by code")
+
+	byCode := map[ex.ExType]ex.RegisteredType{}
+	for _, rt := range reg.Types {
+		byCode[rt.Code] = rt
+	}
+	assert.Equal(t, ex.RegisteredType{
+		Code: ex.ExTypeNotFound, Type: "NotFound", Severity: "Warning", HTTPStatus: 404, GRPCCode: 5,
+	}, byCode[ex.ExTypeNotFound])
+	assert.Equal(t, ex.RegisteredType{
+		Code: 4701, Type: "QuotaExceeded", Severity: "Critical", HTTPStatus: 429, GRPCCode: 8,
+		DocsURL: "https://docs.example.com/errors/quota",
+	}, byCode[4701])
+	assert.Equal(t, ex.CatalogSnapshot().Entries, reg.Entries)
+
+	data, err := json.Marshal(reg)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `{"code":4701,"type":"QuotaExceeded","severity":"Critical","http_status":429,"grpc_code":8,"docs_url":"https://docs.example.com/errors/quota"}`)
+}
+
+// sortedByCode reports whether types are in increasing code order.
+func sortedByCode(types []ex.RegisteredType) bool {
+	for i := 1; i < len(types); i++ {
+		if types[i-1].Code >= types[i].Code {
+			return false
+		}
+	}
+	return true
+}
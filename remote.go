@@ -0,0 +1,93 @@
+package ex
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// serviceName is the name stamped on exceptions as their origin when they
+// are encoded; see SetServiceName.
+var serviceName atomic.Pointer[string]
+
+// SetServiceName sets the name of this service, which is recorded as the
+// "origin" of every locally created exception when it is encoded, so that
+// a downstream service decoding it can tell where it came from. An empty
+// name stops origins from being recorded.
+func SetServiceName(name string) {
+	serviceName.Store(&name)
+}
+
+// ServiceName returns the name set by SetServiceName, or "".
+func ServiceName() string {
+	if p := serviceName.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// Remote reports whether the exception was decoded from a serialized form
+// produced by another process, rather than created locally. Every
+// Exception level of a decoded chain is remote. A remote exception's
+// stack, if any, belongs to the process named by Origin, not this one.
+func (e Exception) Remote() bool {
+	return e.remote
+}
+
+// Origin returns the name of the service a remote exception was created
+// in, as recorded by that service's SetServiceName, or "" for local
+// exceptions and for remote ones whose origin was not recorded.
+func (e Exception) Origin() string {
+	return e.origin
+}
+
+// RemoteError stands in for an error other than an Exception in a chain
+// decoded from a serialized form. It keeps the message and the Go type
+// name the error had in the process that encoded it, such as "*pq.Error",
+// so debug output can still say what kind of error it was; the original
+// value and its methods are gone.
+//
+// Error prefixes the message with the type name, without its leading
+// "*", so %+v, Explain and a decoded chain's Error() read
+// "pq.Error: deadlock detected"; Message returns the message alone.
+type RemoteError struct {
+	typeName string
+	message  string
+}
+
+// Error implements the error interface.
+func (r *RemoteError) Error() string {
+	if r.typeName == "" {
+		return r.message
+	}
+	return strings.TrimPrefix(r.typeName, "*") + ": " + r.message
+}
+
+// Message returns the original error's message, without the type name.
+func (r *RemoteError) Message() string {
+	return r.message
+}
+
+// TypeName returns the Go type name of the original error, as printed by
+// the %T verb.
+func (r *RemoteError) TypeName() string {
+	return r.typeName
+}
+
+// plainTypeName returns the type name recorded for a non-Exception error,
+// or "" for the anonymous types of errors.New and fmt.Errorf, whose names
+// say nothing about the error.
+func plainTypeName(err error) string {
+	switch v := err.(type) {
+	case *RemoteError:
+		return v.typeName
+	case *redactedWrapper:
+		return plainTypeName(v.orig)
+	}
+	name := fmt.Sprintf("%T", err)
+	switch name {
+	case "*errors.errorString", "*fmt.wrapError", "*fmt.wrapErrors", "*errors.joinError":
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,126 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemote_DecodeMarksChain(t *testing.T) {
+	ex.SetServiceName("billing")
+	defer ex.SetServiceName("")
+
+	local := ex.New(ex.ExTypeApplicationFailure, 500, "Charge failed").
+		WithInnerError(ex.New(ex.ExTypeIncorrectData, 400, "Card expired"))
+	assert.False(t, local.Remote())
+	assert.Empty(t, local.Origin())
+
+	data, err := json.Marshal(local)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"origin":"billing"`)
+
+	// Decode as a different service and re-encode: the origin survives.
+	ex.SetServiceName("checkout")
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.Remote())
+	assert.Equal(t, "billing", decoded.Origin())
+
+	var inner ex.Exception
+	require.True(t, errors.As(decoded.InnerError(), &inner))
+	assert.True(t, inner.Remote())
+	assert.Equal(t, "billing", inner.Origin())
+
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.NotContains(t, string(again), "checkout")
+
+	// Wrapping a remote cause locally stamps only the new level.
+	wrapped, err := json.Marshal(ex.New(ex.ExTypeApplicationFailure, 502, "Upstream failed").
+		WithInnerError(decoded))
+	require.NoError(t, err)
+	var w ex.Exception
+	require.NoError(t, json.Unmarshal(wrapped, &w))
+	assert.Equal(t, "checkout", w.Origin())
+	require.True(t, errors.As(w.InnerError(), &inner))
+	assert.Equal(t, "billing", inner.Origin())
+
+	// Origins do not affect the fingerprint.
+	assert.Equal(t, local.Fingerprint(), decoded.Fingerprint())
+}
+
+func TestRemote_NoServiceName(t *testing.T) {
+	data, err := json.Marshal(ex.New(ex.ExTypeIncorrectData, 400, "x"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "origin")
+	assert.Empty(t, ex.ServiceName())
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.Remote())
+	assert.Empty(t, decoded.Origin())
+}
+
+func TestRemote_HTML(t *testing.T) {
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"code":4,"id":500,"message":"boom","origin":"billing","stack":[{"function":"main.f","file":"f.go","line":1}]}`),
+		&decoded))
+	html := string(ex.ToHTML(decoded))
+	assert.Contains(t, html, "ApplicationFailure / 500 (remote: billing)")
+	assert.Contains(t, html, "<summary>remote stack</summary>")
+}
+
+type deadlockError struct{}
+
+func (deadlockError) Error() string { return "deadlock detected" }
+
+func TestRemote_InnerErrorTypeName(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "Save failed").
+		WithInnerError(fmt.Errorf("tx: %w", &deadlockError{}))
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "go_type", "fmt.Errorf wrappers carry no useful name")
+
+	e = ex.New(ex.ExTypeApplicationFailure, 500, "Save failed").WithInnerError(&deadlockError{})
+	data, err = json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"go_type":"*ex_test.deadlockError"`)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "Save failed: ex_test.deadlockError: deadlock detected", decoded.Error())
+	var remote *ex.RemoteError
+	require.True(t, errors.As(decoded, &remote))
+	assert.Equal(t, "*ex_test.deadlockError", remote.TypeName())
+	assert.Equal(t, "ex_test.deadlockError: deadlock detected", remote.Error())
+	assert.Equal(t, "deadlock detected", remote.Message())
+	assert.Contains(t, fmt.Sprintf("%+v", decoded), "ex_test.deadlockError: deadlock detected")
+	assert.Contains(t, ex.Explain(decoded), "ex_test.deadlockError: deadlock detected")
+	assert.Contains(t, string(ex.ToHTML(decoded)), "*ex_test.deadlockError (remote)")
+
+	// The name survives re-encoding but not the canonical form.
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+	canonical, err := decoded.MarshalCanonical()
+	require.NoError(t, err)
+	assert.NotContains(t, string(canonical), "go_type")
+	assert.Equal(t, e.Fingerprint(), decoded.Fingerprint())
+}
+
+func TestRemote_PlainInnerErrorStaysPlain(t *testing.T) {
+	data, err := json.Marshal(ex.New(ex.ExTypeApplicationFailure, 500, "x").
+		WithInnerError(errors.New("boom")))
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	var remote *ex.RemoteError
+	assert.False(t, errors.As(decoded, &remote))
+	assert.Equal(t, "x: boom", decoded.Error())
+}
@@ -0,0 +1,136 @@
+package ex
+
+import (
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ellipsis marks text cut by RenderBudget.
+const ellipsis = "…"
+
+// RenderBudget writes e's chain to w in at most maxBytes bytes, for log
+// pipelines that bill by the byte or reject oversized records. It returns
+// the number of bytes written and any error from w.
+//
+// A chain that fits is written exactly as Error renders it. Otherwise
+// RenderBudget keeps what identifies the failure and gives up prose first:
+// every Exception level is written as its code and ID in brackets, and the
+// remaining budget is spent on messages, outermost first, with the last
+// message that only partly fits cut and marked with "…":
+//
+//	[NotFound/4041] Account not found: [ApplicationFailure/5001] Loading acc…: [ApplicationFailure/0]
+//
+// If even the codes do not fit, the innermost levels are replaced by "…".
+// Text is only ever cut at rune boundaries, and nothing is written for a
+// maxBytes of zero or less.
+func (e Exception) RenderBudget(w io.Writer, maxBytes int) (int, error) {
+	if maxBytes <= 0 {
+		return 0, nil
+	}
+	if e.Size() <= maxBytes {
+		return w.Write(e.AppendError(nil))
+	}
+	e.creation.observe()
+
+	type part struct {
+		tag, msg string
+		keep     int // bytes of msg kept
+	}
+	var parts []part
+	for cur := e; ; {
+		parts = append(parts, part{tag: "[" + cur.code.String() + "/" + strconv.Itoa(cur.id) + "]", msg: cur.message})
+		next, ok := nextLevel(cur.innerError)
+		if ok {
+			cur = next
+			continue
+		}
+		if cur.innerError != nil {
+			parts = append(parts, part{msg: cur.innerError.Error()})
+		}
+		break
+	}
+
+	// Codes first: the brackets of every level, with inner levels dropped
+	// behind an ellipsis until they fit.
+	levels := len(parts)
+	if parts[levels-1].tag == "" {
+		levels--
+	}
+	tagsLen := func(n int) int {
+		size := 0
+		for _, p := range parts[:n] {
+			size = joinedLen(size, len(p.tag))
+		}
+		if n < levels {
+			size += len(": " + ellipsis)
+		}
+		return size
+	}
+	n := levels
+	for n > 0 && tagsLen(n) > maxBytes {
+		n--
+	}
+	if n == 0 {
+		return io.WriteString(w, truncateUTF8(parts[0].tag, maxBytes))
+	}
+	used, cut := tagsLen(n), n < levels
+	if cut {
+		parts = parts[:n]
+	}
+
+	// Then messages, outermost first, with the tail error last.
+	for i := range parts {
+		if parts[i].msg == "" {
+			continue
+		}
+		cost := len(parts[i].msg) + 1 // "[tag] msg"
+		if parts[i].tag == "" {
+			cost = len(parts[i].msg) + 2 // ": msg"
+		}
+		if used+cost <= maxBytes {
+			parts[i].keep = len(parts[i].msg)
+			used += cost
+			continue
+		}
+		room := maxBytes - used - (cost - len(parts[i].msg)) - len(ellipsis)
+		if room > 0 {
+			parts[i].msg = truncateUTF8(parts[i].msg, room) + ellipsis
+			parts[i].keep = len(parts[i].msg)
+		}
+		break
+	}
+
+	buf := make([]byte, 0, maxBytes)
+	for _, p := range parts {
+		switch {
+		case p.tag == "" && p.keep == 0:
+			continue
+		case len(buf) > 0:
+			buf = append(buf, ": "...)
+		}
+		buf = append(buf, p.tag...)
+		if p.keep > 0 {
+			if p.tag != "" {
+				buf = append(buf, ' ')
+			}
+			buf = append(buf, p.msg[:p.keep]...)
+		}
+	}
+	if cut {
+		buf = append(buf, ": "+ellipsis...)
+	}
+	return w.Write(buf)
+}
+
+// truncateUTF8 returns the longest prefix of s of at most n bytes that
+// does not split a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
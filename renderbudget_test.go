@@ -0,0 +1,65 @@
+package ex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetChain() ex.Exception {
+	db := ex.New(ex.ExTypeApplicationFailure, 5001, "Loading account failed").
+		WithInnerError(errors.New("dial tcp 10.0.0.7:5432: connect: connection refused"))
+	return ex.New(ex.ExTypeNotFound, 4041, "Account not found").WithInnerError(db)
+}
+
+func renderBudget(t *testing.T, e ex.Exception, maxBytes int) string {
+	t.Helper()
+	var b strings.Builder
+	n, err := e.RenderBudget(&b, maxBytes)
+	require.NoError(t, err)
+	assert.Equal(t, b.Len(), n)
+	assert.LessOrEqual(t, n, max(maxBytes, 0))
+	assert.True(t, utf8.ValidString(b.String()))
+	return b.String()
+}
+
+func TestException_RenderBudget(t *testing.T) {
+	e := budgetChain()
+	full := e.Error()
+
+	tests := []struct {
+		name     string
+		maxBytes int
+		want     string
+	}{
+		{"fits", len(full), full},
+		{"roomy", 1000, full},
+		{"root cause cut", 90, "[NotFound/4041] Account not found: [ApplicationFailure/5001] Loading account failed: di…"},
+		{"inner message cut", 80, "[NotFound/4041] Account not found: [ApplicationFailure/5001] Loading account …"},
+		{"codes and outer message", 60, "[NotFound/4041] Account not found: [ApplicationFailure/5001]"},
+		{"outer message cut", 50, "[NotFound/4041] Acco…: [ApplicationFailure/5001]"},
+		{"codes only", 42, "[NotFound/4041]: [ApplicationFailure/5001]"},
+		{"inner levels dropped", 30, "[NotFound/4041] Accoun…: …"},
+		{"first code only", 20, "[NotFound/4041]: …"},
+		{"first code cut", 10, "[NotFound/"},
+		{"zero", 0, ""},
+		{"negative", -1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderBudget(t, e, tt.maxBytes))
+		})
+	}
+}
+
+func TestException_RenderBudget_RuneBoundary(t *testing.T) {
+	e := ex.New(ex.ExTypeNotFound, 1, strings.Repeat("é", 40))
+	got := renderBudget(t, e, 30)
+	assert.True(t, strings.HasPrefix(got, "[NotFound/1] é"))
+	assert.True(t, strings.HasSuffix(got, "…"))
+}
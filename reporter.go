@@ -0,0 +1,357 @@
+package ex
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default Reporter configuration values.
+const (
+	DefaultReportInterval = time.Minute
+	DefaultMaxGroups      = 1000
+	DefaultMaxTenants     = 100
+)
+
+// OverflowTenant is the tenant of the groups a PerTenant Reporter holds
+// for tenants beyond ReporterConfig.MaxTenants. Its batches mix tenants,
+// so route them to operators rather than to any tenant's dashboard.
+const OverflowTenant = "*"
+
+// ErrReporterShutdown is returned by Reporter.Flush after Shutdown.
+var ErrReporterShutdown = errors.New("ex: reporter is shut down")
+
+// SinkError is returned by a sink that delivered part of its batch, so
+// that the Reporter counts only the exceptions of Failed as dropped; any
+// other error from a sink drops the whole batch.
+type SinkError struct {
+	// Failed lists the aggregates that were not delivered.
+	Failed []Aggregate
+	// Err is the cause of the failures.
+	Err error
+}
+
+// Error returns the message of Err.
+func (e *SinkError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns Err.
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// Aggregate is one group of exceptions collected by a Reporter.
+type Aggregate struct {
+	// Key identifies the group; see ReporterConfig.Key.
+	Key string
+	// Fingerprint is the fingerprint of Sample; see
+	// Exception.Fingerprint.
+	Fingerprint string
+	// Sample is the first exception of the group, with its stack if one
+	// was captured.
+	Sample Exception
+	// Count is the number of exceptions reported in the group.
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Tenant is the tenant of the group's exceptions (see TenantOf), or
+	// "" if they have none.
+	Tenant string
+}
+
+// ReporterConfig configures a Reporter. Zero values select the defaults.
+type ReporterConfig struct {
+	// Sink receives the aggregates of each flush, in the order their
+	// groups were first seen. It is never called concurrently with
+	// itself, and never with an empty batch. A sink that delivers part of
+	// a batch returns a *SinkError listing the rest.
+	Sink func(ctx context.Context, batch []Aggregate) error
+
+	// FlushInterval is how often aggregates are flushed in the background.
+	FlushInterval time.Duration
+
+	// MaxGroups bounds the number of groups held between flushes; reports
+	// that would start a new group beyond it are dropped and counted.
+	MaxGroups int
+
+	// PerTenant isolates tenants (see WithTenant), for platforms that
+	// must not show one tenant's errors on another's dashboards: each
+	// flush calls Sink once per tenant, in the order the tenants were
+	// first seen, with a batch holding only that tenant's aggregates and
+	// a ctx carrying the tenant (see TenantFrom), and MaxGroups applies
+	// to each tenant separately, so a noisy tenant cannot crowd out the
+	// others. Errors without a tenant form a batch of their own.
+	//
+	// The tenant is read from the reported error's chain, so report
+	// errors where the tenant is known, tagged with WithTenant or
+	// TagTenant. Hooks run inside New, before either can tag the
+	// exception, so every report made through Hook has no tenant.
+	PerTenant bool
+
+	// MaxTenants bounds the number of tenants a PerTenant Reporter holds
+	// groups for between flushes, since tenant IDs come from requests and
+	// memory would otherwise grow with MaxGroups times their number.
+	// Errors of further tenants are grouped under OverflowTenant, which
+	// has MaxGroups of its own.
+	MaxTenants int
+
+	// Key returns the key exceptions are grouped by. Defaults to
+	// GroupKey, which leaves out messages and field values, so that an
+	// error carrying a request ID or a user name still forms one group.
+	Key func(Exception) string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// GroupKey returns the default grouping key of a Reporter: the type,
+// code, and ID of e, and the taxonomy version they belong to. Exceptions
+// that differ only in message, fields, or inner errors share it.
+func GroupKey(e Exception) string {
+	return e.code.String() + "/" + strconv.Itoa(int(e.code)) + "/" + strconv.Itoa(e.id) + "@" + strconv.Itoa(e.Taxonomy())
+}
+
+// Reporter deduplicates exceptions before they reach a sink, so a failure
+// repeated thousands of times becomes one record with a count. Exceptions
+// are grouped by key (see ReporterConfig.Key) and tenant, and flushed
+// periodically and on Shutdown. Report errors where they are handled, so
+// the samples carry their fields and inner errors, for instance from a
+// Router:
+//
+//	r := ex.NewReporter(ex.ReporterConfig{Sink: sendToTracker})
+//	defer r.Shutdown(context.Background())
+//	router.Route(ex.IsServerFault, ex.Report(r.Report))
+//
+// A Reporter is safe for concurrent use.
+type Reporter struct {
+	cfg     ReporterConfig
+	mu      sync.Mutex
+	groups  map[string]*Aggregate
+	order   []string
+	flushMu sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+	// closed is set, under mu, by the final flush of Shutdown; reports
+	// that find it set are dropped.
+	closed bool
+	// unregister removes the Reporter from the Shutdown registry.
+	unregister func()
+
+	// tenantGroups counts the groups of each tenant when PerTenant is set.
+	tenantGroups map[string]int
+}
+
+// NewReporter returns a Reporter for cfg and starts its background
+// flusher. Call its Shutdown method, or the package-level Shutdown, to
+// flush and stop it.
+func NewReporter(cfg ReporterConfig) *Reporter {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultReportInterval
+	}
+	if cfg.MaxGroups <= 0 {
+		cfg.MaxGroups = DefaultMaxGroups
+	}
+	if cfg.MaxTenants <= 0 {
+		cfg.MaxTenants = DefaultMaxTenants
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Key == nil {
+		cfg.Key = GroupKey
+	}
+	r := &Reporter{
+		cfg:          cfg,
+		groups:       make(map[string]*Aggregate),
+		tenantGroups: make(map[string]int),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	r.unregister = OnShutdown(r)
+	go r.run()
+	return r
+}
+
+// Report adds err to its group. Errors that are not exceptions are
+// promoted first (see Promote). Nil, quiet, and expected errors (see Quiet
+// and WithExpected) are ignored, and so are reports after Shutdown, which
+// are counted as dropped.
+func (r *Reporter) Report(err error) {
+	if err == nil || IsQuiet(err) || IsExpected(err) {
+		return
+	}
+	select {
+	case <-r.stop:
+		r.dropped.Add(1)
+		return
+	default:
+	}
+	e, ok := As(err)
+	if !ok {
+		e = Promote(err, nil)
+	}
+	key := r.cfg.Key(e)
+	tenant, _ := TenantOf(err)
+	now := r.cfg.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		r.dropped.Add(1)
+		return
+	}
+	if _, held := r.tenantGroups[tenant]; r.cfg.PerTenant && !held && len(r.tenantGroups) >= r.cfg.MaxTenants {
+		tenant = OverflowTenant
+	}
+	group := tenant + "\x00" + key
+	if g, found := r.groups[group]; found {
+		g.Count++
+		g.LastSeen = now
+		return
+	}
+	if r.cfg.PerTenant {
+		if r.tenantGroups[tenant] >= r.cfg.MaxGroups {
+			r.dropped.Add(1)
+			return
+		}
+		r.tenantGroups[tenant]++
+	} else if len(r.groups) >= r.cfg.MaxGroups {
+		r.dropped.Add(1)
+		return
+	}
+	r.groups[group] = &Aggregate{
+		Key:         key,
+		Fingerprint: e.Fingerprint(),
+		Sample:      e,
+		Count:       1,
+		FirstSeen:   now,
+		LastSeen:    now,
+		Tenant:      tenant,
+	}
+	r.order = append(r.order, group)
+}
+
+// Hook returns a Hook that reports every exception created by New. Hooks
+// run inside New, before the caller adds fields, inner errors, a tenant,
+// or the expected mark, so the samples of a hook-fed Reporter carry only
+// a code, ID, and message. Prefer Report at the boundary.
+func (r *Reporter) Hook() Hook {
+	return func(e Exception) { r.Report(e) }
+}
+
+// Dropped returns the number of reports rejected by MaxGroups or a
+// shut-down Reporter, plus the exceptions in batches the sink failed to
+// accept.
+func (r *Reporter) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Flush hands the current aggregates to the sink and starts new groups.
+// It returns the sink's error, in which case the batch is dropped; with
+// PerTenant, the errors of the failed batches, joined.
+func (r *Reporter) Flush(ctx context.Context) error {
+	select {
+	case <-r.done:
+		return ErrReporterShutdown
+	default:
+	}
+	return r.flush(ctx, false)
+}
+
+// Shutdown stops the background flusher and flushes what is left. Reports
+// made after Shutdown, including those racing with it that miss the final
+// flush, are dropped and counted. It is safe to call more than once.
+func (r *Reporter) Shutdown(ctx context.Context) error {
+	r.once.Do(func() {
+		close(r.stop)
+		r.unregister()
+	})
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return r.flush(ctx, true)
+}
+
+// flush swaps out the current groups and sends them to the sink. A final
+// flush closes the Reporter to further reports in the same critical
+// section, so no report can slip in after it.
+func (r *Reporter) flush(ctx context.Context, final bool) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	groups, order := r.groups, r.order
+	r.groups, r.order = make(map[string]*Aggregate, len(groups)), nil
+	clear(r.tenantGroups)
+	if final {
+		r.closed = true
+	}
+	r.mu.Unlock()
+
+	if len(order) == 0 || r.cfg.Sink == nil {
+		return nil
+	}
+	batch := make([]Aggregate, len(order))
+	for i, group := range order {
+		batch[i] = *groups[group]
+	}
+	if !r.cfg.PerTenant {
+		return r.send(ctx, batch)
+	}
+	var tenants []string
+	byTenant := map[string][]Aggregate{}
+	for _, a := range batch {
+		if _, seen := byTenant[a.Tenant]; !seen {
+			tenants = append(tenants, a.Tenant)
+		}
+		byTenant[a.Tenant] = append(byTenant[a.Tenant], a)
+	}
+	var errs []error
+	for _, tenant := range tenants {
+		if err := r.send(ContextWithTenant(ctx, tenant), byTenant[tenant]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send hands batch to the sink, counting its exceptions as dropped if the
+// sink fails, or those of the aggregates it reports failed (see SinkError).
+func (r *Reporter) send(ctx context.Context, batch []Aggregate) error {
+	if err := r.cfg.Sink(ctx, batch); err != nil {
+		var partial *SinkError
+		if errors.As(err, &partial) {
+			batch = partial.Failed
+		}
+		var lost uint64
+		for _, a := range batch {
+			lost += uint64(a.Count) // #nosec G115 -- counts are positive
+		}
+		r.dropped.Add(lost)
+		return err
+	}
+	return nil
+}
+
+// run is the background flush loop.
+func (r *Reporter) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = r.flush(context.Background(), false)
+		case <-r.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchSink records the batches a Reporter flushes.
+type batchSink struct {
+	mu      sync.Mutex
+	batches [][]ex.Aggregate
+	err     error
+}
+
+func (s *batchSink) sink(_ context.Context, batch []ex.Aggregate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, batch)
+	return s.err
+}
+
+func (s *batchSink) all() [][]ex.Aggregate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func TestReporter_Deduplicates(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var tick int
+	now := func() time.Time { tick++; return base.Add(time.Duration(tick) * time.Second) }
+
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour, Now: now})
+	defer r.Shutdown(context.Background())
+
+	timeout := ex.New(ex.ExTypeTimeout, 5041, "Ledger timed out").WithStack()
+	for range 3 {
+		r.Report(timeout)
+	}
+	r.Report(ex.New(ex.ExTypeNotFound, 4041, "No such order"))
+	r.Report(errors.New("plain"))
+	r.Report(nil)
+
+	require.NoError(t, r.Flush(context.Background()))
+	batches := s.all()
+	require.Len(t, batches, 1)
+	batch := batches[0]
+	require.Len(t, batch, 3)
+
+	assert.Equal(t, timeout.Fingerprint(), batch[0].Fingerprint)
+	assert.Equal(t, 3, batch[0].Count)
+	assert.Equal(t, base.Add(time.Second), batch[0].FirstSeen)
+	assert.Equal(t, base.Add(3*time.Second), batch[0].LastSeen)
+	assert.True(t, batch[0].Sample.HasStack())
+	assert.Equal(t, 1, batch[1].Count)
+	assert.Equal(t, "plain", batch[2].Sample.Error())
+
+	// Flushing starts new groups; an empty flush does not call the sink.
+	require.NoError(t, r.Flush(context.Background()))
+	assert.Len(t, s.all(), 1)
+	r.Report(timeout)
+	require.NoError(t, r.Flush(context.Background()))
+	require.Len(t, s.all(), 2)
+	assert.Equal(t, 1, s.all()[1][0].Count)
+}
+
+func TestReporter_GroupKey(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	defer r.Shutdown(context.Background())
+
+	for _, user := range []string{"ada", "grace", "linus"} {
+		r.Report(ex.New(ex.ExTypeNotFound, 4041, "No such user "+user).WithField("user", user))
+	}
+	require.NoError(t, r.Flush(context.Background()))
+	require.Len(t, s.all(), 1)
+	batch := s.all()[0]
+	require.Len(t, batch, 1, "messages and field values do not split groups")
+	assert.Equal(t, 3, batch[0].Count)
+	assert.Equal(t, "NotFound/7/4041@0", batch[0].Key)
+	assert.Equal(t, ex.GroupKey(batch[0].Sample), batch[0].Key)
+	assert.Equal(t, batch[0].Sample.Fingerprint(), batch[0].Fingerprint)
+
+	byMessage := &batchSink{}
+	custom := ex.NewReporter(ex.ReporterConfig{
+		Sink: byMessage.sink, FlushInterval: time.Hour,
+		Key: func(e ex.Exception) string { return e.Message() },
+	})
+	defer custom.Shutdown(context.Background())
+	custom.Report(ex.New(ex.ExTypeNotFound, 4041, "No such user"))
+	custom.Report(ex.New(ex.ExTypeNotFound, 4041, "No such order"))
+	require.NoError(t, custom.Flush(context.Background()))
+	assert.Len(t, byMessage.all()[0], 2)
+}
+
+func TestReporter_ShutdownRace(t *testing.T) {
+	var mu sync.Mutex
+	var delivered int
+	sink := func(_ context.Context, batch []ex.Aggregate) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, a := range batch {
+			delivered += a.Count
+		}
+		return nil
+	}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: sink, FlushInterval: time.Millisecond})
+
+	const reporters, reports = 8, 200
+	var wg sync.WaitGroup
+	for range reporters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range reports {
+				r.Report(ex.New(ex.ExTypeTimeout, 5041, "Ledger timed out"))
+			}
+		}()
+	}
+	time.Sleep(time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, reporters*reports, delivered+int(r.Dropped()), "every report is delivered or counted as dropped")
+}
+
+func TestReporter_MaxGroupsAndSinkErrors(t *testing.T) {
+	s := &batchSink{err: errors.New("tracker down")}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour, MaxGroups: 2})
+	defer r.Shutdown(context.Background())
+
+	r.Report(ex.New(ex.ExTypeNotFound, 1, "a"))
+	r.Report(ex.New(ex.ExTypeNotFound, 1, "a"))
+	r.Report(ex.New(ex.ExTypeNotFound, 2, "b"))
+	r.Report(ex.New(ex.ExTypeNotFound, 3, "c"))
+	assert.Equal(t, uint64(1), r.Dropped())
+
+	assert.EqualError(t, r.Flush(context.Background()), "tracker down")
+	assert.Equal(t, uint64(4), r.Dropped())
+}
+
+func TestReporter_PartialSinkError(t *testing.T) {
+	r := ex.NewReporter(ex.ReporterConfig{
+		Sink: func(_ context.Context, batch []ex.Aggregate) error {
+			return &ex.SinkError{Failed: batch[1:], Err: errors.New("tracker down")}
+		},
+		FlushInterval: time.Hour,
+	})
+	defer r.Shutdown(context.Background())
+
+	r.Report(ex.New(ex.ExTypeNotFound, 1, "a"))
+	r.Report(ex.New(ex.ExTypeNotFound, 1, "a"))
+	r.Report(ex.New(ex.ExTypeNotFound, 2, "b"))
+
+	err := r.Flush(context.Background())
+	assert.EqualError(t, err, "tracker down")
+	var partial *ex.SinkError
+	require.ErrorAs(t, err, &partial)
+	assert.Equal(t, uint64(1), r.Dropped(), "only the failed aggregate counts as dropped")
+}
+
+func TestReporter_IntervalAndShutdown(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: 5 * time.Millisecond})
+	remove := ex.AddHook(r.Hook())
+	defer remove()
+
+	_ = ex.New(ex.ExTypeUnavailable, 5031, "Reporter interval test")
+	assert.Eventually(t, func() bool { return len(s.all()) > 0 }, time.Second, time.Millisecond)
+
+	r.Report(ex.New(ex.ExTypeUnavailable, 5032, "Reporter shutdown test"))
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	var flushed []string
+	for _, b := range s.all() {
+		for _, a := range b {
+			flushed = append(flushed, a.Sample.Message())
+		}
+	}
+	assert.Contains(t, flushed, "Reporter interval test")
+	assert.Contains(t, flushed, "Reporter shutdown test")
+
+	before := r.Dropped()
+	r.Report(errors.New("late"))
+	assert.Equal(t, before+1, r.Dropped())
+	assert.ErrorIs(t, r.Flush(context.Background()), ex.ErrReporterShutdown)
+}
+
+func TestReporter_HookSamples(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	defer r.Shutdown(context.Background())
+	cause := errors.New("connection refused")
+
+	remove := ex.AddHook(r.Hook())
+	_ = ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").WithField("order", 42).WithInnerError(cause)
+	remove()
+	require.NoError(t, r.Flush(context.Background()))
+	require.Len(t, s.all(), 1)
+	sample := s.all()[0][0].Sample
+	assert.Empty(t, sample.Fields(), "hooks run before the caller adds context")
+	assert.NoError(t, sample.InnerError())
+
+	router := ex.NewRouter().Fallback(ex.Report(r.Report))
+	_ = router.Handle(ex.New(ex.ExTypeApplicationFailure, 5001, "Checkout failed").WithField("order", 42).WithInnerError(cause))
+	require.NoError(t, r.Flush(context.Background()))
+	require.Len(t, s.all(), 2)
+	sample = s.all()[1][0].Sample
+	assert.Equal(t, []ex.Field{ex.F("order", 42)}, sample.Fields())
+	assert.ErrorIs(t, sample, cause)
+}
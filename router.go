@@ -0,0 +1,156 @@
+package ex
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Action is one step of a Router route. It receives the error being
+// handled and returns the error the next action, and ultimately the
+// caller of Handle, sees. Observing actions return err unchanged; an action
+// returning nil ends the route.
+type Action func(err error) error
+
+// Router maps predicates to actions: a small policy engine that decides,
+// in one place, how each class of error in a service is logged, counted,
+// reported, or reclassified.
+//
+// Routes are tried in the order they were added and the first match wins;
+// errors matching no route go through the fallback actions, if any. A
+// Router is configured once at startup and is then safe for concurrent
+// use.
+type Router struct {
+	mu       sync.RWMutex
+	routes   []route
+	fallback []Action
+}
+
+type route struct {
+	match   Predicate
+	actions []Action
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Route adds a route that runs actions, in order, for errors matching p.
+// It returns r so routes can be chained.
+func (r *Router) Route(p Predicate, actions ...Action) *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route{match: p, actions: actions})
+	return r
+}
+
+// Fallback sets the actions run for errors that match no route.
+func (r *Router) Fallback(actions ...Action) *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = actions
+	return r
+}
+
+// Handle routes err and returns the error produced by the matching route's
+// actions. A nil err is returned as is without running any action.
+func (r *Router) Handle(err error) error {
+	if err == nil {
+		return nil
+	}
+	r.mu.RLock()
+	actions := r.fallback
+	for _, rt := range r.routes {
+		if rt.match(err) {
+			actions = rt.actions
+			break
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, a := range actions {
+		if err = a(err); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Hook returns a Hook that routes every exception created by New. The
+// error returned by the route is discarded, so reclassifying actions have
+// no effect there.
+func (r *Router) Hook() Hook {
+	return func(e Exception) { _ = r.Handle(e) }
+}
+
+// Log returns an Action that logs the error to logger at level, with the
+// code, ID, and fields of its outermost Exception as attributes. Sensitive
+// fields are redacted as in Exception.Redacted.
+func Log(logger *slog.Logger, level slog.Level) Action {
+	return func(err error) error {
+		logAttrs(logger, level, err, true)
+		return err
+	}
+}
+
+// LogProfile is Log with the error pruned by p first (see Prune), so the
+// profile decides what is logged: sensitive values are redacted only if
+// p.RedactFields is set. Errors p prunes away entirely are not logged.
+// The action still passes on the error in full.
+func LogProfile(logger *slog.Logger, level slog.Level, p Profile) Action {
+	return func(err error) error {
+		if pruned := Prune(err, p); pruned != nil {
+			logAttrs(logger, level, pruned, p.RedactFields)
+		}
+		return err
+	}
+}
+
+// logAttrs logs err to logger at level, redacting its fields if redact is
+// set.
+func logAttrs(logger *slog.Logger, level slog.Level, err error, redact bool) {
+	attrs := []slog.Attr{slog.String("error", err.Error())}
+	if e, ok := outermost(err); ok {
+		attrs = append(attrs, slog.String("code", e.Code().String()), slog.Int("id", e.ID()))
+		if cs, hasCS := codeStringOf(e); hasCS {
+			attrs = append(attrs, slog.String("code_string", cs))
+		}
+		fields := e.Fields()
+		if redact {
+			fields = RedactFields(fields)
+		}
+		for _, f := range fields {
+			if fl, leveled := f.Level(); leveled && !logger.Enabled(context.Background(), fl) {
+				continue
+			}
+			attrs = append(attrs, slog.Any(f.Key, ResolveValue(f.Value)))
+		}
+	}
+	logger.LogAttrs(context.Background(), level, "error", attrs...)
+}
+
+// Report returns an Action that passes the error to fn, for metrics,
+// error trackers, and other side effects, and continues with it unchanged.
+func Report(fn func(error)) Action {
+	return func(err error) error {
+		fn(err)
+		return err
+	}
+}
+
+// RethrowWith returns an Action that reclassifies the error, wrapping it
+// as the inner error of a new Exception with the given code, ID, and
+// message. The wrapper is a reclassification of an error that has already
+// been observed, so it does not run hooks.
+func RethrowWith(code ExType, id int, message string) Action {
+	return func(err error) error {
+		return Exception{code: code, id: id, message: message, innerError: err}
+	}
+}
+
+// Suppress returns an Action that drops the error: Handle returns nil and
+// no later action on the route runs.
+func Suppress() Action {
+	return func(error) error { return nil }
+}
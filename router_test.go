@@ -0,0 +1,118 @@
+package ex_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Handle(t *testing.T) {
+	var reported []error
+	r := ex.NewRouter().
+		Route(ex.IDIn(404), ex.Suppress()).
+		Route(ex.CodeIs(ex.ExTypeApplicationFailure),
+			ex.Report(func(err error) { reported = append(reported, err) }),
+			ex.RethrowWith(ex.ExTypeApplicationFailure, 503, "Service unavailable")).
+		Fallback(ex.Report(func(err error) { reported = append(reported, err) }))
+
+	assert.NoError(t, r.Handle(nil))
+	assert.NoError(t, r.Handle(ex.New(ex.ExTypeIncorrectData, 404, "missing")))
+
+	dbErr := ex.New(ex.ExTypeApplicationFailure, 500, "Database down")
+	got := r.Handle(dbErr)
+	var e ex.Exception
+	require.True(t, errors.As(got, &e))
+	assert.Equal(t, 503, e.ID())
+	assert.ErrorIs(t, got, ex.New(ex.ExTypeApplicationFailure, 500, ""))
+
+	plain := errors.New("plain")
+	assert.Equal(t, plain, r.Handle(plain))
+	assert.Equal(t, []error{dbErr, plain}, reported)
+}
+
+func TestRouter_FirstMatchWins(t *testing.T) {
+	var hits []string
+	mark := func(name string) ex.Action {
+		return ex.Report(func(error) { hits = append(hits, name) })
+	}
+	r := ex.NewRouter().
+		Route(ex.IDIn(400), mark("first")).
+		Route(ex.CodeIs(ex.ExTypeIncorrectData), mark("second"))
+
+	_ = r.Handle(ex.New(ex.ExTypeIncorrectData, 400, "x"))
+	_ = r.Handle(ex.New(ex.ExTypeIncorrectData, 401, "x"))
+	_ = r.Handle(ex.New(ex.ExTypeLoginRequired, 401, "unrouted"))
+	assert.Equal(t, []string{"first", "second"}, hits)
+}
+
+func TestRouter_HookAndLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	r := ex.NewRouter().
+		Route(ex.SeverityAtLeast(ex.SeverityError), ex.Log(logger, slog.LevelError),
+			ex.RethrowWith(ex.ExTypeApplicationFailure, 1, "ignored by hooks"))
+
+	remove := ex.AddHook(r.Hook())
+	defer remove()
+
+	ex.New(ex.ExTypeIncorrectData, 400, "not logged")
+	ex.New(ex.ExTypeApplicationFailure, 500, "Database down").WithField("table", "orders")
+	assert.Equal(t, "level=ERROR msg=error error=\"Database down\" code=ApplicationFailure id=500\n", buf.String())
+}
+
+func TestLog_RedactsSensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	r := ex.NewRouter().Fallback(ex.Log(logger, slog.LevelWarn))
+
+	err := ex.New(ex.ExTypeLoginRequired, 401, "Login failed").
+		WithField("user", "ada").
+		WithField("password", "hunter2")
+	_ = r.Handle(err)
+	assert.Contains(t, buf.String(), "user=ada")
+	assert.Contains(t, buf.String(), "password="+ex.RedactedValue)
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestLogProfile(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	err := ex.New(ex.ExTypeLoginRequired, 401, "Login failed").
+		WithField("user", "ada").
+		WithField("password", "hunter2")
+
+	r := ex.NewRouter().Fallback(ex.LogProfile(logger, slog.LevelWarn, ex.ProfileClient))
+	assert.Equal(t, error(err), r.Handle(err), "the error is passed on in full")
+	assert.Equal(t, `level=WARN msg=error error="Login failed" code=LoginRequired id=401`+"\n", buf.String())
+
+	buf.Reset()
+	r = ex.NewRouter().Fallback(ex.LogProfile(logger, slog.LevelWarn, ex.ProfileDebug))
+	_ = r.Handle(err)
+	assert.Contains(t, buf.String(), "password=hunter2")
+}
@@ -0,0 +1,81 @@
+package ex
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Shutdowner is a component holding buffered error telemetry, such as a
+// Reporter or an otlpx.Exporter, that must flush before the process
+// exits.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// shutdownEntry gives each registration its own identity, as hookEntry
+// does for hooks.
+type shutdownEntry struct {
+	s Shutdowner
+}
+
+var (
+	shutdownMu sync.Mutex
+	shutdowns  []*shutdownEntry
+)
+
+// OnShutdown registers s to be shut down by Shutdown and returns a
+// function that unregisters it. Reporters and otlpx exporters register
+// themselves when created and unregister when shut down directly.
+func OnShutdown(s Shutdowner) (remove func()) {
+	if s == nil {
+		return func() {}
+	}
+	entry := &shutdownEntry{s: s}
+	shutdownMu.Lock()
+	shutdowns = append(shutdowns, entry)
+	shutdownMu.Unlock()
+	return func() { removeShutdown(entry) }
+}
+
+// removeShutdown unregisters entry if it is still registered.
+func removeShutdown(entry *shutdownEntry) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	for i, e := range shutdowns {
+		if e == entry {
+			shutdowns = append(shutdowns[:i:i], shutdowns[i+1:]...)
+			return
+		}
+	}
+}
+
+// Shutdown flushes and stops every registered Shutdowner, so buffered
+// error telemetry is not lost when the process terminates. Call it on the
+// way out, with the deadline the platform allows:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	_ = ex.Shutdown(ctx)
+//
+// The Shutdowners run concurrently, all bounded by ctx, and are
+// unregistered. Shutdown waits for all of them and returns their errors
+// joined.
+func Shutdown(ctx context.Context) error {
+	shutdownMu.Lock()
+	pending := shutdowns
+	shutdowns = nil
+	shutdownMu.Unlock()
+
+	errs := make([]error, len(pending))
+	var wg sync.WaitGroup
+	wg.Add(len(pending))
+	for i, entry := range pending {
+		go func() {
+			defer wg.Done()
+			errs[i] = entry.s.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,51 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shutdownFunc adapts a function to ex.Shutdowner.
+type shutdownFunc func(context.Context) error
+
+func (f shutdownFunc) Shutdown(ctx context.Context) error { return f(ctx) }
+
+func TestShutdown(t *testing.T) {
+	var calls atomic.Int64
+	ok := shutdownFunc(func(context.Context) error { calls.Add(1); return nil })
+	failing := shutdownFunc(func(context.Context) error { calls.Add(1); return errors.New("flush failed") })
+	removed := shutdownFunc(func(context.Context) error { t.Error("removed Shutdowner was called"); return nil })
+
+	ex.OnShutdown(ok)
+	ex.OnShutdown(failing)
+	remove := ex.OnShutdown(removed)
+	remove()
+	remove()
+
+	assert.EqualError(t, ex.Shutdown(context.Background()), "flush failed")
+	assert.Equal(t, int64(2), calls.Load())
+
+	// Shutdowners are unregistered once shut down.
+	require.NoError(t, ex.Shutdown(context.Background()))
+	assert.Equal(t, int64(2), calls.Load())
+}
+
+func TestShutdown_FlushesReporters(t *testing.T) {
+	s := &batchSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour})
+	r.Report(ex.New(ex.ExTypeUnavailable, 5033, "Pending at shutdown"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, ex.Shutdown(ctx))
+	require.Len(t, s.all(), 1)
+	assert.Equal(t, "Pending at shutdown", s.all()[0][0].Sample.Message())
+	assert.ErrorIs(t, r.Flush(ctx), ex.ErrReporterShutdown)
+}
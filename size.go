@@ -0,0 +1,139 @@
+package ex
+
+import "slices"
+
+// Size returns the length in bytes of e.Error(), so log encoders can plan
+// their buffers without rendering the message first.
+//
+// Exceptions built with New, NewTyped, WrapAuto, or WithInnerError record
+// the length when they are built, which makes Size a field read and lets
+// Error render a whole chain in a single pass with one allocation. For
+// others, such as decoded or promoted exceptions, Size measures the chain
+// on each call. Either way the inner errors' own Error methods are assumed
+// to return the same text every time.
+func (e Exception) Size() int {
+	if e.frozen != nil {
+		return len(e.frozen.text())
+	}
+	size := e.plainSize()
+	if tagged() {
+		size = joinedTag(e.tagLen(), size)
+	}
+	return size
+}
+
+// plainSize returns the length of e.Error() under FormatPlain.
+func (e Exception) plainSize() int {
+	if e.sizeHint > 0 {
+		return e.sizeHint - 1
+	}
+	size, _ := e.measure()
+	return size
+}
+
+// joinedTag is the length of a tag followed by text of length n, as
+// FormatTagged renders them.
+func joinedTag(tag, n int) int {
+	if n > 0 {
+		return tag + 1 + n
+	}
+	return tag
+}
+
+// AppendError appends the text of e.Error() to dst and returns the
+// extended buffer. It grows dst at most once, by Size, and never builds
+// the intermediate string, which suits log encoders writing into a reused
+// buffer.
+func (e Exception) AppendError(dst []byte) []byte {
+	e.creation.observe()
+	if e.frozen != nil {
+		return append(dst, e.frozen.text()...)
+	}
+	size, tail, measured := e.sizeHint-1, "", false
+	if e.sizeHint == 0 {
+		size, tail = e.measure()
+		measured = true
+	}
+	isTagged := tagged()
+	if isTagged {
+		size = joinedTag(e.tagLen(), size)
+	}
+	dst = slices.Grow(dst, size)
+	if isTagged {
+		dst = e.appendTag(dst)
+	}
+	start := len(dst)
+	e.segments(tail, measured, func(s string) {
+		switch {
+		case len(dst) > start:
+			dst = append(dst, ": "...)
+		case isTagged:
+			dst = append(dst, ' ')
+			start++
+		}
+		dst = append(dst, s...)
+	})
+	return dst
+}
+
+// sizer is implemented by the error types of this package that can
+// report their rendered length without rendering.
+type sizer interface {
+	errorSize() int
+}
+
+// errorSize implements sizer. Exceptions nested in a chain render their
+// messages only, so the tag of FormatTagged is not counted.
+func (e Exception) errorSize() int {
+	return e.plainSize()
+}
+
+// errorSize implements sizer; an annotation renders as the error it
+// wraps.
+func (a *annotation) errorSize() int {
+	if s, ok := a.err.(sizer); ok {
+		return s.errorSize()
+	}
+	return len(a.err.Error())
+}
+
+// sizeHintOf returns the sizeHint of an exception with the given message
+// and inner error.
+func sizeHintOf(message string, inner error) int {
+	innerSize := 0
+	switch v := inner.(type) {
+	case nil:
+	case sizer:
+		innerSize = v.errorSize()
+	default:
+		innerSize = len(v.Error())
+	}
+	return joinedLen(len(message), innerSize) + 1
+}
+
+// joinedLen is the length of the ": "-join of two parts, either of which
+// may be empty, as the formatting rules of Error join them.
+func joinedLen(a, b int) int {
+	if a > 0 && b > 0 {
+		return a + 2 + b
+	}
+	return a + b
+}
+
+// measure walks e's chain and returns the length Error renders, together
+// with the text of the first non-Exception error in the chain, if any.
+func (e Exception) measure() (int, string) {
+	size := 0
+	for cur := e; ; {
+		size = joinedLen(size, len(cur.message))
+		next, ok := nextLevel(cur.innerError)
+		if !ok {
+			var tail string
+			if cur.innerError != nil {
+				tail = cur.innerError.Error()
+			}
+			return joinedLen(size, len(tail)), tail
+		}
+		cur = next
+	}
+}
@@ -0,0 +1,61 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sizeCases(t *testing.T) map[string]ex.Exception {
+	t.Helper()
+	root := errors.New("connection refused")
+	chain := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").
+		WithInnerError(ex.New(ex.ExTypeApplicationFailure, 500, "").
+			WithInnerError(ex.Annotate(ex.New(ex.ExTypeUnavailable, 503, "Query failed").WithInnerError(root))))
+
+	data, err := json.Marshal(chain)
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	return map[string]ex.Exception{
+		"bare":       ex.New(ex.ExTypeIncorrectData, 400, "Bad input"),
+		"empty":      ex.New(ex.ExTypeIncorrectData, 400, ""),
+		"plainInner": ex.New(ex.ExTypeIncorrectData, 400, "Bad input").WithInnerError(root),
+		"emptyInner": ex.New(ex.ExTypeIncorrectData, 400, "Bad input").WithInnerError(errors.New("")),
+		"chain":      chain,
+		"decoded":    decoded,
+		"promoted":   ex.Promote(fmt.Errorf("loading: %w", root), nil),
+		"compacted":  ex.Compact(chain),
+		"wrapAuto":   ex.WrapAuto(fmt.Errorf("repo: %w", chain), 1, "Loading failed"),
+		"panic":      ex.FromPanic("boom"),
+	}
+}
+
+func TestException_Size(t *testing.T) {
+	for name, e := range sizeCases(t) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, len(e.Error()), e.Size())
+		})
+	}
+}
+
+func TestException_AppendError(t *testing.T) {
+	for name, e := range sizeCases(t) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, "error="+e.Error(), string(e.AppendError([]byte("error="))))
+		})
+	}
+
+	e := sizeCases(t)["chain"]
+	buf := make([]byte, 0, 256)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = e.AppendError(buf[:0])
+	})
+	assert.Zero(t, allocs)
+}
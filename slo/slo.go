@@ -0,0 +1,230 @@
+// Package slo derives application-level error-budget signals from
+// exceptions.
+//
+// A Tracker counts operations (via Op) and system failures (via Report,
+// where errors are handled) in a sliding time window and reports how
+// much of the error budget implied by the service-level objective has
+// been burned:
+//
+//	tracker := slo.New(slo.Config{Objective: 0.999, Window: time.Hour})
+//
+//	func handle(...) {
+//	    tracker.Op()
+//	    if err := serve(...); err != nil {
+//	        tracker.Report(ex.TagTenant(ctx, err))
+//	    }
+//	}
+//
+//	if tracker.Burned() > 1 { /* failing faster than the SLO allows */ }
+package slo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bold-minds/ex"
+)
+
+// Default configuration values.
+const (
+	DefaultObjective = 0.999
+	DefaultWindow    = time.Hour
+	DefaultBuckets   = 60
+)
+
+// Config configures a Tracker. Zero values select the defaults.
+type Config struct {
+	// Objective is the target success ratio, e.g. 0.999 for "three nines".
+	// It must be in (0, 1); anything else selects DefaultObjective.
+	Objective float64
+
+	// Window is the length of the sliding window.
+	Window time.Duration
+
+	// Buckets is the number of sub-intervals the window is divided into.
+	// More buckets make the window slide more smoothly.
+	Buckets int
+
+	// IsFailure reports whether an exception counts against the budget.
+	// The default counts ExTypeApplicationFailure, ExTypeTimeout, and
+	// ExTypeUnavailable only: user errors such as bad input or missing
+	// permissions are not the service's fault.
+	IsFailure func(ex.Exception) bool
+
+	// Tenant, if set, restricts the failures counted to exceptions of
+	// that tenant (see ex.WithTenant), for a budget per tenant: keep one
+	// Tracker per tenant and call Op on the tenant's own. Feed such a
+	// Tracker with Report: hooks run before an exception is tagged, so
+	// its hook sees no tenant and counts nothing.
+	Tenant string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// bucket accumulates counts for one sub-interval of the window.
+type bucket struct {
+	start    time.Time
+	ops      uint64
+	failures uint64
+}
+
+// Tracker accumulates operations and failures over a sliding window. It is
+// safe for concurrent use.
+type Tracker struct {
+	cfg   Config
+	width time.Duration
+
+	mu      sync.Mutex
+	buckets []bucket
+}
+
+// New returns a Tracker for cfg.
+func New(cfg Config) *Tracker {
+	if cfg.Objective <= 0 || cfg.Objective >= 1 {
+		cfg.Objective = DefaultObjective
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = DefaultBuckets
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isServiceFailure
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	width := cfg.Window / time.Duration(cfg.Buckets)
+	if width <= 0 {
+		width = 1
+	}
+	return &Tracker{cfg: cfg, width: width, buckets: make([]bucket, cfg.Buckets)}
+}
+
+// isServiceFailure is the default failure classifier.
+func isServiceFailure(e ex.Exception) bool {
+	switch e.Code() {
+	case ex.ExTypeApplicationFailure, ex.ExTypeTimeout, ex.ExTypeUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// Hook returns an ex.Hook that feeds created exceptions into the tracker.
+// Hooks run inside New, before the caller can tag the exception with a
+// tenant or mark it expected, so a tracker with Config.Tenant set counts
+// nothing through its hook, and expected errors are counted; use Report
+// for either.
+func (t *Tracker) Hook() ex.Hook {
+	return t.Observe
+}
+
+// Attach registers the tracker's hook with ex.AddHook and returns the
+// function that removes it.
+func (t *Tracker) Attach() (remove func()) {
+	return ex.AddHook(t.Hook())
+}
+
+// Op records one operation. Call it once per unit of work (request, job,
+// message) so that the failure ratio has a denominator.
+func (t *Tracker) Op() {
+	t.mu.Lock()
+	t.current().ops++
+	t.mu.Unlock()
+}
+
+// Observe records e as a failure if it counts against the budget. Quiet
+// and expected exceptions (see ex.Quiet and Exception.WithExpected) never
+// do, and neither do those of other tenants when Config.Tenant is set.
+func (t *Tracker) Observe(e ex.Exception) {
+	t.observe(e, e)
+}
+
+// Report records err as Observe does, reading its tenant and expected
+// mark anywhere in its chain, so errors tagged with ex.TagTenant count
+// for their tenant. Errors that are not exceptions are promoted first
+// (see ex.Promote). Report has the signature ex.Report takes, for
+// routes:
+//
+//	router.Route(ex.IsServerFault, ex.Report(tracker.Report))
+func (t *Tracker) Report(err error) {
+	if err == nil {
+		return
+	}
+	e, ok := ex.As(err)
+	if !ok {
+		e = ex.Promote(err, nil)
+	}
+	t.observe(e, err)
+}
+
+// observe records e, which err is or wraps, as a failure if it counts
+// against the budget.
+func (t *Tracker) observe(e ex.Exception, err error) {
+	if ex.IsQuiet(err) || ex.IsExpected(err) || !t.cfg.IsFailure(e) {
+		return
+	}
+	if tenant, _ := ex.TenantOf(err); t.cfg.Tenant != "" && tenant != t.cfg.Tenant {
+		return
+	}
+	t.mu.Lock()
+	t.current().failures++
+	t.mu.Unlock()
+}
+
+// Ratio returns the fraction of operations in the window that failed. It
+// returns 0 when no operations have been recorded.
+func (t *Tracker) Ratio() float64 {
+	ops, failures := t.totals()
+	if ops == 0 {
+		return 0
+	}
+	return float64(failures) / float64(ops)
+}
+
+// Burned returns the failure ratio as a multiple of the error budget
+// (1 - Objective). A value of 1 means failures are consuming the budget
+// exactly as fast as the objective allows; above 1 the budget will run out
+// before the window does.
+func (t *Tracker) Burned() float64 {
+	return t.Ratio() / (1 - t.cfg.Objective)
+}
+
+// Counts returns the number of operations and failures in the window.
+func (t *Tracker) Counts() (ops, failures uint64) {
+	return t.totals()
+}
+
+// totals sums the buckets that are still inside the window.
+func (t *Tracker) totals() (ops, failures uint64) {
+	now := t.cfg.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.buckets {
+		b := &t.buckets[i]
+		if b.start.IsZero() || now.Sub(b.start) >= t.cfg.Window {
+			continue
+		}
+		ops += b.ops
+		failures += b.failures
+	}
+	return ops, failures
+}
+
+// current returns the bucket for the current time, recycling it if it
+// last held an older interval. t.mu must be held.
+func (t *Tracker) current() *bucket {
+	start := t.cfg.Now().Truncate(t.width)
+	idx := int((start.UnixNano() / int64(t.width)) % int64(len(t.buckets)))
+	if idx < 0 {
+		idx += len(t.buckets)
+	}
+	b := &t.buckets[idx]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	return b
+}
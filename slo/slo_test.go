@@ -0,0 +1,172 @@
+package slo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/slo"
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a manually advanced time source.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newClock() *clock {
+	return &clock{now: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)}
+}
+
+func TestTracker_Burned(t *testing.T) {
+	c := newClock()
+	tracker := slo.New(slo.Config{Objective: 0.99, Window: time.Minute, Buckets: 6, Now: c.Now})
+
+	for range 100 {
+		tracker.Op()
+	}
+	tracker.Observe(ex.New(ex.ExTypeApplicationFailure, 500, "boom"))
+	tracker.Observe(ex.New(ex.ExTypeIncorrectData, 400, "user error, not counted"))
+
+	ops, failures := tracker.Counts()
+	assert.Equal(t, uint64(100), ops)
+	assert.Equal(t, uint64(1), failures)
+	assert.InDelta(t, 0.01, tracker.Ratio(), 1e-9)
+	// 1% failures against a 1% budget: burning exactly at the SLO rate.
+	assert.InDelta(t, 1.0, tracker.Burned(), 1e-9)
+}
+
+func TestTracker_WindowSlides(t *testing.T) {
+	c := newClock()
+	tracker := slo.New(slo.Config{Objective: 0.9, Window: time.Minute, Buckets: 6, Now: c.Now})
+
+	tracker.Op()
+	tracker.Observe(ex.New(ex.ExTypeApplicationFailure, 500, "old failure"))
+
+	c.Advance(30 * time.Second)
+	tracker.Op()
+	assert.InDelta(t, 0.5, tracker.Ratio(), 1e-9)
+
+	// The first bucket has aged out of the window; the second has not.
+	c.Advance(35 * time.Second)
+	ops, failures := tracker.Counts()
+	assert.Equal(t, uint64(1), ops)
+	assert.Equal(t, uint64(0), failures)
+	assert.Zero(t, tracker.Burned())
+
+	c.Advance(time.Hour)
+	ops, _ = tracker.Counts()
+	assert.Zero(t, ops)
+	assert.Zero(t, tracker.Ratio())
+}
+
+func TestTracker_Attach(t *testing.T) {
+	c := newClock()
+	tracker := slo.New(slo.Config{Now: c.Now})
+	remove := tracker.Attach()
+
+	tracker.Op()
+	tracker.Op()
+	ex.New(ex.ExTypeApplicationFailure, 500, "created through ex.New")
+	remove()
+	ex.New(ex.ExTypeApplicationFailure, 500, "after removal")
+
+	_, failures := tracker.Counts()
+	assert.Equal(t, uint64(1), failures)
+	assert.InDelta(t, 0.5/(1-slo.DefaultObjective), tracker.Burned(), 1e-6)
+}
+
+func TestTracker_CustomFailure(t *testing.T) {
+	tracker := slo.New(slo.Config{
+		IsFailure: func(e ex.Exception) bool { return e.ID() >= 500 },
+	})
+	tracker.Op()
+	tracker.Observe(ex.New(ex.ExTypeIncorrectData, 503, "counted by ID"))
+
+	_, failures := tracker.Counts()
+	assert.Equal(t, uint64(1), failures)
+}
+
+func TestTracker_SkipsQuiet(t *testing.T) {
+	tracker := slo.New(slo.Config{Now: newClock().Now})
+
+	tracker.Op()
+	tracker.Observe(ex.Quiet(ex.New(ex.ExTypeUnavailable, 5034, "Probe failed")))
+	_, failures := tracker.Counts()
+	assert.Zero(t, failures)
+}
+
+func TestTracker_SkipsExpected(t *testing.T) {
+	tracker := slo.New(slo.Config{Now: newClock().Now})
+
+	tracker.Op()
+	tracker.Observe(ex.New(ex.ExTypeUnavailable, 5035, "Lock held, retrying").WithExpected(true))
+	_, failures := tracker.Counts()
+	assert.Zero(t, failures)
+}
+
+func TestTracker_Tenant(t *testing.T) {
+	tracker := slo.New(slo.Config{Tenant: "acme"})
+	tracker.Op()
+	tracker.Observe(ex.New(ex.ExTypeApplicationFailure, 500, "boom").WithTenant("acme"))
+	tracker.Observe(ex.New(ex.ExTypeApplicationFailure, 500, "boom").WithTenant("globex"))
+	tracker.Observe(ex.New(ex.ExTypeApplicationFailure, 500, "boom"))
+
+	_, failures := tracker.Counts()
+	assert.Equal(t, uint64(1), failures)
+}
+
+func TestTracker_ReportTenant(t *testing.T) {
+	ctx := ex.ContextWithTenant(context.Background(), "acme")
+	tracker := slo.New(slo.Config{Tenant: "acme"})
+	remove := tracker.Attach()
+	tracker.Op()
+	err := ex.New(ex.ExTypeApplicationFailure, 500, "boom")
+	remove()
+
+	_, failures := tracker.Counts()
+	assert.Zero(t, failures, "the hook runs before the tenant is tagged")
+
+	tracker.Report(ex.TagTenant(ctx, err))
+	tracker.Report(ex.TagTenant(ex.ContextWithTenant(context.Background(), "globex"), err))
+	tracker.Report(ex.TagTenant(ctx, errors.New("plain")))
+	tracker.Report(nil)
+	_, failures = tracker.Counts()
+	assert.Equal(t, uint64(2), failures)
+
+	router := ex.NewRouter().Fallback(ex.Report(tracker.Report))
+	_ = router.Handle(ex.TagTenant(ctx, err))
+	_, failures = tracker.Counts()
+	assert.Equal(t, uint64(3), failures)
+}
+
+func TestTracker_ExpectedThroughHook(t *testing.T) {
+	tracker := slo.New(slo.Config{})
+	remove := tracker.Attach()
+	tracker.Op()
+	miss := ex.New(ex.ExTypeUnavailable, 5035, "Lock held, retrying").WithExpected(true)
+	remove()
+
+	_, failures := tracker.Counts()
+	assert.Equal(t, uint64(1), failures, "hooks run before the mark is set")
+
+	tracker.Report(miss)
+	_, failures = tracker.Counts()
+	assert.Equal(t, uint64(1), failures, "Report skips expected errors")
+}
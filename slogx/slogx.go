@@ -0,0 +1,101 @@
+// Package slogx logs exceptions with log/slog at the level their severity
+// calls for, so call sites do not have to decide: user errors land at
+// Warn and application failures at Error (see ex.LogLevel).
+//
+//	if err := svc.Checkout(ctx, cart); err != nil {
+//	    slogx.Log(ctx, logger, "checkout failed", err, "cart", cart.ID)
+//	}
+//
+// Routers can use the same behavior with Action:
+//
+//	router := ex.NewRouter().Fallback(slogx.Action(logger))
+package slogx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bold-minds/ex"
+)
+
+// Attrs returns the attributes describing err: its message under
+// "error", the code, ID, and symbolic code (if any) of its outermost
+// Exception under "code", "id", and "code_string", and every field in its
+// chain (see ex.FieldsOf) under its own key, except those made with
+// ex.FieldAt, which need a level to be rendered. Sensitive fields are
+// redacted as in ex.Exception.Redacted.
+func Attrs(err error) []slog.Attr {
+	return attrs(err, nil, true)
+}
+
+// attrs is Attrs keeping the fields made with ex.FieldAt for levels that
+// enabled reports as enabled, and redacting sensitive fields only if
+// redact is set. A nil enabled keeps none of them.
+func attrs(err error, enabled func(slog.Level) bool, redact bool) []slog.Attr {
+	if err == nil {
+		return nil
+	}
+	attrs := []slog.Attr{slog.String("error", err.Error())}
+	if e, ok := ex.AsErrorer(err); ok {
+		attrs = append(attrs, slog.String("code", e.Code().String()), slog.Int("id", e.ID()))
+		if cs, hasCS := ex.CodeStringOf(err); hasCS {
+			attrs = append(attrs, slog.String("code_string", cs))
+		}
+	}
+	fields := ex.FieldsOf(err)
+	if redact {
+		fields = ex.RedactFields(fields)
+	}
+	for _, f := range fields {
+		if level, leveled := f.Level(); leveled && (enabled == nil || !enabled(level)) {
+			continue
+		}
+		attrs = append(attrs, slog.Any(f.Key, ex.ResolveValue(f.Value)))
+	}
+	return attrs
+}
+
+// Log logs msg and err's attributes, followed by args, at
+// ex.LogLevel(err). args are key-value pairs or slog.Attr values, as for
+// slog.Logger.Log. Fields made with ex.FieldAt are included only if logger
+// is enabled at their level, so verbose context costs nothing in
+// production logs. The tenant carried by ctx is logged if err has none
+// (see ex.TagTenant).
+func Log(ctx context.Context, logger *slog.Logger, msg string, err error, args ...any) {
+	log(ctx, logger, msg, err, ex.LogLevel(err), true, args)
+}
+
+// LogProfile is Log with err pruned by p first (see ex.Prune), so the
+// profile decides what is logged: its fields, with sensitive values
+// redacted only if p.RedactFields is set, and its messages. The level is
+// still that of err. Nothing is logged if p prunes err away.
+func LogProfile(ctx context.Context, logger *slog.Logger, msg string, err error, p ex.Profile, args ...any) {
+	level := ex.LogLevel(err)
+	if err = ex.Prune(err, p); err == nil {
+		return
+	}
+	log(ctx, logger, msg, err, level, p.RedactFields, args)
+}
+
+// log logs err at level, redacting its fields if redact is set.
+func log(ctx context.Context, logger *slog.Logger, msg string, err error, level slog.Level, redact bool, args []any) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	err = ex.TagTenant(ctx, err)
+	all := make([]any, 0, len(args)+4)
+	for _, a := range attrs(err, func(l slog.Level) bool { return logger.Enabled(ctx, l) }, redact) {
+		all = append(all, a)
+	}
+	all = append(all, args...)
+	logger.Log(ctx, level, msg, all...)
+}
+
+// Action returns an ex.Action that logs the error with Log under the
+// message "error" and passes it on unchanged.
+func Action(logger *slog.Logger) ex.Action {
+	return func(err error) error {
+		Log(context.Background(), logger, "error", err)
+		return err
+	}
+}
@@ -0,0 +1,124 @@
+package slogx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/slogx"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLogger returns a text logger without timestamps writing to buf.
+func newLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf, slog.LevelDebug)
+	ctx := context.Background()
+
+	badInput := ex.Annotate(ex.New(ex.ExTypeIncorrectData, 400, "Invalid email"), ex.F("field", "email"))
+	slogx.Log(ctx, logger, "signup failed", badInput, "attempt", 2)
+	slogx.Log(ctx, logger, "db failed", ex.New(ex.ExTypeApplicationFailure, 500, "Database down"))
+	slogx.Log(ctx, logger, "plain", errors.New("EOF"))
+
+	assert.Equal(t,
+		`level=WARN msg="signup failed" error="Invalid email" code=IncorrectData id=400 field=email attempt=2`+"\n"+
+			`level=ERROR msg="db failed" error="Database down" code=ApplicationFailure id=500`+"\n"+
+			`level=ERROR msg=plain error=EOF`+"\n",
+		buf.String())
+}
+
+func TestLog_RespectsLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf, slog.LevelError)
+
+	slogx.Log(context.Background(), logger, "ignored", ex.New(ex.ExTypeIncorrectData, 400, "bad input"))
+	assert.Empty(t, buf.String())
+}
+
+func TestAction(t *testing.T) {
+	var buf bytes.Buffer
+	router := ex.NewRouter().Fallback(slogx.Action(newLogger(&buf, slog.LevelInfo)))
+
+	err := ex.New(ex.ExTypeLoginRequired, 401, "Login required")
+	assert.Equal(t, err.Error(), router.Handle(err).Error())
+	assert.Equal(t, `level=WARN msg=error error="Login required" code=LoginRequired id=401`+"\n", buf.String())
+}
+
+func TestAttrs(t *testing.T) {
+	assert.Nil(t, slogx.Attrs(nil))
+	assert.Equal(t, []slog.Attr{slog.String("error", "EOF")}, slogx.Attrs(errors.New("EOF")))
+
+	e := ex.New(ex.ExTypeIncorrectData, 4021, "Card declined").WithCodeString("BILLING_CARD_DECLINED")
+	assert.Contains(t, slogx.Attrs(e), slog.String("code_string", "BILLING_CARD_DECLINED"))
+}
+
+func TestLog_FieldAt(t *testing.T) {
+	err := ex.New(ex.ExTypeApplicationFailure, 500, "Query failed").
+		WithFields(ex.F("table", "orders"), ex.FieldAt(slog.LevelDebug, "query", "SELECT 1"))
+
+	var buf bytes.Buffer
+	slogx.Log(context.Background(), newLogger(&buf, slog.LevelInfo), "db", err)
+	assert.Equal(t, `level=ERROR msg=db error="Query failed" code=ApplicationFailure id=500 table=orders`+"\n", buf.String())
+
+	buf.Reset()
+	slogx.Log(context.Background(), newLogger(&buf, slog.LevelDebug), "db", err)
+	assert.Contains(t, buf.String(), `query="SELECT 1"`)
+
+	assert.Len(t, slogx.Attrs(err), 4, "Attrs has no level to render leveled fields at")
+}
+
+func TestAttrs_RedactsSensitiveFields(t *testing.T) {
+	err := ex.Annotate(ex.New(ex.ExTypeLoginRequired, 401, "Login failed").WithField("password", "hunter2"),
+		ex.F("X-Api-Key", "k-123"), ex.F("user", "ada"))
+
+	attrs := slogx.Attrs(err)
+	assert.Contains(t, attrs, slog.Any("password", ex.RedactedValue))
+	assert.Contains(t, attrs, slog.Any("X-Api-Key", ex.RedactedValue))
+	assert.Contains(t, attrs, slog.Any("user", "ada"))
+}
+
+func TestLog_TenantFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf, slog.LevelDebug)
+	ctx := ex.ContextWithTenant(context.Background(), "acme")
+
+	slogx.Log(ctx, logger, "plain", errors.New("EOF"))
+	slogx.Log(ctx, logger, "tagged", ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("globex"))
+
+	assert.Equal(t,
+		`level=ERROR msg=plain error=EOF tenant=acme`+"\n"+
+			`level=WARN msg=tagged error="No such order" code=NotFound id=4041 tenant=globex`+"\n",
+		buf.String())
+}
+
+func TestLogProfile(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf, slog.LevelDebug)
+	err := ex.New(ex.ExTypeLoginRequired, 401, "Login failed").
+		WithField("user", "ada").
+		WithField("password", "hunter2")
+
+	slogx.LogProfile(context.Background(), logger, "debug", err, ex.ProfileDebug)
+	slogx.LogProfile(context.Background(), logger, "client", err, ex.ProfileClient)
+	slogx.LogProfile(context.Background(), logger, "dropped", errors.New("EOF"), ex.ProfileClient)
+
+	assert.Equal(t,
+		`level=WARN msg=debug error="Login failed" code=LoginRequired id=401 user=ada password=hunter2`+"\n"+
+			`level=WARN msg=client error="Login failed" code=LoginRequired id=401`+"\n",
+		buf.String())
+}
@@ -0,0 +1,82 @@
+package ex
+
+import "errors"
+
+// ExceptionData is a plain, read-only copy of an exception and its chain,
+// with exported fields only and no methods, for log encoders, template
+// engines, and anything else that works by reflection:
+//
+//	tmpl := template.Must(template.New("").Parse(
+//	    `{{.Type}}/{{.ID}}: {{.Message}}{{range .Inner}} <- {{.Message}}{{end}}`))
+//	_ = tmpl.Execute(w, e.Snapshot())
+//
+// Changing an ExceptionData does not affect the exception it was taken
+// from, apart from field values that are themselves references.
+type ExceptionData struct {
+	// Code and Type are the exception code and its name; zero and "" for
+	// errors that are not exceptions.
+	Code ExType
+	Type string
+
+	ID int
+
+	// Message is the exception's own message, or the full Error() text
+	// of an error that is not an exception.
+	Message string
+
+	// Fields holds the fields of this level of the chain, including those
+	// added by Annotate above it, or nil if there are none.
+	Fields map[string]any
+
+	// Stack is the captured stack trace, or nil.
+	Stack []Frame
+
+	// Inner holds the error this one wraps, or one entry per member for
+	// errors that aggregate several (see FromJoined); it is empty at the
+	// end of the chain.
+	Inner []ExceptionData
+}
+
+// Snapshot returns a copy of e and its chain as an ExceptionData.
+// Annotations are folded into the level they annotate; any other error in
+// the chain, Errorer or not, becomes a level of its own.
+func (e Exception) Snapshot() ExceptionData {
+	return snapshotOf(e)
+}
+
+// snapshotOf returns the ExceptionData for err and the errors it wraps.
+func snapshotOf(err error) ExceptionData {
+	var annotations [][]Field
+	for {
+		a, ok := err.(*annotation)
+		if !ok {
+			break
+		}
+		annotations = append(annotations, a.fields)
+		err = a.err
+	}
+	var d ExceptionData
+	var fields []Field
+	if e, ok := err.(Errorer); ok {
+		d = ExceptionData{Code: e.Code(), Type: e.Code().String(), ID: e.ID(), Message: e.Message(), Stack: e.StackTrace()}
+		fields = e.Fields()
+	} else {
+		d.Message = err.Error()
+	}
+	// Apply annotations innermost first so that the outermost value of a
+	// key wins, as in FieldsOf.
+	for i := len(annotations) - 1; i >= 0; i-- {
+		fields = append(fields[:len(fields):len(fields)], annotations[i]...)
+	}
+	d.Fields = fieldMap(fields, ResolveValue)
+	if errs, ok := members(err); ok {
+		for _, member := range errs {
+			if member != nil {
+				d.Inner = append(d.Inner, snapshotOf(member))
+			}
+		}
+	} else if next := errors.Unwrap(err); next != nil {
+		d.Inner = []ExceptionData{snapshotOf(next)}
+	}
+	return d
+}
@@ -0,0 +1,84 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	root := errors.New("connection refused")
+	db := ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable").
+		WithInnerError(root).
+		WithField("host", "db-7").
+		WithStack()
+	annotated := ex.Annotate(db, ex.F("host", "db-7.internal"), ex.F("table", "orders"))
+	e := ex.New(ex.ExTypeNotFound, 4041, "Order not found").
+		WithInnerError(fmt.Errorf("lookup: %w", annotated)).
+		WithField("order", 42)
+
+	d := e.Snapshot()
+	assert.Equal(t, ex.ExTypeNotFound, d.Code)
+	assert.Equal(t, "NotFound", d.Type)
+	assert.Equal(t, 4041, d.ID)
+	assert.Equal(t, "Order not found", d.Message)
+	assert.Equal(t, map[string]any{"order": 42}, d.Fields)
+	assert.Nil(t, d.Stack)
+
+	require.Len(t, d.Inner, 1)
+	wrapper := d.Inner[0]
+	assert.Equal(t, "lookup: Database unreachable: connection refused", wrapper.Message)
+	assert.Zero(t, wrapper.Code)
+
+	require.Len(t, wrapper.Inner, 1)
+	level := wrapper.Inner[0]
+	assert.Equal(t, 5031, level.ID)
+	assert.Equal(t, map[string]any{"host": "db-7.internal", "table": "orders"}, level.Fields,
+		"annotations are folded in, the outermost value winning")
+	assert.Equal(t, db.StackTrace(), level.Stack)
+
+	require.Len(t, level.Inner, 1)
+	assert.Equal(t, ex.ExceptionData{Message: "connection refused"}, level.Inner[0])
+}
+
+func TestSnapshot_Joined(t *testing.T) {
+	e := ex.New(ex.ExTypeApplicationFailure, 5001, "Batch failed").WithInnerError(errors.Join(
+		ex.New(ex.ExTypeIncorrectData, 4001, "Row 3 invalid"),
+		errors.New("row 9: boom"),
+	))
+
+	d := e.Snapshot()
+	require.Len(t, d.Inner, 1)
+	require.Len(t, d.Inner[0].Inner, 2)
+	assert.Equal(t, 4001, d.Inner[0].Inner[0].ID)
+	assert.Equal(t, "row 9: boom", d.Inner[0].Inner[1].Message)
+}
+
+func TestSnapshot_Reflection(t *testing.T) {
+	e := ex.New(ex.ExTypeNotFound, 4041, "Order not found").
+		WithInnerError(ex.New(ex.ExTypeUnavailable, 5031, "Database unreachable"))
+
+	var b strings.Builder
+	tmpl := template.Must(template.New("").Parse(`{{.Type}}/{{.ID}}: {{.Message}}{{range .Inner}} <- {{.Message}}{{end}}`))
+	require.NoError(t, tmpl.Execute(&b, e.Snapshot()))
+	assert.Equal(t, "NotFound/4041: Order not found <- Database unreachable", b.String())
+
+	data, err := json.Marshal(e.Snapshot())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Message":"Database unreachable"`)
+}
+
+func TestSnapshot_Independent(t *testing.T) {
+	e := ex.New(ex.ExTypeNotFound, 4041, "Order not found").WithField("order", 42)
+	d := e.Snapshot()
+	d.Fields["order"] = 43
+	v, _ := e.FieldValue("order")
+	assert.Equal(t, 42, v)
+}
@@ -0,0 +1,280 @@
+// Package soapx translates exceptions to and from SOAP 1.1 faults and the
+// numeric fault codes of legacy partner protocols, so edge adapters can
+// speak old protocols while everything behind them stays on ex.
+//
+// Each integration gets a Mapper configured with the partner's numbering:
+//
+//	var acme = soapx.NewMapper(soapx.Config{
+//	    Codes: map[ex.CodeID]int{
+//	        {Code: ex.ExTypeNotFound, ID: 4041}: 1042,
+//	    },
+//	    Types: map[ex.ExType]int{
+//	        ex.ExTypeIncorrectData:      1000,
+//	        ex.ExTypeApplicationFailure: 9000,
+//	    },
+//	    Default: 9999,
+//	})
+//
+//	func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//	    if err := h.serve(w, r); err != nil {
+//	        acme.WriteFault(w, err)
+//	    }
+//	}
+//
+// and on the way in, partner faults become exceptions again:
+//
+//	fault, err := soapx.ParseFault(body)
+//	if err == nil {
+//	    return acme.FromFault(fault)
+//	}
+package soapx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bold-minds/ex"
+)
+
+// EnvelopeNamespace is the namespace of SOAP 1.1 envelopes.
+const EnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+
+// ContentType is the media type of SOAP 1.1 messages.
+const ContentType = "text/xml; charset=utf-8"
+
+// Fault codes of SOAP 1.1: the sender of the message is at fault, or the
+// receiver.
+const (
+	FaultClient = "soap:Client"
+	FaultServer = "soap:Server"
+)
+
+// ErrNoFault is returned by ParseFault for envelopes without a fault.
+var ErrNoFault = errors.New("soapx: envelope holds no fault")
+
+// Config configures a Mapper. Zero values map nothing and use zero as the
+// default code.
+type Config struct {
+	// Codes maps individual exceptions to partner fault codes.
+	Codes map[ex.CodeID]int
+
+	// Types maps exception codes to partner fault codes, for exceptions
+	// not listed in Codes.
+	Types map[ex.ExType]int
+
+	// Default is the partner fault code of errors mapped by neither Codes
+	// nor Types.
+	Default int
+
+	// Actor is reported as the faultactor of every fault, if set.
+	Actor string
+}
+
+// Mapper translates between exceptions and one partner's fault codes. It
+// is safe for concurrent use.
+type Mapper struct {
+	cfg Config
+	// byLegacy and typeByLegacy invert cfg.Codes and cfg.Types.
+	byLegacy     map[int]ex.CodeID
+	typeByLegacy map[int]ex.ExType
+}
+
+// NewMapper returns a Mapper configured by cfg. When several exceptions or
+// codes map to the same partner code, FromLegacy picks the lowest code,
+// then the lowest ID.
+func NewMapper(cfg Config) *Mapper {
+	m := &Mapper{cfg: cfg, byLegacy: map[int]ex.CodeID{}, typeByLegacy: map[int]ex.ExType{}}
+	keys := make([]ex.CodeID, 0, len(cfg.Codes))
+	for k := range cfg.Codes {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Code != keys[j].Code {
+			return keys[i].Code < keys[j].Code
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	for _, k := range keys {
+		if _, taken := m.byLegacy[cfg.Codes[k]]; !taken {
+			m.byLegacy[cfg.Codes[k]] = k
+		}
+	}
+	for code, legacy := range cfg.Types {
+		if cur, taken := m.typeByLegacy[legacy]; !taken || code < cur {
+			m.typeByLegacy[legacy] = code
+		}
+	}
+	return m
+}
+
+// LegacyCode returns the partner fault code for err, from the first
+// Errorer in its chain: the code configured for its code and ID, failing
+// that for its code, and failing that the default. A nil err yields zero.
+func (m *Mapper) LegacyCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	e, ok := ex.AsErrorer(err)
+	if !ok {
+		return m.cfg.Default
+	}
+	if legacy, ok := m.cfg.Codes[ex.CodeID{Code: e.Code(), ID: e.ID()}]; ok {
+		return legacy
+	}
+	if legacy, ok := m.cfg.Types[e.Code()]; ok {
+		return legacy
+	}
+	if legacy, ok := m.cfg.Types[e.Code().Category()]; ok {
+		return legacy
+	}
+	return m.cfg.Default
+}
+
+// FromLegacy builds the exception for a partner fault code: the exception
+// configured for it in Codes, or one with the code configured for it in
+// Types, or an ExTypeApplicationFailure, the last two with the partner
+// code as their ID. A zero code means success and yields nil.
+func (m *Mapper) FromLegacy(code int, message string) error {
+	if code == 0 {
+		return nil
+	}
+	if k, ok := m.byLegacy[code]; ok {
+		return ex.New(k.Code, k.ID, message)
+	}
+	if t, ok := m.typeByLegacy[code]; ok {
+		return ex.New(t, code, message)
+	}
+	return ex.New(ex.ExTypeApplicationFailure, code, message)
+}
+
+// Fault is a SOAP 1.1 fault.
+type Fault struct {
+	// Code is FaultClient or FaultServer for faults built by a Mapper.
+	Code   string
+	String string
+	Actor  string
+
+	// LegacyCode is the partner fault code, carried in the detail
+	// element; zero if absent.
+	LegacyCode int
+}
+
+// Fault returns the fault for err. Client faults (see ex.IsClientFault)
+// get FaultClient and report the exception's message unless its code is
+// internal (see ex.IsInternal); everything else gets FaultServer, and
+// internal and server faults report their code name instead, since their
+// messages may describe internals, as with httpx.NewEnvelope.
+func (m *Mapper) Fault(err error) Fault {
+	f := Fault{Code: FaultServer, String: ex.ExTypeApplicationFailure.String(), Actor: m.cfg.Actor, LegacyCode: m.LegacyCode(err)}
+	e, ok := ex.AsErrorer(err)
+	if !ok {
+		return f
+	}
+	f.String = e.Code().String()
+	if ex.IsClientFault(err) {
+		f.Code = FaultClient
+		if !ex.IsInternal(err) && e.Message() != "" {
+			f.String = e.Message()
+		}
+	}
+	return f
+}
+
+// FromFault rebuilds the error a partner reported in f: from its legacy
+// code with FromLegacy if it has one, and otherwise as an
+// ExTypeIncorrectData for client faults and an ExTypeApplicationFailure
+// for any other.
+func (m *Mapper) FromFault(f Fault) error {
+	if f.LegacyCode != 0 {
+		return m.FromLegacy(f.LegacyCode, f.String)
+	}
+	if localName(f.Code) == "Client" {
+		return ex.New(ex.ExTypeIncorrectData, 0, f.String)
+	}
+	return ex.New(ex.ExTypeApplicationFailure, 0, f.String)
+}
+
+// MarshalFault returns the SOAP envelope carrying the fault for err.
+func (m *Mapper) MarshalFault(err error) ([]byte, error) {
+	f := m.Fault(err)
+	env := envelopeOut{NS: EnvelopeNamespace}
+	env.Body.Fault = faultXML{Code: f.Code, String: f.String, Actor: f.Actor}
+	if f.LegacyCode != 0 {
+		env.Body.Fault.Detail = &detailXML{FaultCode: f.LegacyCode}
+	}
+	out, mErr := xml.Marshal(env)
+	if mErr != nil {
+		return nil, mErr
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// WriteFault writes the fault for err as a SOAP response. As SOAP 1.1
+// requires, the status is 500 whatever the fault.
+func (m *Mapper) WriteFault(w http.ResponseWriter, err error) {
+	body, mErr := m.MarshalFault(err)
+	if mErr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write(body)
+}
+
+// ParseFault reads the fault from a SOAP envelope. It returns ErrNoFault
+// for envelopes without one.
+func ParseFault(data []byte) (Fault, error) {
+	var env envelopeIn
+	if err := xml.Unmarshal(data, &env); err != nil {
+		return Fault{}, fmt.Errorf("soapx: %w", err)
+	}
+	if env.Fault == nil {
+		return Fault{}, ErrNoFault
+	}
+	f := Fault{Code: env.Fault.Code, String: env.Fault.String, Actor: env.Fault.Actor}
+	if env.Fault.Detail != nil {
+		f.LegacyCode = env.Fault.Detail.FaultCode
+	}
+	return f, nil
+}
+
+// localName returns the local part of a qualified name, such as "Client"
+// for "soap:Client".
+func localName(qname string) string {
+	return qname[strings.LastIndexByte(qname, ':')+1:]
+}
+
+// envelopeOut is the encoded form of a fault envelope. encoding/xml
+// writes prefixed names as given, which is what SOAP peers expect.
+type envelopeOut struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	NS      string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Fault faultXML `xml:"soap:Fault"`
+	} `xml:"soap:Body"`
+}
+
+// envelopeIn is the decoded form of an envelope. Unqualified names match
+// whatever prefix the peer used.
+type envelopeIn struct {
+	XMLName xml.Name  `xml:"Envelope"`
+	Fault   *faultXML `xml:"Body>Fault"`
+}
+
+// faultXML is the fault element; its children are unqualified, as SOAP
+// 1.1 specifies.
+type faultXML struct {
+	Code   string     `xml:"faultcode"`
+	String string     `xml:"faultstring"`
+	Actor  string     `xml:"faultactor,omitempty"`
+	Detail *detailXML `xml:"detail,omitempty"`
+}
+
+type detailXML struct {
+	FaultCode int `xml:"faultCode"`
+}
@@ -0,0 +1,110 @@
+package soapx_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/soapx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func acme() *soapx.Mapper {
+	return soapx.NewMapper(soapx.Config{
+		Codes: map[ex.CodeID]int{
+			{Code: ex.ExTypeNotFound, ID: 4041}: 1042,
+			{Code: ex.ExTypeNotFound, ID: 4042}: 1042,
+		},
+		Types: map[ex.ExType]int{
+			ex.ExTypeIncorrectData:      1000,
+			ex.ExTypeApplicationFailure: 9000,
+		},
+		Default: 9999,
+		Actor:   "urn:orders",
+	})
+}
+
+func TestMapper_LegacyCode(t *testing.T) {
+	m := acme()
+	assert.Equal(t, 1042, m.LegacyCode(ex.New(ex.ExTypeNotFound, 4041, "No such order")))
+	assert.Equal(t, 1000, m.LegacyCode(ex.New(ex.ExTypeIncorrectData, 4001, "Bad input")))
+	assert.Equal(t, 1000, m.LegacyCode(ex.New(ex.ExTypeIncorrectData|ex.FacetTransient, 4001, "Bad input")))
+	assert.Equal(t, 9999, m.LegacyCode(ex.New(ex.ExTypeNotFound, 4049, "No such user")))
+	assert.Equal(t, 9999, m.LegacyCode(errors.New("plain")))
+	assert.Zero(t, m.LegacyCode(nil))
+}
+
+func TestMapper_FromLegacy(t *testing.T) {
+	m := acme()
+	assert.NoError(t, m.FromLegacy(0, ""))
+
+	err := m.FromLegacy(1042, "Order missing")
+	assert.ErrorIs(t, err, ex.New(ex.ExTypeNotFound, 4041, ""), "the lowest ID wins")
+	assert.Equal(t, "Order missing", err.Error())
+
+	assert.ErrorIs(t, m.FromLegacy(1000, "Bad"), ex.New(ex.ExTypeIncorrectData, 1000, ""))
+	assert.ErrorIs(t, m.FromLegacy(7, "Odd"), ex.New(ex.ExTypeApplicationFailure, 7, ""))
+}
+
+func TestMapper_Fault(t *testing.T) {
+	m := acme()
+	client := m.Fault(ex.New(ex.ExTypeNotFound, 4041, "No such order"))
+	assert.Equal(t, soapx.Fault{Code: soapx.FaultClient, String: "No such order", Actor: "urn:orders", LegacyCode: 1042}, client)
+
+	server := m.Fault(ex.New(ex.ExTypeApplicationFailure, 5001, "db-7 refused the connection"))
+	assert.Equal(t, soapx.FaultServer, server.Code)
+	assert.Equal(t, "ApplicationFailure", server.String, "server messages are not exposed")
+	assert.Equal(t, 9000, server.LegacyCode)
+
+	internal := m.Fault(ex.New(ex.ExTypeNotFound|ex.FacetInternal, 4041, "No row in shard 7"))
+	assert.Equal(t, soapx.FaultClient, internal.Code)
+	assert.Equal(t, "NotFound|Internal", internal.String, "internal messages are not exposed")
+}
+
+func TestMapper_WriteFaultRoundTrip(t *testing.T) {
+	m := acme()
+	rec := httptest.NewRecorder()
+	m.WriteFault(rec, ex.New(ex.ExTypeNotFound, 4041, "No such order"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, soapx.ContentType, rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault>`)
+	assert.Contains(t, body, `<faultcode>soap:Client</faultcode><faultstring>No such order</faultstring><faultactor>urn:orders</faultactor><detail><faultCode>1042</faultCode></detail>`)
+
+	f, err := soapx.ParseFault(rec.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 1042, f.LegacyCode)
+	got := m.FromFault(f)
+	assert.ErrorIs(t, got, ex.New(ex.ExTypeNotFound, 4041, ""))
+	assert.Equal(t, "No such order", got.Error())
+}
+
+func TestParseFault_Partner(t *testing.T) {
+	partner := []byte(`<?xml version="1.0"?>
+<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/">
+  <env:Body>
+    <env:Fault>
+      <faultcode>env:Client</faultcode>
+      <faultstring>Account locked</faultstring>
+    </env:Fault>
+  </env:Body>
+</env:Envelope>`)
+	f, err := soapx.ParseFault(partner)
+	require.NoError(t, err)
+	assert.Equal(t, soapx.Fault{Code: "env:Client", String: "Account locked"}, f)
+	code, ok := ex.CodeOf(acme().FromFault(f))
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeIncorrectData, code)
+
+	server := acme().FromFault(soapx.Fault{Code: "env:Server", String: "Down"})
+	assert.True(t, ex.IsServerFault(server))
+
+	_, err = soapx.ParseFault([]byte(`<Envelope><Body><Ok/></Body></Envelope>`))
+	assert.ErrorIs(t, err, soapx.ErrNoFault)
+	_, err = soapx.ParseFault([]byte(`not xml`))
+	assert.Error(t, err)
+}
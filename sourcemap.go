@@ -0,0 +1,73 @@
+package ex
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// sourceRoot maps one recorded path prefix to a local directory.
+type sourceRoot struct {
+	prefix string
+	root   string
+}
+
+// sourceRoots holds the mapping table, longest prefix first, or nil when
+// none is set.
+var sourceRoots atomic.Pointer[[]sourceRoot]
+
+// SetSourceRoots installs a table mapping the file paths recorded in the
+// binary to local source directories, applied when captured stacks are
+// symbolized. Binaries built with -trimpath record paths such as
+// "github.com/acme/billing/invoice.go" or, for dependencies,
+// "github.com/bold-minds/ex@v1.4.0/ex.go", which IDEs and log viewers
+// cannot open; mapping the module path to a checkout makes them
+// clickable again:
+//
+//	ex.SetSourceRoots(map[string]string{
+//	    "github.com/acme/billing": "/home/dev/src/billing",
+//	})
+//
+// Keys match whole path elements, and the longest matching key wins.
+// Frames decoded from other processes are left as they were recorded.
+// Calling it with an empty or nil map removes the table.
+func SetSourceRoots(roots map[string]string) {
+	if len(roots) == 0 {
+		sourceRoots.Store(nil)
+		return
+	}
+	table := make([]sourceRoot, 0, len(roots))
+	for prefix, root := range roots {
+		table = append(table, sourceRoot{prefix: strings.TrimSuffix(prefix, "/"), root: strings.TrimSuffix(root, "/")})
+	}
+	sort.Slice(table, func(i, j int) bool { return len(table[i].prefix) > len(table[j].prefix) })
+	sourceRoots.Store(&table)
+}
+
+// SourceRoots returns a copy of the table set with SetSourceRoots, or nil.
+func SourceRoots() map[string]string {
+	table := sourceRoots.Load()
+	if table == nil {
+		return nil
+	}
+	out := make(map[string]string, len(*table))
+	for _, r := range *table {
+		out[r.prefix] = r.root
+	}
+	return out
+}
+
+// MapSourcePath applies the SetSourceRoots table to file, returning it
+// unchanged when no key matches.
+func MapSourcePath(file string) string {
+	table := sourceRoots.Load()
+	if table == nil {
+		return file
+	}
+	for _, r := range *table {
+		if rest, ok := strings.CutPrefix(file, r.prefix); ok && strings.HasPrefix(rest, "/") {
+			return r.root + rest
+		}
+	}
+	return file
+}
@@ -0,0 +1,56 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapSourcePath(t *testing.T) {
+	saved := ex.SourceRoots()
+	t.Cleanup(func() { ex.SetSourceRoots(saved) })
+
+	assert.Equal(t, "github.com/acme/billing/x.go", ex.MapSourcePath("github.com/acme/billing/x.go"))
+
+	ex.SetSourceRoots(map[string]string{
+		"github.com/acme":          "/src/acme/",
+		"github.com/acme/billing/": "/home/dev/billing",
+	})
+	assert.Equal(t, map[string]string{"github.com/acme": "/src/acme", "github.com/acme/billing": "/home/dev/billing"}, ex.SourceRoots())
+
+	assert.Equal(t, "/home/dev/billing/invoice/x.go", ex.MapSourcePath("github.com/acme/billing/invoice/x.go"))
+	assert.Equal(t, "/src/acme/billingx/y.go", ex.MapSourcePath("github.com/acme/billingx/y.go"))
+	assert.Equal(t, "runtime/proc.go", ex.MapSourcePath("runtime/proc.go"))
+	assert.Equal(t, "github.com/acmex/z.go", ex.MapSourcePath("github.com/acmex/z.go"))
+
+	ex.SetSourceRoots(nil)
+	assert.Nil(t, ex.SourceRoots())
+	assert.Equal(t, "github.com/acme/billing/x.go", ex.MapSourcePath("github.com/acme/billing/x.go"))
+}
+
+func TestSourceRoots_AppliedToCapturedStacks(t *testing.T) {
+	saved := ex.SourceRoots()
+	t.Cleanup(func() { ex.SetSourceRoots(saved) })
+
+	_, file, _, ok := runtime.Caller(0)
+	require.True(t, ok)
+	ex.SetSourceRoots(map[string]string{filepath.Dir(file): "/mapped"})
+
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack()
+	frames := e.StackTrace()
+	require.NotEmpty(t, frames)
+	assert.Equal(t, "/mapped/sourcemap_test.go", frames[0].File)
+
+	// Decoded frames keep the paths they were recorded with.
+	data, err := json.Marshal(ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack())
+	require.NoError(t, err)
+	ex.SetSourceRoots(map[string]string{"/mapped": "/elsewhere"})
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "/mapped/sourcemap_test.go", decoded.StackTrace()[0].File)
+}
@@ -0,0 +1,82 @@
+package ex
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxStackDepth bounds the number of frames captured by WithStack.
+const maxStackDepth = 32
+
+// Frame is a single symbolized stack frame.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String renders the frame as "function\n\tfile:line", the layout used by
+// runtime/debug.Stack.
+func (f Frame) String() string {
+	return f.Function + "\n\t" + f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// WithStack returns a new Exception carrying the call stack of the code
+// that called WithStack. Stacks are opt-in because capturing one costs far
+// more than building the exception itself; see also SetStackPolicy. Under
+// StackNever, WithStack returns e unchanged.
+func (e Exception) WithStack() Exception {
+	if stacksDisabled() {
+		return e
+	}
+	e.stack = callers(3)
+	e.frames = nil
+	e.frozen = nil
+	return e
+}
+
+// HasStack reports whether a stack was captured for this exception.
+func (e Exception) HasStack() bool {
+	return len(e.stack) > 0 || len(e.frames) > 0
+}
+
+// StackTrace returns the symbolized frames captured by WithStack,
+// innermost call first, or nil if no stack was captured.
+func (e Exception) StackTrace() []Frame {
+	if len(e.stack) == 0 {
+		if len(e.frames) == 0 {
+			return nil
+		}
+		out := make([]Frame, len(e.frames))
+		copy(out, e.frames)
+		return out
+	}
+	return symbolize(e.stack)
+}
+
+// FormatStack renders frames one per entry in the debug.Stack layout. It
+// returns "" for an empty stack.
+func FormatStack(frames []Frame) string {
+	var b strings.Builder
+	for i, f := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(f.String())
+	}
+	return b.String()
+}
+
+// callers captures the calling goroutine's program counters, skipping the
+// given number of frames (as runtime.Callers counts them).
+func callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	if n == 0 {
+		return nil
+	}
+	out := make([]uintptr, n)
+	copy(out, pcs[:n])
+	return out
+}
@@ -0,0 +1,78 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWithStack() ex.Exception {
+	return ex.New(ex.ExTypeApplicationFailure, 500, "boom").WithStack()
+}
+
+func TestException_WithStack(t *testing.T) {
+	plain := ex.New(ex.ExTypeApplicationFailure, 500, "boom")
+	assert.False(t, plain.HasStack())
+	assert.Nil(t, plain.StackTrace())
+
+	exc := newWithStack()
+	require.True(t, exc.HasStack())
+
+	frames := exc.StackTrace()
+	require.NotEmpty(t, frames)
+	// The innermost frame is the function that called WithStack, not
+	// WithStack itself or the runtime.
+	assert.True(t, strings.HasSuffix(frames[0].Function, "ex_test.newWithStack"), frames[0].Function)
+	assert.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"), frames[0].File)
+	assert.Positive(t, frames[0].Line)
+	assert.True(t, strings.HasSuffix(frames[1].Function, "ex_test.TestException_WithStack"), frames[1].Function)
+
+	// Deriving a new exception keeps the captured stack.
+	assert.True(t, exc.WithField("k", "v").HasStack())
+}
+
+func TestFormatStack(t *testing.T) {
+	frames := []ex.Frame{
+		{Function: "main.run", File: "/src/main.go", Line: 10},
+		{Function: "main.main", File: "/src/main.go", Line: 3},
+	}
+	assert.Equal(t, "main.run\n\t/src/main.go:10\nmain.main\n\t/src/main.go:3", ex.FormatStack(frames))
+	assert.Equal(t, "", ex.FormatStack(nil))
+}
+
+func TestException_StackSurvivesJSON(t *testing.T) {
+	exc := newWithStack()
+	data, err := json.Marshal(exc)
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.HasStack())
+	assert.Equal(t, exc.StackTrace(), decoded.StackTrace())
+
+	// Stacks are excluded from the canonical form and so from fingerprints.
+	assert.Equal(t, ex.New(ex.ExTypeApplicationFailure, 500, "boom").Fingerprint(), exc.Fingerprint())
+}
+
+func TestSetFrameCacheSize(t *testing.T) {
+	t.Cleanup(func() { ex.SetFrameCacheSize(ex.DefaultFrameCacheSize) })
+	e := ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack()
+
+	ex.SetFrameCacheSize(0)
+	uncached := e.StackTrace()
+
+	for _, size := range []int{1, 2, ex.DefaultFrameCacheSize} {
+		ex.SetFrameCacheSize(size)
+		assert.Equal(t, uncached, e.StackTrace(), "size %d, cold", size)
+		assert.Equal(t, uncached, e.StackTrace(), "size %d, warm", size)
+	}
+
+	// Callers own the returned slice; mutating it must not poison the cache.
+	got := e.StackTrace()
+	got[0].Function = "mutated"
+	assert.Equal(t, uncached, e.StackTrace())
+}
@@ -0,0 +1,93 @@
+package ex
+
+import (
+	"errors"
+	"strings"
+)
+
+// DiffStacks compares the stacks captured for a and b and returns them
+// as a unified diff, in the StackTrace order and debug.Stack layout, with
+// the frames only a has marked "- ", those only b has marked "+ ", and
+// shared frames indented by two spaces:
+//
+//	  main.handle
+//	  	app/main.go:30
+//	- app.(*Repo).Load
+//	- 	app/repo.go:42
+//	+ app.(*Repo).Save
+//	+ 	app/repo.go:57
+//
+// It helps decide whether two alert groups are the same bug: errors raised
+// from the same place share every frame, while two bugs diverge where
+// their call paths do. A frame counts as shared only if its function,
+// file, and line all match.
+//
+// The stack compared is that of the innermost Errorer in each chain that
+// has one, the closest to where the failure happened. DiffStacks returns
+// "" if neither chain carries a stack.
+func DiffStacks(a, b error) string {
+	fa, fb := chainStack(a), chainStack(b)
+	if len(fa) == 0 && len(fb) == 0 {
+		return ""
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// fa[i:] and fb[j:]. Stacks are at most maxStackDepth frames deep
+	// when captured locally, so the table stays small.
+	lcs := make([][]int, len(fa)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(fb)+1)
+	}
+	for i := len(fa) - 1; i >= 0; i-- {
+		for j := len(fb) - 1; j >= 0; j-- {
+			if fa[i] == fb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(fa) || j < len(fb) {
+		switch {
+		case i < len(fa) && j < len(fb) && fa[i] == fb[j]:
+			writeDiffFrame(&out, "  ", fa[i])
+			i++
+			j++
+		case j == len(fb) || (i < len(fa) && lcs[i+1][j] >= lcs[i][j+1]):
+			writeDiffFrame(&out, "- ", fa[i])
+			i++
+		default:
+			writeDiffFrame(&out, "+ ", fb[j])
+			j++
+		}
+	}
+	return out.String()
+}
+
+// chainStack returns the stack of the innermost Errorer in err's chain
+// that carries one.
+func chainStack(err error) []Frame {
+	var frames []Frame
+	for err != nil {
+		if e, ok := err.(Errorer); ok {
+			if stack := e.StackTrace(); len(stack) > 0 {
+				frames = stack
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return frames
+}
+
+// writeDiffFrame writes f in the debug.Stack layout with every line
+// prefixed by marker.
+func writeDiffFrame(b *strings.Builder, marker string, f Frame) {
+	for _, line := range strings.Split(f.String(), "\n") {
+		b.WriteString(marker)
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
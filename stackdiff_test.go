@@ -0,0 +1,70 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withFrames decodes an exception carrying the given stack, so tests get
+// stable frames.
+func withFrames(t *testing.T, frames ...ex.Frame) ex.Exception {
+	t.Helper()
+	stack, err := json.Marshal(frames)
+	require.NoError(t, err)
+	var e ex.Exception
+	require.NoError(t, json.Unmarshal([]byte(`{"code":4,"id":500,"message":"x","stack":`+string(stack)+`}`), &e))
+	return e
+}
+
+func TestDiffStacks(t *testing.T) {
+	handle := ex.Frame{Function: "main.handle", File: "app/main.go", Line: 30}
+	serve := ex.Frame{Function: "net/http.(*conn).serve", File: "net/http/server.go", Line: 2092}
+	a := withFrames(t, ex.Frame{Function: "app.(*Repo).Load", File: "app/repo.go", Line: 42}, handle, serve)
+	b := withFrames(t, ex.Frame{Function: "app.(*Repo).Save", File: "app/repo.go", Line: 57}, handle, serve)
+
+	want := `- app.(*Repo).Load
+- 	app/repo.go:42
++ app.(*Repo).Save
++ 	app/repo.go:57
+  main.handle
+  	app/main.go:30
+  net/http.(*conn).serve
+  	net/http/server.go:2092
+`
+	assert.Equal(t, want, ex.DiffStacks(a, b))
+
+	// The same call site on different lines diverges.
+	c := withFrames(t, ex.Frame{Function: "app.(*Repo).Load", File: "app/repo.go", Line: 42},
+		ex.Frame{Function: "main.handle", File: "app/main.go", Line: 31}, serve)
+	diff := ex.DiffStacks(a, c)
+	assert.Contains(t, diff, "- \tapp/main.go:30\n")
+	assert.Contains(t, diff, "+ \tapp/main.go:31\n")
+	assert.Contains(t, diff, "  app.(*Repo).Load\n")
+
+	// Identical stacks share every frame.
+	assert.NotContains(t, ex.DiffStacks(a, a), "- ")
+}
+
+func TestDiffStacks_UsesInnermostStack(t *testing.T) {
+	inner := withFrames(t, ex.Frame{Function: "app.query", File: "app/db.go", Line: 9})
+	outer := ex.New(ex.ExTypeApplicationFailure, 500, "Request failed").WithStack().WithInnerError(inner)
+
+	assert.Equal(t, "  app.query\n  \tapp/db.go:9\n", ex.DiffStacks(outer, inner))
+	assert.Equal(t, "- app.query\n- \tapp/db.go:9\n", ex.DiffStacks(outer, errors.New("plain")))
+	assert.Empty(t, ex.DiffStacks(errors.New("a"), ex.New(ex.ExTypeIncorrectData, 400, "b")))
+}
+
+func TestDiffStacks_Captured(t *testing.T) {
+	first := ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack()
+	second := ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack()
+
+	lines := strings.Split(strings.TrimSuffix(ex.DiffStacks(first, second), "\n"), "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "- "), lines[0])
+	assert.True(t, strings.HasPrefix(lines[len(lines)-1], "  "), "callers of the test share their frames")
+}
@@ -0,0 +1,108 @@
+package ex
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// FromStackDump creates an exception like New for a failure observed
+// from outside the goroutines involved, carrying a goroutine dump that was
+// captured at the time: the output of runtime/debug.Stack, of
+// runtime.Stack with all goroutines, or of a SIGQUIT. It lets watchdogs
+// and supervisors report hangs and deadlocks through the same pipeline as
+// every other error:
+//
+//	buf := make([]byte, 1<<20)
+//	buf = buf[:runtime.Stack(buf, true)]
+//	report(ex.FromStackDump(buf, ex.ExTypeTimeout, 5041, "Worker pool stalled"))
+//
+// The frames of the first goroutine in the dump become the exception's
+// stack, so StackTrace, the JSON encoding, and the renderers show them as
+// if WithStack had captured them, whatever the stack policy. The whole
+// dump, every goroutine included, is kept as text and returned by
+// StackDump. It is not a field, so it stays out of logs, alerts, and the
+// fingerprint, and travels only in the JSON encoding. A dump that cannot
+// be parsed is kept all the same, with no stack. Hooks run as for New.
+func FromStackDump(dump []byte, code ExType, id int, message string) Exception {
+	checkNew(code, id, message)
+	e := Exception{
+		code:      code,
+		id:        id,
+		message:   message,
+		frames:    parseDumpFrames(dump),
+		stackDump: string(dump),
+		sizeHint:  len(message) + 1,
+	}
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// StackDump returns the goroutine dump of an exception built by
+// FromStackDump, and reports false for all other exceptions.
+func (e Exception) StackDump() (string, bool) {
+	return e.stackDump, e.stackDump != ""
+}
+
+// parseDumpFrames returns the frames of the first goroutine in a dump in
+// the runtime's traceback format:
+//
+//	goroutine 7 [chan receive, 3 minutes]:
+//	main.worker(0xc000012345)
+//		/app/worker.go:42 +0x1d
+//	created by main.start in goroutine 1
+//		/app/main.go:17 +0x45
+func parseDumpFrames(dump []byte) []Frame {
+	var frames []Frame
+	var function string
+	started := false
+	for line := range bytes.Lines(dump) {
+		text := strings.TrimRight(string(line), "\r\n")
+		switch {
+		case strings.HasPrefix(text, "goroutine "):
+			if started {
+				return frames
+			}
+			started = true
+		case !started:
+		case text == "":
+			if len(frames) > 0 {
+				return frames
+			}
+		case strings.HasPrefix(text, "\t"):
+			if function == "" {
+				continue
+			}
+			if f, ok := parseDumpLocation(function, text[1:]); ok {
+				frames = append(frames, f)
+			}
+			function = ""
+		case strings.HasPrefix(text, "created by "):
+			function, _, _ = strings.Cut(strings.TrimPrefix(text, "created by "), " in goroutine ")
+		case strings.HasPrefix(text, "..."):
+			// "...additional frames elided..."
+		default:
+			function = text
+			if i := strings.LastIndexByte(text, '('); i > 0 && strings.HasSuffix(text, ")") {
+				function = text[:i]
+			}
+		}
+	}
+	return frames
+}
+
+// parseDumpLocation parses the "file:line +0xoffset" line that follows a
+// function in a traceback.
+func parseDumpLocation(function, loc string) (Frame, bool) {
+	loc, _, _ = strings.Cut(loc, " +0x")
+	i := strings.LastIndexByte(loc, ':')
+	if i < 0 {
+		return Frame{}, false
+	}
+	line, err := strconv.Atoi(loc[i+1:])
+	if err != nil {
+		return Frame{}, false
+	}
+	return Frame{Function: function, File: loc[:i], Line: line}, true
+}
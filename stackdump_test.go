@@ -0,0 +1,88 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDump = `goroutine 7 [chan receive, 3 minutes]:
+main.(*Pool).worker(0xc000012345, {0x1, 0x2})
+	/app/pool.go:42 +0x1d
+main.run(...)
+	/app/main.go:30
+created by main.start in goroutine 1
+	/app/main.go:17 +0x45
+
+goroutine 1 [running]:
+main.main()
+	/app/main.go:12 +0x10
+`
+
+func TestFromStackDump(t *testing.T) {
+	e := ex.FromStackDump([]byte(sampleDump), ex.ExTypeTimeout, 5041, "Worker pool stalled")
+
+	assert.Equal(t, "Worker pool stalled", e.Error())
+	assert.Equal(t, ex.ExTypeTimeout, e.Code())
+	require.True(t, e.HasStack())
+	assert.Equal(t, []ex.Frame{
+		{Function: "main.(*Pool).worker", File: "/app/pool.go", Line: 42},
+		{Function: "main.run", File: "/app/main.go", Line: 30},
+		{Function: "main.start", File: "/app/main.go", Line: 17},
+	}, e.StackTrace())
+
+	dump, ok := e.StackDump()
+	require.True(t, ok)
+	assert.Equal(t, sampleDump, dump)
+	assert.Empty(t, e.Fields())
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, e.StackTrace(), decoded.StackTrace())
+	decodedDump, ok := decoded.StackDump()
+	require.True(t, ok)
+	assert.Equal(t, sampleDump, decodedDump)
+}
+
+func TestFromStackDump_NotFingerprinted(t *testing.T) {
+	first := ex.FromStackDump([]byte(sampleDump), ex.ExTypeTimeout, 5041, "Worker pool stalled")
+	second := ex.FromStackDump([]byte("goroutine 9 [select]:\n"), ex.ExTypeTimeout, 5041, "Worker pool stalled")
+	assert.Equal(t, first.Fingerprint(), second.Fingerprint())
+
+	canonical, err := first.MarshalCanonical()
+	require.NoError(t, err)
+	assert.NotContains(t, string(canonical), "goroutine")
+	assert.NotContains(t, ex.Explain(first), "goroutine")
+
+	_, ok := ex.New(ex.ExTypeTimeout, 5041, "Worker pool stalled").StackDump()
+	assert.False(t, ok)
+}
+
+func TestFromStackDump_Runtime(t *testing.T) {
+	e := ex.FromStackDump(debug.Stack(), ex.ExTypeApplicationFailure, 1, "debug.Stack")
+	require.True(t, e.HasStack())
+	assert.Equal(t, "runtime/debug.Stack", e.StackTrace()[0].Function)
+	assert.True(t, strings.HasSuffix(e.StackTrace()[1].Function, "TestFromStackDump_Runtime"))
+
+	buf := make([]byte, 1<<16)
+	buf = buf[:runtime.Stack(buf, true)]
+	all := ex.FromStackDump(buf, ex.ExTypeApplicationFailure, 2, "all goroutines")
+	require.True(t, all.HasStack())
+	assert.True(t, strings.HasSuffix(all.StackTrace()[0].Function, "TestFromStackDump_Runtime"), all.StackTrace()[0].Function)
+}
+
+func TestFromStackDump_Unparseable(t *testing.T) {
+	e := ex.FromStackDump([]byte("not a dump"), ex.ExTypeTimeout, 1, "Stalled")
+	assert.False(t, e.HasStack())
+	dump, ok := e.StackDump()
+	require.True(t, ok)
+	assert.Equal(t, "not a dump", dump)
+}
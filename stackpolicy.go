@@ -0,0 +1,192 @@
+package ex
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stackMode enumerates the kinds of StackPolicy.
+type stackMode uint8
+
+const (
+	stackOnRequest stackMode = iota
+	stackNever
+	stackAlways
+	stackOnFailure
+	stackSampled
+	stackPerFingerprint
+)
+
+// StackPolicy controls when stacks are captured. Set it process-wide with
+// SetStackPolicy.
+type StackPolicy struct {
+	mode    stackMode
+	rate    float64
+	sampler *fingerprintSampler
+}
+
+var (
+	// StackOnRequest captures stacks only when WithStack is called (and
+	// for panics, via FromPanic). It is the default.
+	StackOnRequest = StackPolicy{mode: stackOnRequest}
+
+	// StackNever captures no stacks at all: WithStack is a no-op and
+	// FromPanic records none. Use it to rule out the cost entirely.
+	StackNever = StackPolicy{mode: stackNever}
+
+	// StackAlways captures a stack in New for every exception.
+	StackAlways = StackPolicy{mode: stackAlways}
+
+	// StackOnFailure captures a stack in New for exceptions that are the
+	// service's own fault: ExTypeApplicationFailure, or any code
+	// registered with a severity of SeverityError or higher.
+	StackOnFailure = StackPolicy{mode: stackOnFailure}
+)
+
+// StackSampled returns a policy that captures a stack in New for a random
+// fraction rate of exceptions. rate is clamped to [0, 1].
+func StackSampled(rate float64) StackPolicy {
+	return StackPolicy{mode: stackSampled, rate: min(max(rate, 0), 1)}
+}
+
+// StackPerFingerprint returns a policy that captures a stack in New for
+// the first n exceptions of each fingerprint in every interval, so a hot
+// error still arrives with a stack from time to time while most of its
+// occurrences skip the cost:
+//
+//	ex.SetStackPolicy(ex.StackPerFingerprint(5, time.Minute))
+//
+// The fingerprint here is the new exception's code and ID, which identify
+// where it was raised; unlike Fingerprint, they do not vary with message
+// text or with the inner errors attached later. Counts restart for every
+// fingerprint at the start of each interval, so the memory used is bounded
+// by the number of distinct codes and IDs seen in one interval. n below 1
+// captures no stacks; an interval of zero or less counts forever.
+func StackPerFingerprint(n int, interval time.Duration) StackPolicy {
+	return StackPolicy{mode: stackPerFingerprint, sampler: &fingerprintSampler{
+		n:        max(n, 0),
+		interval: interval,
+		counts:   map[CodeID]int{},
+	}}
+}
+
+// fingerprintSampler counts the exceptions of each fingerprint in the
+// current interval for StackPerFingerprint.
+type fingerprintSampler struct {
+	n        int
+	interval time.Duration
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[CodeID]int
+}
+
+// allow counts an exception with the given code and ID and reports
+// whether its stack should be captured.
+func (s *fingerprintSampler) allow(code ExType, id int) bool {
+	if s.n == 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.interval > 0 {
+		if now := time.Now(); now.Sub(s.start) >= s.interval {
+			s.start = now
+			clear(s.counts)
+		}
+	}
+	key := CodeID{Code: code, ID: id}
+	if s.counts[key] >= s.n {
+		return false
+	}
+	s.counts[key]++
+	return true
+}
+
+// String returns the policy's name, such as "OnFailure", "Sampled(0.01)",
+// or "PerFingerprint(5/1m0s)".
+func (p StackPolicy) String() string {
+	switch p.mode {
+	case stackNever:
+		return "Never"
+	case stackAlways:
+		return "Always"
+	case stackOnFailure:
+		return "OnFailure"
+	case stackSampled:
+		return "Sampled(" + strconv.FormatFloat(p.rate, 'g', -1, 64) + ")"
+	case stackPerFingerprint:
+		return "PerFingerprint(" + strconv.Itoa(p.sampler.n) + "/" + p.sampler.interval.String() + ")"
+	default:
+		return "OnRequest"
+	}
+}
+
+// stackPolicy holds the active policy; nil means StackOnRequest, which
+// keeps New's fast path to a single atomic load.
+var stackPolicy atomic.Pointer[StackPolicy]
+
+// SetStackPolicy sets the process-wide stack capture policy. Stacks are
+// the most expensive part of an exception by far, so the policy lets the
+// cost be paid only where the data is valuable:
+//
+//	ex.SetStackPolicy(ex.StackOnFailure)   // server faults only
+//	ex.SetStackPolicy(ex.StackSampled(0.01)) // 1% of exceptions
+//	ex.SetStackPolicy(ex.StackPerFingerprint(5, time.Minute)) // 5 per code and ID a minute
+func SetStackPolicy(p StackPolicy) {
+	if p.mode == stackOnRequest {
+		stackPolicy.Store(nil)
+		return
+	}
+	stackPolicy.Store(&p)
+}
+
+// CurrentStackPolicy returns the policy set by SetStackPolicy.
+func CurrentStackPolicy() StackPolicy {
+	if p := stackPolicy.Load(); p != nil {
+		return *p
+	}
+	return StackOnRequest
+}
+
+// stacksDisabled reports whether the policy forbids all stack capture.
+func stacksDisabled() bool {
+	p := stackPolicy.Load()
+	return p != nil && p.mode == stackNever
+}
+
+// autoStack returns the stack to record for a new exception with code and
+// id, as the policy dictates, starting skip frames above its caller.
+func autoStack(code ExType, id int, skip int) []uintptr {
+	return policyStack(stackPolicy.Load(), LookupType, code, id, skip+1)
+}
+
+// policyStack is autoStack for the policy p, where nil means
+// StackOnRequest, with type metadata from lookup.
+func policyStack(p *StackPolicy, lookup func(ExType) (TypeInfo, bool), code ExType, id int, skip int) []uintptr {
+	if p == nil {
+		return nil
+	}
+	var capture bool
+	switch p.mode {
+	case stackAlways:
+		capture = true
+	case stackOnFailure:
+		capture = code == ExTypeApplicationFailure
+		if info, ok := lookup(code); ok && info.Severity >= SeverityError {
+			capture = true
+		}
+	case stackSampled:
+		capture = rand.Float64() < p.rate // #nosec G404 -- sampling, not security
+	case stackPerFingerprint:
+		capture = p.sampler.allow(code, id)
+	}
+	if !capture {
+		return nil
+	}
+	// +3 skips runtime.Callers, callers, and policyStack itself.
+	return callers(skip + 3)
+}
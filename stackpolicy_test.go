@@ -0,0 +1,107 @@
+package ex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withStackPolicy sets p for the duration of the test.
+func withStackPolicy(t *testing.T, p ex.StackPolicy) {
+	t.Helper()
+	saved := ex.CurrentStackPolicy()
+	ex.SetStackPolicy(p)
+	t.Cleanup(func() { ex.SetStackPolicy(saved) })
+}
+
+func TestStackPolicy_Default(t *testing.T) {
+	assert.Equal(t, ex.StackOnRequest, ex.CurrentStackPolicy())
+	assert.False(t, ex.New(ex.ExTypeApplicationFailure, 500, "x").HasStack())
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack().HasStack())
+}
+
+func TestStackPolicy_Always(t *testing.T) {
+	withStackPolicy(t, ex.StackAlways)
+
+	e := ex.New(ex.ExTypeIncorrectData, 400, "x")
+	require.True(t, e.HasStack())
+	assert.True(t, strings.HasSuffix(e.StackTrace()[0].Function, "TestStackPolicy_Always"),
+		"first frame should be New's caller, got %s", e.StackTrace()[0].Function)
+
+	typed := ex.NewTyped(ex.ExTypeIncorrectData, 400, "x")
+	require.True(t, typed.HasStack())
+	assert.True(t, strings.HasSuffix(typed.StackTrace()[0].Function, "TestStackPolicy_Always"))
+}
+
+func TestStackPolicy_OnFailure(t *testing.T) {
+	lenient(t)
+
+	withStackPolicy(t, ex.StackOnFailure)
+	require.NoError(t, ex.RegisterType(ex.ExType(4471), ex.TypeInfo{Severity: ex.SeverityCritical}))
+
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 500, "x").HasStack())
+	assert.True(t, ex.New(ex.ExType(4471), 1, "x").HasStack())
+	assert.False(t, ex.New(ex.ExTypeIncorrectData, 400, "x").HasStack())
+	assert.False(t, ex.New(ex.ExType(4472), 1, "unregistered").HasStack())
+}
+
+func TestStackPolicy_Never(t *testing.T) {
+	withStackPolicy(t, ex.StackNever)
+
+	assert.False(t, ex.New(ex.ExTypeApplicationFailure, 500, "x").WithStack().HasStack())
+	assert.False(t, ex.FromPanic("boom").HasStack())
+}
+
+func TestStackPolicy_Sampled(t *testing.T) {
+	withStackPolicy(t, ex.StackSampled(0))
+	assert.False(t, ex.New(ex.ExTypeApplicationFailure, 500, "x").HasStack())
+
+	ex.SetStackPolicy(ex.StackSampled(2))
+	assert.Equal(t, "Sampled(1)", ex.CurrentStackPolicy().String())
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 500, "x").HasStack())
+
+	ex.SetStackPolicy(ex.StackSampled(0.5))
+	captured := 0
+	for range 1000 {
+		if ex.New(ex.ExTypeIncorrectData, 400, "x").HasStack() {
+			captured++
+		}
+	}
+	assert.InDelta(t, 500, captured, 150)
+}
+
+func TestStackPolicy_PerFingerprint(t *testing.T) {
+	withStackPolicy(t, ex.StackPerFingerprint(2, time.Hour))
+
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 5001, "x").HasStack())
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 5001, "other message").HasStack())
+	assert.False(t, ex.New(ex.ExTypeApplicationFailure, 5001, "x").HasStack(), "third occurrence")
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 5002, "x").HasStack(), "another ID")
+	assert.True(t, ex.WrapAuto(errors.New("boom"), 7, "x").HasStack())
+
+	ex.SetStackPolicy(ex.StackPerFingerprint(0, time.Hour))
+	assert.False(t, ex.New(ex.ExTypeApplicationFailure, 5001, "x").HasStack())
+}
+
+func TestStackPolicy_PerFingerprintInterval(t *testing.T) {
+	withStackPolicy(t, ex.StackPerFingerprint(1, 20*time.Millisecond))
+
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 5001, "x").HasStack())
+	assert.False(t, ex.New(ex.ExTypeApplicationFailure, 5001, "x").HasStack())
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, ex.New(ex.ExTypeApplicationFailure, 5001, "x").HasStack(), "counts restart each interval")
+}
+
+func TestStackPolicy_String(t *testing.T) {
+	assert.Equal(t, "OnRequest", ex.StackOnRequest.String())
+	assert.Equal(t, "Never", ex.StackNever.String())
+	assert.Equal(t, "Always", ex.StackAlways.String())
+	assert.Equal(t, "OnFailure", ex.StackOnFailure.String())
+	assert.Equal(t, "Sampled(0.01)", ex.StackSampled(0.01).String())
+	assert.Equal(t, "PerFingerprint(5/1m0s)", ex.StackPerFingerprint(5, time.Minute).String())
+}
@@ -0,0 +1,33 @@
+package ex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ToStd converts e into an equivalent chain of standard-library errors,
+// for handing errors to libraries that inspect concrete stdlib types.
+//
+// Every Exception level becomes a fmt.Errorf-wrapped error whose text is
+// prefixed with its code and ID in brackets:
+//
+//	[ApplicationFailure/500] Database operation failed: [IncorrectData/400] Bad input: connection timeout
+//
+// Non-Exception inner errors are wrapped verbatim, so errors.Is and
+// errors.As still find them. Fields, stacks, and the (Code, ID) identity
+// used by Exception.Is do not survive the conversion.
+func ToStd(e Exception) error {
+	label := "[" + e.code.String() + "/" + strconv.Itoa(e.id) + "]"
+	if e.message != "" {
+		label += " " + e.message
+	}
+	if e.innerError == nil {
+		return errors.New(label)
+	}
+	inner := e.innerError
+	if innerExc, ok := inner.(Exception); ok {
+		inner = ToStd(innerExc)
+	}
+	return fmt.Errorf("%s: %w", label, inner)
+}
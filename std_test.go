@@ -0,0 +1,38 @@
+package ex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToStd(t *testing.T) {
+	root := errors.New("connection timeout")
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Database operation failed").
+		WithInnerError(ex.New(ex.ExTypeIncorrectData, 400, "Bad input").WithInnerError(root))
+
+	std := ex.ToStd(exc)
+
+	assert.Equal(t, "[ApplicationFailure/500] Database operation failed: [IncorrectData/400] Bad input: connection timeout", std.Error())
+	assert.True(t, errors.Is(std, root), "non-Exception leaves are wrapped verbatim")
+
+	var target ex.Exception
+	assert.False(t, errors.As(std, &target), "no Exception remains in the chain")
+
+	depth := 0
+	for e := std; e != nil; e = errors.Unwrap(e) {
+		depth++
+	}
+	assert.Equal(t, 3, depth)
+}
+
+func TestToStd_NoInnerAndEmptyMessage(t *testing.T) {
+	std := ex.ToStd(ex.New(ex.ExTypePermissionDenied, 403, "Access denied"))
+	assert.Equal(t, "[PermissionDenied/403] Access denied", std.Error())
+	assert.Nil(t, errors.Unwrap(std))
+
+	std = ex.ToStd(ex.New(ex.ExTypeApplicationFailure, 500, "").WithInnerError(errors.New("boom")))
+	assert.Equal(t, "[ApplicationFailure/500]: boom", std.Error())
+}
@@ -0,0 +1,76 @@
+package ex
+
+// Standard detail types, modeled after the google.rpc error details, so
+// that services describe common failures in one shape and adapters such
+// as the problem package can render them. They are registered with
+// RegisterDetail under the names given on each type.
+
+// BadRequest lists the request fields that failed validation. Its detail
+// name is "ex.BadRequest".
+type BadRequest struct {
+	FieldViolations []BadField `json:"field_violations"`
+}
+
+// BadField describes one invalid request field.
+type BadField struct {
+	// Field is the path to the field, such as "address.zip".
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// PreconditionFailure lists the preconditions the request did not meet,
+// such as terms of service not yet accepted. Its detail name is
+// "ex.PreconditionFailure".
+type PreconditionFailure struct {
+	Violations []PreconditionViolation `json:"violations"`
+}
+
+// PreconditionViolation describes one failed precondition.
+type PreconditionViolation struct {
+	// Type is a service-specific category, such as "TOS".
+	Type string `json:"type"`
+	// Subject names what failed the check, such as "user:42".
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// QuotaFailure lists the quotas the request exceeded. Its detail name is
+// "ex.QuotaFailure". For a simple rate limit, WithRateLimit is usually
+// enough.
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations"`
+}
+
+// QuotaViolation describes one exceeded quota.
+type QuotaViolation struct {
+	// Subject names what ran out of quota, such as "project:7".
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// Help points the caller at documentation about the failure. Its detail
+// name is "ex.Help".
+type Help struct {
+	Links []HelpLink `json:"links"`
+}
+
+// HelpLink is one documentation link.
+type HelpLink struct {
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+func init() {
+	mustRegisterDetail[BadRequest]("ex.BadRequest")
+	mustRegisterDetail[PreconditionFailure]("ex.PreconditionFailure")
+	mustRegisterDetail[QuotaFailure]("ex.QuotaFailure")
+	mustRegisterDetail[Help]("ex.Help")
+}
+
+// mustRegisterDetail registers a built-in detail type, which cannot
+// conflict with anything at init time.
+func mustRegisterDetail[T any](name string) {
+	if err := RegisterDetail[T](name); err != nil {
+		panic(err)
+	}
+}
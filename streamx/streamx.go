@@ -0,0 +1,115 @@
+// Package streamx turns the failures of io.Reader and io.Writer into
+// exceptions that say where in the stream they happened, for parsers and
+// protocol servers built on ex.
+//
+// A Reader or Writer counts the bytes that pass through it and wraps any
+// error other than io.EOF in an exception carrying the name of the
+// operation and the byte offset at which it failed:
+//
+//	r := streamx.NewReader(conn, "frames.Decode")
+//	hdr := make([]byte, 8)
+//	if _, err := io.ReadFull(r, hdr); err != nil {
+//	    return err // Read failed, stream.op=frames.Decode stream.offset=3
+//	}
+//	if hdr[0] != magic {
+//	    return r.Fail(4201, "Bad frame magic") // stream.offset=8
+//	}
+//
+// Wrap a Reader in a bufio.Reader, rather than the other way round, to
+// get offsets of the underlying stream; offsets of a Reader wrapping a
+// bufio.Reader are those of the bytes the parser consumed.
+package streamx
+
+import (
+	"io"
+
+	"github.com/bold-minds/ex"
+)
+
+// Field keys of the exceptions made by Reader and Writer.
+const (
+	FieldOp     = "stream.op"
+	FieldOffset = "stream.offset"
+)
+
+// IDs of the exceptions made by Reader and Writer. Their codes are
+// inferred from the failure, as by ex.WrapAuto.
+const (
+	IDRead  = 1
+	IDWrite = 2
+)
+
+// Reader is an io.Reader that reports failures as exceptions.
+type Reader struct {
+	r   io.Reader
+	op  string
+	off int64
+}
+
+// NewReader returns a Reader reading from r on behalf of op, such as
+// "orders.ParseCSV".
+func NewReader(r io.Reader, op string) *Reader {
+	return &Reader{r: r, op: op}
+}
+
+// Read implements io.Reader. io.EOF is returned as is, so that callers
+// can compare against it; any other error is wrapped in an exception with
+// ID IDRead and the offset of the first byte that could not be read.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.off += int64(n)
+	if err == nil || err == io.EOF {
+		return n, err
+	}
+	return n, wrap(err, IDRead, "Read failed", r.op, r.off)
+}
+
+// Offset returns the number of bytes read so far.
+func (r *Reader) Offset() int64 {
+	return r.off
+}
+
+// Fail returns an ExTypeIncorrectData exception for malformed input found
+// at the current offset, with the same fields as a read failure.
+func (r *Reader) Fail(id int, message string) ex.Exception {
+	return ex.New(ex.ExTypeIncorrectData, id, message).
+		WithFields(ex.F(FieldOp, r.op), ex.F(FieldOffset, r.off))
+}
+
+// Writer is an io.Writer that reports failures as exceptions.
+type Writer struct {
+	w   io.Writer
+	op  string
+	off int64
+}
+
+// NewWriter returns a Writer writing to w on behalf of op.
+func NewWriter(w io.Writer, op string) *Writer {
+	return &Writer{w: w, op: op}
+}
+
+// Write implements io.Writer. Errors are wrapped in an exception with ID
+// IDWrite and the offset of the first byte that could not be written. A
+// short write without an error, which breaks the io.Writer contract, is
+// reported as io.ErrShortWrite.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.off += int64(n)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	if err == nil {
+		return n, nil
+	}
+	return n, wrap(err, IDWrite, "Write failed", w.op, w.off)
+}
+
+// Offset returns the number of bytes written so far.
+func (w *Writer) Offset() int64 {
+	return w.off
+}
+
+// wrap wraps err in an exception with the stream fields.
+func wrap(err error, id int, message, op string, off int64) ex.Exception {
+	return ex.WrapAuto(err, id, message).WithFields(ex.F(FieldOp, op), ex.F(FieldOffset, off))
+}
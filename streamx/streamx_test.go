@@ -0,0 +1,112 @@
+package streamx_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/streamx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldValue returns the value of the field key of e.
+func fieldValue(t *testing.T, e ex.Exception, key string) any {
+	t.Helper()
+	v, ok := e.FieldValue(key)
+	require.True(t, ok, "field %q", key)
+	return v
+}
+
+func TestReader_Failure(t *testing.T) {
+	broken := errors.New("connection reset by peer")
+	r := streamx.NewReader(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(broken)), "frames.Decode")
+
+	_, err := io.ReadFull(r, make([]byte, 8))
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeApplicationFailure, e.Code())
+	assert.Equal(t, streamx.IDRead, e.ID())
+	assert.Equal(t, "frames.Decode", fieldValue(t, e, streamx.FieldOp))
+	assert.Equal(t, int64(3), fieldValue(t, e, streamx.FieldOffset))
+	assert.ErrorIs(t, err, broken)
+	assert.Equal(t, int64(3), r.Offset())
+}
+
+func TestReader_EOF(t *testing.T) {
+	r := streamx.NewReader(strings.NewReader("line one\nline two\n"), "lines")
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	require.NoError(t, sc.Err())
+	assert.Equal(t, []string{"line one", "line two"}, lines)
+	assert.Equal(t, int64(18), r.Offset())
+
+	n, err := r.Read(make([]byte, 1))
+	assert.Zero(t, n)
+	assert.Same(t, io.EOF, err, "io.EOF is not wrapped")
+}
+
+func TestReader_Timeout(t *testing.T) {
+	r := streamx.NewReader(iotest.ErrReader(context.DeadlineExceeded), "rpc.Read")
+	_, err := r.Read(make([]byte, 2))
+	code, ok := ex.CodeOf(err)
+	require.True(t, ok)
+	assert.Equal(t, ex.ExTypeTimeout, code)
+}
+
+func TestReader_Fail(t *testing.T) {
+	r := streamx.NewReader(strings.NewReader("XXXXpayload"), "frames.Decode")
+	_, err := io.ReadFull(r, make([]byte, 4))
+	require.NoError(t, err)
+
+	e := r.Fail(4201, "Bad frame magic")
+	assert.Equal(t, ex.ExTypeIncorrectData, e.Code())
+	assert.Equal(t, "Bad frame magic", e.Error())
+	assert.Equal(t, int64(4), fieldValue(t, e, streamx.FieldOffset))
+}
+
+// shortWriter accepts at most limit bytes, silently.
+type shortWriter struct{ limit int }
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	n := min(len(p), w.limit)
+	w.limit -= n
+	return n, nil
+}
+
+func TestWriter(t *testing.T) {
+	var b strings.Builder
+	w := streamx.NewWriter(&b, "report.Write")
+	n, err := io.WriteString(w, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, int64(5), w.Offset())
+
+	short := streamx.NewWriter(&shortWriter{limit: 7}, "report.Write")
+	_, err = short.Write([]byte("0123456789"))
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	e, ok := ex.As(err)
+	require.True(t, ok)
+	assert.Equal(t, streamx.IDWrite, e.ID())
+	assert.Equal(t, int64(7), fieldValue(t, e, streamx.FieldOffset))
+	assert.Equal(t, "report.Write", fieldValue(t, e, streamx.FieldOp))
+}
+
+func TestWriter_Failure(t *testing.T) {
+	pr, pw := io.Pipe()
+	require.NoError(t, pr.Close())
+	w := streamx.NewWriter(pw, "pipe")
+	_, err := w.Write([]byte("x"))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	id, ok := ex.IDOf(err)
+	require.True(t, ok)
+	assert.Equal(t, streamx.IDWrite, id)
+}
@@ -0,0 +1,143 @@
+package ex
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// strict enables the unknown-code check in New; see SetStrict.
+var strict atomic.Bool
+
+// IsKnownType reports whether code is one of the predefined codes or has
+// been registered with RegisterType.
+func IsKnownType(code ExType) bool {
+	_, ok := LookupType(code)
+	return ok
+}
+
+// SetStrict turns strict mode on or off. In strict mode New panics when
+// given a code that is not known (see IsKnownType), so a typo such as
+// ExType(44) for ExTypeApplicationFailure fails loudly in development
+// instead of surfacing as "Unknown(44)" in production logs.
+//
+// Strict mode is meant for tests and development builds:
+//
+//	func TestMain(m *testing.M) {
+//	    ex.SetStrict(true)
+//	    os.Exit(m.Run())
+//	}
+//
+// Register custom codes before enabling it.
+func SetStrict(on bool) {
+	strict.Store(on)
+}
+
+// Strict reports whether strict mode is on.
+func Strict() bool {
+	return strict.Load()
+}
+
+// checkKnown panics if strict mode is on and code is not known.
+func checkKnown(code ExType) {
+	if strict.Load() && !IsKnownType(code) {
+		panic(fmt.Sprintf("ex: strict mode: unregistered exception code %s; register it with ex.RegisterType", code))
+	}
+}
+
+// StrictMessageLimit is the longest message, in bytes, that exceptions
+// may have in builds with the exstrict tag. Longer messages are usually
+// payloads or dumps that belong in a field.
+const StrictMessageLimit = 1024
+
+// strictIDs enables the duplicate ID check; see SetStrictIDs.
+var strictIDs atomic.Bool
+
+// SetStrictIDs turns the duplicate ID check of builds with the exstrict
+// tag on or off. When on, creating the same code and ID at two call sites
+// panics, which keeps every error of a service traceable to one place.
+// It is off by default because tests routinely create the same error at
+// several sites; turn it on from the TestMain of packages that define
+// errors:
+//
+//	func TestMain(m *testing.M) {
+//	    ex.SetStrictIDs(true)
+//	    os.Exit(m.Run())
+//	}
+//
+// Builds without the tag never check.
+func SetStrictIDs(on bool) {
+	strictIDs.Store(on)
+}
+
+var (
+	// idSitesMu guards idSites.
+	idSitesMu sync.Mutex
+	// idSites maps each code and ID to the call site that first used
+	// them, in builds with the exstrict tag.
+	idSites = map[CodeID]string{}
+
+	// modulePrefix is the prefix of the functions of this module's
+	// subpackages, which are exempt from the duplicate ID check.
+	modulePrefix = strings.TrimSuffix(pkgPrefix, ".") + "/"
+)
+
+// checkNew applies the checks of the constructors that take a code and
+// ID from their caller: strict mode, and the misuse checks in builds with
+// the exstrict tag.
+func checkNew(code ExType, id int, message string) {
+	checkKnown(code)
+	if buildStrict {
+		checkMisuse(code, id, message, 3)
+	}
+}
+
+// checkMisuse panics if message is longer than StrictMessageLimit, or, if
+// SetStrictIDs is on, if code and id were used before at a call site other
+// than the one skip frames above checkMisuse. ID 0, which means no ID, and
+// calls from this module are not checked for duplicates.
+func checkMisuse(code ExType, id int, message string, skip int) {
+	if len(message) > StrictMessageLimit {
+		panic(fmt.Sprintf("ex: exstrict: message of %s/%d is %d bytes, over StrictMessageLimit (%d); move the details to fields",
+			code, id, len(message), StrictMessageLimit))
+	}
+	if id == 0 || !strictIDs.Load() {
+		return
+	}
+	pc, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil && (strings.HasPrefix(fn.Name(), pkgPrefix) || strings.HasPrefix(fn.Name(), modulePrefix)) {
+		return
+	}
+	site := file + ":" + strconv.Itoa(line)
+	key := CodeID{Code: code, ID: id}
+	idSitesMu.Lock()
+	first, seen := idSites[key]
+	if !seen {
+		idSites[key] = site
+	}
+	idSitesMu.Unlock()
+	if seen && first != site {
+		panic(fmt.Sprintf("ex: exstrict: duplicate ID: %s/%d is created at %s and at %s; give each error its own ID",
+			code, id, first, site))
+	}
+}
+
+// checkInner panics, in builds with the exstrict tag, if e.WithInnerError
+// is given e itself (an exception with e's code, ID, and text), or nil
+// when e has no inner error to clear, which is almost always an error
+// variable that was never set.
+func checkInner(e Exception, err error) {
+	if err == nil && e.innerError == nil {
+		panic(fmt.Sprintf("ex: exstrict: %s/%d: WithInnerError(nil); wrap only non-nil errors", e.code, e.id))
+	}
+	if inner, ok := err.(Exception); ok && inner.code == e.code && inner.id == e.id && inner.message == e.message &&
+		inner.Error() == e.Error() {
+		panic(fmt.Sprintf("ex: exstrict: %s/%d: WithInnerError with the exception itself", e.code, e.id))
+	}
+}
@@ -0,0 +1,16 @@
+//go:build exstrict
+
+package ex
+
+// buildStrict enables the misuse checks; see StrictMessageLimit.
+const buildStrict = true
+
+// Building with -tags exstrict turns strict mode on from the start and
+// makes misuse panic with a diagnostic: unregistered codes (see
+// SetStrict), an ID used for the same code at two call sites once
+// SetStrictIDs turns that check on, messages over StrictMessageLimit, and
+// WithInnerError given the exception itself or a nil error with nothing
+// to clear. Builds without the tag tolerate all of them.
+func init() {
+	strict.Store(true)
+}
@@ -0,0 +1,6 @@
+//go:build !exstrict
+
+package ex
+
+// buildStrict enables the misuse checks of the exstrict build tag.
+const buildStrict = false
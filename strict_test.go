@@ -0,0 +1,51 @@
+package ex_test
+
+import (
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKnownType(t *testing.T) {
+	assert.True(t, ex.IsKnownType(ex.ExTypeIncorrectData))
+	assert.True(t, ex.IsKnownType(ex.ExTypeApplicationFailure))
+	assert.False(t, ex.IsKnownType(ex.ExType(0)))
+	assert.False(t, ex.IsKnownType(ex.ExType(4401)))
+
+	require.NoError(t, ex.RegisterType(ex.ExType(4402), ex.TypeInfo{Name: "Throttled"}))
+	assert.True(t, ex.IsKnownType(ex.ExType(4402)))
+}
+
+func TestSetStrict(t *testing.T) {
+	saved := ex.Strict()
+	ex.SetStrict(true)
+	t.Cleanup(func() { ex.SetStrict(saved) })
+	assert.True(t, ex.Strict())
+
+	assert.NotPanics(t, func() { ex.New(ex.ExTypeApplicationFailure, 500, "ok") })
+	assert.PanicsWithValue(t,
+		"ex: strict mode: unregistered exception code Unknown(44); register it with ex.RegisterType",
+		func() { ex.New(ex.ExType(44), 500, "typo") })
+
+	ex.SetStrict(false)
+	assert.NotPanics(t, func() { ex.New(ex.ExType(44), 500, "tolerated") })
+}
+
+// lenient turns strict mode off for the rest of the test, for tests that
+// create unregistered codes on purpose; exstrict builds turn it on.
+func lenient(t *testing.T) {
+	t.Helper()
+	saved := ex.Strict()
+	ex.SetStrict(false)
+	t.Cleanup(func() { ex.SetStrict(saved) })
+}
+
+// skipExstrict skips tests of behavior that exstrict builds reject.
+func skipExstrict(t *testing.T) {
+	t.Helper()
+	if ex.BuildStrict {
+		t.Skip("exstrict builds panic on this misuse")
+	}
+}
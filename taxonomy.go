@@ -0,0 +1,125 @@
+package ex
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrInvalidMigration is returned by RegisterMigration for a migration
+// that does not move to a later version, or whose starting version
+// already has a migration registered.
+var ErrInvalidMigration = errors.New("ex: invalid taxonomy migration")
+
+// taxonomyVersion is the version of this service's error-code scheme; see
+// SetTaxonomyVersion.
+var taxonomyVersion atomic.Int64
+
+// CodeID identifies an error by its code and ID.
+type CodeID struct {
+	Code ExType
+	ID   int
+}
+
+// Migration maps the (code, ID) pairs of one taxonomy version to their
+// replacements in the next. Pairs that are not listed carry over
+// unchanged.
+type Migration map[CodeID]CodeID
+
+// migrationStep is a registered Migration with its target version.
+type migrationStep struct {
+	to int
+	m  Migration
+}
+
+// migrations holds the registered migrations keyed by starting version.
+// It is copy-on-write: writers replace the whole map under migrationsMu.
+var (
+	migrationsMu sync.Mutex
+	migrations   atomic.Pointer[map[int]migrationStep]
+)
+
+// SetTaxonomyVersion sets the version of this service's error-code
+// scheme. Bump it whenever codes or IDs are renumbered. Every locally
+// created exception is tagged with it when encoded, in a "taxonomy"
+// member, and so is the catalog Snapshot. Zero, the default, means the
+// scheme is unversioned and nothing is tagged.
+func SetTaxonomyVersion(v int) {
+	taxonomyVersion.Store(int64(v))
+}
+
+// TaxonomyVersion returns the version set by SetTaxonomyVersion.
+func TaxonomyVersion() int {
+	return int(taxonomyVersion.Load())
+}
+
+// RegisterMigration registers m as the step from taxonomy version from to
+// version to. When an exception tagged with an older version than this
+// service's is decoded, the steps are applied in turn, starting at its
+// version, until it reaches this service's version or no further step is
+// registered:
+//
+//	ex.SetTaxonomyVersion(2)
+//	ex.RegisterMigration(1, 2, ex.Migration{
+//	    {Code: ex.ExTypeIncorrectData, ID: 4001}: {Code: ex.ExTypeNotFound, ID: 4041},
+//	})
+//
+// Exceptions from unversioned producers count as version 0, so a step
+// from 0 upgrades them too. It returns ErrInvalidMigration unless from is
+// non-negative and less than to, or if a step from from is already
+// registered.
+func RegisterMigration(from, to int, m Migration) error {
+	if from < 0 || to <= from {
+		return fmt.Errorf("%w %d -> %d", ErrInvalidMigration, from, to)
+	}
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	var cur map[int]migrationStep
+	if p := migrations.Load(); p != nil {
+		cur = *p
+	}
+	if _, exists := cur[from]; exists {
+		return fmt.Errorf("%w: version %d already migrates", ErrInvalidMigration, from)
+	}
+	next := make(map[int]migrationStep, len(cur)+1)
+	for k, v := range cur {
+		next[k] = v
+	}
+	next[from] = migrationStep{to: to, m: m}
+	migrations.Store(&next)
+	return nil
+}
+
+// migrate upgrades (code, id) from taxonomy version v as far as the
+// registered migrations allow, stopping at this service's version. It
+// returns the resulting pair and version.
+func migrate(code ExType, id, v int) (ExType, int, int) {
+	p := migrations.Load()
+	if p == nil {
+		return code, id, v
+	}
+	current := TaxonomyVersion()
+	for v < current {
+		step, ok := (*p)[v]
+		if !ok {
+			break
+		}
+		if next, mapped := step.m[CodeID{code, id}]; mapped {
+			code, id = next.Code, next.ID
+		}
+		v = step.to
+	}
+	return code, id, v
+}
+
+// Taxonomy returns the taxonomy version the exception's code and ID
+// belong to: this service's version (see SetTaxonomyVersion) for local
+// exceptions, and for decoded ones the version they were tagged with,
+// after any migrations applied on decode.
+func (e Exception) Taxonomy() int {
+	if e.remote {
+		return e.taxonomy
+	}
+	return TaxonomyVersion()
+}
@@ -0,0 +1,93 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTaxonomy(t *testing.T, v int) {
+	t.Helper()
+	ex.SetTaxonomyVersion(v)
+	t.Cleanup(func() {
+		ex.SetTaxonomyVersion(0)
+		ex.ResetMigrations()
+	})
+}
+
+func TestTaxonomy_TagsEncoding(t *testing.T) {
+	useTaxonomy(t, 3)
+
+	exc := ex.New(ex.ExTypeIncorrectData, 400, "Bad input")
+	assert.Equal(t, 3, exc.Taxonomy())
+
+	data, err := json.Marshal(exc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code":1,"type":"IncorrectData","id":400,"taxonomy":3,"message":"Bad input"}`, string(data))
+
+	// The canonical form ignores the version.
+	canonical, err := exc.MarshalCanonical()
+	require.NoError(t, err)
+	assert.NotContains(t, string(canonical), "taxonomy")
+
+	assert.Equal(t, 3, ex.NewCatalog().Snapshot().Taxonomy)
+}
+
+func TestTaxonomy_Unversioned(t *testing.T) {
+	data, err := json.Marshal(ex.New(ex.ExTypeIncorrectData, 400, "Bad input"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "taxonomy")
+}
+
+func TestTaxonomy_MigratesOnDecode(t *testing.T) {
+	useTaxonomy(t, 3)
+	require.NoError(t, ex.RegisterMigration(1, 2, ex.Migration{
+		{Code: ex.ExTypeIncorrectData, ID: 4001}: {Code: ex.ExTypeNotFound, ID: 4041},
+	}))
+	require.NoError(t, ex.RegisterMigration(2, 3, ex.Migration{
+		{Code: ex.ExTypeNotFound, ID: 4041}: {Code: ex.ExTypeNotFound, ID: 40401},
+	}))
+
+	payload := `{"code":4,"id":500,"taxonomy":1,"message":"Lookup failed",
+		"inner":{"code":1,"id":4001,"taxonomy":1,"message":"No such user"}}`
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal([]byte(payload), &decoded))
+
+	assert.Equal(t, 3, decoded.Taxonomy())
+	assert.Equal(t, 500, decoded.ID())
+	var inner ex.Exception
+	require.True(t, errors.As(decoded.InnerError(), &inner))
+	assert.Equal(t, ex.ExTypeNotFound, inner.Code())
+	assert.Equal(t, 40401, inner.ID())
+	assert.Equal(t, "No such user", inner.Message())
+
+	// Current and newer versions are left alone.
+	require.NoError(t, json.Unmarshal([]byte(`{"code":1,"id":4001,"taxonomy":4,"message":"x"}`), &decoded))
+	assert.Equal(t, 4001, decoded.ID())
+	assert.Equal(t, 4, decoded.Taxonomy())
+}
+
+func TestTaxonomy_MigrationStopsAtGap(t *testing.T) {
+	useTaxonomy(t, 3)
+	require.NoError(t, ex.RegisterMigration(0, 1, ex.Migration{
+		{Code: ex.ExTypeIncorrectData, ID: 1}: {Code: ex.ExTypeIncorrectData, ID: 10},
+	}))
+
+	// Untagged payloads count as version 0.
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal([]byte(`{"code":1,"id":1,"message":"x"}`), &decoded))
+	assert.Equal(t, 10, decoded.ID())
+	assert.Equal(t, 1, decoded.Taxonomy())
+}
+
+func TestRegisterMigration_Invalid(t *testing.T) {
+	useTaxonomy(t, 2)
+	assert.ErrorIs(t, ex.RegisterMigration(2, 2, nil), ex.ErrInvalidMigration)
+	assert.ErrorIs(t, ex.RegisterMigration(-1, 1, nil), ex.ErrInvalidMigration)
+	require.NoError(t, ex.RegisterMigration(1, 2, nil))
+	assert.ErrorIs(t, ex.RegisterMigration(1, 3, nil), ex.ErrInvalidMigration)
+}
@@ -0,0 +1,110 @@
+// Package temporalx converts exceptions to the parts of a Temporal
+// ApplicationError and back, so activity failures keep their code, ID,
+// and fields across workflow boundaries.
+//
+// The module does not depend on the Temporal SDK, so the conversion works
+// on plain values; binding it takes a line in each direction:
+//
+//	// In an activity:
+//	if err != nil {
+//	    a := temporalx.ToApplicationError(err)
+//	    return temporal.NewApplicationErrorWithOptions(a.Message, a.Type,
+//	        temporal.ApplicationErrorOptions{NonRetryable: a.NonRetryable, Details: []any{a.Details}})
+//	}
+//
+//	// In the workflow:
+//	var appErr *temporal.ApplicationError
+//	if errors.As(err, &appErr) {
+//	    err = temporalx.FromApplicationError(appErr.Error(), appErr.Type(), appErr.Details)
+//	}
+package temporalx
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/bold-minds/ex"
+)
+
+// ApplicationError holds the arguments for
+// temporal.NewApplicationErrorWithOptions.
+type ApplicationError struct {
+	Message string
+	// Type is the exception's code name, such as "NotFound", which retry
+	// policies can list in NonRetryableErrorTypes.
+	Type string
+	// NonRetryable is set for failures that would recur if retried
+	// unchanged (see ex.Retryable), such as bad input, but not for rate
+	// limits or transient failures.
+	NonRetryable bool
+	// Details is the single detail value to attach.
+	Details Details
+}
+
+// Details is the detail payload of an ApplicationError. Fields are listed
+// on their own so they are readable in the Temporal UI; Error holds the
+// JSON encoding of the whole chain (see ex.Exception.MarshalJSON), with
+// its type, taxonomy version, origin, and trace context.
+type Details struct {
+	Code   ex.ExType       `json:"code"`
+	ID     int             `json:"id"`
+	Fields map[string]any  `json:"fields,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// ToApplicationError describes err as a Temporal ApplicationError. Errors
+// other than Exceptions are promoted first (see ex.Promote), and
+// sensitive field values are redacted.
+func ToApplicationError(err error) ApplicationError {
+	e, ok := ex.As(err)
+	if !ok {
+		e = ex.Promote(err, nil)
+	}
+	e = e.Redacted()
+	d := Details{Code: e.Code(), ID: e.ID()}
+	if fields := ex.FieldsOf(e); len(fields) > 0 {
+		d.Fields = make(map[string]any, len(fields))
+		for _, f := range fields {
+			if _, leveled := f.Level(); !leveled {
+				d.Fields[f.Key] = ex.ResolveValue(f.Value)
+			}
+		}
+	}
+	if data, mErr := e.MarshalJSON(); mErr == nil {
+		d.Error = data
+	}
+	return ApplicationError{
+		Message:      e.Error(),
+		Type:         e.Code().String(),
+		NonRetryable: !ex.Retryable(e),
+		Details:      d,
+	}
+}
+
+// FromApplicationError rebuilds an exception from a Temporal
+// ApplicationError's message, type, and Details method. The encoded chain
+// in its Details is decoded if present; failing that, an exception is
+// built from the code, ID, and fields. ApplicationErrors that did not come
+// from ToApplicationError become ExTypeApplicationFailure exceptions with
+// the error type in a "temporal.type" field.
+func FromApplicationError(message, errType string, details func(valuePtrs ...any) error) ex.Exception {
+	var d Details
+	if details == nil || details(&d) != nil {
+		d = Details{}
+	}
+	if len(d.Error) > 0 {
+		var e ex.Exception
+		if json.Unmarshal(d.Error, &e) == nil {
+			return e
+		}
+	}
+	if d.Code == 0 {
+		return ex.New(ex.ExTypeApplicationFailure, 0, message).WithField("temporal.type", errType)
+	}
+	fields := make([]ex.Field, 0, len(d.Fields))
+	for _, k := range slices.Sorted(maps.Keys(d.Fields)) {
+		fields = append(fields, ex.F(k, d.Fields[k]))
+	}
+	return ex.New(d.Code, d.ID, message).WithFields(fields...)
+}
@@ -0,0 +1,90 @@
+package temporalx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/temporalx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// detailsOf mimics ApplicationError.Details after Temporal's JSON data
+// converter has carried d across the wire.
+func detailsOf(t *testing.T, d any) func(...any) error {
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	return func(ptrs ...any) error {
+		return json.Unmarshal(data, ptrs[0])
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	sent := ex.New(ex.ExTypeNotFound, 4041, "No such order").
+		WithField("order", "A-17").
+		WithField("secret", "x").
+		WithInnerError(errors.New("sql: no rows in result set"))
+
+	a := temporalx.ToApplicationError(fmt.Errorf("activity: %w", sent))
+	assert.Equal(t, "NotFound", a.Type)
+	assert.True(t, a.NonRetryable)
+	assert.Equal(t, sent.Error(), a.Message)
+	assert.Equal(t, map[string]any{"order": "A-17", "secret": ex.RedactedValue}, a.Details.Fields)
+
+	got := temporalx.FromApplicationError(a.Message, a.Type, detailsOf(t, a.Details))
+	assert.True(t, errors.Is(got, sent))
+	assert.Equal(t, sent.Error(), got.Error())
+	assert.True(t, got.Remote())
+}
+
+func TestToApplicationError_ServerFaultIsRetryable(t *testing.T) {
+	a := temporalx.ToApplicationError(ex.New(ex.ExTypeUnavailable, 503, "Down"))
+	assert.False(t, a.NonRetryable)
+	assert.Equal(t, "Unavailable", a.Type)
+
+	a = temporalx.ToApplicationError(errors.New("boom"))
+	assert.False(t, a.NonRetryable)
+	assert.Equal(t, "ApplicationFailure", a.Type)
+}
+
+func TestFromApplicationError_Fallbacks(t *testing.T) {
+	d := temporalx.Details{Code: ex.ExTypeConflict, ID: 4091, Fields: map[string]any{"b": 2.0, "a": "x"}}
+	got := temporalx.FromApplicationError("Already exists", "Conflict", detailsOf(t, d))
+	assert.Equal(t, ex.ExTypeConflict, got.Code())
+	assert.Equal(t, 4091, got.ID())
+	assert.Equal(t, []ex.Field{ex.F("a", "x"), ex.F("b", 2.0)}, got.Fields())
+
+	foreign := temporalx.FromApplicationError("card declined", "CardDeclined", func(...any) error { return errors.New("no details") })
+	assert.Equal(t, ex.ExTypeApplicationFailure, foreign.Code())
+	assert.Equal(t, "card declined", foreign.Message())
+	v, _ := foreign.FieldValue("temporal.type")
+	assert.Equal(t, "CardDeclined", v)
+
+	assert.Equal(t, ex.ExTypeApplicationFailure, temporalx.FromApplicationError("x", "T", nil).Code())
+}
+
+func TestToApplicationError_Retryable(t *testing.T) {
+	a := temporalx.ToApplicationError(ex.New(ex.ExTypeRateLimited, 4291, "Too many requests"))
+	assert.False(t, a.NonRetryable, "rate limits clear up")
+
+	a = temporalx.ToApplicationError(ex.New(ex.ExTypeConflict|ex.FacetTransient, 4092, "Row locked"))
+	assert.False(t, a.NonRetryable, "transient failures are retried")
+
+	a = temporalx.ToApplicationError(ex.New(ex.ExTypeIncorrectData, 400, "Invalid email"))
+	assert.True(t, a.NonRetryable)
+}
+
+func TestRoundTrip_Metadata(t *testing.T) {
+	sent := ex.New(ex.ExTypeNotFound, 4041, "No such order").
+		WithTraceContext(ex.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+
+	a := temporalx.ToApplicationError(sent)
+	got := temporalx.FromApplicationError(a.Message, a.Type, detailsOf(t, a.Details))
+	tc, ok := got.TraceContext()
+	require.True(t, ok)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tc.TraceParent)
+	assert.Equal(t, sent.Taxonomy(), got.Taxonomy())
+}
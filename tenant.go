@@ -0,0 +1,66 @@
+package ex
+
+import "context"
+
+// TenantKey is the field under which WithTenant stores the tenant.
+const TenantKey = "tenant"
+
+// tenantKey is the context key of the tenant.
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying the tenant id, usually
+// set once by the middleware that authenticates a request.
+func ContextWithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFrom returns the tenant carried by ctx, or "".
+func TenantFrom(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// WithTenant returns a new Exception tagged with the tenant id, as the
+// TenantKey field, so the tenant survives encoding and reporters can keep
+// each tenant's errors apart (see ReporterConfig.PerTenant). An empty id
+// leaves the exception unchanged, so tagging from a context that may not
+// carry a tenant is safe:
+//
+//	return ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant(ex.TenantFrom(ctx))
+func (e Exception) WithTenant(id string) Exception {
+	if id == "" {
+		return e
+	}
+	return e.WithField(TenantKey, id)
+}
+
+// TagTenant returns err tagged with the tenant carried by ctx (see
+// ContextWithTenant), as Annotate with a TenantKey field, unless ctx
+// carries none or err already has one. Adapters that are handed a context,
+// such as Collect and slogx.Log, tag errors with it themselves, so a
+// tenant set once by middleware reaches them without WithTenant calls:
+//
+//	ex.Collect(ctx, err) // tagged with ex.TenantFrom(ctx)
+func TagTenant(ctx context.Context, err error) error {
+	id := TenantFrom(ctx)
+	if id == "" || err == nil {
+		return err
+	}
+	if _, tagged := TenantOf(err); tagged {
+		return err
+	}
+	return Annotate(err, F(TenantKey, id))
+}
+
+// TenantOf returns the tenant err was tagged with by WithTenant, or
+// Annotate with a TenantKey field, anywhere in its chain; the outermost
+// tag wins.
+func TenantOf(err error) (string, bool) {
+	for _, f := range FieldsOf(err) {
+		if f.Key == TenantKey {
+			id, ok := f.Value.(string)
+			return id, ok && id != ""
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,159 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTenant(t *testing.T) {
+	ctx := ex.ContextWithTenant(context.Background(), "acme")
+	assert.Equal(t, "acme", ex.TenantFrom(ctx))
+	assert.Empty(t, ex.TenantFrom(context.Background()))
+
+	e := ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant(ex.TenantFrom(ctx))
+	tenant, ok := ex.TenantOf(e)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+
+	wrapped := ex.WrapAuto(e, 5001, "Checkout failed")
+	tenant, ok = ex.TenantOf(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+
+	plain := ex.New(ex.ExTypeNotFound, 4041, "No such order")
+	assert.Equal(t, plain, plain.WithTenant(""))
+	_, ok = ex.TenantOf(plain)
+	assert.False(t, ok)
+	_, ok = ex.TenantOf(errors.New("plain"))
+	assert.False(t, ok)
+}
+
+func TestWithTenant_SurvivesJSON(t *testing.T) {
+	e := ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("acme")
+	data, err := e.MarshalJSON()
+	require.NoError(t, err)
+
+	var decoded ex.Exception
+	require.NoError(t, decoded.UnmarshalJSON(data))
+	tenant, ok := ex.TenantOf(decoded)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+}
+
+// tenantSink records the batches a Reporter flushes by the tenant of
+// their ctx.
+type tenantSink struct {
+	mu      sync.Mutex
+	tenants []string
+	batches [][]ex.Aggregate
+}
+
+func (s *tenantSink) sink(ctx context.Context, batch []ex.Aggregate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants = append(s.tenants, ex.TenantFrom(ctx))
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func TestReporter_PerTenant(t *testing.T) {
+	s := &tenantSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour, MaxGroups: 2, PerTenant: true})
+	t.Cleanup(func() { _ = r.Shutdown(context.Background()) })
+
+	for id := range 3 {
+		r.Report(ex.New(ex.ExTypeNotFound, 4041+id, "No such order").WithTenant("acme"))
+	}
+	r.Report(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("globex"))
+	r.Report(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("globex"))
+	r.Report(ex.New(ex.ExTypeNotFound, 4041, "No such order"))
+	require.NoError(t, r.Flush(context.Background()))
+
+	assert.Equal(t, []string{"acme", "globex", ""}, s.tenants)
+	require.Len(t, s.batches, 3)
+	require.Len(t, s.batches[0], 2)
+	for _, a := range s.batches[0] {
+		assert.Equal(t, "acme", a.Tenant)
+	}
+	require.Len(t, s.batches[1], 1)
+	assert.Equal(t, "globex", s.batches[1][0].Tenant)
+	assert.Equal(t, 2, s.batches[1][0].Count)
+	require.Len(t, s.batches[2], 1)
+	assert.Empty(t, s.batches[2][0].Tenant)
+	assert.Equal(t, uint64(1), r.Dropped())
+
+	// Group limits start over after each flush.
+	r.Report(ex.New(ex.ExTypeNotFound, 4050, "No such order").WithTenant("acme"))
+	require.NoError(t, r.Flush(context.Background()))
+	assert.Len(t, s.batches, 4)
+}
+
+func TestReporter_TenantAtBoundary(t *testing.T) {
+	s := &tenantSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour, PerTenant: true})
+	t.Cleanup(func() { _ = r.Shutdown(context.Background()) })
+	ctx := ex.ContextWithTenant(context.Background(), "acme")
+
+	remove := ex.AddHook(r.Hook())
+	_ = ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("acme")
+	remove()
+	require.NoError(t, r.Flush(context.Background()))
+	assert.Equal(t, []string{""}, s.tenants, "hooks run before the tenant is tagged")
+
+	router := ex.NewRouter().Fallback(ex.Report(r.Report))
+	_ = router.Handle(ex.TagTenant(ctx, ex.New(ex.ExTypeNotFound, 4041, "No such order")))
+	r.Report(ex.TagTenant(ctx, errors.New("plain")))
+	require.NoError(t, r.Flush(context.Background()))
+	assert.Equal(t, []string{"", "acme"}, s.tenants)
+	require.Len(t, s.batches, 2)
+	assert.Len(t, s.batches[1], 2)
+}
+
+func TestReporter_MaxTenants(t *testing.T) {
+	s := &tenantSink{}
+	r := ex.NewReporter(ex.ReporterConfig{Sink: s.sink, FlushInterval: time.Hour, PerTenant: true, MaxTenants: 2})
+	t.Cleanup(func() { _ = r.Shutdown(context.Background()) })
+
+	for _, tenant := range []string{"acme", "globex", "initech", "umbrella", "acme"} {
+		r.Report(ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant(tenant))
+	}
+	require.NoError(t, r.Flush(context.Background()))
+
+	assert.Equal(t, []string{"acme", "globex", ex.OverflowTenant}, s.tenants)
+	require.Len(t, s.batches, 3)
+	assert.Equal(t, 2, s.batches[0][0].Count)
+	require.Len(t, s.batches[2], 1)
+	assert.Equal(t, ex.OverflowTenant, s.batches[2][0].Tenant)
+	assert.Equal(t, 2, s.batches[2][0].Count, "later tenants share the overflow groups")
+	assert.Zero(t, r.Dropped())
+}
+
+func TestTagTenant(t *testing.T) {
+	ctx := ex.ContextWithTenant(context.Background(), "acme")
+
+	err := ex.TagTenant(ctx, errors.New("plain"))
+	tenant, ok := ex.TenantOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, "plain", err.Error())
+
+	tagged := ex.New(ex.ExTypeNotFound, 4041, "No such order").WithTenant("globex")
+	assert.Equal(t, error(tagged), ex.TagTenant(ctx, tagged), "an existing tenant is kept")
+
+	plain := errors.New("plain")
+	assert.Equal(t, plain, ex.TagTenant(context.Background(), plain))
+	assert.NoError(t, ex.TagTenant(ctx, nil))
+
+	c := ex.NewCollector()
+	assert.True(t, ex.Collect(ex.ContextWithCollector(ctx, c), errors.New("cache miss")))
+	tenant, ok = ex.TenantOf(c.Errors()[0])
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+}
@@ -0,0 +1,56 @@
+package ex
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// IsTimeout reports whether err, or any error in its chain, is a timeout:
+// an Exception whose code has category ExTypeTimeout, whatever its
+// facets, context.DeadlineExceeded, os.ErrDeadlineExceeded, or an error
+// with a Timeout() method reporting true, as net.Error has.
+func IsTimeout(err error) bool {
+	return hasCode(err, ExTypeTimeout) || isStdTimeout(err)
+}
+
+// IsUnavailable reports whether err, or any error in its chain, is an
+// Exception whose code has category ExTypeUnavailable, whatever its
+// facets.
+func IsUnavailable(err error) bool {
+	return hasCode(err, ExTypeUnavailable)
+}
+
+// isStdTimeout recognizes the standard library's timeout errors.
+func isStdTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// hasCode reports whether any Errorer in err's chain, including every
+// branch of errors that aggregate several (see members), has a code of
+// category code.
+func hasCode(err error, code ExType) bool {
+	for err != nil {
+		if e, ok := err.(Errorer); ok && e.Code().Category() == code {
+			return true
+		}
+		if branches, ok := members(err); ok {
+			for _, branch := range branches {
+				if hasCode(branch, code) {
+					return true
+				}
+			}
+			return false
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return false
+		}
+		err = u.Unwrap()
+	}
+	return false
+}
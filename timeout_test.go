@@ -0,0 +1,59 @@
+package ex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutAndUnavailableTypes(t *testing.T) {
+	info, ok := ex.LookupType(ex.ExTypeTimeout)
+	require.True(t, ok)
+	assert.Equal(t, ex.TypeInfo{Severity: ex.SeverityError, HTTPStatus: 504, GRPCCode: 4}, info)
+
+	info, ok = ex.LookupType(ex.ExTypeUnavailable)
+	require.True(t, ok)
+	assert.Equal(t, ex.TypeInfo{Severity: ex.SeverityError, HTTPStatus: 503, GRPCCode: 14}, info)
+
+	assert.True(t, ex.IsServerFault(ex.New(ex.ExTypeTimeout, 1, "x")))
+	assert.True(t, ex.IsServerFault(ex.New(ex.ExTypeUnavailable, 1, "x")))
+}
+
+func TestIsTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	var dnsTimeout net.Error = &net.DNSError{Err: "timeout", IsTimeout: true}
+
+	assert.True(t, ex.IsTimeout(ex.New(ex.ExTypeTimeout, 1, "slow")))
+	assert.True(t, ex.IsTimeout(ex.New(ex.ExTypeApplicationFailure, 500, "failed").
+		WithInnerError(ex.New(ex.ExTypeTimeout, 1, "slow"))))
+	assert.True(t, ex.IsTimeout(fmt.Errorf("query: %w", ctx.Err())))
+	assert.True(t, ex.IsTimeout(os.ErrDeadlineExceeded))
+	assert.True(t, ex.IsTimeout(dnsTimeout))
+	assert.True(t, ex.IsTimeout(errors.Join(errors.New("a"), ex.New(ex.ExTypeTimeout, 1, "b"))))
+
+	assert.False(t, ex.IsTimeout(ex.New(ex.ExTypeApplicationFailure, 500, "failed")))
+	assert.False(t, ex.IsTimeout(&net.DNSError{Err: "no such host"}))
+	assert.False(t, ex.IsTimeout(nil))
+
+	assert.True(t, ex.IsUnavailable(fmt.Errorf("call: %w", ex.New(ex.ExTypeUnavailable, 1, "down"))))
+	assert.False(t, ex.IsUnavailable(ex.New(ex.ExTypeTimeout, 1, "slow")))
+}
+
+func TestPromote_ClassifiesTimeouts(t *testing.T) {
+	err := fmt.Errorf("loading profile: %w", context.DeadlineExceeded)
+	e := ex.Promote(err, nil)
+	assert.Equal(t, ex.ExTypeTimeout, e.Code())
+	assert.Equal(t, "loading profile: context deadline exceeded", e.Error())
+	assert.True(t, ex.IsTimeout(e))
+}
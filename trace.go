@@ -0,0 +1,75 @@
+package ex
+
+// TraceContext is a W3C Trace Context (https://www.w3.org/TR/trace-context/)
+// attached to an exception, so the exception can be correlated with the
+// trace it originated in even after crossing a queue or other transport
+// that drops request headers.
+type TraceContext struct {
+	// TraceParent is the traceparent header value, e.g.
+	// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+	TraceParent string `json:"traceparent"`
+
+	// TraceState is the optional tracestate header value.
+	TraceState string `json:"tracestate,omitempty"`
+}
+
+// Valid reports whether TraceParent is a well-formed version-00
+// traceparent with non-zero trace and parent IDs. Future versions, which
+// may append fields, are accepted when their common prefix is valid.
+func (tc TraceContext) Valid() bool {
+	p := tc.TraceParent
+	if len(p) < 55 || (len(p) > 55 && p[55] != '-') {
+		return false
+	}
+	if p[2] != '-' || p[35] != '-' || p[52] != '-' {
+		return false
+	}
+	version, traceID, parentID, flags := p[0:2], p[3:35], p[36:52], p[53:55]
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(p) != 55) {
+		return false
+	}
+	return isLowerHex(traceID) && !isZeros(traceID) &&
+		isLowerHex(parentID) && !isZeros(parentID) &&
+		isLowerHex(flags)
+}
+
+// WithTraceContext returns a new Exception carrying tc, which is then
+// included in its JSON encoding. Attaching a trace context is the opt-in:
+// exceptions without one encode no trace information. An invalid tc (see
+// Valid) is ignored.
+func (e Exception) WithTraceContext(tc TraceContext) Exception {
+	if !tc.Valid() {
+		return e
+	}
+	e.trace = &tc
+	e.frozen = nil
+	return e
+}
+
+// TraceContext returns the trace context attached to the exception, either
+// with WithTraceContext or by decoding an encoding that carried one.
+func (e Exception) TraceContext() (TraceContext, bool) {
+	if e.trace == nil {
+		return TraceContext{}, false
+	}
+	return *e.trace, true
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZeros(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+	return true
+}
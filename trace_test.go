@@ -0,0 +1,71 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTraceContext_Valid(t *testing.T) {
+	tests := []struct {
+		parent string
+		want   bool
+	}{
+		{testTraceParent, true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ex.TraceContext{TraceParent: tt.parent}.Valid(), tt.parent)
+	}
+}
+
+func TestTraceContext_RoundTrip(t *testing.T) {
+	tc := ex.TraceContext{TraceParent: testTraceParent, TraceState: "vendor=abc"}
+	exc := ex.New(ex.ExTypeApplicationFailure, 500, "Job failed").WithTraceContext(tc)
+
+	got, ok := exc.TraceContext()
+	require.True(t, ok)
+	assert.Equal(t, tc, got)
+
+	data, err := json.Marshal(exc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"trace":{"traceparent":"`+testTraceParent+`","tracestate":"vendor=abc"}`)
+
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	got, ok = decoded.TraceContext()
+	require.True(t, ok)
+	assert.Equal(t, tc, got)
+
+	// Trace context is per-occurrence and does not affect the fingerprint.
+	assert.Equal(t, ex.New(ex.ExTypeApplicationFailure, 500, "Job failed").Fingerprint(), exc.Fingerprint())
+}
+
+func TestTraceContext_OptIn(t *testing.T) {
+	plain := ex.New(ex.ExTypeApplicationFailure, 500, "Job failed")
+	_, ok := plain.TraceContext()
+	assert.False(t, ok)
+	data, err := json.Marshal(plain)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "trace")
+
+	// Malformed values are neither attached nor re-attached on decode.
+	_, ok = plain.WithTraceContext(ex.TraceContext{TraceParent: "garbage"}).TraceContext()
+	assert.False(t, ok)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal([]byte(`{"code":4,"id":500,"message":"x","trace":{"traceparent":"garbage"}}`), &decoded))
+	_, ok = decoded.TraceContext()
+	assert.False(t, ok)
+}
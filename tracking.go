@@ -0,0 +1,201 @@
+package ex
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TrackingEnvVar is the environment variable that turns creation tracking
+// on at startup when set to a true value such as "1"; see SetTracking.
+// Building with the extrack tag does the same.
+const TrackingEnvVar = "EX_TRACK_CREATIONS"
+
+// maxTracked bounds the number of unobserved creations kept; later
+// creations go unrecorded until some are observed or Reset.
+const maxTracked = 10000
+
+// Creation describes where and when an exception was created, as recorded
+// by creation tracking.
+type Creation struct {
+	Code     ExType
+	ID       int
+	Message  string
+	Function string
+	File     string
+	Line     int
+	Time     time.Time
+}
+
+// creation is the tracking record an exception points to. Copies of the
+// exception share it, so observing any of them observes the creation.
+type creation struct {
+	Creation
+	observed atomic.Bool
+	// tracker holds the record until it is observed.
+	tracker *tracker
+}
+
+// tracker holds the unobserved creations of the package, or of a Factory
+// with its own tracking.
+type tracker struct {
+	mu      sync.Mutex
+	records map[*creation]struct{}
+}
+
+var (
+	// tracking enables creation tracking; see SetTracking.
+	tracking atomic.Bool
+
+	// tracked holds the creations recorded by the package's constructors.
+	tracked = &tracker{records: map[*creation]struct{}{}}
+
+	// pkgPrefix is the prefix of the functions of this package, whose
+	// frames are skipped when looking for the creation site.
+	pkgPrefix = reflect.TypeFor[Exception]().PkgPath() + "."
+)
+
+func init() {
+	if on, err := strconv.ParseBool(os.Getenv(TrackingEnvVar)); err == nil && on {
+		tracking.Store(true)
+	}
+}
+
+// SetTracking turns creation tracking on or off. While it is on, every
+// exception made by New, WrapAuto, and the other constructors records its
+// call site and time, and the record is dropped as soon as the exception
+// is observed: rendered with Error, encoded, inspected with errors.Is,
+// errors.As, Code, or ID, or wrapped by another exception. Unobserved
+// then lists the exceptions that were created but never went anywhere,
+// which in development usually means a forgotten return:
+//
+//	if err != nil {
+//	    ex.New(ex.ExTypeApplicationFailure, 5001, "Saving order failed") // missing return
+//	}
+//
+// Tracking costs an allocation and a stack walk per exception, so it is
+// meant for tests and development builds only. It can also be turned on
+// with the EX_TRACK_CREATIONS environment variable or the extrack build
+// tag. Exceptions created during package initialization, such as
+// package-level sentinels, are never tracked, and neither are hooks'
+// views of new exceptions, so hooks do not count as observers.
+func SetTracking(on bool) {
+	tracking.Store(on)
+}
+
+// Tracking reports whether creation tracking is on.
+func Tracking() bool {
+	return tracking.Load()
+}
+
+// Unobserved returns the tracked exceptions created at least minAge ago
+// that have not been observed since, oldest first. A minAge of a few
+// seconds leaves time for exceptions still on their way up the stack.
+func Unobserved(minAge time.Duration) []Creation {
+	return tracked.unobserved(minAge)
+}
+
+// ResetTracking forgets every unobserved creation recorded so far.
+func ResetTracking() {
+	tracked.reset()
+}
+
+// unobserved is Unobserved for the creations held by t.
+func (t *tracker) unobserved(minAge time.Duration) []Creation {
+	cutoff := time.Now().Add(-minAge)
+	t.mu.Lock()
+	out := make([]Creation, 0, len(t.records))
+	for c := range t.records {
+		if !c.Time.After(cutoff) {
+			out = append(out, c.Creation)
+		}
+	}
+	t.mu.Unlock()
+	slices.SortFunc(out, func(a, b Creation) int { return a.Time.Compare(b.Time) })
+	return out
+}
+
+// reset forgets the creations held by t.
+func (t *tracker) reset() {
+	t.mu.Lock()
+	clear(t.records)
+	t.mu.Unlock()
+}
+
+// trackCreation returns the tracking record for a new exception, or nil
+// if tracking is off. Constructors call it after running the hooks. It is
+// small enough to inline, so New pays only an atomic load while tracking
+// is off.
+func trackCreation(code ExType, id int, message string) *creation {
+	if !tracking.Load() {
+		return nil
+	}
+	return tracked.newCreation(code, id, message)
+}
+
+// newCreation records a creation in t, found by skipping this package's
+// frames, or returns nil if it is not to be tracked.
+func (t *tracker) newCreation(code ExType, id int, message string) *creation {
+	var pcs [16]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	var site runtime.Frame
+	for {
+		f, more := frames.Next()
+		site = f
+		if !strings.HasPrefix(f.Function, pkgPrefix) || !more {
+			break
+		}
+	}
+	if isInitFunction(site.Function) {
+		return nil
+	}
+
+	c := &creation{Creation: Creation{
+		Code:     code,
+		ID:       id,
+		Message:  message,
+		Function: site.Function,
+		File:     site.File,
+		Line:     site.Line,
+		Time:     time.Now(),
+	}, tracker: t}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.records) >= maxTracked {
+		return nil
+	}
+	t.records[c] = struct{}{}
+	return c
+}
+
+// isInitFunction reports whether fn names a package initializer, such as
+// "acme/billing.init" or "acme/billing.init.0".
+func isInitFunction(fn string) bool {
+	name := fn[strings.LastIndexByte(fn, '/')+1:]
+	_, after, ok := strings.Cut(name, ".")
+	return ok && (after == "init" || strings.HasPrefix(after, "init."))
+}
+
+// observe marks c observed, dropping its record. It is safe to call on a
+// nil receiver, which untracked exceptions hold.
+func (c *creation) observe() {
+	if c == nil || !c.observed.CompareAndSwap(false, true) {
+		return
+	}
+	c.tracker.mu.Lock()
+	delete(c.tracker.records, c)
+	c.tracker.mu.Unlock()
+}
+
+// observeErr observes err if it is an Exception.
+func observeErr(err error) {
+	if e, ok := err.(Exception); ok {
+		e.creation.observe()
+	}
+}
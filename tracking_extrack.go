@@ -0,0 +1,9 @@
+//go:build extrack
+
+package ex
+
+// Building with -tags extrack turns creation tracking on from the start;
+// see SetTracking.
+func init() {
+	tracking.Store(true)
+}
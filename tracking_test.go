@@ -0,0 +1,85 @@
+package ex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackCreations turns creation tracking on for the rest of the test.
+func trackCreations(t *testing.T) {
+	t.Helper()
+	saved := ex.Tracking()
+	ex.ResetTracking()
+	ex.SetTracking(true)
+	t.Cleanup(func() {
+		ex.SetTracking(saved)
+		ex.ResetTracking()
+	})
+}
+
+func TestTracking_ReportsUnobserved(t *testing.T) {
+	trackCreations(t)
+
+	_ = ex.New(ex.ExTypeApplicationFailure, 5001, "Saving order failed") // forgotten
+	unobserved := ex.Unobserved(0)
+	require.Len(t, unobserved, 1)
+	c := unobserved[0]
+	assert.Equal(t, ex.ExTypeApplicationFailure, c.Code)
+	assert.Equal(t, 5001, c.ID)
+	assert.Equal(t, "Saving order failed", c.Message)
+	assert.True(t, strings.HasSuffix(c.Function, "ex_test.TestTracking_ReportsUnobserved"), c.Function)
+	assert.True(t, strings.HasSuffix(c.File, "tracking_test.go"), c.File)
+	assert.False(t, c.Time.IsZero())
+
+	assert.Empty(t, ex.Unobserved(time.Hour), "too recent")
+}
+
+func TestTracking_Observers(t *testing.T) {
+	trackCreations(t)
+	newErr := func() ex.Exception { return ex.New(ex.ExTypeNotFound, 404, "No such user") }
+
+	observers := map[string]func(ex.Exception){
+		"Error":       func(e ex.Exception) { _ = e.Error() },
+		"fmt":         func(e ex.Exception) { _ = fmt.Sprintf("%v", e) },
+		"Code":        func(e ex.Exception) { _ = e.Code() },
+		"errors.Is":   func(e ex.Exception) { _ = errors.Is(e, io.EOF) },
+		"ex.As":       func(e ex.Exception) { _, _ = ex.As(fmt.Errorf("w: %w", e)) },
+		"JSON":        func(e ex.Exception) { _, _ = json.Marshal(e) },
+		"Fingerprint": func(e ex.Exception) { _ = e.Fingerprint() },
+		"wrapped": func(e ex.Exception) {
+			_ = ex.New(ex.ExTypeApplicationFailure, 500, "x").WithInnerError(e).Error()
+		},
+		"WrapAuto": func(e ex.Exception) { _ = ex.WrapAuto(e, 1, "x").Error() },
+	}
+	for name, observe := range observers {
+		observe(newErr().WithField("derived", true))
+		assert.Empty(t, ex.Unobserved(0), name)
+	}
+}
+
+func TestTracking_Off(t *testing.T) {
+	saved := ex.Tracking()
+	t.Cleanup(func() { ex.SetTracking(saved) })
+	ex.SetTracking(false)
+	ex.ResetTracking()
+	_ = ex.New(ex.ExTypeApplicationFailure, 500, "x")
+	assert.Empty(t, ex.Unobserved(0))
+}
+
+func TestTracking_HooksDoNotObserve(t *testing.T) {
+	trackCreations(t)
+	remove := ex.AddHook(func(e ex.Exception) { _ = e.Error() })
+	defer remove()
+
+	_ = ex.WrapAuto(errors.New("boom"), 1, "x")
+	assert.Len(t, ex.Unobserved(0), 1)
+}
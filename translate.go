@@ -0,0 +1,151 @@
+package ex
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// GenericUserMessage is what UserMessage returns for an error it has no
+// message for. It deliberately says nothing about the failure.
+const GenericUserMessage = "An unexpected error occurred."
+
+// translationKey is the identity of a registered template.
+type translationKey struct {
+	code   ExType
+	id     int
+	locale string
+}
+
+// Translator maps (code, ID, locale) to end-user message templates. It is
+// safe for concurrent use. Most programs use the package-level
+// DefaultTranslator through RegisterTranslation and UserMessage.
+type Translator struct {
+	mu        sync.RWMutex
+	templates map[translationKey]string
+	catalog   *Catalog
+}
+
+// NewTranslator returns an empty Translator that falls back to the
+// messages of catalog, which may be nil.
+func NewTranslator(catalog *Catalog) *Translator {
+	return &Translator{templates: make(map[translationKey]string), catalog: catalog}
+}
+
+// DefaultTranslator is the translator used by the package-level functions.
+// It falls back to DefaultCatalog.
+var DefaultTranslator = NewTranslator(DefaultCatalog)
+
+// Register sets the template for (code, id) in locale, replacing any
+// previous one. Locales are BCP 47 tags such as "de" or "pt-BR", matched
+// case-insensitively; the empty locale holds the default used when no
+// locale matches.
+//
+// A template is plain text in which {key} is replaced by the value of the
+// exception's field key (see FieldsOf). Sensitive fields (see
+// SetSensitiveKeys) are replaced by RedactedValue, and placeholders naming
+// no field are left as they are.
+func (t *Translator) Register(code ExType, id int, locale, template string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.templates[translationKey{code, id, normalizeLocale(locale)}] = template
+}
+
+// Message returns the end-user message for err in locale. It never
+// returns the exception's own text, which is written for developers: the
+// outermost Errorer's (code, ID) is looked up in locale, then in its base
+// language ("de" for "de-AT"), then in the default locale, then in the
+// catalog. Errors with no match, including plain errors, get
+// GenericUserMessage. A nil err yields "".
+func (t *Translator) Message(err error, locale string) string {
+	if err == nil {
+		return ""
+	}
+	e, ok := outermost(err)
+	if !ok {
+		return GenericUserMessage
+	}
+	code, id := e.Code(), e.ID()
+	if tmpl, found := t.lookup(code, id, normalizeLocale(locale)); found {
+		return expandTemplate(tmpl, FieldsOf(err))
+	}
+	if t.catalog != nil {
+		if entry, found := t.catalog.Lookup(code, id); found && entry.Message != "" {
+			return entry.Message
+		}
+	}
+	return GenericUserMessage
+}
+
+// lookup finds the template for (code, id), falling back from locale to
+// its base language and then to the default locale.
+func (t *Translator) lookup(code ExType, id int, locale string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for {
+		if tmpl, ok := t.templates[translationKey{code, id, locale}]; ok {
+			return tmpl, true
+		}
+		if locale == "" {
+			return "", false
+		}
+		if i := strings.LastIndexByte(locale, '-'); i >= 0 {
+			locale = locale[:i]
+		} else {
+			locale = ""
+		}
+	}
+}
+
+// normalizeLocale lower-cases locale and accepts "_" as a separator.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// expandTemplate replaces each {key} in tmpl with the value of field key.
+func expandTemplate(tmpl string, fields []Field) string {
+	if len(fields) == 0 || !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(tmpl, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(tmpl[open:], '}')
+		if end < 0 {
+			break
+		}
+		end += open
+		b.WriteString(tmpl[:open])
+		key := tmpl[open+1 : end]
+		if i := indexField(fields, key); i >= 0 {
+			if IsSensitiveKey(key) {
+				b.WriteString(RedactedValue)
+			} else {
+				fmt.Fprint(&b, ResolveValue(fields[i].Value))
+			}
+		} else {
+			b.WriteString(tmpl[open : end+1])
+		}
+		tmpl = tmpl[end+1:]
+	}
+	b.WriteString(tmpl)
+	return b.String()
+}
+
+// RegisterTranslation sets a template in DefaultTranslator; see
+// Translator.Register.
+func RegisterTranslation(code ExType, id int, locale, template string) {
+	DefaultTranslator.Register(code, id, locale, template)
+}
+
+// UserMessage returns the end-user message for err in locale from
+// DefaultTranslator; see Translator.Message.
+//
+//	ex.RegisterTranslation(ex.ExTypeNotFound, 4041, "de", "Bestellung {order} nicht gefunden.")
+//	msg := ex.UserMessage(err, "de-AT")
+func UserMessage(err error, locale string) string {
+	return DefaultTranslator.Message(err, locale)
+}
@@ -0,0 +1,52 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslator_Locales(t *testing.T) {
+	tr := ex.NewTranslator(nil)
+	tr.Register(ex.ExTypeNotFound, 4041, "", "Order {order} was not found.")
+	tr.Register(ex.ExTypeNotFound, 4041, "de", "Bestellung {order} nicht gefunden.")
+	tr.Register(ex.ExTypeNotFound, 4041, "pt-BR", "Pedido {order} não encontrado.")
+
+	err := fmt.Errorf("handler: %w", ex.New(ex.ExTypeNotFound, 4041, "orders row missing in shard 7").
+		WithField("order", "A-17"))
+
+	assert.Equal(t, "Bestellung A-17 nicht gefunden.", tr.Message(err, "de"))
+	assert.Equal(t, "Bestellung A-17 nicht gefunden.", tr.Message(err, "de_AT"))
+	assert.Equal(t, "Pedido A-17 não encontrado.", tr.Message(err, "PT-br"))
+	assert.Equal(t, "Order A-17 was not found.", tr.Message(err, "fr"))
+	assert.Equal(t, "Order A-17 was not found.", tr.Message(err, ""))
+}
+
+func TestTranslator_Fallbacks(t *testing.T) {
+	c := ex.NewCatalog()
+	require.NoError(t, c.Register(ex.Entry{Code: ex.ExTypeConflict, ID: 4091, Message: "That name is taken."}))
+	tr := ex.NewTranslator(c)
+
+	internal := ex.New(ex.ExTypeConflict, 4091, "unique violation on users_name_idx")
+	assert.Equal(t, "That name is taken.", tr.Message(internal, "de"))
+	assert.Equal(t, ex.GenericUserMessage, tr.Message(ex.New(ex.ExTypeConflict, 1, "internal"), "de"))
+	assert.Equal(t, ex.GenericUserMessage, tr.Message(errors.New("dial tcp: refused"), "de"))
+	assert.Equal(t, "", tr.Message(nil, "de"))
+}
+
+func TestTranslator_Placeholders(t *testing.T) {
+	tr := ex.NewTranslator(nil)
+	tr.Register(ex.ExTypeIncorrectData, 1, "", "Check {password} and {missing} {unclosed")
+
+	err := ex.New(ex.ExTypeIncorrectData, 1, "x").WithField("password", "hunter2")
+	assert.Equal(t, "Check "+ex.RedactedValue+" and {missing} {unclosed", tr.Message(err, ""))
+}
+
+func TestUserMessage(t *testing.T) {
+	ex.RegisterTranslation(ex.ExTypeRateLimited, 42901, "", "Slow down.")
+	assert.Equal(t, "Slow down.", ex.UserMessage(ex.New(ex.ExTypeRateLimited, 42901, "bucket empty"), "en"))
+}
@@ -0,0 +1,30 @@
+package ex
+
+// NewTyped is New for teams that keep their error IDs in a typed enum:
+//
+//	type OrderError int
+//
+//	const ErrOrderNotFound OrderError = 4041
+//
+//	err := ex.NewTyped(ex.ExTypeIncorrectData, ErrOrderNotFound, "Order not found")
+//	id, ok := ex.IDAs[OrderError](err) // ErrOrderNotFound, true
+//
+// The ID is stored as an int, so typed and untyped exceptions with the
+// same code and ID value are identical to errors.Is.
+func NewTyped[C ~int](code ExType, id C, message string) Exception {
+	checkNew(code, int(id), message)
+	e := Exception{code: code, id: int(id), message: message, stack: autoStack(code, int(id), 1), sizeHint: len(message) + 1}
+	runHooks(e)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// IDAs returns the ID of the outermost Exception in err's chain converted
+// to C, and whether there was one.
+func IDAs[C ~int](err error) (C, bool) {
+	e, ok := outermost(err)
+	if !ok {
+		return 0, false
+	}
+	return C(e.ID()), true
+}
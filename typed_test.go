@@ -0,0 +1,33 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderError int
+
+const errOrderNotFound orderError = 4041
+
+func TestNewTyped(t *testing.T) {
+	var hooked int
+	remove := ex.AddHook(func(e ex.Exception) { hooked = e.ID() })
+	defer remove()
+
+	err := ex.NewTyped(ex.ExTypeIncorrectData, errOrderNotFound, "Order not found")
+	assert.Equal(t, 4041, err.ID())
+	assert.Equal(t, 4041, hooked)
+	assert.ErrorIs(t, err, ex.New(ex.ExTypeIncorrectData, 4041, ""))
+
+	id, ok := ex.IDAs[orderError](fmt.Errorf("lookup: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, errOrderNotFound, id)
+
+	id, ok = ex.IDAs[orderError](errors.New("plain"))
+	assert.False(t, ok)
+	assert.Zero(t, id)
+}
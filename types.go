@@ -0,0 +1,217 @@
+package ex
+
+import (
+	"errors"
+	"maps"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Severity ranks how serious an exception is for alerting and logging.
+// The zero value means the severity is unspecified.
+type Severity int
+
+const (
+	// SeverityDebug is diagnostic detail that is not a problem by itself.
+	SeverityDebug Severity = iota + 1
+
+	// SeverityInfo is an expected, benign condition.
+	SeverityInfo
+
+	// SeverityWarning is a problem caused by the caller, such as bad input
+	// or missing credentials, that the service handled correctly.
+	SeverityWarning
+
+	// SeverityError is a failure of the service itself.
+	SeverityError
+
+	// SeverityCritical is a failure that needs immediate attention.
+	SeverityCritical
+)
+
+// String returns the severity name, "Unspecified" for the zero value, or
+// "Severity(N)" for values outside the defined range.
+func (s Severity) String() string {
+	switch s {
+	case 0:
+		return "Unspecified"
+	case SeverityDebug:
+		return "Debug"
+	case SeverityInfo:
+		return "Info"
+	case SeverityWarning:
+		return "Warning"
+	case SeverityError:
+		return "Error"
+	case SeverityCritical:
+		return "Critical"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// TypeInfo is the metadata registered for an ExType. Adapters consult it
+// so that a code's HTTP status, gRPC code, and severity are configured in
+// exactly one place.
+type TypeInfo struct {
+	// Name is the String() rendering for custom codes. Names of the
+	// predefined codes are fixed and cannot be changed.
+	Name string `json:"name,omitempty"`
+
+	// Severity is the default severity of exceptions with this code.
+	Severity Severity `json:"severity,omitempty"`
+
+	// HTTPStatus is the default HTTP response status.
+	HTTPStatus int `json:"http_status,omitempty"`
+
+	// GRPCCode is the default gRPC status code, using the numbering of
+	// google.golang.org/grpc/codes. Zero (OK) means unspecified.
+	GRPCCode uint32 `json:"grpc_code,omitempty"`
+
+	// DocsURL links to documentation for this kind of failure.
+	DocsURL string `json:"docs_url,omitempty"`
+}
+
+// gRPC status codes used by the predefined types, numbered as in
+// google.golang.org/grpc/codes.
+const (
+	grpcInvalidArgument   uint32 = 3
+	grpcDeadlineExceeded  uint32 = 4
+	grpcNotFound          uint32 = 5
+	grpcAlreadyExists     uint32 = 6
+	grpcPermissionDenied  uint32 = 7
+	grpcResourceExhausted uint32 = 8
+	grpcInternal          uint32 = 13
+	grpcUnavailable       uint32 = 14
+	grpcUnauthenticated   uint32 = 16
+)
+
+// ErrInvalidType is returned when registering the reserved ExType(0).
+var ErrInvalidType = errors.New("ex: ExType(0) is reserved")
+
+// ErrReservedType is returned when registering a code reserved for
+// future predefined types (see MaxBuiltinType).
+var ErrReservedType = errors.New("ex: code reserved for predefined types")
+
+var (
+	typesMu sync.Mutex
+	// types is published copy-on-write so lookups never lock.
+	types atomic.Pointer[map[ExType]TypeInfo]
+)
+
+func init() {
+	builtin := maps.Clone(builtinTypes)
+	types.Store(&builtin)
+}
+
+// builtinTypes is the default metadata of the predefined codes. It is
+// never modified.
+var builtinTypes = map[ExType]TypeInfo{
+	ExTypeIncorrectData:      {Severity: SeverityWarning, HTTPStatus: 400, GRPCCode: grpcInvalidArgument},
+	ExTypeLoginRequired:      {Severity: SeverityWarning, HTTPStatus: 401, GRPCCode: grpcUnauthenticated},
+	ExTypePermissionDenied:   {Severity: SeverityWarning, HTTPStatus: 403, GRPCCode: grpcPermissionDenied},
+	ExTypeApplicationFailure: {Severity: SeverityError, HTTPStatus: 500, GRPCCode: grpcInternal},
+	ExTypeTimeout:            {Severity: SeverityError, HTTPStatus: 504, GRPCCode: grpcDeadlineExceeded},
+	ExTypeUnavailable:        {Severity: SeverityError, HTTPStatus: 503, GRPCCode: grpcUnavailable},
+	ExTypeNotFound:           {Severity: SeverityWarning, HTTPStatus: 404, GRPCCode: grpcNotFound},
+	ExTypeConflict:           {Severity: SeverityWarning, HTTPStatus: 409, GRPCCode: grpcAlreadyExists},
+	ExTypeRateLimited:        {Severity: SeverityWarning, HTTPStatus: 429, GRPCCode: grpcResourceExhausted},
+}
+
+// RegisterType sets the metadata for code, replacing any previous
+// registration. The predefined codes come pre-registered and may be
+// re-registered to change their defaults; the rest of the codes up to
+// MaxBuiltinType are reserved and rejected with ErrReservedType.
+//
+// Register custom codes during initialization, before exceptions using
+// them are rendered, so that every adapter sees the same metadata.
+func RegisterType(code ExType, info TypeInfo) error {
+	if err := checkRegistrable(code); err != nil {
+		return err
+	}
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	cur := *types.Load()
+	next := make(map[ExType]TypeInfo, len(cur)+1)
+	for k, v := range cur {
+		next[k] = v
+	}
+	next[code] = info
+	types.Store(&next)
+	return nil
+}
+
+// checkRegistrable returns the error registering metadata for code fails
+// with, if any.
+func checkRegistrable(code ExType) error {
+	if code == 0 {
+		return ErrInvalidType
+	}
+	if category := code.Category(); category <= MaxBuiltinType {
+		if _, predefined := builtinTypes[category]; !predefined {
+			return ErrReservedType
+		}
+	}
+	return nil
+}
+
+// LookupType returns the metadata registered for code. A code with
+// facets (see FacetTransient) that is not registered itself gets the
+// metadata of its category.
+func LookupType(code ExType) (TypeInfo, bool) {
+	cur := *types.Load()
+	info, ok := cur[code]
+	if !ok && code.Facets() != 0 {
+		info, ok = cur[code.Category()]
+	}
+	return info, ok
+}
+
+// Info returns the metadata registered for the exception's code, in the
+// registry of the Factory that created it, if any (see
+// Factory.LookupType), or with LookupType.
+func (e Exception) Info() (TypeInfo, bool) {
+	if e.factory != nil {
+		return e.factory.LookupType(e.code)
+	}
+	return LookupType(e.code)
+}
+
+// TypeInfoOf returns the metadata registered for the code of the first
+// Exception (or other Errorer) in err's chain, as Exception.Info does, so
+// exceptions made by a Factory report the metadata of its registry. If
+// that Exception was made with WrapKeepStatus, the HTTPStatus and
+// GRPCCode are those TypeInfoOf reports for its inner error, where it has
+// them.
+func TypeInfoOf(err error) (TypeInfo, bool) {
+	return typeInfoOf(err, nil)
+}
+
+// typeInfoOf is TypeInfoOf with metadata from lookup, or, if lookup is
+// nil, from each level's own registry.
+func typeInfoOf(err error, lookup func(ExType) (TypeInfo, bool)) (TypeInfo, bool) {
+	e, ok := AsErrorer(err)
+	if !ok {
+		return TypeInfo{}, false
+	}
+	levelLookup := lookup
+	if levelLookup == nil {
+		levelLookup = LookupType
+		if exc, isExc := e.(Exception); isExc && exc.factory != nil {
+			levelLookup = exc.factory.LookupType
+		}
+	}
+	info, found := levelLookup(e.Code())
+	if exc, isExc := e.(Exception); isExc && exc.keepStatus {
+		if inner, innerFound := typeInfoOf(exc.innerError, lookup); innerFound {
+			if inner.HTTPStatus != 0 {
+				info.HTTPStatus, found = inner.HTTPStatus, true
+			}
+			if inner.GRPCCode != 0 {
+				info.GRPCCode, found = inner.GRPCCode, true
+			}
+		}
+	}
+	return info, found
+}
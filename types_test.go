@@ -0,0 +1,111 @@
+package ex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupType_Builtins(t *testing.T) {
+	tests := []struct {
+		code     ex.ExType
+		status   int
+		grpc     uint32
+		severity ex.Severity
+	}{
+		{ex.ExTypeIncorrectData, 400, 3, ex.SeverityWarning},
+		{ex.ExTypeLoginRequired, 401, 16, ex.SeverityWarning},
+		{ex.ExTypePermissionDenied, 403, 7, ex.SeverityWarning},
+		{ex.ExTypeApplicationFailure, 500, 13, ex.SeverityError},
+		{ex.ExTypeTimeout, 504, 4, ex.SeverityError},
+		{ex.ExTypeUnavailable, 503, 14, ex.SeverityError},
+		{ex.ExTypeNotFound, 404, 5, ex.SeverityWarning},
+		{ex.ExTypeConflict, 409, 6, ex.SeverityWarning},
+		{ex.ExTypeRateLimited, 429, 8, ex.SeverityWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			info, ok := ex.LookupType(tt.code)
+			require.True(t, ok)
+			assert.Equal(t, tt.status, info.HTTPStatus)
+			assert.Equal(t, tt.grpc, info.GRPCCode)
+			assert.Equal(t, tt.severity, info.Severity)
+		})
+	}
+
+	_, ok := ex.LookupType(ex.ExType(0))
+	assert.False(t, ok)
+}
+
+func TestRegisterType_Custom(t *testing.T) {
+	t.Cleanup(ex.SaveTypes())
+	const quotaExceeded = ex.ExType(1132)
+	assert.Equal(t, "Unknown(1132)", quotaExceeded.String())
+
+	info := ex.TypeInfo{
+		Name:       "QuotaExceeded",
+		Severity:   ex.SeverityWarning,
+		HTTPStatus: 429,
+		GRPCCode:   8,
+		DocsURL:    "https://example.com/errors/quota",
+	}
+	require.NoError(t, ex.RegisterType(quotaExceeded, info))
+
+	got, ok := ex.LookupType(quotaExceeded)
+	require.True(t, ok)
+	assert.Equal(t, info, got)
+	assert.Equal(t, "QuotaExceeded", quotaExceeded.String())
+
+	exc := ex.New(quotaExceeded, 1, "Too many requests")
+	got, ok = exc.Info()
+	assert.True(t, ok)
+	assert.Equal(t, 429, got.HTTPStatus)
+
+	got, ok = ex.TypeInfoOf(fmt.Errorf("wrapped: %w", exc))
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/errors/quota", got.DocsURL)
+}
+
+func TestRegisterType_BuiltinNameIsFixed(t *testing.T) {
+	original, _ := ex.LookupType(ex.ExTypeIncorrectData)
+	t.Cleanup(func() { _ = ex.RegisterType(ex.ExTypeIncorrectData, original) })
+
+	require.NoError(t, ex.RegisterType(ex.ExTypeIncorrectData, ex.TypeInfo{Name: "Renamed", HTTPStatus: 422}))
+	info, _ := ex.LookupType(ex.ExTypeIncorrectData)
+	assert.Equal(t, 422, info.HTTPStatus)
+	assert.Equal(t, "IncorrectData", ex.ExTypeIncorrectData.String())
+}
+
+func TestRegisterType_Zero(t *testing.T) {
+	assert.ErrorIs(t, ex.RegisterType(ex.ExType(0), ex.TypeInfo{}), ex.ErrInvalidType)
+}
+
+func TestRegisterType_Reserved(t *testing.T) {
+	assert.ErrorIs(t, ex.RegisterType(ex.ExType(10), ex.TypeInfo{}), ex.ErrReservedType)
+	assert.ErrorIs(t, ex.RegisterType(ex.MaxBuiltinType, ex.TypeInfo{}), ex.ErrReservedType)
+	assert.ErrorIs(t, ex.RegisterType(ex.ExType(10)|ex.FacetTransient, ex.TypeInfo{}), ex.ErrReservedType)
+	assert.ErrorIs(t, ex.NewFactory(ex.Config{}).RegisterType(ex.ExType(10), ex.TypeInfo{}), ex.ErrReservedType)
+	_, ok := ex.LookupType(ex.ExType(10))
+	assert.False(t, ok)
+}
+
+func TestTypeInfoOf_NoException(t *testing.T) {
+	_, ok := ex.TypeInfoOf(errors.New("plain"))
+	assert.False(t, ok)
+	_, ok = ex.TypeInfoOf(nil)
+	assert.False(t, ok)
+}
+
+func TestSeverity_String(t *testing.T) {
+	assert.Equal(t, "Unspecified", ex.Severity(0).String())
+	assert.Equal(t, "Debug", ex.SeverityDebug.String())
+	assert.Equal(t, "Info", ex.SeverityInfo.String())
+	assert.Equal(t, "Warning", ex.SeverityWarning.String())
+	assert.Equal(t, "Error", ex.SeverityError.String())
+	assert.Equal(t, "Critical", ex.SeverityCritical.String())
+	assert.Equal(t, "Severity(9)", ex.Severity(9).String())
+}
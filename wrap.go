@@ -0,0 +1,176 @@
+package ex
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// classifierEntry gives each registration its own identity, as hookEntry
+// does for hooks.
+type classifierEntry struct {
+	fn Classifier
+}
+
+var (
+	classifiersMu sync.Mutex
+	// classifiers is published copy-on-write; WrapAuto and Promote load it
+	// without locking.
+	classifiers atomic.Pointer[[]*classifierEntry]
+)
+
+// RegisterClassifier adds c to the classifiers consulted by WrapAuto, and
+// by Promote when it is given none, and returns a function that removes
+// it. Adapters register one for the errors of the library they cover, for
+// example mapping sql.ErrNoRows to ExTypeNotFound. A registered classifier
+// returns a zero code for errors it does not recognize, and the next one
+// is tried; classifiers are tried in registration order. WrapAuto uses
+// only the code, since its caller chooses the ID.
+func RegisterClassifier(c Classifier) (remove func()) {
+	if c == nil {
+		return func() {}
+	}
+	entry := &classifierEntry{fn: c}
+
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	var next []*classifierEntry
+	if cur := classifiers.Load(); cur != nil {
+		next = append(next, *cur...)
+	}
+	next = append(next, entry)
+	classifiers.Store(&next)
+
+	return func() { removeClassifier(entry) }
+}
+
+// removeClassifier unregisters entry if it is still registered.
+func removeClassifier(entry *classifierEntry) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	cur := classifiers.Load()
+	if cur == nil {
+		return
+	}
+	next := make([]*classifierEntry, 0, len(*cur))
+	for _, e := range *cur {
+		if e != entry {
+			next = append(next, e)
+		}
+	}
+	if len(next) == 0 {
+		classifiers.Store(nil)
+		return
+	}
+	classifiers.Store(&next)
+}
+
+// WrapAuto wraps err in a new Exception whose code is inferred rather than
+// chosen by the caller, so a re-wrap cannot hide the true category of the
+// failure:
+//
+//	return ex.WrapAuto(err, 2001, "Loading profile failed")
+//
+// The code is that of the innermost Errorer in err's chain. If there is
+// none, the registered classifiers (see RegisterClassifier) are tried in
+// turn, and failing those, timeouts (see IsTimeout) get ExTypeTimeout and
+// everything else ExTypeApplicationFailure. Errors that wrap several at
+// once (errors.Join) end the search for an Errorer. A nil err yields an
+// ExTypeApplicationFailure exception with no inner error.
+//
+// Like New, WrapAuto follows the stack policy and runs the hooks.
+func WrapAuto(err error, id int, message string) Exception {
+	code := inferCode(err)
+	if buildStrict {
+		checkMisuse(code, id, message, 2)
+	}
+	err = limitDepth(err)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: err,
+		quiet:      quietInner(err),
+		stack:      autoStack(code, id, 1),
+		sizeHint:   sizeHintOf(message, err),
+	}
+	runHooks(e)
+	observeErr(err)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// WrapKeepStatus wraps err in a new exception with code, id, and message,
+// like New(code, id, message).WithInnerError(err), except that the
+// exception keeps reporting the HTTP status and gRPC code of err, as found
+// by TypeInfoOf, rather than those registered for code. It is for layers
+// that reclassify an error for their own logs and metrics without
+// changing what the caller is told:
+//
+//	order, err := repo.Load(ctx, id) // NotFound, 404
+//	if err != nil {
+//	    return ex.WrapKeepStatus(err, ex.ExTypeApplicationFailure, 5003, "Loading order failed")
+//	}
+//
+// httpx.StatusCode, grpcx, IsClientFault, and the other adapters then
+// still see a 404 instead of the 500 ApplicationFailure would give. If err
+// has no registered status, code's status applies as usual; wrap with
+// WithInnerError instead to override the inner status on purpose. Like
+// New, WrapKeepStatus follows the stack policy and runs the hooks.
+func WrapKeepStatus(err error, code ExType, id int, message string) Exception {
+	checkNew(code, id, message)
+	err = limitDepth(err)
+	e := Exception{
+		code:       code,
+		id:         id,
+		message:    message,
+		innerError: err,
+		quiet:      quietInner(err),
+		keepStatus: true,
+		stack:      autoStack(code, id, 1),
+		sizeHint:   sizeHintOf(message, err),
+	}
+	runHooks(e)
+	observeErr(err)
+	e.creation = trackCreation(e.code, e.id, e.message)
+	return e
+}
+
+// inferCode picks the code WrapAuto gives a wrapper of err.
+func inferCode(err error) ExType {
+	if err == nil {
+		return ExTypeApplicationFailure
+	}
+	if e, ok := innermost(err); ok {
+		return e.Code()
+	}
+	code, _ := classifyRegistered(err)
+	return code
+}
+
+// classifyRegistered is the Classifier of WrapAuto, and of Promote when it
+// is given none and err holds no Errorer (see classifyPromoted): the first
+// registered classifier that recognizes err, and failing those,
+// defaultClassify.
+func classifyRegistered(err error) (ExType, int) {
+	if cur := classifiers.Load(); cur != nil {
+		for _, entry := range *cur {
+			if code, id := entry.fn(err); code != 0 {
+				return code, id
+			}
+		}
+	}
+	return defaultClassify(err)
+}
+
+// innermost returns the last Errorer in err's single-error chain.
+func innermost(err error) (Errorer, bool) {
+	var found Errorer
+	for err != nil {
+		if e, ok := err.(Errorer); ok {
+			found = e
+		}
+		err = errors.Unwrap(err)
+	}
+	return found, found != nil
+}
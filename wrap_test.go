@@ -0,0 +1,98 @@
+package ex_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapAuto_InheritsInnermostCode(t *testing.T) {
+	root := ex.New(ex.ExTypeNotFound, 404, "No such user")
+	mid := ex.New(ex.ExTypeApplicationFailure, 500, "Lookup failed").WithInnerError(root)
+
+	e := ex.WrapAuto(fmt.Errorf("repo: %w", mid), 2001, "Loading profile failed")
+	assert.Equal(t, ex.ExTypeNotFound, e.Code())
+	assert.Equal(t, 2001, e.ID())
+	assert.Equal(t, "Loading profile failed: repo: Lookup failed: No such user", e.Error())
+	assert.ErrorIs(t, e, root)
+}
+
+func TestWrapAuto_Classifiers(t *testing.T) {
+	remove := ex.RegisterClassifier(func(err error) (ex.ExType, int) {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ex.ExTypeNotFound, 4041
+		}
+		return 0, 0
+	})
+
+	wrapped := ex.WrapAuto(fmt.Errorf("query: %w", sql.ErrNoRows), 1, "x")
+	assert.Equal(t, ex.ExTypeNotFound, wrapped.Code())
+	assert.Equal(t, 1, wrapped.ID(), "the caller's ID wins")
+	promoted := ex.Promote(fmt.Errorf("query: %w", sql.ErrNoRows), nil)
+	assert.Equal(t, ex.ExTypeNotFound, promoted.Code(), "Promote consults the classifiers too")
+	assert.Equal(t, 4041, promoted.ID())
+	assert.Equal(t, ex.ExTypeTimeout, ex.WrapAuto(context.DeadlineExceeded, 1, "x").Code())
+	assert.Equal(t, ex.ExTypeApplicationFailure, ex.WrapAuto(errors.New("boom"), 1, "x").Code())
+
+	remove()
+	remove()
+	assert.Equal(t, ex.ExTypeApplicationFailure, ex.WrapAuto(sql.ErrNoRows, 1, "x").Code())
+}
+
+func TestWrapAuto_Nil(t *testing.T) {
+	e := ex.WrapAuto(nil, 1, "Failed")
+	assert.Equal(t, ex.ExTypeApplicationFailure, e.Code())
+	assert.NoError(t, e.InnerError())
+}
+
+func TestWrapAuto_RunsHooks(t *testing.T) {
+	var seen []ex.ExType
+	remove := ex.AddHook(func(e ex.Exception) { seen = append(seen, e.Code()) })
+	defer remove()
+
+	ex.WrapAuto(context.DeadlineExceeded, 1, "x")
+	assert.Equal(t, []ex.ExType{ex.ExTypeTimeout}, seen)
+}
+
+func TestWrapKeepStatus(t *testing.T) {
+	notFound := ex.New(ex.ExTypeNotFound, 4041, "No such order")
+	e := ex.WrapKeepStatus(fmt.Errorf("repo: %w", notFound), ex.ExTypeApplicationFailure, 5003, "Loading order failed")
+
+	assert.Equal(t, ex.ExTypeApplicationFailure, e.Code())
+	assert.Equal(t, "Loading order failed: repo: No such order", e.Error())
+	info, ok := ex.TypeInfoOf(e)
+	assert.True(t, ok)
+	assert.Equal(t, 404, info.HTTPStatus)
+	assert.Equal(t, uint32(5), info.GRPCCode)
+	assert.Equal(t, ex.SeverityError, info.Severity, "severity stays that of the wrapper's code")
+	assert.True(t, ex.IsClientFault(e))
+
+	// Nested wrappers keep deferring, and the status survives encoding.
+	inner := ex.WrapKeepStatus(notFound, ex.ExTypeApplicationFailure, 5003, "Loading order failed")
+	outer := ex.WrapKeepStatus(inner, ex.ExTypeUnavailable, 5031, "Checkout failed")
+	data, err := json.Marshal(outer)
+	require.NoError(t, err)
+	var decoded ex.Exception
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	info, _ = ex.TypeInfoOf(decoded)
+	assert.Equal(t, 404, info.HTTPStatus)
+
+	// Plain wrapping overrides the inner status.
+	info, _ = ex.TypeInfoOf(ex.New(ex.ExTypeApplicationFailure, 5003, "x").WithInnerError(notFound))
+	assert.Equal(t, 500, info.HTTPStatus)
+}
+
+func TestWrapKeepStatus_NoInnerStatus(t *testing.T) {
+	e := ex.WrapKeepStatus(errors.New("boom"), ex.ExTypeUnavailable, 5031, "Upstream failed")
+	info, ok := ex.TypeInfoOf(e)
+	assert.True(t, ok)
+	assert.Equal(t, 503, info.HTTPStatus)
+	assert.Equal(t, "Upstream failed: boom", e.Error())
+}
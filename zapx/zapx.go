@@ -0,0 +1,146 @@
+// Package zapx logs exceptions with zap at the level their severity calls
+// for, as slogx does for log/slog: user errors land at Warn and
+// application failures at Error (see ex.LogLevel).
+//
+// The package does not import zap, so the module stays free of
+// dependencies: Logger is the part of *zap.SugaredLogger it uses, and a
+// sugared logger satisfies it as it is.
+//
+//	if err := svc.Checkout(ctx, cart); err != nil {
+//	    zapx.Log(ctx, logger.Sugar(), "checkout failed", err, "cart", cart.ID)
+//	}
+//
+// Routers can use the same behavior with Action:
+//
+//	router := ex.NewRouter().Fallback(zapx.Action(logger.Sugar()))
+package zapx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bold-minds/ex"
+)
+
+// Logger is the subset of *zap.SugaredLogger that zapx uses.
+type Logger interface {
+	Debugw(msg string, keysAndValues ...any)
+	Infow(msg string, keysAndValues ...any)
+	Warnw(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+}
+
+// The zapcore.Level values Level returns.
+const (
+	DebugLevel int8 = -1
+	InfoLevel  int8 = 0
+	WarnLevel  int8 = 1
+	ErrorLevel int8 = 2
+)
+
+// Level returns ex.LogLevel(err) as a zapcore.Level value, for loggers
+// used without sugar:
+//
+//	if ce := logger.Check(zapcore.Level(zapx.Level(err)), "checkout failed"); ce != nil {
+//	    ce.Write(zap.Error(err))
+//	}
+//
+// Levels between the slog ones round down, as slog.Level.String does, and
+// levels above Error, such as ex.LevelCritical, map to ErrorLevel, since
+// zap's higher levels panic or exit.
+func Level(err error) int8 {
+	return zapLevel(ex.LogLevel(err))
+}
+
+// zapLevel maps a slog level to the zapcore.Level value below or at it.
+func zapLevel(level slog.Level) int8 {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+// KeysAndValues returns the key-value pairs describing err, as
+// slogx.Attrs does: its message under "error", the code, ID, and symbolic
+// code (if any) of its outermost Exception under "code", "id", and
+// "code_string", and every field in its chain (see ex.FieldsOf) under its
+// own key, except those made with ex.FieldAt, since a sugared logger does
+// not expose its level. Sensitive fields are redacted as in
+// ex.Exception.Redacted.
+func KeysAndValues(err error) []any {
+	return keysAndValues(err, true)
+}
+
+// keysAndValues is KeysAndValues redacting sensitive fields only if
+// redact is set.
+func keysAndValues(err error, redact bool) []any {
+	if err == nil {
+		return nil
+	}
+	kv := []any{"error", err.Error()}
+	if e, ok := ex.AsErrorer(err); ok {
+		kv = append(kv, "code", e.Code().String(), "id", e.ID())
+		if cs, hasCS := ex.CodeStringOf(err); hasCS {
+			kv = append(kv, "code_string", cs)
+		}
+	}
+	fields := ex.FieldsOf(err)
+	if redact {
+		fields = ex.RedactFields(fields)
+	}
+	for _, f := range fields {
+		if _, leveled := f.Level(); leveled {
+			continue
+		}
+		kv = append(kv, f.Key, ex.ResolveValue(f.Value))
+	}
+	return kv
+}
+
+// Log logs msg and err's key-value pairs, followed by keysAndValues, at
+// ex.LogLevel(err). The tenant carried by ctx is logged if err has none
+// (see ex.TagTenant).
+func Log(ctx context.Context, logger Logger, msg string, err error, keysAndValues ...any) {
+	log(ctx, logger, msg, err, ex.LogLevel(err), true, keysAndValues)
+}
+
+// LogProfile is Log with err pruned by p first (see ex.Prune), so the
+// profile decides what is logged: its fields, with sensitive values
+// redacted only if p.RedactFields is set, and its messages. The level is
+// still that of err. Nothing is logged if p prunes err away.
+func LogProfile(ctx context.Context, logger Logger, msg string, err error, p ex.Profile, keysAndValues ...any) {
+	level := ex.LogLevel(err)
+	if err = ex.Prune(err, p); err == nil {
+		return
+	}
+	log(ctx, logger, msg, err, level, p.RedactFields, keysAndValues)
+}
+
+// log logs err at level, redacting its fields if redact is set.
+func log(ctx context.Context, logger Logger, msg string, err error, level slog.Level, redact bool, args []any) {
+	kv := append(keysAndValues(ex.TagTenant(ctx, err), redact), args...)
+	switch zapLevel(level) {
+	case DebugLevel:
+		logger.Debugw(msg, kv...)
+	case InfoLevel:
+		logger.Infow(msg, kv...)
+	case WarnLevel:
+		logger.Warnw(msg, kv...)
+	default:
+		logger.Errorw(msg, kv...)
+	}
+}
+
+// Action returns an ex.Action that logs the error with Log under the
+// message "error" and passes it on unchanged.
+func Action(logger Logger) ex.Action {
+	return func(err error) error {
+		Log(context.Background(), logger, "error", err)
+		return err
+	}
+}
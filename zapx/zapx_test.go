@@ -0,0 +1,107 @@
+package zapx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/bold-minds/ex"
+	"github.com/bold-minds/ex/zapx"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a zapx.Logger that renders each entry as one line, as a
+// *zap.SugaredLogger with a console encoder would.
+type recorder struct {
+	lines []string
+}
+
+func (r *recorder) log(level, msg string, kv []any) {
+	var b strings.Builder
+	b.WriteString(level + " " + msg)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", kv[i], kv[i+1])
+	}
+	r.lines = append(r.lines, b.String())
+}
+
+func (r *recorder) Debugw(msg string, kv ...any) { r.log("debug", msg, kv) }
+func (r *recorder) Infow(msg string, kv ...any)  { r.log("info", msg, kv) }
+func (r *recorder) Warnw(msg string, kv ...any)  { r.log("warn", msg, kv) }
+func (r *recorder) Errorw(msg string, kv ...any) { r.log("error", msg, kv) }
+
+func TestLog(t *testing.T) {
+	r := &recorder{}
+	ctx := context.Background()
+
+	badInput := ex.Annotate(ex.New(ex.ExTypeIncorrectData, 400, "Invalid email"), ex.F("field", "email"))
+	zapx.Log(ctx, r, "signup failed", badInput, "attempt", 2)
+	zapx.Log(ctx, r, "db failed", ex.New(ex.ExTypeApplicationFailure, 500, "Database down"))
+	zapx.Log(ctx, r, "plain", errors.New("EOF"))
+
+	assert.Equal(t, []string{
+		"warn signup failed error=Invalid email code=IncorrectData id=400 field=email attempt=2",
+		"error db failed error=Database down code=ApplicationFailure id=500",
+		"error plain error=EOF",
+	}, r.lines)
+}
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, zapx.WarnLevel, zapx.Level(ex.New(ex.ExTypeIncorrectData, 400, "Invalid email")))
+	assert.Equal(t, zapx.ErrorLevel, zapx.Level(errors.New("EOF")))
+	assert.Equal(t, zapx.InfoLevel, zapx.Level(nil))
+
+	t.Cleanup(func() { ex.SetLevelMapping(nil) })
+	ex.SetLevelMapping(map[ex.Severity]slog.Level{ex.SeverityWarning: slog.LevelDebug, ex.SeverityError: ex.LevelCritical})
+	assert.Equal(t, zapx.DebugLevel, zapx.Level(ex.New(ex.ExTypeIncorrectData, 400, "Invalid email")))
+	assert.Equal(t, zapx.ErrorLevel, zapx.Level(ex.New(ex.ExTypeApplicationFailure, 500, "Database down")), "zap's higher levels panic or exit")
+}
+
+func TestAction(t *testing.T) {
+	r := &recorder{}
+	router := ex.NewRouter().Fallback(zapx.Action(r))
+
+	err := ex.New(ex.ExTypeLoginRequired, 401, "Login required")
+	assert.Equal(t, err.Error(), router.Handle(err).Error())
+	assert.Equal(t, []string{"warn error error=Login required code=LoginRequired id=401"}, r.lines)
+}
+
+func TestKeysAndValues(t *testing.T) {
+	assert.Nil(t, zapx.KeysAndValues(nil))
+
+	err := ex.Annotate(ex.New(ex.ExTypeLoginRequired, 401, "Login failed").
+		WithCodeString("AUTH_LOGIN_FAILED").
+		WithFields(ex.F("password", "hunter2"), ex.FieldAt(slog.LevelDebug, "query", "SELECT 1")),
+		ex.F("user", "ada"))
+	assert.Equal(t, []any{
+		"error", "Login failed", "code", "LoginRequired", "id", 401, "code_string", "AUTH_LOGIN_FAILED",
+		"user", "ada", "password", ex.RedactedValue,
+	}, zapx.KeysAndValues(err))
+}
+
+func TestLog_TenantFromContext(t *testing.T) {
+	r := &recorder{}
+	ctx := ex.ContextWithTenant(context.Background(), "acme")
+
+	zapx.Log(ctx, r, "plain", errors.New("EOF"))
+	assert.Equal(t, []string{"error plain error=EOF tenant=acme"}, r.lines)
+}
+
+func TestLogProfile(t *testing.T) {
+	r := &recorder{}
+	err := ex.New(ex.ExTypeLoginRequired, 401, "Login failed").
+		WithField("user", "ada").
+		WithField("password", "hunter2")
+
+	zapx.LogProfile(context.Background(), r, "debug", err, ex.ProfileDebug)
+	zapx.LogProfile(context.Background(), r, "client", err, ex.ProfileClient)
+	zapx.LogProfile(context.Background(), r, "dropped", errors.New("EOF"), ex.ProfileClient)
+
+	assert.Equal(t, []string{
+		"warn debug error=Login failed code=LoginRequired id=401 user=ada password=hunter2",
+		"warn client error=Login failed code=LoginRequired id=401",
+	}, r.lines)
+}